@@ -3,6 +3,7 @@ package ast
 import (
 	"bytes"
 	"fmt"
+	"math/big"
 	"strings"
 )
 
@@ -10,6 +11,8 @@ import (
 type IntegerLiteral struct {
 	*BaseNode
 	Value int
+	// BigValue holds the value of a literal too large for an int64, and is nil otherwise
+	BigValue *big.Int
 }
 
 func (il *IntegerLiteral) expressionNode() {}
@@ -66,6 +69,26 @@ func (sl *StringLiteral) String() string {
 	return out.String()
 }
 
+// RegexpLiteral contains the node expression, the pattern and the options of a regexp literal like `/a+b/i`
+type RegexpLiteral struct {
+	*BaseNode
+	Value   string
+	Options string
+}
+
+// Define the regexp literal which contains the node expression and its value
+func (rl *RegexpLiteral) expressionNode() {}
+
+// RegexpLiteral.TokenLiteral gets the literal of the Regexp type token
+func (rl *RegexpLiteral) TokenLiteral() string {
+	return rl.Token.Literal
+}
+
+// RegexpLiteral.String gets the string format of the Regexp type token
+func (rl *RegexpLiteral) String() string {
+	return rl.Token.Literal
+}
+
 // ArrayExpression defines the array expression literal which contains the node expression and its value
 type ArrayExpression struct {
 	*BaseNode
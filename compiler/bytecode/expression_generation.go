@@ -17,11 +17,17 @@ func (g *Generator) compileExpression(is *InstructionSet, exp ast.Expression, sc
 	case *ast.InstanceVariable:
 		is.define(GetInstanceVariable, sourceLine, exp.Value)
 	case *ast.IntegerLiteral:
-		is.define(PutObject, sourceLine, fmt.Sprint(exp.Value))
+		if exp.BigValue != nil {
+			is.define(PutObject, sourceLine, exp.BigValue.String())
+		} else {
+			is.define(PutObject, sourceLine, fmt.Sprint(exp.Value))
+		}
 	case *ast.FloatLiteral:
 		is.define(PutFloat, sourceLine, fmt.Sprint(exp.Value))
 	case *ast.StringLiteral:
 		is.define(PutString, sourceLine, exp.Value)
+	case *ast.RegexpLiteral:
+		is.define(PutRegexp, sourceLine, exp.Value, exp.Options)
 	case *ast.BooleanExpression:
 		is.define(PutBoolean, sourceLine, fmt.Sprint(exp.Value))
 	case *ast.NilExpression:
@@ -189,9 +195,14 @@ func (g *Generator) compileBlockArgExpression(index int, exp *ast.CallExpression
 	is := &InstructionSet{}
 	is.name = fmt.Sprint(index)
 	is.isType = Block
+	is.argTypes = &ArgSet{
+		names: make([]string, len(exp.BlockArguments)),
+		types: make([]int, len(exp.BlockArguments)),
+	}
 
 	for i := 0; i < len(exp.BlockArguments); i++ {
 		table.set(exp.BlockArguments[i].Value)
+		is.argTypes.setArg(i, exp.BlockArguments[i].Value, NormalArg)
 	}
 
 	g.compileCodeBlock(is, exp.Block, scope, table)
@@ -233,7 +244,7 @@ func (g *Generator) compileIfExpression(is *InstructionSet, exp *ast.IfExpressio
 
 func (g *Generator) compilePrefixExpression(is *InstructionSet, exp *ast.PrefixExpression, scope *scope, table *localTable) {
 	switch exp.Operator {
-	case "!":
+	case "!", "~":
 		g.compileExpression(is, exp.Right, scope, table)
 		is.define(Send, exp.Line(), exp.Operator, 0, "")
 	case "*":
@@ -477,3 +477,20 @@ func TestUnusedExpressionRemoval(t *testing.T) {
 	bytecode := compileToBytecode(input)
 	compareBytecode(t, bytecode, expected)
 }
+
+func TestRegexpLiteralCompilation(t *testing.T) {
+	input := `
+	"Goby" =~ /o+b/i
+	`
+
+	expected := `
+<ProgramStart>
+0 putstring Goby
+1 putregexp o+b i
+2 send =~ 1
+3 leave
+`
+
+	bytecode := compileToBytecode(input)
+	compareBytecode(t, bytecode, expected)
+}
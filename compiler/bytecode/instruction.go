@@ -24,6 +24,7 @@ const (
 	SetInstanceVariable = "setinstancevariable"
 	PutBoolean          = "putboolean"
 	PutString           = "putstring"
+	PutRegexp           = "putregexp"
 	PutFloat            = "putfloat"
 	PutSelf             = "putself"
 	PutObject           = "putobject"
@@ -135,6 +136,14 @@ func (as *ArgSet) FindIndex(name string) int {
 	return -1
 }
 
+// Prepend returns a copy of the ArgSet with an argument added in front of the others
+func (as *ArgSet) Prepend(name string, argType int) *ArgSet {
+	return &ArgSet{
+		names: append([]string{name}, as.names...),
+		types: append([]int{argType}, as.types...),
+	}
+}
+
 func (as *ArgSet) setArg(index int, name string, argType int) {
 	as.names[index] = name
 	as.types[index] = argType
@@ -13,6 +13,8 @@ type Lexer struct {
 	ch           rune
 	line         int
 	FSM          *fsm.FSM
+	// The type of the last token, which tells if `/` is a division or starts a regexp
+	lastTokenType token.Type
 }
 
 // New initializes a new lexer with input string
@@ -39,6 +41,12 @@ func New(input string) *Lexer {
 
 // NextToken makes lexer tokenize next character(s)
 func (l *Lexer) NextToken() token.Token {
+	tok := l.nextToken()
+	l.lastTokenType = tok.Type
+	return tok
+}
+
+func (l *Lexer) nextToken() token.Token {
 
 	var tok token.Token
 	l.resetNosymbol()
@@ -81,6 +89,13 @@ func (l *Lexer) NextToken() token.Token {
 			tok = newToken(token.Bang, l.ch, l.line)
 		}
 	case '/':
+		if l.regexpAllowed() {
+			tok.Literal = l.readRegexp()
+			tok.Type = token.Regexp
+			tok.Line = l.line
+			return tok
+		}
+
 		tok = newToken(token.Slash, l.ch, l.line)
 	case '*':
 		if l.peekChar() == '*' {
@@ -90,7 +105,10 @@ func (l *Lexer) NextToken() token.Token {
 			tok = newToken(token.Asterisk, l.ch, l.line)
 		}
 	case '<':
-		if l.peekChar() == '=' {
+		if l.peekChar() == '<' {
+			l.readChar()
+			tok = token.Token{Type: token.LShift, Literal: "<<", Line: l.line}
+		} else if l.peekChar() == '=' {
 			l.readChar()
 			if l.peekChar() == '>' {
 				l.readChar()
@@ -105,6 +123,9 @@ func (l *Lexer) NextToken() token.Token {
 		if l.peekChar() == '=' {
 			l.readChar()
 			tok = token.Token{Type: token.GTE, Literal: ">=", Line: l.line}
+		} else if l.peekChar() == '>' {
+			l.readChar()
+			tok = token.Token{Type: token.RShift, Literal: ">>", Line: l.line}
 		} else {
 			tok = newToken(token.GT, l.ch, l.line)
 		}
@@ -180,7 +201,13 @@ func (l *Lexer) NextToken() token.Token {
 		if l.peekChar() == '&' {
 			l.readChar()
 			tok = token.Token{Type: token.And, Literal: "&&", Line: l.line}
+		} else {
+			tok = newToken(token.BitAnd, l.ch, l.line)
 		}
+	case '^':
+		tok = newToken(token.BitXor, l.ch, l.line)
+	case '~':
+		tok = newToken(token.BitNot, l.ch, l.line)
 	case '%':
 		tok = newToken(token.Modulo, l.ch, l.line)
 	case '#':
@@ -278,7 +305,8 @@ func (l *Lexer) readIdentifier() []rune {
 		l.readChar()
 	}
 
-	if l.ch == '?' {
+	// Destructive methods end with `!`, which isn't followed by `=` like in `a != b`
+	if l.ch == '?' || (l.ch == '!' && l.peekChar() != '=') {
 		l.readChar()
 	}
 
@@ -332,6 +360,52 @@ func (l *Lexer) readString(ch rune) string {
 	return result
 }
 
+// regexpAllowed tells if a `/` starts a regexp literal instead of being a division, which happens where
+// an expression starts, like after an assignment, a comma or an opening parenthesis.
+func (l *Lexer) regexpAllowed() bool {
+	switch l.lastTokenType {
+	case "", token.Assign, token.Match, token.Eq, token.NotEq, token.And, token.Or, token.OrEq, token.Comma,
+		token.Semicolon, token.Colon, token.Bar, token.LParen, token.LBracket, token.LBrace, token.Comment,
+		token.If, token.ElsIf, token.Else, token.When, token.Return, token.Do, token.While:
+		return true
+	}
+
+	return false
+}
+
+// readRegexp reads a regexp literal like `/a+b/i`, and returns it with the slashes and the options.
+// Escapes are kept for the regexp engine, except `\/`, which is a slash in the pattern.
+func (l *Lexer) readRegexp() string {
+	result := "/"
+	l.readChar()
+
+	for l.ch != '/' && l.ch != 0 {
+		if isEscapedChar(l.ch) && l.peekChar() == '/' {
+			l.readChar()
+		} else if isEscapedChar(l.ch) && l.peekChar() != 0 {
+			result += string(l.ch)
+			l.readChar()
+		}
+
+		if l.ch == '\n' {
+			l.line++
+		}
+
+		result += string(l.ch)
+		l.readChar()
+	}
+
+	result += "/"
+	l.readChar() // move to the options
+
+	for l.ch == 'i' || l.ch == 'm' || l.ch == 'x' {
+		result += string(l.ch)
+		l.readChar()
+	}
+
+	return result
+}
+
 func (l *Lexer) readSymbol() []rune {
 	l.readChar()
 
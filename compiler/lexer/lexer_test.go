@@ -458,3 +458,147 @@ func TestNextToken(t *testing.T) {
 		}
 	}
 }
+
+func TestMethodNameSuffixes(t *testing.T) {
+	input := `a.sort!
+	b.empty?
+	c!=d`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.Ident, "a"},
+		{token.Dot, "."},
+		{token.Ident, "sort!"},
+		{token.Ident, "b"},
+		{token.Dot, "."},
+		{token.Ident, "empty?"},
+		{token.Ident, "c"},
+		{token.NotEq, "!="},
+		{token.Ident, "d"},
+		{token.EOF, ""},
+	}
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestBitwiseOperators(t *testing.T) {
+	input := `a & b | c ^ ~d
+	e << 1 >> 2 <= f >= g
+	h && i
+	j do |k| end`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.Ident, "a"},
+		{token.BitAnd, "&"},
+		{token.Ident, "b"},
+		{token.Bar, "|"},
+		{token.Ident, "c"},
+		{token.BitXor, "^"},
+		{token.BitNot, "~"},
+		{token.Ident, "d"},
+		{token.Ident, "e"},
+		{token.LShift, "<<"},
+		{token.Int, "1"},
+		{token.RShift, ">>"},
+		{token.Int, "2"},
+		{token.LTE, "<="},
+		{token.Ident, "f"},
+		{token.GTE, ">="},
+		{token.Ident, "g"},
+		{token.Ident, "h"},
+		{token.And, "&&"},
+		{token.Ident, "i"},
+		{token.Ident, "j"},
+		{token.Do, "do"},
+		{token.Bar, "|"},
+		{token.Ident, "k"},
+		{token.Bar, "|"},
+		{token.End, "end"},
+		{token.EOF, ""},
+	}
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestRegexpLiteral(t *testing.T) {
+	input := `a = /go+\/by/i
+	b = 10 / 2 / 5
+	c =~ /\d/
+	[/x/, (/y/)]
+	d(1) / e[0] /f`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.Ident, "a"},
+		{token.Assign, "="},
+		{token.Regexp, "/go+/by/i"},
+		{token.Ident, "b"},
+		{token.Assign, "="},
+		{token.Int, "10"},
+		{token.Slash, "/"},
+		{token.Int, "2"},
+		{token.Slash, "/"},
+		{token.Int, "5"},
+		{token.Ident, "c"},
+		{token.Match, "=~"},
+		{token.Regexp, "/\\d/"},
+		{token.LBracket, "["},
+		{token.Regexp, "/x/"},
+		{token.Comma, ","},
+		{token.LParen, "("},
+		{token.Regexp, "/y/"},
+		{token.RParen, ")"},
+		{token.RBracket, "]"},
+		{token.Ident, "d"},
+		{token.LParen, "("},
+		{token.Int, "1"},
+		{token.RParen, ")"},
+		{token.Slash, "/"},
+		{token.Ident, "e"},
+		{token.LBracket, "["},
+		{token.Int, "0"},
+		{token.RBracket, "]"},
+		{token.Slash, "/"},
+		{token.Ident, "f"},
+		{token.EOF, ""},
+	}
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"github.com/goby-lang/goby/compiler/parser/errors"
 	"github.com/goby-lang/goby/compiler/parser/precedence"
 	"github.com/goby-lang/goby/compiler/token"
+	"math/big"
 	"strconv"
+	"strings"
 )
 
 func (p *Parser) parseIntegerLiteral() ast.Expression {
@@ -14,8 +16,14 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 
 	value, err := strconv.ParseInt(lit.TokenLiteral(), 0, 64)
 	if err != nil {
-		p.error = errors.NewTypeParsingError(lit.TokenLiteral(), "integer", p.curToken.Line)
-		return nil
+		bigValue, ok := new(big.Int).SetString(lit.TokenLiteral(), 0)
+		if !ok {
+			p.error = errors.NewTypeParsingError(lit.TokenLiteral(), "integer", p.curToken.Line)
+			return nil
+		}
+
+		lit.BigValue = bigValue
+		return lit
 	}
 
 	lit.Value = int(value)
@@ -49,6 +57,18 @@ func (p *Parser) parseStringLiteral() ast.Expression {
 	return lit
 }
 
+func (p *Parser) parseRegexpLiteral() ast.Expression {
+	lit := &ast.RegexpLiteral{BaseNode: &ast.BaseNode{Token: p.curToken}}
+
+	// The literal is like `/pattern/options`
+	literal := p.curToken.Literal
+	end := strings.LastIndex(literal, "/")
+	lit.Value = literal[1:end]
+	lit.Options = literal[end+1:]
+
+	return lit
+}
+
 func (p *Parser) parseBooleanLiteral() ast.Expression {
 	lit := &ast.BooleanExpression{BaseNode: &ast.BaseNode{Token: p.curToken}}
 
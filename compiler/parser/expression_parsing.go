@@ -109,8 +109,8 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 		return nil
 	}
 
-	// Prohibit calling a capitalized method on toplevel:
-	if p.curTokenIs(token.Constant) && (p.fsm.Is(states.Normal) || p.fsm.Is(states.ParsingAssignment)) {
+	// Prohibit calling a capitalized method on toplevel, except conversion methods like `Integer("42")`:
+	if p.curTokenIs(token.Constant) && !conversionMethodNames[p.curToken.Literal] && (p.fsm.Is(states.Normal) || p.fsm.Is(states.ParsingAssignment)) {
 		if p.peekTokenIs(token.LParen) {
 			p.callConstantError(p.curToken.Type)
 			return nil
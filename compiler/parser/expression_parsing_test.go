@@ -3,6 +3,7 @@ package parser
 import (
 	"github.com/goby-lang/goby/compiler/ast"
 	"github.com/goby-lang/goby/compiler/lexer"
+	"strings"
 	"testing"
 )
 
@@ -217,6 +218,52 @@ func TestAssignExpressionWithVariableValue(t *testing.T) {
 	}
 }
 
+func TestAssignExpressionWithKeywordArguments(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedMethod string
+		expectedKeys   []string
+	}{
+		{`x = foo(a: 1)`, "foo", []string{"a"}},
+		{`x = p.foo(1, a: 2, b: 3)`, "foo", []string{"a", "b"}},
+		{`@x = Foo.new(a: bar(b: 1))`, "new", []string{"a"}},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program, err := p.ParseProgram()
+
+		if err != nil {
+			t.Fatal(err.Message)
+		}
+
+		callExpression := program.FirstStmt().IsExpression(t).IsAssignExpression(t).TestableValue().IsCallExpression(t)
+		callExpression.ShouldHasMethodName(tt.expectedMethod)
+
+		var keys []string
+		for _, arg := range callExpression.Arguments {
+			if pair, ok := arg.(*ast.ArgumentPairExpression); ok {
+				keys = append(keys, pair.Key.(*ast.Identifier).Value)
+			}
+		}
+
+		if strings.Join(keys, ",") != strings.Join(tt.expectedKeys, ",") {
+			t.Fatalf("expect keyword arguments to be %v, got %v", tt.expectedKeys, keys)
+		}
+	}
+}
+
+func TestAssignExpressionWithKeywordArgumentsFail(t *testing.T) {
+	l := lexer.New(`x = a: 1`)
+	p := New(l)
+	_, err := p.ParseProgram()
+
+	if err == nil || err.Message != "unexpected : Line: 0" {
+		t.Fatal("Expected keyword argument outside of a method call to fail")
+	}
+}
+
 func TestCallExpression(t *testing.T) {
 	input := `
 		p.add(1, 2 * 3, 4 + 5)
@@ -524,6 +571,11 @@ func TestInfixExpression(t *testing.T) {
 	}{
 		{"4 + 1;", 4, "+", 1},
 		{"3 - 2;", 3, "-", 2},
+		{"6 & 3;", 6, "&", 3},
+		{"6 | 3;", 6, "|", 3},
+		{"6 ^ 3;", 6, "^", 3},
+		{"1 << 4;", 1, "<<", 4},
+		{"16 >> 4;", 16, ">>", 4},
 	}
 
 	for _, tt := range infixTests {
@@ -557,20 +609,22 @@ func TestIntegerLiteralExpression(t *testing.T) {
 	integerLiteral.ShouldEqualTo(5)
 }
 
-func TestIntegerLiteralExpressionFail(t *testing.T) {
+func TestIntegerLiteralExpressionWithBigValue(t *testing.T) {
 	input := `9223372036854775808;`
 
 	l := lexer.New(input)
 	p := New(l)
-	_, err := p.ParseProgram()
+	program, err := p.ParseProgram()
 
-	if err == nil {
-		t.Fatal("Expected Integer literal parsing error")
-	} else if p.error.Message != "could not parse \"9223372036854775808\" as integer. Line: 0" {
-		t.Fatalf("Unexpected parsing error: %s", p.error.Message)
+	if err != nil {
+		t.Fatal(err.Message)
 	}
 
-	// "could not parse 9223372036854775808 as integer. Line: 1"
+	integerLiteral := program.FirstStmt().IsExpression(t).IsIntegerLiteral(t)
+
+	if integerLiteral.BigValue == nil || integerLiteral.BigValue.String() != "9223372036854775808" {
+		t.Fatalf("Expect the literal to have a big value of 9223372036854775808. got: %v", integerLiteral.BigValue)
+	}
 }
 
 func TestNamespaceConstant(t *testing.T) {
@@ -613,6 +667,39 @@ func TestNilExpression(t *testing.T) {
 	}
 }
 
+func TestRegexpLiteralExpression(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedValue   string
+		expectedOptions string
+	}{
+		{`/goby[0-9]+/`, "goby[0-9]+", ""},
+		{`/a\/b\d/mi`, "a/b\\d", "mi"},
+		{`//`, "", ""},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program, err := p.ParseProgram()
+
+		if err != nil {
+			t.Fatal(err.Message)
+		}
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		literal, ok := stmt.Expression.(*ast.RegexpLiteral)
+
+		if !ok {
+			t.Fatalf("Expect expression to be RegexpLiteral. got=%T", stmt.Expression)
+		}
+
+		if literal.Value != tt.expectedValue || literal.Options != tt.expectedOptions {
+			t.Fatalf("Expect regexp literal to be %s with options %q. got=%s with options %q", tt.expectedValue, tt.expectedOptions, literal.Value, literal.Options)
+		}
+	}
+}
+
 func TestSelfExpression(t *testing.T) {
 	input := `
 		self.add(1, 2 * 3, 4 + 5);
@@ -9,7 +9,14 @@ import (
 )
 
 func (p *Parser) parseCallExpressionWithoutReceiver(receiver ast.Expression) ast.Expression {
-	methodToken := receiver.(*ast.Identifier).Token
+	var methodToken token.Token
+
+	if c, ok := receiver.(*ast.Constant); ok {
+		// A constant followed by parentheses is a method call, like `Integer("0x1f")`
+		methodToken = c.Token
+	} else {
+		methodToken = receiver.(*ast.Identifier).Token
+	}
 
 	exp := &ast.CallExpression{BaseNode: &ast.BaseNode{}}
 
@@ -83,3 +83,35 @@ func TestDefStatementWithYield(t *testing.T) {
 	secondExp := stmt.MethodBody().NthStmt(2).IsExpression(t)
 	secondExp.IsYieldExpression(t)
 }
+
+func TestDefStatementWithOperatorName(t *testing.T) {
+	input := `
+	def <=>(other)
+	  0
+	end
+
+	def ==(other); end
+
+	def sort!; end
+	`
+
+	l := lexer.New(input)
+	p := New(l)
+	program, err := p.ParseProgram()
+
+	if err != nil {
+		t.Fatal(err.Message)
+	}
+
+	firstStmt := program.FirstStmt().IsDefStmt(t)
+	firstStmt.ShouldHasName("<=>")
+	firstStmt.ShouldHasNormalParam("other")
+
+	secondStmt := program.NthStmt(2).IsDefStmt(t)
+	secondStmt.ShouldHasName("==")
+	secondStmt.ShouldHasNormalParam("other")
+
+	thirdStmt := program.NthStmt(3).IsDefStmt(t)
+	thirdStmt.ShouldHasName("sort!")
+	thirdStmt.ShouldHasNoParam()
+}
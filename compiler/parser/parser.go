@@ -48,7 +48,7 @@ func New(l *lexer.Lexer) *Parser {
 	p.fsm = fsm.NewFSM(
 		states.Normal,
 		fsm.Events{
-			{Name: events.ParseFuncCall, Src: []string{states.Normal}, Dst: states.ParsingFuncCall},
+			{Name: events.ParseFuncCall, Src: []string{states.Normal, states.ParsingAssignment}, Dst: states.ParsingFuncCall},
 			{Name: events.ParseMethodParam, Src: []string{states.Normal, states.ParsingAssignment}, Dst: states.ParsingMethodParam},
 			{Name: events.ParseAssignment, Src: []string{states.Normal, states.ParsingFuncCall}, Dst: states.ParsingAssignment},
 			{Name: events.BackToNormal, Src: []string{states.ParsingFuncCall, states.ParsingMethodParam, states.ParsingAssignment}, Dst: states.Normal},
@@ -62,12 +62,14 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerPrefix(token.InstanceVariable, p.parseInstanceVariable)
 	p.registerPrefix(token.Int, p.parseIntegerLiteral)
 	p.registerPrefix(token.String, p.parseStringLiteral)
+	p.registerPrefix(token.Regexp, p.parseRegexpLiteral)
 	p.registerPrefix(token.True, p.parseBooleanLiteral)
 	p.registerPrefix(token.False, p.parseBooleanLiteral)
 	p.registerPrefix(token.Null, p.parseNilExpression)
 	p.registerPrefix(token.Minus, p.parsePrefixExpression)
 	p.registerPrefix(token.Asterisk, p.parsePrefixExpression)
 	p.registerPrefix(token.Bang, p.parsePrefixExpression)
+	p.registerPrefix(token.BitNot, p.parsePrefixExpression)
 	p.registerPrefix(token.LParen, p.parseGroupedExpression)
 	p.registerPrefix(token.If, p.parseIfExpression)
 	p.registerPrefix(token.Case, p.parseCaseExpression)
@@ -94,6 +96,11 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerInfix(token.GT, p.parseInfixExpression)
 	p.registerInfix(token.GTE, p.parseInfixExpression)
 	p.registerInfix(token.COMP, p.parseInfixExpression)
+	p.registerInfix(token.Bar, p.parseInfixExpression)
+	p.registerInfix(token.BitXor, p.parseInfixExpression)
+	p.registerInfix(token.BitAnd, p.parseInfixExpression)
+	p.registerInfix(token.LShift, p.parseInfixExpression)
+	p.registerInfix(token.RShift, p.parseInfixExpression)
 	p.registerInfix(token.And, p.parseInfixExpression)
 	p.registerInfix(token.Or, p.parseInfixExpression)
 	p.registerInfix(token.OrEq, p.parseAssignExpression)
@@ -230,10 +237,39 @@ func (p *Parser) callConstantError(t token.Type) {
 	p.error = errors.InitError(msg, errors.UnexpectedTokenError)
 }
 
+// conversionMethodNames are the capitalized Kernel methods that can be called like `Integer("0x1f")`
+var conversionMethodNames = map[string]bool{
+	"Integer": true,
+}
+
+// operatorMethodNames are the operators that can be defined as methods, like `def <=>(other)`
+var operatorMethodNames = map[token.Type]bool{
+	token.Plus:     true,
+	token.Minus:    true,
+	token.Asterisk: true,
+	token.Pow:      true,
+	token.Slash:    true,
+	token.Modulo:   true,
+	token.Match:    true,
+	token.LT:       true,
+	token.LTE:      true,
+	token.GT:       true,
+	token.GTE:      true,
+	token.COMP:     true,
+	token.Eq:       true,
+	token.NotEq:    true,
+	token.Bar:      true,
+	token.BitXor:   true,
+	token.BitAnd:   true,
+	token.BitNot:   true,
+	token.LShift:   true,
+	token.RShift:   true,
+}
+
 // IsNotDefMethodToken ensures correct naming in Def statement
 func (p *Parser) IsNotDefMethodToken() bool {
 
-	return p.curToken.Type != token.Ident && !(p.peekToken.Type == token.Dot && (p.curToken.Type == token.InstanceVariable || p.curToken.Type == token.Constant || p.curToken.Type == token.Self))
+	return p.curToken.Type != token.Ident && !operatorMethodNames[p.curToken.Type] && !(p.peekToken.Type == token.Dot && (p.curToken.Type == token.InstanceVariable || p.curToken.Type == token.Constant || p.curToken.Type == token.Self))
 }
 
 // Token type InstanceVariable and Constant will trigger IsNotParamsToken()
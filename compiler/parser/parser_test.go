@@ -150,6 +150,26 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"n.add(a + b + c * d / f + g)",
 			"n.add((((a + b) + ((c * d) / f)) + g))",
 		},
+		{
+			"a | b ^ c & d",
+			"((a | b) ^ (c & d))",
+		},
+		{
+			"a & b << c + d",
+			"(a & (b << (c + d)))",
+		},
+		{
+			"a << b >> c",
+			"((a << b) >> c)",
+		},
+		{
+			"a & b == c | d",
+			"((a & b) == (c | d))",
+		},
+		{
+			"~a & b",
+			"(~a & b)",
+		},
 	}
 
 	for _, tt := range tests {
@@ -215,6 +235,25 @@ func TestProhibitingCallingCapitalizedMethod(t *testing.T) {
 	}
 }
 
+// Conversion methods like `Integer("42")` can be called on toplevel
+func TestCallingConversionMethod(t *testing.T) {
+	input := `
+	a = Integer("0x1f")
+	`
+
+	l := lexer.New(input)
+	p := New(l)
+	program, err := p.ParseProgram()
+
+	if err != nil {
+		t.Fatal(err.Message)
+	}
+
+	if program.String() != `a = self.Integer("0x1f")` {
+		t.Fatalf("expect conversion method to be parsed as a call expression. got: %s", program.String())
+	}
+}
+
 // If parser doesn't crash then we covered panic successfully
 func TestRecoverMechanism(t *testing.T) {
 	input := `
@@ -21,6 +21,7 @@ const (
 	Int              = "INT"
 	Float            = "FLOAT"
 	String           = "STRING"
+	Regexp           = "REGEXP"
 	Comment          = "COMMENT"
 
 	Assign   = "="
@@ -45,6 +46,12 @@ const (
 	GTE   = ">="
 	COMP  = "<=>"
 
+	BitAnd = "&"
+	BitXor = "^"
+	BitNot = "~"
+	LShift = "<<"
+	RShift = ">>"
+
 	Comma     = ","
 	Semicolon = ";"
 	Colon     = ":"
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// extensionName is the name of a native extension, which is also its package name and the path to require.
+var extensionName = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
+// extensionFiles are the templates of the files `goby ext new` creates.
+var extensionFiles = map[string]*template.Template{
+	"{{.Name}}.go":      template.Must(template.New("ext").Parse(extensionTemplate)),
+	"{{.Name}}_test.go": template.Must(template.New("test").Parse(extensionTestTemplate)),
+}
+
+const extensionTemplate = `package {{.Name}}
+
+import (
+	"strings"
+
+	"github.com/goby-lang/goby/vm"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// The {{.Class}} class is a native extension. Import this package in goby.go to build it into Goby:
+//
+// ` + "```go" + `
+// _ "path/to/{{.Name}}"
+// ` + "```" + `
+//
+// And require it in Goby code:
+//
+// ` + "```ruby" + `
+// require "{{.Name}}"
+//
+// {{.Class}}.greet("Goby")                  # => "Hello, Goby"
+// {{.Class}}.greet("Goby", { shout: true }) # => "HELLO, GOBY"
+// {{.Class}}.each_word("a b") do |w| end    # yields "a" and "b"
+// ` + "```" + `
+//
+// See vm/extension.go for the signatures and helpers native methods use.
+func init() {
+	vm.RegisterExternalClass("{{.Name}}", vm.ExternalClass("{{.Class}}", "",
+		// class methods
+		map[string]vm.MethodBuilder{
+			"greet":     vm.NativeMethod(vm.Sig(vm.ArgString, vm.Optional(vm.ArgHash)), greet),
+			"each_word": vm.NativeMethod(vm.Sig(vm.ArgString).WithBlock(), eachWord),
+		},
+		// instance methods
+		map[string]vm.MethodBuilder{},
+	))
+}
+
+func greet(c *vm.Call) vm.Object {
+	name := c.String(0)
+
+	if name == "" {
+		return c.Error(errors.ArgumentError, "Expect a name")
+	}
+
+	greeting := "Hello, " + name
+
+	if c.Hash(1)["shout"] == vm.TRUE {
+		greeting = strings.ToUpper(greeting)
+	}
+
+	return c.Return(greeting)
+}
+
+func eachWord(c *vm.Call) vm.Object {
+	for _, word := range strings.Fields(c.String(0)) {
+		c.Yield(c.Return(word))
+	}
+
+	return c.Arg(0)
+}
+`
+
+const extensionTestTemplate = `package {{.Name}}
+
+import (
+	"testing"
+
+	"github.com/goby-lang/goby/vm"
+)
+
+func Test{{.Class}}Greet(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{` + "`{{.Class}}.greet(\"Goby\")`" + `, "Hello, Goby"},
+		{` + "`{{.Class}}.greet(\"Goby\", { shout: true })`" + `, "HELLO, GOBY"},
+		{` + "`" + `
+			words = []
+			{{.Class}}.each_word("native extension") do |word|
+			  words.push(word)
+			end
+			words.join(",")
+			` + "`" + `, "native,extension"},
+	}
+
+	for i, tt := range tests {
+		evaluated := vm.ExecAndReturn(t, "require \"{{.Name}}\"\n"+tt.input)
+		vm.VerifyExpected(t, i, evaluated, tt.expected)
+	}
+}
+
+func Test{{.Class}}ArgumentErrors(t *testing.T) {
+	tests := []string{
+		` + "`{{.Class}}.greet`" + `,
+		` + "`{{.Class}}.greet(1)`" + `,
+		` + "`{{.Class}}.each_word(\"a b\")`" + `,
+	}
+
+	for i, input := range tests {
+		evaluated := vm.ExecAndReturn(t, "require \"{{.Name}}\"\n"+input)
+
+		if _, ok := evaluated.(*vm.Error); !ok {
+			t.Errorf("At test case %d: Expect an error. got: %v", i, evaluated)
+		}
+	}
+}
+`
+
+// runExt runs `goby ext new [-dir path] name`, which creates a native extension named name in the
+// directory, which defaults to the current one.
+func runExt(args []string) {
+	if len(args) == 0 || args[0] != "new" {
+		fmt.Println("Usage: goby ext new [-dir path] name")
+		os.Exit(1)
+	}
+
+	fs := flag.NewFlagSet("ext new", flag.ExitOnError)
+	dir := fs.String("dir", ".", "Directory to create the extension in")
+	fs.Parse(args[1:])
+
+	if fs.NArg() != 1 {
+		fmt.Println("Usage: goby ext new [-dir path] name")
+		os.Exit(1)
+	}
+
+	name := fs.Arg(0)
+
+	if !extensionName.MatchString(name) {
+		fmt.Printf("Invalid extension name %q. Expect lowercase letters and digits, like \"greeter\"\n", name)
+		os.Exit(1)
+	}
+
+	path := filepath.Join(*dir, name)
+	_, err := os.Stat(path)
+
+	if err == nil {
+		fmt.Printf("%s already exists\n", path)
+		os.Exit(1)
+	}
+
+	err = os.MkdirAll(path, 0755)
+	reportErrorAndExit(err)
+
+	data := struct{ Name, Class string }{name, strings.ToUpper(name[:1]) + name[1:]}
+
+	for fileName, tmpl := range extensionFiles {
+		fileName = strings.Replace(fileName, "{{.Name}}", name, 1)
+		file, err := os.Create(filepath.Join(path, fileName))
+		reportErrorAndExit(err)
+
+		err = tmpl.Execute(file, data)
+		file.Close()
+		reportErrorAndExit(err)
+
+		fmt.Printf("Created %s\n", filepath.Join(path, fileName))
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/goby-lang/goby/compiler/parser"
 	"github.com/goby-lang/goby/igb"
 	_ "github.com/goby-lang/goby/native/db"
+	_ "github.com/goby-lang/goby/native/redis"
 	"github.com/goby-lang/goby/vm"
 	"github.com/pkg/profile"
 )
@@ -74,6 +75,12 @@ func main() {
 		instructionSets, err := compiler.CompileToInstructions("Spec.run", parser.NormalMode)
 		v.ExecInstructions(instructionSets, filePath)
 		return
+	case "migrate", "migrate:up", "migrate:down", "migrate:status", "migrate:redo":
+		runMigrations(flag.Arg(0), flag.Args()[1:])
+		return
+	case "ext":
+		runExt(flag.Args()[1:])
+		return
 	default:
 		fp = flag.Arg(0)
 
@@ -113,6 +120,57 @@ func main() {
 	}
 }
 
+// migrateScript runs a migrate command with the driver name, data source, migrations directory and
+// command given in ARGV.
+const migrateScript = `
+require "db/migrate"
+
+db = DB.open(ARGV[0], ARGV[1])
+DB::Migrator.new(db, ARGV[2]).run(ARGV[3])
+db.close
+`
+
+// runMigrations runs `goby migrate [-driver name] [-source source] [-dir path] [up|down|status|redo]`,
+// which is also available as `goby migrate:up`, `goby migrate:down` ... etc.
+func runMigrations(subcommand string, args []string) {
+	fs := flag.NewFlagSet(subcommand, flag.ExitOnError)
+	driver := fs.String("driver", "sqlite", "Database driver name")
+	source := fs.String("source", os.Getenv("DATABASE_URL"), "Data source of the database, defaults to $DATABASE_URL")
+	dir := fs.String("dir", "db/migrations", "Directory of the migration files")
+	fs.Parse(args)
+
+	command := strings.TrimPrefix(subcommand, "migrate:")
+
+	if subcommand == "migrate" {
+		command = fs.Arg(0)
+
+		if command == "" {
+			command = "up"
+		}
+	}
+
+	if *source == "" {
+		fmt.Println("Expect a data source, given with -source or $DATABASE_URL")
+		os.Exit(1)
+	}
+
+	wd, err := os.Getwd()
+	reportErrorAndExit(err)
+
+	v, err := vm.New(wd, []string{*driver, *source, *dir, command})
+	reportErrorAndExit(err)
+
+	instructionSets, err := compiler.CompileToInstructions(migrateScript, parser.NormalMode)
+	reportErrorAndExit(err)
+
+	v.ExecInstructions(instructionSets, filepath.Join(wd, "migrate"))
+
+	// The error has been printed by the VM
+	if _, ok := v.GetExecResult().(*vm.Error); ok {
+		os.Exit(1)
+	}
+}
+
 func extractFileInfo(fp string) (dir, filename, fileExt string) {
 	dir, filename = filepath.Split(fp)
 	dir, _ = filepath.Abs(dir)
@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
@@ -162,3 +163,79 @@ func TestTestCommand(t *testing.T) {
 		t.Fatalf("Test files by giving file name failed, got: %s", string(byt))
 	}
 }
+
+func TestMigrateCommand(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "migrate_test.db")
+
+	_, out := execGoby(t, "migrate", "-source", source, "-dir", "test_fixtures/migrate_test", "up")
+
+	byt, err := ioutil.ReadAll(out)
+	if err != nil {
+		t.Fatalf("Couldn't read from pipe: %s", err.Error())
+	}
+
+	if string(byt) != "Applied 1 create_users\nApplied 2 add_admins\nApplied 3 add_age_to_users\n" {
+		t.Fatalf("Migrate up failed, got: %s", string(byt))
+	}
+
+	_, out = execGoby(t, "migrate:redo", "-source", source, "-dir", "test_fixtures/migrate_test")
+
+	byt, err = ioutil.ReadAll(out)
+	if err != nil {
+		t.Fatalf("Couldn't read from pipe: %s", err.Error())
+	}
+
+	if !strings.Contains(string(byt), "has no down file") {
+		t.Fatalf("Expect migrate:redo to fail on a migration without down file, got: %s", string(byt))
+	}
+
+	_, out = execGoby(t, "migrate", "-source", source, "-dir", "test_fixtures/migrate_test", "status")
+
+	byt, err = ioutil.ReadAll(out)
+	if err != nil {
+		t.Fatalf("Couldn't read from pipe: %s", err.Error())
+	}
+
+	if !strings.Contains(string(byt), "up    2               add_admins\n") {
+		t.Fatalf("Migrate status failed, got: %s", string(byt))
+	}
+}
+
+func TestExtNewCommand(t *testing.T) {
+	dir := t.TempDir()
+
+	_, out := execGoby(t, "ext", "new", "-dir", dir, "greeter")
+
+	byt, err := ioutil.ReadAll(out)
+	if err != nil {
+		t.Fatalf("Couldn't read from pipe: %s", err.Error())
+	}
+
+	for _, name := range []string{"greeter.go", "greeter_test.go"} {
+		path := filepath.Join(dir, "greeter", name)
+
+		if !strings.Contains(string(byt), "Created "+path+"\n") {
+			t.Fatalf("Expect ext new to create %s, got: %s", path, string(byt))
+		}
+
+		src, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Couldn't read %s: %s", path, err.Error())
+		}
+
+		if !strings.HasPrefix(string(src), "package greeter\n") {
+			t.Fatalf("Expect %s to be in the greeter package, got: %s", path, string(src))
+		}
+	}
+
+	_, out = execGoby(t, "ext", "new", "-dir", dir, "greeter")
+
+	byt, err = ioutil.ReadAll(out)
+	if err != nil {
+		t.Fatalf("Couldn't read from pipe: %s", err.Error())
+	}
+
+	if !strings.Contains(string(byt), "already exists") {
+		t.Fatalf("Expect ext new not to overwrite an extension, got: %s", string(byt))
+	}
+}
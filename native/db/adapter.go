@@ -0,0 +1,152 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// adapter hides the differences between database drivers, so DB#exec behaves the same with any of them.
+// Support for another driver is added by implementing adapter and registering it in adapters.
+type adapter interface {
+	// result converts the result of a statement which returns no rows.
+	result(r sql.Result) (*execResult, error)
+	// returning adds a clause to the statement, which makes it return the given columns of the rows it affects.
+	returning(query string, columns []string) (string, error)
+}
+
+// execResult is the result of DB#exec, see DB::Result in db.gb file.
+type execResult struct {
+	rowsAffected int64
+	// lastInsertID is nil when the driver can't report it
+	lastInsertID *int64
+	// returning holds the raw values of the returned columns of each affected row, when they are requested
+	returning [][]interface{}
+	decoder   *rowDecoder
+}
+
+var adapters = map[string]adapter{
+	"postgres": postgresAdapter{},
+	"sqlite":   sqliteAdapter{},
+	"sqlite3":  sqliteAdapter{},
+}
+
+// adapterFor returns the adapter of the driver, or a generic one which only relies on database/sql.
+func adapterFor(driverName string) adapter {
+	if a, ok := adapters[driverName]; ok {
+		return a
+	}
+
+	return genericAdapter{driverName: driverName}
+}
+
+// postgresAdapter supports RETURNING, but the driver can't report the last inserted id.
+type postgresAdapter struct{}
+
+func (postgresAdapter) result(r sql.Result) (*execResult, error) {
+	affected, err := r.RowsAffected()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &execResult{rowsAffected: affected}, nil
+}
+
+func (postgresAdapter) returning(query string, columns []string) (string, error) {
+	return returningClause(query, columns), nil
+}
+
+// sqliteAdapter supports RETURNING (since SQLite 3.35) and reports the last inserted rowid.
+type sqliteAdapter struct{}
+
+func (sqliteAdapter) result(r sql.Result) (*execResult, error) {
+	affected, err := r.RowsAffected()
+
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := r.LastInsertId()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &execResult{rowsAffected: affected, lastInsertID: &id}, nil
+}
+
+func (sqliteAdapter) returning(query string, columns []string) (string, error) {
+	return returningClause(query, columns), nil
+}
+
+// genericAdapter is used for drivers without their own adapter. It reports whatever database/sql
+// supports, and doesn't know how to return columns.
+type genericAdapter struct {
+	driverName string
+}
+
+func (a genericAdapter) result(r sql.Result) (*execResult, error) {
+	result := &execResult{}
+	result.rowsAffected, _ = r.RowsAffected()
+
+	if id, err := r.LastInsertId(); err == nil {
+		result.lastInsertID = &id
+	}
+
+	return result, nil
+}
+
+func (a genericAdapter) returning(query string, columns []string) (string, error) {
+	return "", fmt.Errorf("The %s driver doesn't support returning columns", a.driverName)
+}
+
+// returningClause adds a RETURNING clause to the query. Columns are quoted as identifiers, so a column name
+// can't inject SQL into the statement.
+func returningClause(query string, columns []string) string {
+	quoted := make([]string, len(columns))
+
+	for i, column := range columns {
+		quoted[i] = quoteIdentifier(column)
+	}
+
+	return fmt.Sprintf("%s RETURNING %s", strings.TrimRight(strings.TrimSpace(query), ";"), strings.Join(quoted, ", "))
+}
+
+// quoteIdentifier quotes a name with double quotes, doubling the ones inside, as Postgres and SQLite do.
+func quoteIdentifier(name string) string {
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
+
+// returningResult collects the rows returned by a statement with a RETURNING clause.
+func returningResult(rows *sqlx.Rows) (*execResult, error) {
+	defer rows.Close()
+
+	decoder, err := newRowDecoder(rows)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := &execResult{returning: [][]interface{}{}, decoder: decoder}
+
+	for rows.Next() {
+		values, err := decoder.scan(rows)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result.returning = append(result.returning, values)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result.rowsAffected = int64(len(result.returning))
+
+	return result, nil
+}
@@ -0,0 +1,179 @@
+package db
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/goby-lang/goby/vm"
+)
+
+// postgresResult behaves like the sql.Result of lib/pq, which can't report the last inserted id.
+type postgresResult struct {
+	rowsAffected int64
+}
+
+func (r postgresResult) LastInsertId() (int64, error) {
+	return 0, errors.New("LastInsertId is not supported by this driver")
+}
+
+func (r postgresResult) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
+}
+
+func newTestThread(t *testing.T) *Thread {
+	v, err := vm.New(os.Getenv("GOBY_ROOT"), []string{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return v.NewThread()
+}
+
+func TestPostgresAdapter(t *testing.T) {
+	a := adapterFor("postgres")
+
+	result, err := a.result(postgresResult{rowsAffected: 3})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.rowsAffected != 3 || result.lastInsertID != nil {
+		t.Fatalf("Expect 3 affected rows without a last inserted id. got: %d, %v", result.rowsAffected, result.lastInsertID)
+	}
+
+	query, err := a.returning("INSERT INTO users (name) VALUES ($1);", []string{"id", "name"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `INSERT INTO users (name) VALUES ($1) RETURNING "id", "name"`
+
+	if query != expected {
+		t.Fatalf("Expect query to be %q. got: %q", expected, query)
+	}
+
+	query, err = a.returning("DELETE FROM users", []string{`id" FROM users; DROP TABLE users; --`})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected = `DELETE FROM users RETURNING "id"" FROM users; DROP TABLE users; --"`
+
+	if query != expected {
+		t.Fatalf("Expect query to be %q. got: %q", expected, query)
+	}
+}
+
+// The values are in the forms lib/pq scans them into, so the Postgres decoding is covered without a server.
+func TestDecodePostgresValues(t *testing.T) {
+	thread := newTestThread(t)
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		typeName string
+		value    interface{}
+		class    string
+		expected interface{}
+	}{
+		{"NUMERIC", []byte("12.50"), "Decimal", "25/2"},
+		{"NUMERIC", []byte("-0.001"), "Decimal", "-1/1000"},
+		{"DECIMAL", []byte("100000000000000000000.1"), "Decimal", "1000000000000000000001/10"},
+		{"JSONB", []byte(`{"a": [1, 2.5, "x"], "b": null}`), "Hash", map[string]interface{}{"a": []interface{}{1, 2.5, "x"}, "b": nil}},
+		{"JSON", []byte(`[true, {"c": 3}]`), "Array", []interface{}{true, map[string]interface{}{"c": 3}}},
+		{"BOOL", true, "Boolean", true},
+		{"INT4", int64(42), "Integer", 42},
+		{"TEXT", "Stan", "String", "Stan"},
+		{"TIMESTAMPTZ", createdAt, "Time", createdAt},
+		{"NUMERIC", nil, "Null", nil},
+	}
+
+	for i, tt := range tests {
+		decoded, err := decodeValue(thread, tt.typeName, tt.value)
+
+		if err != nil {
+			t.Fatalf("At test case %d: %s", i, err.Error())
+		}
+
+		if decoded.Class().Name != tt.class {
+			t.Fatalf("At test case %d: Expect a %s. got: %s", i, tt.class, decoded.Class().Name)
+		}
+
+		value := vm.ToGoValue(decoded)
+
+		if r, ok := value.(*big.Rat); ok {
+			value = r.String()
+		}
+
+		if !equalGoValues(value, tt.expected) {
+			t.Fatalf("At test case %d: Expect %#v. got: %#v", i, tt.expected, value)
+		}
+	}
+}
+
+func TestDecodePostgresValuesFail(t *testing.T) {
+	thread := newTestThread(t)
+
+	tests := []struct {
+		typeName string
+		value    interface{}
+		expected string
+	}{
+		{"NUMERIC", []byte("NaN"), "invalid decimal NaN"},
+		{"NUMERIC", []byte("Infinity"), "invalid decimal Infinity"},
+		{"JSONB", []byte(`{"a": `), "unexpected EOF"},
+	}
+
+	for i, tt := range tests {
+		_, err := decodeValue(thread, tt.typeName, tt.value)
+
+		if err == nil || err.Error() != tt.expected {
+			t.Fatalf("At test case %d: Expect error %q. got: %v", i, tt.expected, err)
+		}
+	}
+}
+
+// equalGoValues compares the results of vm.ToGoValue, which holds Times and nested collections.
+func equalGoValues(a, b interface{}) bool {
+	switch b := b.(type) {
+	case time.Time:
+		a, ok := a.(time.Time)
+		return ok && a.Equal(b)
+	case []interface{}:
+		a, ok := a.([]interface{})
+
+		if !ok || len(a) != len(b) {
+			return false
+		}
+
+		for i := range b {
+			if !equalGoValues(a[i], b[i]) {
+				return false
+			}
+		}
+
+		return true
+	case map[string]interface{}:
+		a, ok := a.(map[string]interface{})
+
+		if !ok || len(a) != len(b) {
+			return false
+		}
+
+		for k := range b {
+			if !equalGoValues(a[k], b[k]) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return a == b
+	}
+}
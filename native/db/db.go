@@ -1,13 +1,24 @@
 package db
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
 
 	"github.com/goby-lang/goby/vm"
+	"github.com/goby-lang/goby/vm/classes"
 	"github.com/goby-lang/goby/vm/errors"
 	"github.com/jmoiron/sqlx"
 	// all packages imported by this need postgres
 	_ "github.com/lib/pq"
+	// the sqlite driver is a pure-Go build of SQLite, running on a WebAssembly runtime
+	"github.com/ncruces/go-sqlite3"
+	sqlitedriver "github.com/ncruces/go-sqlite3/driver"
+	_ "github.com/ncruces/go-sqlite3/embed"
+	_ "github.com/ncruces/go-sqlite3/vfs/memdb"
 )
 
 // Imported objects from vm
@@ -15,21 +26,31 @@ type Object = vm.Object
 type GoObject = vm.GoObject
 type VM = vm.VM
 type Thread = vm.Thread
-type Method = vm.Method
 type StringObject = vm.StringObject
 
 func init() {
+	sql.Register("sqlite", &sqlitedriver.SQLite{Init: initSQLiteConn})
+	sqlx.BindDriver("sqlite", sqlx.QUESTION)
+
 	vm.RegisterExternalClass("db", vm.ExternalClass("DB", "db.gb",
 		// class methods
 		map[string]vm.MethodBuilder{
-			"get_connection": getConnection,
+			"get_connection":  vm.NativeMethod(vm.Sig(vm.ArgString, vm.ArgString, vm.Optional(vm.ArgHash)), getConnection),
+			"migration_files": vm.NativeMethod(vm.Sig(vm.ArgString), migrationFiles),
+			"load_migration":  vm.NativeMethod(vm.Sig(vm.ArgString, vm.ArgString), loadMigration),
 		},
 		// instance methods
 		map[string]vm.MethodBuilder{
-			"query": query,
-			"close": closeDB,
-			"exec":  exec,
-			"run":   run,
+			"fetch_rows":        vm.NativeMethod(vm.Sig(vm.Rest(vm.ArgAny)), fetchRows),
+			"fetch_each":        vm.NativeMethod(vm.Sig(vm.Rest(vm.ArgAny)).WithBlock(), fetchEach),
+			"fetch_one":         vm.NativeMethod(vm.Sig(vm.Rest(vm.ArgAny)), fetchOne),
+			"close":             vm.NativeMethod(vm.Sig(), closeDB),
+			"exec_statement":    vm.NativeMethod(vm.Sig(vm.Nullable(vm.ArgString), vm.ArgAny, vm.ArgArray), execStatement),
+			"run":               vm.NativeMethod(vm.Sig(vm.ArgString, vm.Rest(vm.ArgAny)), run),
+			"begin_transaction": vm.NativeMethod(vm.Sig(vm.Optional(vm.ArgHash)), beginTransaction),
+			"rollback":          vm.NativeMethod(vm.Sig(), rollback),
+			"prepare_statement": vm.NativeMethod(vm.Sig(vm.ArgString), prepareStatement),
+			"stats":             vm.NativeMethod(vm.Sig(), stats),
 		},
 	))
 }
@@ -38,7 +59,11 @@ func init() {
 // and the source which specifies the parameter including the name of the database and the
 // username ...etc.
 //
-// Currently supported DB driver is 'postgres'
+// Currently supported DB drivers are 'postgres' and 'sqlite'. The sqlite driver accepts a file path
+// or ":memory:" for an in-memory database, which is dropped when the connection is closed.
+//
+// An optional Hash configures the connection pool, with `max_open`, `max_idle` and `conn_max_lifetime`
+// (in seconds) keys. nil values keep database/sql's defaults.
 //
 // (The example is the DB#open class method which is implemented in db.gb file)
 //
@@ -55,275 +80,746 @@ func init() {
 //
 // @return [Object]
 //
-func getConnection(receiver vm.Object, sourceLine int) vm.Method {
-	return func(t *vm.Thread, args []vm.Object) vm.Object {
-		if len(args) != 2 {
-			return t.VM().InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 2, len(args))
-		}
+func getConnection(c *vm.Call) Object {
+	driver, source := c.String(0), c.String(1)
 
-		driverName, ok := args[0].(*vm.StringObject)
+	if driver == "sqlite" {
+		source = sqliteDataSource(source)
+	}
 
-		if !ok {
-			return t.VM().InitErrorObject(errors.ArgumentError, sourceLine, "Expect database's driver name to be a String object. got: %s", args[0].Class().Name)
-		}
+	conn, err := sqlx.Open(driver, source)
 
-		dataSource, ok := args[1].(*vm.StringObject)
+	if err != nil {
+		return c.Error(errors.InternalError, err.Error())
+	}
 
-		if !ok {
-			return t.VM().InitErrorObject(errors.ArgumentError, sourceLine, "Expect database's data source to be a String object. got: %s", args[1].Class().Name)
-		}
+	err = configurePool(conn, c.Hash(2))
 
-		conn, err := sqlx.Open(driverName.Value().(string), dataSource.Value().(string))
+	if err != nil {
+		conn.Close()
+		return c.Error(errors.ArgumentError, err.Error())
+	}
 
-		if err != nil {
-			return t.VM().InitErrorObject(errors.InternalError, sourceLine, err.Error())
+	return c.Return(conn)
+}
+
+// configurePool applies the pool options given to DB.open, see getConnection.
+func configurePool(conn *sqlx.DB, options map[string]Object) error {
+	for k, value := range options {
+		if value == vm.NULL {
+			continue
 		}
 
-		connObj := t.VM().InitObjectFromGoType(conn)
-		return connObj
+		switch k {
+		case "max_open", "max_idle":
+			n, ok := value.(*vm.IntegerObject)
+
+			if !ok {
+				return fmt.Errorf("Expect %s to be an Integer. got: %s", k, value.Class().Name)
+			}
+
+			// Integers too big for an int are backed by a *big.Int
+			size, ok := n.Value().(int)
+
+			if !ok || size > math.MaxInt32 {
+				return fmt.Errorf("Expect %s to be at most %d. got: %v", k, math.MaxInt32, n.Value())
+			}
+
+			if k == "max_open" {
+				conn.SetMaxOpenConns(size)
+			} else {
+				conn.SetMaxIdleConns(size)
+			}
+		case "conn_max_lifetime":
+			var seconds float64
+
+			switch value := value.(type) {
+			case *vm.IntegerObject:
+				if i, ok := value.Value().(int); ok {
+					seconds = float64(i)
+				} else {
+					seconds = math.Inf(1)
+				}
+			case *vm.FloatObject:
+				seconds = value.Value().(float64)
+			default:
+				return fmt.Errorf("Expect conn_max_lifetime to be an Integer or a Float. got: %s", value.Class().Name)
+			}
+
+			if math.IsNaN(seconds) || seconds < 0 || seconds > math.MaxInt64/float64(time.Second) {
+				return fmt.Errorf("Expect conn_max_lifetime to be between 0 and %d seconds. got: %v", math.MaxInt64/int64(time.Second), value.Value())
+			}
+
+			conn.SetConnMaxLifetime(time.Duration(seconds * float64(time.Second)))
+		default:
+			return fmt.Errorf("Unknown pool option: %s", k)
+		}
 	}
+
+	return nil
 }
 
-func closeDB(receiver vm.Object, sourceLine int) vm.Method {
-	return func(t *vm.Thread, args []vm.Object) vm.Object {
-		conn, err := getDBConn(t, receiver)
+// The stats method returns the statistics of the connection pool as a Hash. `wait_duration` is
+// in seconds, the other values are counts.
+//
+// ```ruby
+// require "db"
+//
+// db = DB.open("sqlite", "app.db", max_open: 10)
+// db.stats[:max_open_connections] # => 10
+// db.stats[:in_use]               # => 0
+// ```
+//
+// @return [Hash]
+//
+func stats(c *vm.Call) Object {
+	v := c.VM()
+	conn, ok := connValue(c.Receiver).(*sqlx.DB)
 
-		if err != nil {
-			return t.VM().InitErrorObject(errors.InternalError, sourceLine, err.Error())
-		}
+	if !ok {
+		return c.Error(errors.InternalError, "Pool stats are only available on the DB object returned by DB.open")
+	}
 
-		err = conn.Close()
+	s := conn.Stats()
+
+	return v.InitHashObject(map[string]Object{
+		"max_open_connections": v.InitIntegerObject(s.MaxOpenConnections),
+		"open_connections":     v.InitIntegerObject(s.OpenConnections),
+		"in_use":               v.InitIntegerObject(s.InUse),
+		"idle":                 v.InitIntegerObject(s.Idle),
+		"wait_count":           v.InitIntegerObject(int(s.WaitCount)),
+		"wait_duration":        c.Return(s.WaitDuration.Seconds()),
+		"max_idle_closed":      v.InitIntegerObject(int(s.MaxIdleClosed)),
+		"max_idle_time_closed": v.InitIntegerObject(int(s.MaxIdleTimeClosed)),
+		"max_lifetime_closed":  v.InitIntegerObject(int(s.MaxLifetimeClosed)),
+	})
+}
+
+func closeDB(c *vm.Call) Object {
+	if stmt, ok := connValue(c.Receiver).(*statement); ok {
+		err := stmt.stmt.Close()
 
 		if err != nil {
-			if err != nil {
-				return t.VM().InitErrorObject(errors.InternalError, sourceLine, "Error happens when closing DB connection: %s", err.Error())
-			}
+			return c.Error(errors.InternalError, err.Error())
 		}
 
 		return vm.TRUE
 	}
+
+	conn, err := getDBConn(c.Receiver)
+
+	if err != nil {
+		return c.Error(errors.InternalError, err.Error())
+	}
+
+	err = conn.Close()
+
+	if err != nil {
+		return c.Error(errors.InternalError, "Error happens when closing DB connection: %s", err.Error())
+	}
+
+	return vm.TRUE
 }
 
-// Class methods --------------------------------------------------------
-func builtinDBClassMethods() map[string]vm.MethodBuilder {
-	return map[string]vm.MethodBuilder{
-		"get_connection": getConnection,
+func run(c *vm.Call) Object {
+	conn, err := getExecer(c.Receiver)
+
+	if err != nil {
+		return c.Error(errors.InternalError, err.Error())
+	}
+
+	query, execArgs, err := bindArguments(conn, c.String(0), c.Rest(1))
+
+	if err != nil {
+		return c.Error(errorType(err), err.Error())
 	}
+
+	_, err = conn.Exec(query, execArgs...)
+
+	if err != nil {
+		return c.Error(errors.InternalError, err.Error())
+	}
+
+	return vm.TRUE
+}
+
+// The exec_statement method executes a statement through the adapter of the connection's driver. It takes
+// the query, the columns to return (nil, a String or an Array of Strings) and an Array of bind arguments,
+// and returns a Hash with `rows_affected`, `last_insert_id` and `returning` keys. See DB#exec in db.gb file.
+//
+// @return [Hash]
+//
+func execStatement(c *vm.Call) Object {
+	columns, err := returningColumns(c.Arg(1))
+
+	if err != nil {
+		return c.Error(errors.TypeError, err.Error())
+	}
+
+	result, err := execute(c.Receiver, c.Arg(0), columns, c.Array(2))
+
+	if err != nil {
+		return c.Error(errorType(err), err.Error())
+	}
+
+	hash, err := execResultToHash(c.Thread, result)
+
+	if err != nil {
+		return c.Error(errors.InternalError, err.Error())
+	}
+
+	return hash
 }
 
-func run(receiver Object, sourceLine int) Method {
-	return func(t *Thread, args []Object) Object {
-		v := t.VM()
-		if len(args) < 1 {
-			return v.InitErrorObject(errors.ArgumentError, sourceLine, "Expect at least 1 argument.")
+// execute runs a statement through the adapter of the connection's driver. Prepared statements take no query.
+func execute(receiver Object, queryObject Object, columns []string, args []Object) (*execResult, error) {
+	if stmt, ok := connValue(receiver).(*statement); ok {
+		if len(columns) > 0 {
+			return nil, fmt.Errorf("Prepared statements can't return columns")
 		}
 
-		conn, err := getDBConn(t, receiver)
+		r, err := stmt.exec(args)
 
 		if err != nil {
-			return v.InitErrorObject(errors.InternalError, sourceLine, err.Error())
+			return nil, err
 		}
 
-		queryString := args[0].(*vm.StringObject).Value().(string)
-		execArgs := []interface{}{}
+		return adapterFor(stmt.driverName).result(r)
+	}
+
+	queryString, ok := queryObject.(*StringObject)
 
-		for _, arg := range args[1:] {
-			execArgs = append(execArgs, arg.Value())
-		}
+	if !ok {
+		return nil, typeError{fmt.Errorf(errors.WrongArgumentTypeFormat, classes.StringClass, queryObject.Class().Name)}
+	}
+
+	conn, err := getExecer(receiver)
 
-		_, err = conn.Exec(queryString, execArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	query, execArgs, err := bindArguments(conn, queryString.Value().(string), args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	a := adapterFor(conn.DriverName())
+
+	if len(columns) == 0 {
+		r, err := conn.Exec(query, execArgs...)
 
 		if err != nil {
-			return v.InitErrorObject(errors.InternalError, sourceLine, err.Error())
+			return nil, err
 		}
 
-		return vm.TRUE
+		return a.result(r)
+	}
+
+	query, err = a.returning(query, columns)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.Queryx(query, execArgs...)
+
+	if err != nil {
+		return nil, err
 	}
+
+	return returningResult(rows)
 }
 
-// 		{
-// 			Name: "get_connection",
-// 			Fn: 		},
-// 	}
-// }
-
-// // Instance methods -----------------------------------------------------
-// func builtinDBInstanceMethods() []*BuiltinMethodObject {
-// 	return []*BuiltinMethodObject{
-// 		{
-// 			// The close method closes the connection of the DB instance
-// 			//
-// 			// ```ruby
-// 			// require "db"
-// 			//
-// 			// db = DB.open("postgres", "user=postgres sslmode=disable")
-// 			// db.ping  # => true
-// 			//
-// 			// db.close # Close the DB connection
-// 			// db.ping  # => false
-// 			// ```
-// 			//
-// 			Name: "close",
-// 		{
-// 			Name: "run",
-// 		},
-// 		{
-// 			// The exec method executes the Psql and automatically returns the data's primary key value
-// 			//
-// 			// ```ruby
-// 			// require "db"
-// 			//
-// 			// # Assume that there is a User table with name and age column
-// 			//
-// 			// # Create Action
-// 			// db = DB.open("postgres", "user=postgres dbname=goby_doc sslmode=disable")
-// 			// id = db.exec("INSERT INTO users (name, age) VALUES ('Stan', 23)")
-// 			// puts id # => 1
-// 			//
-// 			// # Update Action
-// 			//
-// 			// id2 = db.exec("INSERT INTO users (name, age) VALUES ('Maxwell', 21)")
-// 			// puts id2 # => 2
-// 			// id3 = db.exec("UPDATE users SET age=18 WHERE id = $1", id)
-// 			// puts id3 # => 2
-// 			//
-// 			// # Delete Action
-// 			// id4 = db.exec("DELETE FROM users WHERE id = $1", id3)
-// 			// puts id4 # => 2
-// 			//
-// 			// ```
-// 			//
-// 			// @return [Integer]
-// 			//
-// 			Name: "exec",
-func exec(receiver Object, sourceLine int) Method {
-	return func(t *Thread, args []Object) Object {
-		v := t.VM()
-		if len(args) < 1 {
-			return v.InitErrorObject(errors.ArgumentError, sourceLine, "Expect at least 1 argument.")
+// returningColumns accepts nil, a column name or an Array of column names.
+func returningColumns(arg Object) ([]string, error) {
+	switch arg := arg.(type) {
+	case *vm.NullObject:
+		return nil, nil
+	case *StringObject:
+		return []string{arg.Value().(string)}, nil
+	case *vm.ArrayObject:
+		columns := []string{}
+
+		for _, elem := range arg.Elements {
+			column, ok := elem.(*StringObject)
+
+			if !ok {
+				return nil, fmt.Errorf("Expect returning columns to be Strings. got: %s", elem.Class().Name)
+			}
+
+			columns = append(columns, column.Value().(string))
 		}
 
-		conn, err := getDBConn(t, receiver)
+		return columns, nil
+	default:
+		return nil, fmt.Errorf("Expect returning to be a String or an Array. got: %s", arg.Class().Name)
+	}
+}
 
-		if err != nil {
-			return v.InitErrorObject(errors.InternalError, sourceLine, err.Error())
+func execResultToHash(t *Thread, result *execResult) (Object, error) {
+	v := t.VM()
+	data := map[string]Object{
+		"rows_affected":  v.InitIntegerObject(int(result.rowsAffected)),
+		"last_insert_id": vm.NULL,
+		"returning":      vm.NULL,
+	}
+
+	if result.lastInsertID != nil {
+		data["last_insert_id"] = v.InitIntegerObject(int(*result.lastInsertID))
+	}
+
+	if result.returning != nil {
+		rows := []Object{}
+
+		for _, values := range result.returning {
+			row, err := result.decoder.decode(t, values)
+
+			if err != nil {
+				return nil, err
+			}
+
+			rows = append(rows, row)
 		}
 
-		queryString := args[0].(*vm.StringObject).Value().(string)
-		execArgs := []interface{}{}
+		data["returning"] = v.InitArrayObject(rows)
+	}
 
-		for _, arg := range args[1:] {
-			execArgs = append(execArgs, arg.Value())
+	return v.InitHashObject(data), nil
+}
+
+// The fetch_rows method runs the query and returns a Hash with the decoded `rows` and the `columns` metadata,
+// see DB#query in db.gb file.
+//
+// @return [Hash]
+//
+func fetchRows(c *vm.Call) Object {
+	rows, err := queryRows(c.Receiver, c.Args)
+
+	if err != nil {
+		return c.Error(errorType(err), err.Error())
+	}
+
+	defer rows.Close()
+
+	decoder, err := newRowDecoder(rows)
+
+	if err != nil {
+		return c.Error(errors.InternalError, err.Error())
+	}
+
+	results := []Object{}
+
+	for rows.Next() {
+		row, err := decoder.next(c.Thread, rows)
+
+		if err != nil {
+			return c.Error(errors.InternalError, err.Error())
 		}
 
-		// The reason I implement this way: https://github.com/lib/pq/issues/24
-		var id int
+		results = append(results, row)
+	}
 
-		err = conn.QueryRow(fmt.Sprintf("%s RETURNING id", queryString), execArgs...).Scan(&id)
+	if err = rows.Err(); err != nil {
+		return c.Error(errors.InternalError, err.Error())
+	}
+
+	return c.VM().InitHashObject(map[string]Object{
+		"rows":    c.VM().InitArrayObject(results),
+		"columns": decoder.metadata(c.Thread),
+	})
+}
+
+// The fetch_each method streams the decoded rows of the query to the given block one by one, without
+// loading the whole result set into memory. See DB#each_row in db.gb file.
+//
+// @return [Null]
+//
+func fetchEach(c *vm.Call) Object {
+	rows, err := queryRows(c.Receiver, c.Args)
+
+	if err != nil {
+		return c.Error(errorType(err), err.Error())
+	}
+
+	// Also closes the rows when the block raises an error
+	defer rows.Close()
+
+	decoder, err := newRowDecoder(rows)
+
+	if err != nil {
+		return c.Error(errors.InternalError, err.Error())
+	}
+
+	for rows.Next() {
+		row, err := decoder.next(c.Thread, rows)
 
 		if err != nil {
-			return v.InitErrorObject(errors.InternalError, sourceLine, err.Error())
+			return c.Error(errors.InternalError, err.Error())
+		}
+
+		c.Yield(row)
+	}
+
+	if err = rows.Err(); err != nil {
+		return c.Error(errors.InternalError, err.Error())
+	}
+
+	return vm.NULL
+}
+
+// The fetch_one method returns the first decoded row of the query, or nil if there's none.
+// See DB#query_one in db.gb file.
+//
+// @return [Hash]
+//
+func fetchOne(c *vm.Call) Object {
+	rows, err := queryRows(c.Receiver, c.Args)
+
+	if err != nil {
+		return c.Error(errorType(err), err.Error())
+	}
+
+	defer rows.Close()
+
+	decoder, err := newRowDecoder(rows)
+
+	if err != nil {
+		return c.Error(errors.InternalError, err.Error())
+	}
+
+	if !rows.Next() {
+		if err = rows.Err(); err != nil {
+			return c.Error(errors.InternalError, err.Error())
 		}
 
-		return v.InitIntegerObject(id)
+		return vm.NULL
+	}
+
+	row, err := decoder.next(c.Thread, rows)
+
+	if err != nil {
+		return c.Error(errors.InternalError, err.Error())
+	}
+
+	return row
+}
+
+// queryRows runs a query with the arguments of DB#query and the like: the query String and its bind arguments,
+// or only the bind arguments for prepared statements.
+func queryRows(receiver Object, args []Object) (*sqlx.Rows, error) {
+	if stmt, ok := connValue(receiver).(*statement); ok {
+		return stmt.query(args)
+	}
+
+	if len(args) < 1 {
+		return nil, fmt.Errorf("Expect at least 1 argument.")
+	}
+
+	queryString, ok := args[0].(*StringObject)
+
+	if !ok {
+		return nil, typeError{fmt.Errorf(errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)}
+	}
+
+	conn, err := getExecer(receiver)
+
+	if err != nil {
+		return nil, err
+	}
+
+	query, execArgs, err := bindArguments(conn, queryString.Value().(string), args[1:])
+
+	if err != nil {
+		return nil, err
+	}
+
+	return conn.Queryx(query, execArgs...)
+}
+
+func getDBConn(receiver Object) (*sqlx.DB, error) {
+	value := connValue(receiver)
+
+	if value == nil {
+		return nil, fmt.Errorf("DB connection is nil")
+	}
+
+	conn, ok := value.(*sqlx.DB)
+
+	if !ok {
+		return nil, fmt.Errorf("Connection is not *sql.DB")
+	}
+
+	return conn, nil
+}
+
+// sqliteBusyTimeout is how long a sqlite connection waits for a lock held by another connection.
+const sqliteBusyTimeout = time.Minute
+
+// initSQLiteConn sets up each new sqlite connection. The driver only tracks the connection's
+// query_only pragma (which read-only transactions rely on) when it has an init function.
+func initSQLiteConn(conn *sqlite3.Conn) error {
+	return conn.BusyTimeout(sqliteBusyTimeout)
+}
+
+// memoryDBCount numbers in-memory sqlite databases, so each DB.open("sqlite", ":memory:") gets its own database.
+var memoryDBCount int64
+
+// sqliteDataSource shares an in-memory database between the connections of the pool, which would
+// otherwise get a new empty database each.
+func sqliteDataSource(source string) string {
+	if source != ":memory:" {
+		return source
+	}
+
+	return fmt.Sprintf("file:/goby-memory-%d?vfs=memdb", atomic.AddInt64(&memoryDBCount, 1))
+}
+
+// execer is implemented by both *sqlx.DB and *sqlx.Tx, so statements can be run inside or outside transactions.
+type execer interface {
+	DriverName() string
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Queryx(query string, args ...interface{}) (*sqlx.Rows, error)
+	BindNamed(query string, arg interface{}) (string, []interface{}, error)
+	PrepareNamed(query string) (*sqlx.NamedStmt, error)
+}
+
+// transaction is the connection object of a DB::Transaction. Nested transactions share the parent's
+// sqlx.Tx and are backed by savepoints.
+type transaction struct {
+	tx        *sqlx.Tx
+	savepoint string
+	// savepoints counts the savepoints created in the outermost transaction, for naming them uniquely
+	savepoints *int
+	done       bool
+}
+
+var isolationLevels = map[string]sql.IsolationLevel{
+	"default":          sql.LevelDefault,
+	"read_uncommitted": sql.LevelReadUncommitted,
+	"read_committed":   sql.LevelReadCommitted,
+	"write_committed":  sql.LevelWriteCommitted,
+	"repeatable_read":  sql.LevelRepeatableRead,
+	"snapshot":         sql.LevelSnapshot,
+	"serializable":     sql.LevelSerializable,
+	"linearizable":     sql.LevelLinearizable,
+}
+
+// The begin_transaction method starts a transaction and yields its connection object to the given block.
+// The transaction is committed when the block returns, and rolled back if the block raises an error.
+// When it's called on a transaction, a savepoint is created instead.
+//
+// It accepts an options Hash with `isolation` and `read_only` keys, see DB#transaction in db.gb file.
+//
+// @return [Object] the block's result
+//
+func beginTransaction(c *vm.Call) Object {
+	if !c.BlockGiven() {
+		return c.Error(errors.ArgumentError, "Can't start a transaction without a block")
+	}
+
+	opts, err := transactionOptions(c.Hash(0))
+
+	if err != nil {
+		return c.Error(errors.ArgumentError, err.Error())
 	}
+
+	txn, err := startTransaction(c.Receiver, opts)
+
+	if err != nil {
+		return c.Error(errors.InternalError, err.Error())
+	}
+
+	result := yieldTransaction(c.Thread, txn)
+
+	if _, ok := result.(*vm.Error); ok {
+		txn.rollback()
+		return result
+	}
+
+	err = txn.commit()
+
+	if err != nil {
+		return c.Error(errors.InternalError, err.Error())
+	}
+
+	return result
+}
+
+// The rollback method rolls back the transaction, or the savepoint of a nested transaction.
+// Statements executed with the transaction afterwards raise an error.
+//
+// ```ruby
+// db.transaction do |tx|
+//   tx.run("DELETE FROM users")
+//   tx.rollback
+// end
+// ```
+//
+// @return [Boolean]
+//
+func rollback(c *vm.Call) Object {
+	txn, ok := connValue(c.Receiver).(*transaction)
+
+	if !ok {
+		return c.Error(errors.InternalError, "Can't roll back outside of a transaction")
+	}
+
+	if txn.done {
+		return c.Error(errors.InternalError, "Transaction has already been committed or rolled back")
+	}
+
+	err := txn.rollback()
+
+	if err != nil {
+		return c.Error(errors.InternalError, err.Error())
+	}
+
+	return vm.TRUE
 }
 
-// 		},
-// 		{
-// 			// The query method queries the result of the data set
-// 			//
-// 			// ```ruby
-// 			// require "db"
-// 			//
-// 			// # Assume that there is a User table with name and age column
-// 			//
-// 			// db = DB.open("postgres", "user=postgres dbname=goby_doc sslmode=disable")
-// 			// id = db.exec("INSERT INTO users (name, age) VALUES ('Stan', 23)")
-// 			// puts id # => 1
-// 			//
-// 			// id2 = db.exec("INSERT INTO users (name, age) VALUES ('Maxwell', 21)")
-// 			// puts id # => 2
-// 			//
-// 			// results = db.query("SELECT * FROM users WHERE id = $1", id)
-// 			// results.size          # => 1
-// 			// results.first[:name]  # => 'Stan'
-// 			// results.first[:age]   # => 23
-// 			//
-// 			// age = 21
-// 			// results2 = db.query("SELECT * FROM users WHERE age = $1", age)
-// 			// results2.size         # => 1
-// 			// results2.first[:name] # => 'Maxwell'
-// 			// results2.first[:age]  # => 21
-// 			//
-// 			// ```
-// 			//
-// 			// @return [Array]
-// 			//
-// 			Name: "query",
-func query(receiver Object, sourceLine int) Method {
-	return func(t *Thread, args []Object) Object {
-		if len(args) < 1 {
-			return t.VM().InitErrorObject(errors.ArgumentError, sourceLine, "Expect at least 1 argument.")
+func transactionOptions(options map[string]Object) (*sql.TxOptions, error) {
+	opts := &sql.TxOptions{}
+
+	for k, value := range options {
+		if value == vm.NULL {
+			continue
 		}
 
-		conn, err := getDBConn(t, receiver)
+		switch k {
+		case "isolation":
+			name, ok := value.(*StringObject)
+
+			if !ok {
+				return nil, fmt.Errorf("Expect isolation to be a String. got: %s", value.Class().Name)
+			}
+
+			level, ok := isolationLevels[name.Value().(string)]
+
+			if !ok {
+				return nil, fmt.Errorf("Unknown isolation level: %s", name.Value())
+			}
+
+			opts.Isolation = level
+		case "read_only":
+			opts.ReadOnly = value == vm.TRUE
+		default:
+			return nil, fmt.Errorf("Unknown transaction option: %s", k)
+		}
+	}
+
+	return opts, nil
+}
+
+func startTransaction(receiver Object, opts *sql.TxOptions) (*transaction, error) {
+	switch conn := connValue(receiver).(type) {
+	case *sqlx.DB:
+		tx, err := conn.BeginTxx(context.Background(), opts)
 
 		if err != nil {
-			return t.VM().InitErrorObject(errors.InternalError, sourceLine, err.Error())
+			return nil, err
 		}
 
-		queryString := args[0].(*StringObject).Value().(string)
-		execArgs := []interface{}{}
+		return &transaction{tx: tx, savepoints: new(int)}, nil
+	case *transaction:
+		if conn.done {
+			return nil, fmt.Errorf("Transaction has already been committed or rolled back")
+		}
 
-		for _, arg := range args[1:] {
-			execArgs = append(execArgs, arg.Value())
+		if opts.Isolation != sql.LevelDefault || opts.ReadOnly {
+			return nil, fmt.Errorf("Nested transactions can't change isolation level or read-only mode")
 		}
 
-		rows, err := conn.Queryx(queryString, execArgs...)
+		*conn.savepoints++
+		savepoint := fmt.Sprintf("goby_savepoint_%d", *conn.savepoints)
+
+		_, err := conn.tx.Exec("SAVEPOINT " + savepoint)
 
 		if err != nil {
-			return t.VM().InitErrorObject(errors.InternalError, sourceLine, err.Error())
+			return nil, err
 		}
 
-		results := []Object{}
+		return &transaction{tx: conn.tx, savepoint: savepoint, savepoints: conn.savepoints}, nil
+	case *statement:
+		return nil, fmt.Errorf("Can't start a transaction on a prepared statement")
+	default:
+		return nil, fmt.Errorf("DB connection is nil")
+	}
+}
 
-		for rows.Next() {
-			row := make(map[string]interface{})
+// yieldTransaction yields the transaction to the block and rolls it back if the block raises an error.
+func yieldTransaction(t *Thread, txn *transaction) Object {
+	defer func() {
+		if r := recover(); r != nil {
+			txn.rollback()
+			panic(r)
+		}
+	}()
 
-			err = rows.MapScan(row)
+	return t.Yield(t.VM().InitObjectFromGoType(txn))
+}
 
-			if err != nil {
-				return t.VM().InitErrorObject(errors.InternalError, sourceLine, err.Error())
-			}
+func (txn *transaction) commit() error {
+	if txn.done {
+		return nil
+	}
 
-			data := map[string]Object{}
+	txn.done = true
 
-			for k, v := range row {
-				data[k] = t.VM().InitObjectFromGoType(v)
-			}
+	if len(txn.savepoint) > 0 {
+		_, err := txn.tx.Exec("RELEASE SAVEPOINT " + txn.savepoint)
+		return err
+	}
 
-			result := t.VM().InitHashObject(data)
-			results = append(results, result)
-		}
+	return txn.tx.Commit()
+}
+
+func (txn *transaction) rollback() error {
+	if txn.done {
+		return nil
+	}
+
+	txn.done = true
 
-		return t.VM().InitArrayObject(results)
+	if len(txn.savepoint) > 0 {
+		_, err := txn.tx.Exec("ROLLBACK TO SAVEPOINT " + txn.savepoint)
+		return err
 	}
+
+	return txn.tx.Rollback()
 }
 
-func getDBConn(t *vm.Thread, receiver Object) (*sqlx.DB, error) {
+func connValue(receiver Object) interface{} {
 	connection, _ := receiver.InstanceVariableGet("@connection")
-	connObj, _ := connection.InstanceVariableGet("@conn_obj")
 
-	if connObj == vm.NULL {
-		return nil, fmt.Errorf("DB connection is nil")
+	if connection == nil || connection == vm.NULL {
+		return nil
 	}
 
-	conn, ok := connObj.(*GoObject).Value().(*sqlx.DB)
+	connObj, _ := connection.InstanceVariableGet("@conn_obj")
+	goObj, ok := connObj.(*GoObject)
 
 	if !ok {
-		return nil, fmt.Errorf("Connection is not *sql.DB")
+		return nil
 	}
 
-	return conn, nil
+	return goObj.Value()
+}
+
+func getExecer(receiver Object) (execer, error) {
+	switch conn := connValue(receiver).(type) {
+	case *sqlx.DB:
+		return conn, nil
+	case *transaction:
+		if conn.done {
+			return nil, fmt.Errorf("Transaction has already been committed or rolled back")
+		}
+
+		return conn.tx, nil
+	case *statement:
+		return nil, fmt.Errorf("Prepared statements can only run their own query")
+	default:
+		return nil, fmt.Errorf("DB connection is nil")
+	}
 }
@@ -1,32 +1,41 @@
 package db
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/goby-lang/goby/vm"
 	"github.com/jmoiron/sqlx"
 )
 
-func setupDB(t *testing.T) *sqlx.DB {
-	db, _ := sqlx.Open("postgres", "user=postgres dbname=goby_test sslmode=disable")
-	_, err := db.Exec(`
+// setupDB creates a sqlite database with a users table in a temporary directory and returns its path,
+// so each test gets a fresh database without any server to connect to.
+func setupDB(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "goby_test.db")
+	db, err := sqlx.Open("sqlite", path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	_, err = db.Exec(`
 	CREATE TABLE IF NOT EXISTS users (
-		id   serial PRIMARY KEY,
+		id   integer PRIMARY KEY,
 		name varchar(40),
 		age integer
   	)
 `)
 
 	if err != nil {
-		t.Fatalf(err.Error())
+		t.Fatal(err)
 	}
 
-	return db
-}
-
-func cleanTable() {
-	db, _ := sqlx.Open("postgres", "user=postgres dbname=goby_test sslmode=disable")
-	db.Exec(`DELETE (SELECT * FROM users)`)
+	return path
 }
 
 func TestDBPingMethod(t *testing.T) {
@@ -37,17 +46,24 @@ func TestDBPingMethod(t *testing.T) {
 		{`
 			require "db"
 
-			db = DB.open("postgres", "user=postgres sslmode=disable")
+			db = DB.open("sqlite", ":memory:")
 			db.ping
 			`,
 			true},
 		{`
 			require "db"
 
-			db = DB.open("postgres", "user=test sslmode=disable")
-			db.ping
+			db = DB.open("sqlite", "/no/such/directory/goby_test.db")
+			db.ping.class.name
 			`,
-			false},
+			"ConnectionError"},
+		{`
+			require "db"
+
+			db = DB.open("sqlite", "/no/such/directory/goby_test.db")
+			db.ping.message
+			`,
+			"sqlite3: unable to open database file"},
 	}
 
 	for i, tt := range tests {
@@ -64,13 +80,13 @@ func TestDBCloseMethod(t *testing.T) {
 		{`
 			require "db"
 
-			db = DB.open("postgres", "user=postgres sslmode=disable")
+			db = DB.open("sqlite", ":memory:")
 			first_ping = db.ping # This should be successful
 
 			db.close
 			second_ping = db.ping # This should be failed
 
-			first_ping && !second_ping
+			first_ping == true && second_ping.message == "sql: database is closed"
 			`,
 			true},
 	}
@@ -81,30 +97,119 @@ func TestDBCloseMethod(t *testing.T) {
 	}
 }
 
-func TestDBRunMethod(t *testing.T) {
+func TestDBPoolOptionsAndStats(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+			db = DB.open("sqlite", ":memory:", max_open: 3, max_idle: 1, conn_max_lifetime: 60)
+			db.stats[:max_open_connections]
+			`,
+			3},
+		{`
+			db = DB.open("sqlite", ":memory:", conn_max_lifetime: 0.5)
+			db.run("CREATE TABLE items (id integer PRIMARY KEY)")
+			s = db.stats
+			[s[:open_connections], s[:in_use], s[:idle], s[:wait_count], s[:wait_duration]]
+			`,
+			[]interface{}{1, 0, 1, 0, 0.0}},
+		{`
+			db = DB.open("sqlite", ":memory:")
+			db.stats[:max_open_connections]
+			`,
+			0},
+		{`
+			db = DB.open("sqlite", ":memory:", max_open: 1)
+			db.transaction do |tx|
+			  tx.run("CREATE TABLE items (id integer PRIMARY KEY)")
+			end
+			db.stats[:max_lifetime_closed] + db.stats[:max_idle_closed]
+			`,
+			0},
+	}
+
+	for i, tt := range tests {
+		evaluated := vm.ExecAndReturn(t, `require "db"`+tt.input)
+		vm.VerifyExpected(t, i, evaluated, tt.expected)
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{`DB.open(1, ":memory:")`, "TypeError: Expect argument to be String. got: Integer"},
+		{`DB.get_connection("sqlite")`, "ArgumentError: Expect 2..3 arguments. got: 1"},
+		{`DB.open("sqlite", ":memory:", max_open: "10")`, "ArgumentError: Expect max_open to be an Integer. got: String"},
+		{`DB.open("sqlite", ":memory:", conn_max_lifetime: "1m")`, "ArgumentError: Expect conn_max_lifetime to be an Integer or a Float. got: String"},
+		{`DB.open("sqlite", ":memory:", max_open: 2 ** 70)`, "ArgumentError: Expect max_open to be at most 2147483647. got: 1180591620717411303424"},
+		{`DB.open("sqlite", ":memory:", max_idle: 2 ** 40)`, "ArgumentError: Expect max_idle to be at most 2147483647. got: 1099511627776"},
+		{`DB.open("sqlite", ":memory:", conn_max_lifetime: 2 ** 70)`, "ArgumentError: Expect conn_max_lifetime to be between 0 and 9223372036 seconds. got: 1180591620717411303424"},
+		{`DB.open("sqlite", ":memory:", conn_max_lifetime: -1)`, "ArgumentError: Expect conn_max_lifetime to be between 0 and 9223372036 seconds. got: -1"},
+		{`
+			db = DB.open("sqlite", ":memory:")
+			db.transaction do |tx|
+			  tx.stats
+			end
+			`, "InternalError: Pool stats are only available on the DB object returned by DB.open"},
+	}
+
+	for i, tt := range errorTests {
+		evaluated := vm.ExecAndReturn(t, "require \"db\"\n"+tt.input)
+		err, ok := evaluated.(*vm.Error)
+
+		if !ok || !strings.HasPrefix(err.Message(), tt.expected) {
+			t.Fatalf("At test case %d: Expect error starting with %q. got: %v", i, tt.expected, evaluated)
+		}
+	}
+}
+
+func TestDBMemoryDatabases(t *testing.T) {
 	input := `
 	require "db"
 
-	db = DB.open("postgres", "user=postgres dbname=goby_test sslmode=disable")
+	db = DB.open("sqlite", ":memory:")
+	db.run("CREATE TABLE items (id integer PRIMARY KEY, title varchar(40))")
+
+	# Every statement sees the same database, whichever pooled connection runs it
+	10.times do
+	  db.exec("INSERT INTO items (title) VALUES ('Stan')")
+	end
+
+	other = DB.open("sqlite", ":memory:")
+	tables = other.query("SELECT count(*) AS count FROM sqlite_master").first[:count]
+
+	db.query("SELECT count(*) AS count FROM items").first[:count].to_s + " " + tables.to_s
+	`
+
+	evaluated := vm.ExecAndReturn(t, input)
+	vm.VerifyExpected(t, 0, evaluated, "10 0")
+}
+
+func TestDBRunMethod(t *testing.T) {
+	input := fmt.Sprintf(`
+	require "db"
+
+	db = DB.open("sqlite", "%s")
 	db.run("create table if not exists test_items (
-	  id   serial primary key,
+	  id   integer primary key,
 	  title varchar(40)
 	)")
 
-	id = db.exec("INSERT INTO test_items (title) VALUES ('Stan')")
-	results = db.query("SELECT EXISTS(SELECT * FROM test_items WHERE id = $1)", id)
+	id = db.exec("INSERT INTO test_items (title) VALUES ('Stan')").last_insert_id
+	results = db.query("SELECT EXISTS(SELECT * FROM test_items WHERE id = $1) AS found", id)
 
 	db.run("drop table test_items")
 
-	results.first[:exists]
-	`
+	results.first[:found]
+	`, setupDB(t))
 
 	evaluated := vm.ExecAndReturn(t, input)
-	vm.VerifyExpected(t, 0, evaluated, true)
+	vm.VerifyExpected(t, 0, evaluated, 1)
 }
 
 func TestDBExecMethod(t *testing.T) {
-	setupDB(t)
+	path := setupDB(t)
 
 	tests := []struct {
 		input    string
@@ -114,8 +219,8 @@ func TestDBExecMethod(t *testing.T) {
 		{`
 			require "db"
 
-			db = DB.open("postgres", "user=postgres dbname=goby_test sslmode=disable")
-			id = db.exec("INSERT INTO users (name, age) VALUES ('Stan', 23)")
+			db = DB.open("sqlite", "%s")
+			id = db.exec("INSERT INTO users (name, age) VALUES ('Stan', 23)").last_insert_id
 			results = db.query("SELECT * FROM users WHERE id = $1", id)
 			results.first[:name]
 			`,
@@ -124,29 +229,901 @@ func TestDBExecMethod(t *testing.T) {
 		{`
 			require "db"
 
-			db = DB.open("postgres", "user=postgres dbname=goby_test sslmode=disable")
-			id = db.exec("INSERT INTO users (name, age) VALUES ('Stan', 23)")
+			db = DB.open("sqlite", "%s")
+			id = db.exec("INSERT INTO users (name, age) VALUES ('Stan', 23)").last_insert_id
 			db.exec("DELETE FROM users WHERE id = $1", id)
-			results = db.query("SELECT EXISTS(SELECT * FROM users WHERE id = $1)", id)
-			results.first[:exists]
+			results = db.query("SELECT EXISTS(SELECT * FROM users WHERE id = $1) AS found", id)
+			results.first[:found]
 			`,
-			false},
+			0},
 		// Insert and update and query
 		{`
 			require "db"
 
-			db = DB.open("postgres", "user=postgres dbname=goby_test sslmode=disable")
-			id = db.exec("INSERT INTO users (name, age) VALUES ('John', 20)")
-			id2 = db.exec("UPDATE users SET age=10 WHERE id = $1", id)
-			# See if update returns usable id, too
-			results = db.query("SELECT * FROM users WHERE id = $1", id2)
-			results.first[:age]
+			db = DB.open("sqlite", "%s")
+			id = db.exec("INSERT INTO users (name, age) VALUES ('John', 20)").last_insert_id
+			affected = db.exec("UPDATE users SET age=10 WHERE id = $1", id).rows_affected
+			results = db.query("SELECT * FROM users WHERE id = $1", id)
+			results.first[:age] + affected
+			`,
+			11},
+		// Tables without an id column and DDL statements
+		{`
+			require "db"
+
+			db = DB.open("sqlite", "%s")
+			created = db.exec("CREATE TABLE tags (name varchar(40))").rows_affected
+			inserted = db.exec("INSERT INTO tags (name) VALUES ($1), ($2)", "go", "ruby").rows_affected
+			created.to_s + " " + inserted.to_s
+			`,
+			"0 2"},
+		// Returning columns of the affected rows
+		{`
+			require "db"
+
+			db = DB.open("sqlite", "%s")
+			db.exec("INSERT INTO users (name, age) VALUES ('Maxwell', 21)")
+			result = db.exec("UPDATE users SET age = age + 1 WHERE name = $1", returning: ["name", "age"], "Maxwell")
+			row = result.returning.first
+			row[:name] + " " + row[:age].to_s + " " + result.rows_affected.to_s
+			`,
+			"Maxwell 22 1"},
+		{`
+			require "db"
+
+			db = DB.open("sqlite", "%s")
+			db.exec("DELETE FROM users WHERE name = 'Nobody'", returning: "name").returning
 			`,
-			10},
+			[]interface{}{}},
+		// Returned columns are quoted identifiers
+		{`
+			require "db"
+
+			db = DB.open("sqlite", "%s")
+			db.exec('CREATE TABLE odd ("first name" varchar(40), "say ""hi""" varchar(40))')
+			row = db.exec("INSERT INTO odd VALUES ('Stan', 'hi')", returning: ["first name", 'say "hi"']).returning.first
+			row["first name"] + " " + row['say "hi"']
+			`,
+			"Stan hi"},
 	}
 
 	for i, tt := range tests {
-		evaluated := vm.ExecAndReturn(t, tt.input)
+		evaluated := vm.ExecAndReturn(t, fmt.Sprintf(tt.input, path))
+		vm.VerifyExpected(t, i, evaluated, tt.expected)
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{`
+			require "db"
+
+			db = DB.open("sqlite", "%s")
+			db.exec("DELETE FROM users", returning: 1)
+			`,
+			"TypeError: Expect returning to be a String or an Array. got: Integer"},
+		{`
+			require "db"
+
+			db = DB.open("sqlite", "%s")
+			db.exec("INSERT INTO no_such_table (name) VALUES ('Stan')")
+			`,
+			"InternalError"},
+		// A hostile column name is a single quoted identifier
+		{`
+			require "db"
+
+			db = DB.open("sqlite", "%s")
+			db.exec("UPDATE users SET age = 2", returning: 'age" FROM users; DROP TABLE users; --')
+			`,
+			`InternalError: sqlite3: SQL logic error: no such column: "age" FROM users; DROP TABLE users; --"`},
+	}
+
+	for i, tt := range errorTests {
+		evaluated := vm.ExecAndReturn(t, fmt.Sprintf(tt.input, path))
+		err, ok := evaluated.(*vm.Error)
+
+		if !ok || !strings.HasPrefix(err.Message(), tt.expected) {
+			t.Fatalf("At test case %d: Expect error starting with %q. got: %v", i, tt.expected, evaluated)
+		}
+	}
+}
+
+func TestDBTransactionMethod(t *testing.T) {
+	path := setupDB(t)
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		// Commit on success and return the block's result
+		{`
+			require "db"
+
+			db = DB.open("sqlite", "%s")
+			id = db.transaction do |tx|
+			  tx.exec("INSERT INTO users (name, age) VALUES ('Stan', 23)").last_insert_id
+			end
+			db.query("SELECT * FROM users WHERE id = $1", id).first[:name]
+			`,
+			"Stan"},
+		// Explicit rollback
+		{`
+			require "db"
+
+			db = DB.open("sqlite", "%s")
+			id = db.transaction do |tx|
+			  id = tx.exec("INSERT INTO users (name, age) VALUES ('Stan', 23)").last_insert_id
+			  tx.rollback
+			  id
+			end
+			db.query("SELECT EXISTS(SELECT * FROM users WHERE id = $1) AS found", id).first[:found]
+			`,
+			0},
+		// Rolling back a savepoint keeps the outer transaction
+		{`
+			require "db"
+
+			db = DB.open("sqlite", "%s")
+			id = db.transaction do |tx|
+			  id = tx.exec("INSERT INTO users (name, age) VALUES ('Stan', 23)").last_insert_id
+			  tx.transaction do |nested|
+			    nested.run("UPDATE users SET age = 10 WHERE id = $1", id)
+			    nested.rollback
+			  end
+			  tx.transaction do |nested|
+			    nested.run("UPDATE users SET name = 'Maxwell' WHERE id = $1", id)
+			  end
+			  id
+			end
+			user = db.query("SELECT * FROM users WHERE id = $1", id).first
+			user[:name] + " " + user[:age].to_s
+			`,
+			"Maxwell 23"},
+		// Isolation level and read-only options
+		{`
+			require "db"
+
+			db = DB.open("sqlite", "%s")
+			db.transaction(isolation: "serializable", read_only: true) do |tx|
+			  tx.query("PRAGMA query_only").first[:query_only]
+			end
+			`,
+			1},
+	}
+
+	for i, tt := range tests {
+		evaluated := vm.ExecAndReturn(t, fmt.Sprintf(tt.input, path))
+		vm.VerifyExpected(t, i, evaluated, tt.expected)
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		// Errors roll the transaction back
+		{`
+			require "db"
+
+			db = DB.open("sqlite", "%s")
+			db.transaction do |tx|
+			  tx.exec("INSERT INTO users (name, age) VALUES ('Rollback', 23)")
+			  tx.run("SELECT * FROM no_such_table")
+			end
+			`,
+			"InternalError"},
+		{`
+			require "db"
+
+			db = DB.open("sqlite", "%s")
+			db.transaction(read_only: true) do |tx|
+			  tx.exec("INSERT INTO users (name, age) VALUES ('Rollback', 23)")
+			end
+			`,
+			"InternalError"},
+		{`
+			require "db"
+
+			db = DB.open("sqlite", "%s")
+			db.transaction(isolation: "chaotic") do |tx|
+			end
+			`,
+			"ArgumentError: Unknown isolation level: chaotic"},
+		{`
+			require "db"
+
+			db = DB.open("sqlite", "%s")
+			db.transaction do |tx|
+			  tx.transaction(isolation: "serializable") do |nested|
+			  end
+			end
+			`,
+			"InternalError: Nested transactions can't change isolation level or read-only mode"},
+		{`
+			require "db"
+
+			db = DB.open("sqlite", "%s")
+			db.rollback
+			`,
+			"InternalError: Can't roll back outside of a transaction"},
+	}
+
+	for i, tt := range errorTests {
+		evaluated := vm.ExecAndReturn(t, fmt.Sprintf(tt.input, path))
+		err, ok := evaluated.(*vm.Error)
+
+		if !ok || !strings.HasPrefix(err.Message(), tt.expected) {
+			t.Fatalf("At test case %d: Expect error starting with %q. got: %v", i, tt.expected, evaluated)
+		}
+	}
+
+	evaluated := vm.ExecAndReturn(t, fmt.Sprintf(`
+	require "db"
+
+	db = DB.open("sqlite", "%s")
+	db.query("SELECT EXISTS(SELECT * FROM users WHERE name = 'Rollback') AS found").first[:found]
+	`, path))
+	vm.VerifyExpected(t, 0, evaluated, 0)
+}
+
+func TestDBQueryMethods(t *testing.T) {
+	path := setupDB(t)
+
+	vm.ExecAndReturn(t, fmt.Sprintf(`
+	require "db"
+
+	db = DB.open("sqlite", "%s")
+	db.run("CREATE TABLE items (id integer PRIMARY KEY, title text NOT NULL, price NUMERIC(10, 2), meta JSON, active BOOLEAN, created_at TIMESTAMP, note varchar(10))")
+	db.exec("INSERT INTO items (title, price, meta, active, created_at) VALUES ($1, $2, $3, $4, $5)", "Book", "19.99", "{\"tags\": [\"go\", 1]}", true, "2018-01-02 03:04:05")
+	db.exec("INSERT INTO items (title, price, meta, active) VALUES ($1, $2, $3, $4)", "Pen", "0.5", "[1, 2]", false)
+	`, path))
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		// Typed decoding
+		{`
+			db.query("SELECT * FROM items").first[:price].class.name
+			`,
+			"Decimal"},
+		{`
+			(db.query("SELECT * FROM items").first[:price] + "0.01".to_d).to_s
+			`,
+			"20"},
+		{`
+			db.query_one("SELECT meta FROM items WHERE title = 'Book'")[:meta][:tags]
+			`,
+			[]interface{}{"go", 1}},
+		{`
+			db.query_one("SELECT meta FROM items WHERE title = 'Pen'")[:meta]
+			`,
+			[]interface{}{1, 2}},
+		{`
+			db.query_one("SELECT active FROM items WHERE title = 'Pen'")[:active]
+			`,
+			false},
+		{`
+			db.query_one("SELECT created_at FROM items WHERE title = 'Book'")[:created_at].class.name
+			`,
+			"Time"},
+		{`
+			db.query_one("SELECT created_at FROM items WHERE title = 'Book'")[:created_at].to_s
+			`,
+			"2018-01-02 03:04:05 UTC"},
+		{`
+			db.transaction do |tx|
+			  tx.exec("UPDATE items SET created_at = $1 WHERE title = 'Pen'", Time.at(1500000000, "UTC"))
+			  result = tx.query_one("SELECT created_at FROM items WHERE title = 'Pen'")[:created_at].to_i
+			  tx.rollback
+			  result
+			end
+			`,
+			1500000000},
+		{`
+			db.query_one("SELECT note, created_at FROM items WHERE title = 'Pen'").values
+			`,
+			[]interface{}{nil, nil}},
+		// query_one without results
+		{`
+			db.query_one("SELECT * FROM items WHERE id = $1", 100)
+			`,
+			nil},
+		// Rows delegates to its Array
+		{`
+			rows = db.query("SELECT * FROM items")
+			rows.length.to_s + " " + rows.last[:title] + " " + rows.map do |row| row[:id] end.to_s
+			`,
+			"2 Pen [1, 2]"},
+		{`
+			db.query("SELECT * FROM items").reduce(0) do |sum, row|
+			  sum + row[:id]
+			end
+			`,
+			3},
+		{`
+			titles = []
+			db.query("SELECT * FROM items").each_with_index do |row, i|
+			  titles.push(i.to_s + row[:title])
+			end
+			titles
+			`,
+			[]interface{}{"0Book", "1Pen"}},
+		// Column metadata
+		{`
+			db.query("SELECT id, price FROM items").columns.map do |column|
+			  column[:name] + ":" + column[:type]
+			end
+			`,
+			[]interface{}{"id:INTEGER", "price:NUMERIC"}},
+		// Streaming
+		{`
+			titles = []
+			result = db.each_row("SELECT title FROM items ORDER BY id DESC") do |row|
+			  titles.push(row[:title])
+			end
+			titles.push(result)
+			`,
+			[]interface{}{"Pen", "Book", nil}},
+		// Returning columns are decoded, too
+		{`
+			db.transaction do |tx|
+			  result = tx.exec("UPDATE items SET price = 2.25 WHERE title = 'Pen'", returning: "price").returning.first[:price].to_s
+			  tx.rollback
+			  result
+			end
+			`,
+			"2.25"},
+	}
+
+	for i, tt := range tests {
+		input := fmt.Sprintf(`
+		require "db"
+
+		db = DB.open("sqlite", "%s")
+		`, path) + tt.input
+		evaluated := vm.ExecAndReturn(t, input)
+		vm.VerifyExpected(t, i, evaluated, tt.expected)
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{`
+			db.each_row("SELECT * FROM items")
+			`,
+			"InternalError: Can't yield without a block"},
+		{`
+			db.query_one("SELECT * FROM no_such_table")
+			`,
+			"InternalError"},
+		{`
+			db.query(1)
+			`,
+			"TypeError: Expect argument to be String. got: Integer"},
+	}
+
+	for i, tt := range errorTests {
+		input := fmt.Sprintf(`
+		require "db"
+
+		db = DB.open("sqlite", "%s")
+		`, path) + tt.input
+		evaluated := vm.ExecAndReturn(t, input)
+		err, ok := evaluated.(*vm.Error)
+
+		if !ok || !strings.HasPrefix(err.Message(), tt.expected) {
+			t.Fatalf("At test case %d: Expect error starting with %q. got: %v", i, tt.expected, evaluated)
+		}
+	}
+}
+
+func TestDBNamedAndPreparedStatements(t *testing.T) {
+	path := setupDB(t)
+
+	vm.ExecAndReturn(t, fmt.Sprintf(`
+	require "db"
+
+	db = DB.open("sqlite", "%s")
+	db.exec("INSERT INTO users (name, age) VALUES (:name, :age)", name: "Stan", age: 23)
+	db.exec("INSERT INTO users (name, age) VALUES (:name, :age)", age: 21, name: "Maxwell")
+	`, path))
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		// Named binds
+		{`
+			db.query("SELECT name FROM users WHERE age > :age AND name != :name", name: "Nobody", age: 22).first[:name]
+			`,
+			"Stan"},
+		{`
+			db.query_one("SELECT count(*) AS count FROM users WHERE name = :name OR name = :name", name: "Maxwell")[:count]
+			`,
+			1},
+		{`
+			names = []
+			db.each_row("SELECT name FROM users WHERE age < :age ORDER BY age", age: 100) do |row|
+			  names.push(row[:name])
+			end
+			names
+			`,
+			[]interface{}{"Maxwell", "Stan"}},
+		{`
+			db.exec("UPDATE users SET age = age WHERE name = :name", name: "Stan", returning: "name").returning.first[:name]
+			`,
+			"Stan"},
+		{`
+			options = { name: "Stan", returning: "name" }
+			db.exec("UPDATE users SET age = age WHERE name = :name", options)
+			options.keys.sort
+			`,
+			[]interface{}{"name", "returning"}},
+		{`
+			db.transaction do |tx|
+			  tx.run("UPDATE users SET age = :age WHERE name = :name", { name: "Stan", age: 30 })
+			  result = tx.query_one("SELECT age FROM users WHERE name = :name", name: "Stan")[:age]
+			  tx.rollback
+			  result
+			end
+			`,
+			30},
+		// Bind types
+		{`
+			db.query_one("SELECT $1 AS a, $2 AS b, $3 AS c, $4 AS d", true, nil, "2.50".to_d, 1.5).values_at("a", "b", "c", "d")
+			`,
+			[]interface{}{1, nil, "2.5", 1.5}},
+		// Prepared statements
+		{`
+			stmt = db.prepare("SELECT name FROM users WHERE age > :age ORDER BY age")
+			first = stmt.query(age: 0).map do |row| row[:name] end
+			second = stmt.query_one(age: 22)[:name]
+			stmt.close
+			first.push(second)
+			`,
+			[]interface{}{"Maxwell", "Stan", "Stan"}},
+		{`
+			stmt = db.prepare("SELECT name FROM users WHERE age = $1")
+			names = []
+			stmt.each_row(23) do |row|
+			  names.push(row[:name])
+			end
+			stmt.query(21).first[:name] + " " + names.first
+			`,
+			"Maxwell Stan"},
+		{`
+			db.transaction do |tx|
+			  insert = tx.prepare("INSERT INTO users (name, age) VALUES ($1, $2)")
+			  affected = insert.exec("John", 40).rows_affected + insert.exec("Jane", 41).rows_affected
+			  tx.rollback
+			  affected
+			end
+			`,
+			2},
+	}
+
+	for i, tt := range tests {
+		input := fmt.Sprintf(`
+		require "db"
+
+		db = DB.open("sqlite", "%s")
+		`, path) + tt.input
+		evaluated := vm.ExecAndReturn(t, input)
+		vm.VerifyExpected(t, i, evaluated, tt.expected)
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{`
+			db.query("SELECT * FROM users WHERE id = $1", [1])
+			`,
+			"TypeError: Can't bind Array as a query argument"},
+		{`
+			db.exec("DELETE FROM users WHERE name = :name", name: { first: "Stan" })
+			`,
+			"TypeError: Can't bind Hash as a query argument"},
+		{`
+			db.run("DELETE FROM users WHERE id = $1", Object.new)
+			`,
+			"TypeError: Can't bind Object as a query argument"},
+		{`
+			db.query("SELECT * FROM users WHERE age = $1", 2 ** 64)
+			`,
+			"RangeError: Integer 18446744073709551616 is too big to bind as a query argument"},
+		{`
+			db.prepare("SELECT * FROM users WHERE age > :age").query(1)
+			`,
+			"InternalError: Expect named arguments for parameters [age]"},
+		{`
+			db.prepare("SELECT * FROM users").transaction do |tx|
+			end
+			`,
+			"InternalError: Can't start a transaction on a prepared statement"},
+		{`
+			db.prepare("SELECT * FROM no_such_table")
+			`,
+			"InternalError"},
+	}
+
+	for i, tt := range errorTests {
+		input := fmt.Sprintf(`
+		require "db"
+
+		db = DB.open("sqlite", "%s")
+		`, path) + tt.input
+		evaluated := vm.ExecAndReturn(t, input)
+		err, ok := evaluated.(*vm.Error)
+
+		if !ok || !strings.HasPrefix(err.Message(), tt.expected) {
+			t.Fatalf("At test case %d: Expect error starting with %q. got: %v", i, tt.expected, evaluated)
+		}
+	}
+}
+
+func TestDBMigrator(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "goby_test.db")
+	setup := fmt.Sprintf(`
+	require "db/migrate"
+
+	db = DB.open("sqlite", "%s")
+	migrator = DB::Migrator.new(db, "../../test_fixtures/migrate_test")
+	`, path)
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+			migrator.migrations.map do |m|
+			  m[:name]
+			end
+			`,
+			[]interface{}{"create_users", "add_admins", "add_age_to_users"}},
+		{`
+			migrator.status.map do |m|
+			  m[:applied]
+			end
+			`,
+			[]interface{}{false, false, false}},
+		{`
+			migrator.up(to: 2)
+			`,
+			[]interface{}{1, 2}},
+		{`
+			db.query_one("SELECT name, admin FROM users").values_at("name", "admin")
+			`,
+			[]interface{}{"root", true}},
+		{`
+			migrator.up
+			`,
+			[]interface{}{3}},
+		{`
+			migrator.up
+			`,
+			[]interface{}{}},
+		{`
+			migrator.applied_versions
+			`,
+			[]interface{}{1, 2, 3}},
+		{`
+			db.query_one("SELECT count(*) AS count FROM schema_migrations")[:count]
+			`,
+			3},
+	}
+
+	for i, tt := range tests {
+		evaluated := vm.ExecAndReturn(t, setup+tt.input)
 		vm.VerifyExpected(t, i, evaluated, tt.expected)
 	}
+
+	// 003 has no down file, so rolling it back fails and leaves it applied
+	evaluated := vm.ExecAndReturn(t, setup+`migrator.down`)
+	err, ok := evaluated.(*vm.Error)
+
+	if !ok || !strings.Contains(err.Message(), "Migration 3_add_age_to_users has no down file") {
+		t.Fatalf("Expect rolling back migration 3 to fail. got: %v", evaluated)
+	}
+
+	vm.VerifyExpected(t, 0, vm.ExecAndReturn(t, setup+`migrator.applied_versions`), []interface{}{1, 2, 3})
+
+	path = filepath.Join(t.TempDir(), "goby_test.db")
+	setup = fmt.Sprintf(`
+	require "db/migrate"
+
+	db = DB.open("sqlite", "%s")
+	migrator = DB::Migrator.new(db, "../../test_fixtures/migrate_test", table: "versions")
+	`, path)
+
+	tests = []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+			migrator.up(to: 2)
+			migrator.redo(steps: 2)
+			`,
+			[]interface{}{1, 2}},
+		{`
+			db.query("SELECT name FROM users").length
+			`,
+			1},
+		{`
+			migrator.down
+			`,
+			[]interface{}{2}},
+		{`
+			migrator.status.map do |m|
+			  m[:applied]
+			end
+			`,
+			[]interface{}{true, false, false}},
+		{`
+			db.query("SELECT version FROM versions").length
+			`,
+			1},
+	}
+
+	for i, tt := range tests {
+		evaluated := vm.ExecAndReturn(t, setup+tt.input)
+		vm.VerifyExpected(t, i, evaluated, tt.expected)
+	}
+}
+
+func TestDBMigratorFailures(t *testing.T) {
+	tests := []struct {
+		files    map[string]string
+		input    string
+		expected string
+	}{
+		// A failing migration is rolled back and stops the migration
+		{map[string]string{
+			"1_create_items.up.sql": "CREATE TABLE items (id integer PRIMARY KEY);",
+			"2_broken.up.sql":       "INSERT INTO items (id) VALUES (1); INSERT INTO no_such_table VALUES (1);",
+			"3_never.up.sql":        "CREATE TABLE never (id integer);",
+		}, `
+			migrator.up
+			`, "InternalError"},
+		{map[string]string{
+			"1_create_items.up.sql": "CREATE TABLE items (id integer PRIMARY KEY);",
+			"1_create_other.up.sql": "CREATE TABLE other (id integer PRIMARY KEY);",
+		}, `
+			migrator.up
+			`, "InternalError: Duplicate migration version 1"},
+		{map[string]string{
+			"1_create_items.down.sql": "DROP TABLE items;",
+		}, `
+			migrator.up
+			`, "InternalError: Migration 1_create_items has no up file"},
+		{map[string]string{
+			"1_add_items.gb": "class Items < DB::Migration; end",
+		}, `
+			migrator.up
+			`, "InternalError: Expect"},
+		{map[string]string{
+			"1_add_items.gb": "class AddItems < DB::Migration\n def up\n run(\"CREATE TABLE items (id integer)\")\n end\nend",
+		}, `
+			migrator.up
+			migrator.down
+			`, "InternalError: 'AddItems can't be rolled back'"},
+		{map[string]string{}, `
+			migrator.run("sideways")
+			`, "ArgumentError: 'Unknown migrate command: sideways. Expect up, down, status or redo'"},
+	}
+
+	for i, tt := range tests {
+		dir := t.TempDir()
+
+		for name, content := range tt.files {
+			err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		path := filepath.Join(t.TempDir(), "goby_test.db")
+		evaluated := vm.ExecAndReturn(t, fmt.Sprintf(`
+		require "db/migrate"
+
+		db = DB.open("sqlite", "%s")
+		migrator = DB::Migrator.new(db, "%s")
+		`, path, dir)+tt.input)
+		err, ok := evaluated.(*vm.Error)
+
+		if !ok || !strings.HasPrefix(err.Message(), tt.expected) {
+			t.Fatalf("At test case %d: Expect error starting with %q. got: %v", i, tt.expected, evaluated)
+		}
+	}
+
+	// The failed migration didn't leave any changes behind
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "1_create_items.up.sql"), []byte("CREATE TABLE items (id integer PRIMARY KEY);"), 0644)
+	os.WriteFile(filepath.Join(dir, "2_broken.up.sql"), []byte("INSERT INTO items (id) VALUES (1); INSERT INTO no_such_table VALUES (1);"), 0644)
+	path := filepath.Join(t.TempDir(), "goby_test.db")
+
+	setup := fmt.Sprintf(`
+	require "db/migrate"
+
+	db = DB.open("sqlite", "%s")
+	migrator = DB::Migrator.new(db, "%s")
+	`, path, dir)
+	vm.ExecAndReturn(t, setup+`migrator.up`)
+
+	evaluated := vm.ExecAndReturn(t, setup+`[migrator.applied_versions, db.query("SELECT * FROM items").length]`)
+	vm.VerifyExpected(t, 0, evaluated, []interface{}{[]interface{}{1}, 0})
+}
+
+func TestDBModel(t *testing.T) {
+	path := setupDB(t)
+
+	setup := fmt.Sprintf(`
+	require "db/model"
+
+	db = DB.open("sqlite", "%s")
+	db.run("CREATE TABLE IF NOT EXISTS blog_posts (id integer PRIMARY KEY, title text, published boolean)")
+	DB::Model.connect(db)
+
+	class User < DB::Model
+	  table "users"
+	  attributes :name, :age
+	end
+
+	class BlogPost < DB::Model
+	  attributes :title, :published
+	end
+	`, path)
+
+	vm.ExecAndReturn(t, setup+`
+	User.create(name: "Stan", age: 23)
+	User.create(name: "Maxwell", age: 21)
+	User.create(name: "Jane")
+	`)
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`BlogPost.table_name`, "blog_posts"},
+		{`User.columns`, []interface{}{"id", "name", "age"}},
+		// Query building
+		{`
+			User.where(name: ["Stan", "Maxwell"], age: nil).order(:name, age: "desc").limit(10).offset(5).to_sql
+			`,
+			`SELECT * FROM "users" WHERE "age" IS NULL AND "name" IN ($1, $2) ORDER BY "name" ASC, "age" DESC LIMIT 10 OFFSET 5`},
+		{`
+			User.where("age > ?", 18).where(name: "Stan").binds
+			`,
+			[]interface{}{18, "Stan"}},
+		// A ? inside a string literal or a quoted identifier isn't a placeholder
+		{`
+			User.where("name = '?' AND id = ?", 1).where("\"what?\" = ? OR name = 'it''s ?'", 2).to_sql
+			`,
+			`SELECT * FROM "users" WHERE (name = '?' AND id = $1) AND ("what?" = $2 OR name = 'it''s ?')`},
+		{`
+			User.where("name = '?' OR age = ?", 23).first.name
+			`,
+			"Stan"},
+		{`
+			User.where(name: "Robert'); DROP TABLE users; --").count + User.count
+			`,
+			3},
+		{`
+			User.where(name: ["Stan", "Maxwell"]).order(age: "desc").all.map do |user|
+			  user.name
+			end
+			`,
+			[]interface{}{"Stan", "Maxwell"}},
+		{`
+			User.where("age > ? AND name != ?", 18, "Maxwell").first.name
+			`,
+			"Stan"},
+		{`
+			User.where(age: nil).first.name
+			`,
+			"Jane"},
+		{`
+			User.where(name: []).count
+			`,
+			0},
+		{`
+			User.order(:name).offset(1).limit(1).first.name
+			`,
+			"Maxwell"},
+		{`
+			[User.find(1).name, User.find(99), User.find_by(name: "Jane").id]
+			`,
+			[]interface{}{"Stan", nil, 3}},
+		// Dirty tracking
+		{`
+			user = User.find(1)
+			user.age = 24
+			user.name = "Stan"
+			[user.changed?, user.changed, user.changes["age"]]
+			`,
+			[]interface{}{true, []interface{}{"age"}, []interface{}{23, 24}}},
+		{`
+			user = User.new(name: "Bob")
+			[user.new_record?, user.changed]
+			`,
+			[]interface{}{true, []interface{}{"name"}}},
+		{`
+			user = User.find(2)
+			user.age = 30
+			user.save
+			[user.changed?, User.find(2).age]
+			`,
+			[]interface{}{false, 30}},
+		{`
+			user = User.find(2)
+			user.update(age: 21, name: "Max")
+			[User.find(2).name, User.find(2).age]
+			`,
+			[]interface{}{"Max", 21}},
+		{`
+			user = User.find(3)
+			db.run("UPDATE users SET age = 40 WHERE id = 3")
+			user.reload.age
+			`,
+			40},
+		// Creating and destroying
+		{`
+			post = BlogPost.create(title: "Hello", published: true)
+			[post.id, post.persisted?, BlogPost.find(post.id).published]
+			`,
+			[]interface{}{1, true, true}},
+		{`
+			BlogPost.create.id
+			`,
+			2},
+		{`
+			post = BlogPost.find(1)
+			destroyed = post.destroy
+			[destroyed, post.persisted?, post.destroy, BlogPost.count]
+			`,
+			[]interface{}{true, false, false, 1}},
+	}
+
+	for i, tt := range tests {
+		evaluated := vm.ExecAndReturn(t, setup+tt.input)
+		vm.VerifyExpected(t, i, evaluated, tt.expected)
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{`User.new(nickname: "Stan")`, "ArgumentError: 'Unknown attribute nickname for User'"},
+		{`User.where(1)`, "ArgumentError: 'Expect conditions to be a Hash or a String. got: Integer'"},
+		{`User.order(age: "sideways")`, "ArgumentError: 'Expect order direction to be asc or desc. got: sideways'"},
+		{`User.limit("10")`, "ArgumentError: 'Expect limit to be an Integer. got: String'"},
+		{`User.where(nickname: "Stan").all`, "InternalError"},
+	}
+
+	for i, tt := range errorTests {
+		evaluated := vm.ExecAndReturn(t, setup+tt.input)
+		err, ok := evaluated.(*vm.Error)
+
+		if !ok || !strings.HasPrefix(err.Message(), tt.expected) {
+			t.Fatalf("At test case %d: Expect error starting with %q. got: %v", i, tt.expected, evaluated)
+		}
+	}
+
+	evaluated := vm.ExecAndReturn(t, `
+	require "db/model"
+
+	class Orphan < DB::Model
+	end
+
+	Orphan.count
+	`)
+	err, ok := evaluated.(*vm.Error)
+
+	if !ok || !strings.Contains(err.Message(), "Expect DB::Model.connect to be called before using Orphan") {
+		t.Fatalf("Expect models without a connection to raise an error. got: %v", evaluated)
+	}
 }
@@ -0,0 +1,195 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goby-lang/goby/vm"
+	"github.com/jmoiron/sqlx"
+)
+
+// rowDecoder converts the rows of a result set into Goby Hashes, using the database type of each column:
+//
+// - NULL becomes nil
+// - NUMERIC and DECIMAL columns become Decimals
+// - JSON and JSONB columns are parsed into Hashes and Arrays
+// - BOOLEAN columns become booleans, even when the driver stores them as integers
+// - timestamps become Times
+// - text and binary columns become Strings
+type rowDecoder struct {
+	columns []*sql.ColumnType
+}
+
+func newRowDecoder(rows *sqlx.Rows) (*rowDecoder, error) {
+	columns, err := rows.ColumnTypes()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &rowDecoder{columns: columns}, nil
+}
+
+// scan reads the current row's raw values.
+func (d *rowDecoder) scan(rows *sqlx.Rows) ([]interface{}, error) {
+	values := make([]interface{}, len(d.columns))
+	pointers := make([]interface{}, len(d.columns))
+
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	err := rows.Scan(pointers...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+func (d *rowDecoder) decode(t *Thread, values []interface{}) (Object, error) {
+	data := map[string]Object{}
+
+	for i, column := range d.columns {
+		value, err := decodeValue(t, databaseType(column), values[i])
+
+		if err != nil {
+			return nil, fmt.Errorf("Can't decode column %s: %s", column.Name(), err.Error())
+		}
+
+		data[column.Name()] = value
+	}
+
+	return t.VM().InitHashObject(data), nil
+}
+
+// next scans and decodes the current row.
+func (d *rowDecoder) next(t *Thread, rows *sqlx.Rows) (Object, error) {
+	values, err := d.scan(rows)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return d.decode(t, values)
+}
+
+// metadata describes the columns as an Array of Hashes with `name`, `type` and `nullable` keys.
+// `nullable` is nil when the driver doesn't know it.
+func (d *rowDecoder) metadata(t *Thread) Object {
+	v := t.VM()
+	columns := []Object{}
+
+	for _, column := range d.columns {
+		nullable := Object(vm.NULL)
+
+		if n, ok := column.Nullable(); ok {
+			nullable = v.InitObjectFromGoType(n)
+		}
+
+		columns = append(columns, v.InitHashObject(map[string]Object{
+			"name":     v.InitStringObject(column.Name()),
+			"type":     v.InitStringObject(databaseType(column)),
+			"nullable": nullable,
+		}))
+	}
+
+	return v.InitArrayObject(columns)
+}
+
+// databaseType returns the upper-cased type name of the column without its parameters, like NUMERIC for NUMERIC(10, 2).
+func databaseType(column *sql.ColumnType) string {
+	name := strings.ToUpper(strings.TrimSpace(column.DatabaseTypeName()))
+
+	if i := strings.Index(name, "("); i >= 0 {
+		name = strings.TrimSpace(name[:i])
+	}
+
+	return name
+}
+
+func decodeValue(t *Thread, typeName string, value interface{}) (Object, error) {
+	v := t.VM()
+
+	if value == nil {
+		return vm.NULL, nil
+	}
+
+	if ts, ok := value.(time.Time); ok {
+		return v.InitObjectFromGoType(ts), nil
+	}
+
+	switch typeName {
+	case "NUMERIC", "DECIMAL":
+		text := textValue(value)
+		d, ok := new(big.Rat).SetString(text)
+
+		// Postgres NUMERIC columns can also hold NaN and Infinity, which Decimals can't represent
+		if !ok {
+			return nil, fmt.Errorf("invalid decimal %s", text)
+		}
+
+		return v.InitObjectFromGoType(d), nil
+	case "JSON", "JSONB":
+		var decoded interface{}
+
+		decoder := json.NewDecoder(strings.NewReader(textValue(value)))
+		decoder.UseNumber()
+		err := decoder.Decode(&decoded)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return v.InitObjectFromGoType(jsonNumbers(decoded)), nil
+	case "BOOLEAN", "BOOL":
+		if i, ok := value.(int64); ok {
+			return v.InitObjectFromGoType(i != 0), nil
+		}
+	}
+
+	return v.InitObjectFromGoType(value), nil
+}
+
+// jsonNumbers converts the json.Numbers of a decoded JSON value into ints, or float64s if they aren't integers.
+func jsonNumbers(value interface{}) interface{} {
+	switch value := value.(type) {
+	case json.Number:
+		if i, err := value.Int64(); err == nil {
+			return int(i)
+		}
+
+		f, _ := value.Float64()
+		return f
+	case []interface{}:
+		for i, elem := range value {
+			value[i] = jsonNumbers(elem)
+		}
+	case map[string]interface{}:
+		for k, elem := range value {
+			value[k] = jsonNumbers(elem)
+		}
+	}
+
+	return value
+}
+
+// textValue returns the text form of a raw value, which drivers return as numbers, Strings or bytes.
+func textValue(value interface{}) string {
+	switch value := value.(type) {
+	case []byte:
+		return string(value)
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,177 @@
+package db
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goby-lang/goby/vm"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// Migration files are named after their version and name, like `001_create_users.up.sql` and
+// `001_create_users.down.sql` for SQL migrations, or `002_add_admins.gb` for Goby migrations.
+var (
+	sqlMigrationFile  = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+	gobyMigrationFile = regexp.MustCompile(`^(\d+)_(\w+)\.gb$`)
+)
+
+// migrationFile describes one migration of a migrations directory.
+type migrationFile struct {
+	version int
+	name    string
+	// up and down are the paths of a SQL migration's files
+	up   string
+	down string
+	// script is the path of a Goby migration's file
+	script string
+}
+
+// The migration_files method reads the migrations of the given directory, and returns them ordered by
+// version as an Array of Hashes with `version`, `name`, `up`, `down`, `script` and `class_name` keys.
+// See DB::Migrator in db/migrate.gb file.
+//
+// @return [Array]
+//
+func migrationFiles(c *vm.Call) Object {
+	v := c.VM()
+	migrations, err := readMigrations(c.String(0))
+
+	if err != nil {
+		return c.Error(errors.InternalError, err.Error())
+	}
+
+	elems := []Object{}
+
+	for _, m := range migrations {
+		elems = append(elems, v.InitHashObject(map[string]Object{
+			"version":    v.InitIntegerObject(m.version),
+			"name":       v.InitStringObject(m.name),
+			"up":         pathObject(v, m.up),
+			"down":       pathObject(v, m.down),
+			"script":     pathObject(v, m.script),
+			"class_name": v.InitStringObject(migrationClassName(m.name)),
+		}))
+	}
+
+	return v.InitArrayObject(elems)
+}
+
+// The load_migration method executes a Goby migration file and returns the migration class it defines.
+//
+// @return [Class]
+//
+func loadMigration(c *vm.Call) Object {
+	path, className := c.String(0), c.String(1)
+	err := c.Thread.LoadFile(path)
+
+	if err != nil {
+		return c.Error(errors.InternalError, "Can't load migration %s: %s", path, err.Error())
+	}
+
+	class := c.VM().Constant(className)
+
+	if class == nil {
+		return c.Error(errors.InternalError, "Expect %s to define the %s class", path, className)
+	}
+
+	return class
+}
+
+func readMigrations(dir string) ([]*migrationFile, error) {
+	infos, err := ioutil.ReadDir(dir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*migrationFile{}
+
+	for _, info := range infos {
+		if info.IsDir() {
+			continue
+		}
+
+		var match []string
+		kind := "script"
+
+		if match = sqlMigrationFile.FindStringSubmatch(info.Name()); match != nil {
+			kind = match[3]
+		} else if match = gobyMigrationFile.FindStringSubmatch(info.Name()); match == nil {
+			continue
+		}
+
+		version, err := strconv.Atoi(match[1])
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid migration version %s", match[1])
+		}
+
+		m, ok := byVersion[version]
+
+		if !ok {
+			m = &migrationFile{version: version, name: match[2]}
+			byVersion[version] = m
+		}
+
+		if m.name != match[2] {
+			return nil, fmt.Errorf("Duplicate migration version %d: %s and %s", version, m.name, match[2])
+		}
+
+		path := filepath.Join(dir, info.Name())
+
+		switch kind {
+		case "up":
+			m.up = path
+		case "down":
+			m.down = path
+		default:
+			m.script = path
+		}
+
+		if m.script != "" && (m.up != "" || m.down != "") {
+			return nil, fmt.Errorf("Migration %d_%s has both SQL and Goby files", version, m.name)
+		}
+	}
+
+	migrations := []*migrationFile{}
+
+	for _, m := range byVersion {
+		if m.script == "" && m.up == "" {
+			return nil, fmt.Errorf("Migration %d_%s has no up file", m.version, m.name)
+		}
+
+		migrations = append(migrations, m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].version < migrations[j].version
+	})
+
+	return migrations, nil
+}
+
+// migrationClassName returns the name of the class a Goby migration defines, like CreateUsers for create_users.
+func migrationClassName(name string) string {
+	words := strings.Split(name, "_")
+
+	for i, word := range words {
+		if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+
+	return strings.Join(words, "")
+}
+
+func pathObject(v *VM, path string) Object {
+	if path == "" {
+		return vm.NULL
+	}
+
+	return v.InitStringObject(path)
+}
@@ -0,0 +1,189 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/goby-lang/goby/vm"
+	"github.com/goby-lang/goby/vm/errors"
+	"github.com/jmoiron/sqlx"
+)
+
+// statement is the connection object of a DB::Statement. It's prepared with sqlx's named query support,
+// so it takes either positional arguments or a Hash of named arguments.
+type statement struct {
+	stmt       *sqlx.NamedStmt
+	driverName string
+}
+
+// typeError is returned for arguments of unsupported types, which are raised as TypeErrors.
+type typeError struct {
+	error
+}
+
+// rangeError is returned for Integer arguments that don't fit in 64 bits, which are raised as RangeErrors.
+type rangeError struct {
+	error
+}
+
+// errorType returns the Goby error class of an error returned by this package's functions.
+func errorType(err error) string {
+	switch err.(type) {
+	case typeError:
+		return errors.TypeError
+	case rangeError:
+		return errors.RangeError
+	}
+
+	return errors.InternalError
+}
+
+// The prepare_statement method prepares the query and returns its statement object, see DB#prepare in db.gb file.
+// Prepared in a transaction, the statement belongs to the transaction.
+//
+// @return [Object]
+//
+func prepareStatement(c *vm.Call) Object {
+	if _, ok := connValue(c.Receiver).(*statement); ok {
+		return c.Error(errors.InternalError, "Can't prepare a statement on a statement")
+	}
+
+	conn, err := getExecer(c.Receiver)
+
+	if err != nil {
+		return c.Error(errors.InternalError, err.Error())
+	}
+
+	stmt, err := conn.PrepareNamed(c.String(0))
+
+	if err != nil {
+		return c.Error(errors.InternalError, err.Error())
+	}
+
+	return c.Return(&statement{stmt: stmt, driverName: conn.DriverName()})
+}
+
+func (s *statement) query(args []Object) (*sqlx.Rows, error) {
+	named, positional, err := s.arguments(args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if named != nil {
+		return s.stmt.Queryx(named)
+	}
+
+	return s.stmt.Stmt.Queryx(positional...)
+}
+
+func (s *statement) exec(args []Object) (sql.Result, error) {
+	named, positional, err := s.arguments(args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if named != nil {
+		return s.stmt.Exec(named)
+	}
+
+	return s.stmt.Stmt.Exec(positional...)
+}
+
+// arguments converts the arguments into either named or positional bind values, as the statement expects.
+func (s *statement) arguments(args []Object) (map[string]interface{}, []interface{}, error) {
+	if h, ok := namedArguments(args); ok {
+		named, err := bindHash(h)
+		return named, nil, err
+	}
+
+	if len(s.stmt.Params) > 0 {
+		return nil, nil, fmt.Errorf("Expect named arguments for parameters %v", s.stmt.Params)
+	}
+
+	positional, err := bindValues(args)
+	return nil, positional, err
+}
+
+// bindArguments converts the arguments of a query into bind values. A single Hash argument binds the named
+// parameters of the query, like `:id`, which are replaced with the driver's positional placeholders.
+func bindArguments(conn execer, query string, args []Object) (string, []interface{}, error) {
+	if h, ok := namedArguments(args); ok {
+		named, err := bindHash(h)
+
+		if err != nil {
+			return "", nil, err
+		}
+
+		return conn.BindNamed(query, named)
+	}
+
+	values, err := bindValues(args)
+	return query, values, err
+}
+
+func namedArguments(args []Object) (*vm.HashObject, bool) {
+	if len(args) != 1 {
+		return nil, false
+	}
+
+	h, ok := args[0].(*vm.HashObject)
+	return h, ok
+}
+
+func bindHash(h *vm.HashObject) (map[string]interface{}, error) {
+	named := map[string]interface{}{}
+
+	for k, value := range h.Pairs {
+		v, err := bindValue(value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		named[k] = v
+	}
+
+	return named, nil
+}
+
+func bindValues(args []Object) ([]interface{}, error) {
+	values := []interface{}{}
+
+	for _, arg := range args {
+		v, err := bindValue(arg)
+
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, v)
+	}
+
+	return values, nil
+}
+
+// bindValue converts a Goby object into a value the drivers accept. Decimals are bound as their exact String form,
+// and Times as Go times.
+func bindValue(obj Object) (interface{}, error) {
+	switch obj := obj.(type) {
+	case *vm.NullObject:
+		return nil, nil
+	case *vm.IntegerObject:
+		// Drivers only accept integers that fit in 64 bits
+		if _, ok := obj.Value().(int); !ok {
+			return nil, rangeError{fmt.Errorf("Integer %s is too big to bind as a query argument", obj.Value())}
+		}
+
+		return obj.Value(), nil
+	case *StringObject, *vm.FloatObject, *vm.BooleanObject, *vm.TimeObject:
+		return obj.Value(), nil
+	case *vm.DecimalObject:
+		s := obj.Value().(*vm.Decimal).FloatString(60)
+		return strings.TrimRight(strings.TrimRight(s, "0"), "."), nil
+	default:
+		return nil, typeError{fmt.Errorf("Can't bind %s as a query argument", obj.Class().Name)}
+	}
+}
@@ -0,0 +1,496 @@
+package redis
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeServer is an in-process Redis server, which implements the commands the tests use, so they
+// don't need a real server to connect to.
+type fakeServer struct {
+	listener net.Listener
+	password string
+
+	mu          sync.Mutex
+	strings     map[string]string
+	hashes      map[string]map[string]string
+	lists       map[string][]string
+	expires     map[string]time.Time
+	subscribers map[string][]*fakeConn
+	// commands counts the commands the server received, by name
+	commands map[string]int
+}
+
+type fakeConn struct {
+	mu     sync.Mutex
+	conn   net.Conn
+	writer *bufio.Writer
+	authed bool
+}
+
+// startFakeServer starts a server on a random port, and returns its address. It's closed when the test ends.
+func startFakeServer(t *testing.T, password string) (*fakeServer, string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &fakeServer{
+		listener:    listener,
+		password:    password,
+		strings:     map[string]string{},
+		hashes:      map[string]map[string]string{},
+		lists:       map[string][]string{},
+		expires:     map[string]time.Time{},
+		subscribers: map[string][]*fakeConn{},
+		commands:    map[string]int{},
+	}
+
+	go s.serve()
+	t.Cleanup(func() { listener.Close() })
+
+	return s, listener.Addr().String()
+}
+
+func (s *fakeServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+
+		if err != nil {
+			return
+		}
+
+		go s.handle(&fakeConn{conn: conn, writer: bufio.NewWriter(conn), authed: s.password == ""})
+	}
+}
+
+func (s *fakeServer) handle(c *fakeConn) {
+	defer c.conn.Close()
+	reader := bufio.NewReader(c.conn)
+
+	for {
+		request, err := readReply(reader)
+
+		if err != nil {
+			return
+		}
+
+		args := []string{}
+
+		for _, arg := range request.([]interface{}) {
+			args = append(args, string(arg.([]byte)))
+		}
+
+		reply := s.execute(c, strings.ToUpper(args[0]), args[1:])
+
+		c.mu.Lock()
+		writeReply(c.writer, reply)
+
+		// Replies to pipelined commands are only flushed once all of them are handled
+		if reader.Buffered() == 0 {
+			c.writer.Flush()
+		}
+
+		c.mu.Unlock()
+	}
+}
+
+func (s *fakeServer) commandCount(name string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.commands[name]
+}
+
+// execute runs a command and returns its reply, which is encoded like readReply decodes replies.
+func (s *fakeServer) execute(c *fakeConn, name string, args []string) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.commands[name]++
+
+	if name == "AUTH" {
+		if len(args) != 1 || args[0] != s.password {
+			return respError("WRONGPASS invalid username-password pair")
+		}
+
+		c.authed = true
+		return "OK"
+	}
+
+	if !c.authed {
+		return respError("NOAUTH Authentication required.")
+	}
+
+	for key, at := range s.expires {
+		if time.Now().After(at) {
+			s.delete(key)
+		}
+	}
+
+	switch name {
+	case "PING":
+		return "PONG"
+	case "SELECT":
+		return "OK"
+	case "GET":
+		if value, ok := s.strings[args[0]]; ok {
+			return []byte(value)
+		}
+
+		if s.exists(args[0]) {
+			return wrongType
+		}
+
+		return nil
+	case "SET":
+		s.delete(args[0])
+		s.strings[args[0]] = args[1]
+
+		if len(args) == 4 {
+			n, _ := strconv.Atoi(args[3])
+			unit := time.Second
+
+			if strings.ToUpper(args[2]) == "PX" {
+				unit = time.Millisecond
+			}
+
+			s.expires[args[0]] = time.Now().Add(time.Duration(n) * unit)
+		}
+
+		return "OK"
+	case "DEL":
+		count := int64(0)
+
+		for _, key := range args {
+			if s.exists(key) {
+				s.delete(key)
+				count++
+			}
+		}
+
+		return count
+	case "EXISTS":
+		return boolReply(s.exists(args[0]))
+	case "EXPIRE":
+		if !s.exists(args[0]) {
+			return int64(0)
+		}
+
+		n, _ := strconv.Atoi(args[1])
+		s.expires[args[0]] = time.Now().Add(time.Duration(n) * time.Second)
+		return int64(1)
+	case "TTL":
+		if !s.exists(args[0]) {
+			return int64(-2)
+		}
+
+		at, ok := s.expires[args[0]]
+
+		if !ok {
+			return int64(-1)
+		}
+
+		return int64(time.Until(at).Round(time.Second) / time.Second)
+	case "INCR", "INCRBY", "DECR", "DECRBY":
+		by := int64(1)
+
+		if len(args) == 2 {
+			n, err := strconv.ParseInt(args[1], 10, 64)
+
+			if err != nil {
+				return respError("ERR value is not an integer or out of range")
+			}
+
+			by = n
+		}
+
+		if strings.HasPrefix(name, "DECR") {
+			by = -by
+		}
+
+		current := int64(0)
+
+		if value, ok := s.strings[args[0]]; ok {
+			n, err := strconv.ParseInt(value, 10, 64)
+
+			if err != nil {
+				return respError("ERR value is not an integer or out of range")
+			}
+
+			current = n
+		} else if s.exists(args[0]) {
+			return wrongType
+		}
+
+		current += by
+		s.strings[args[0]] = strconv.FormatInt(current, 10)
+		return current
+	case "HSET":
+		hash, err := s.hash(args[0], true)
+
+		if err != nil {
+			return err
+		}
+
+		added := int64(0)
+
+		for i := 1; i+1 < len(args); i += 2 {
+			if _, ok := hash[args[i]]; !ok {
+				added++
+			}
+
+			hash[args[i]] = args[i+1]
+		}
+
+		return added
+	case "HGET":
+		hash, err := s.hash(args[0], false)
+
+		if err != nil {
+			return err
+		}
+
+		if value, ok := hash[args[1]]; ok {
+			return []byte(value)
+		}
+
+		return nil
+	case "HGETALL":
+		hash, err := s.hash(args[0], false)
+
+		if err != nil {
+			return err
+		}
+
+		fields := []string{}
+
+		for field := range hash {
+			fields = append(fields, field)
+		}
+
+		sort.Strings(fields)
+		reply := []interface{}{}
+
+		for _, field := range fields {
+			reply = append(reply, []byte(field), []byte(hash[field]))
+		}
+
+		return reply
+	case "HDEL":
+		hash, err := s.hash(args[0], false)
+
+		if err != nil {
+			return err
+		}
+
+		count := int64(0)
+
+		for _, field := range args[1:] {
+			if _, ok := hash[field]; ok {
+				delete(hash, field)
+				count++
+			}
+		}
+
+		if len(hash) == 0 {
+			s.delete(args[0])
+		}
+
+		return count
+	case "LPUSH", "RPUSH":
+		list, err := s.list(args[0])
+
+		if err != nil {
+			return err
+		}
+
+		for _, value := range args[1:] {
+			if name == "LPUSH" {
+				list = append([]string{value}, list...)
+			} else {
+				list = append(list, value)
+			}
+		}
+
+		s.lists[args[0]] = list
+		return int64(len(list))
+	case "LPOP", "RPOP":
+		list, err := s.list(args[0])
+
+		if err != nil {
+			return err
+		}
+
+		if len(list) == 0 {
+			return nil
+		}
+
+		var value string
+
+		if name == "LPOP" {
+			value, list = list[0], list[1:]
+		} else {
+			value, list = list[len(list)-1], list[:len(list)-1]
+		}
+
+		s.lists[args[0]] = list
+
+		if len(list) == 0 {
+			s.delete(args[0])
+		}
+
+		return []byte(value)
+	case "LRANGE":
+		list, err := s.list(args[0])
+
+		if err != nil {
+			return err
+		}
+
+		start, _ := strconv.Atoi(args[1])
+		stop, _ := strconv.Atoi(args[2])
+
+		if start < 0 {
+			start += len(list)
+		}
+
+		if stop < 0 {
+			stop += len(list)
+		}
+
+		reply := []interface{}{}
+
+		for i := start; i <= stop && i < len(list); i++ {
+			if i >= 0 {
+				reply = append(reply, []byte(list[i]))
+			}
+		}
+
+		return reply
+	case "LLEN":
+		list, err := s.list(args[0])
+
+		if err != nil {
+			return err
+		}
+
+		return int64(len(list))
+	case "SUBSCRIBE":
+		// The confirmations are written here, since a command has one reply
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		for i, channel := range args {
+			s.subscribers[channel] = append(s.subscribers[channel], c)
+			writeReply(c.writer, []interface{}{[]byte("subscribe"), []byte(channel), int64(i + 1)})
+		}
+
+		c.writer.Flush()
+		return pushed{}
+	case "PUBLISH":
+		count := int64(0)
+
+		for _, sub := range s.subscribers[args[0]] {
+			sub.mu.Lock()
+			writeReply(sub.writer, []interface{}{[]byte("message"), []byte(args[0]), []byte(args[1])})
+			err := sub.writer.Flush()
+			sub.mu.Unlock()
+
+			if err == nil {
+				count++
+			}
+		}
+
+		return count
+	default:
+		return respError(fmt.Sprintf("ERR unknown command '%s'", name))
+	}
+}
+
+// pushed is the reply of commands which wrote their replies already.
+type pushed struct{}
+
+var wrongType = respError("WRONGTYPE Operation against a key holding the wrong kind of value")
+
+func (s *fakeServer) exists(key string) bool {
+	_, isString := s.strings[key]
+	_, isHash := s.hashes[key]
+	_, isList := s.lists[key]
+
+	return isString || isHash || isList
+}
+
+func (s *fakeServer) delete(key string) {
+	delete(s.strings, key)
+	delete(s.hashes, key)
+	delete(s.lists, key)
+	delete(s.expires, key)
+}
+
+func (s *fakeServer) hash(key string, create bool) (map[string]string, interface{}) {
+	if hash, ok := s.hashes[key]; ok {
+		return hash, nil
+	}
+
+	if s.exists(key) {
+		return nil, wrongType
+	}
+
+	if !create {
+		return map[string]string{}, nil
+	}
+
+	s.hashes[key] = map[string]string{}
+	return s.hashes[key], nil
+}
+
+func (s *fakeServer) list(key string) ([]string, interface{}) {
+	if list, ok := s.lists[key]; ok {
+		return list, nil
+	}
+
+	if s.exists(key) {
+		return nil, wrongType
+	}
+
+	return []string{}, nil
+}
+
+func boolReply(b bool) int64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+func writeReply(w *bufio.Writer, reply interface{}) {
+	switch reply := reply.(type) {
+	case pushed:
+	case nil:
+		w.WriteString("$-1\r\n")
+	case string:
+		fmt.Fprintf(w, "+%s\r\n", reply)
+	case respError:
+		fmt.Fprintf(w, "-%s\r\n", reply)
+	case int64:
+		fmt.Fprintf(w, ":%d\r\n", reply)
+	case []byte:
+		fmt.Fprintf(w, "$%d\r\n%s\r\n", len(reply), reply)
+	case []interface{}:
+		fmt.Fprintf(w, "*%d\r\n", len(reply))
+
+		for _, elem := range reply {
+			writeReply(w, elem)
+		}
+	}
+}
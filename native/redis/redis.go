@@ -0,0 +1,489 @@
+package redis
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goby-lang/goby/vm"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// Imported objects from vm
+type Object = vm.Object
+
+func init() {
+	vm.RegisterExternalClass("redis", vm.ExternalClass("Redis", "redis.gb",
+		// class methods
+		map[string]vm.MethodBuilder{
+			"connect":            vm.NativeMethod(vm.Sig(vm.ArgString, vm.ArgHash), connect),
+			"close_subscription": vm.NativeMethod(vm.Sig(vm.ArgGoValue), closeSubscription),
+		},
+		// instance methods
+		map[string]vm.MethodBuilder{
+			"call_command":      vm.NativeMethod(vm.Sig(vm.ArgArray), callCommand),
+			"call_pipeline":     vm.NativeMethod(vm.Sig(vm.ArgArray), callPipeline),
+			"open_subscription": vm.NativeMethod(vm.Sig(vm.ArgArray, vm.ArgChannel), openSubscription),
+			"close":             vm.NativeMethod(vm.Sig(), closeClient),
+		},
+	))
+}
+
+// defaultTimeout limits how long connecting to the server takes, when no timeout is given.
+const defaultTimeout = 10 * time.Second
+
+type options struct {
+	network  string
+	address  string
+	password string
+	db       int
+	// timeout limits connecting and each command, or only connecting when it's 0
+	timeout time.Duration
+}
+
+// client is the connection object of a Redis instance. Its commands are serialized, so threads can share it.
+// The connection is reopened by the next command after an I/O error.
+type client struct {
+	mu      sync.Mutex
+	options options
+	conn    net.Conn
+	reader  *bufio.Reader
+	writer  *bufio.Writer
+	closed  bool
+}
+
+// The connect method returns the connection object for the address, which is either `host:port`, a
+// `redis://:password@host:port/db` URL, or the path of a unix socket. It takes a Hash of options with
+// `password`, `db` and `timeout` (in seconds) keys. See Redis#initialize in redis.gb file.
+//
+// @return [Object]
+//
+func connect(c *vm.Call) Object {
+	opts, err := parseOptions(c.String(0), c.Hash(1))
+
+	if err != nil {
+		return c.Error(errors.ArgumentError, err.Error())
+	}
+
+	cl := &client{options: opts}
+	err = cl.connect()
+
+	if err != nil {
+		return c.Error(errors.InternalError, err.Error())
+	}
+
+	return c.Return(cl)
+}
+
+// The call_command method sends a command, given as an Array of its name and arguments, and returns the reply.
+// Error replies are raised as InternalErrors.
+//
+// @return [Object]
+//
+func callCommand(c *vm.Call) Object {
+	cl, err := getClient(c.Receiver)
+
+	if err != nil {
+		return c.Error(errors.InternalError, err.Error())
+	}
+
+	command, err := commandArgs(c.Arg(0))
+
+	if err != nil {
+		return c.Error(errors.TypeError, err.Error())
+	}
+
+	replies, err := cl.do([][]string{command})
+
+	if err != nil {
+		return c.Error(errors.InternalError, err.Error())
+	}
+
+	reply, err := replyObject(c.VM(), replies[0])
+
+	if err != nil {
+		return c.Error(errors.InternalError, err.Error())
+	}
+
+	return reply
+}
+
+// The call_pipeline method sends an Array of commands at once, then reads their replies, so they take
+// a single round trip. It returns the Array of replies, and raises the first error reply after reading
+// all of them. See Redis#pipelined in redis.gb file.
+//
+// @return [Array]
+//
+func callPipeline(c *vm.Call) Object {
+	cl, err := getClient(c.Receiver)
+
+	if err != nil {
+		return c.Error(errors.InternalError, err.Error())
+	}
+
+	commands := [][]string{}
+
+	for _, elem := range c.Array(0) {
+		command, err := commandArgs(elem)
+
+		if err != nil {
+			return c.Error(errors.TypeError, err.Error())
+		}
+
+		commands = append(commands, command)
+	}
+
+	if len(commands) == 0 {
+		return c.Return([]interface{}{})
+	}
+
+	replies, err := cl.do(commands)
+
+	if err != nil {
+		return c.Error(errors.InternalError, err.Error())
+	}
+
+	results := []Object{}
+	var replyErr error
+
+	for _, reply := range replies {
+		result, err := replyObject(c.VM(), reply)
+
+		if err != nil && replyErr == nil {
+			replyErr = err
+		}
+
+		results = append(results, result)
+	}
+
+	if replyErr != nil {
+		return c.Error(errors.InternalError, replyErr.Error())
+	}
+
+	return c.VM().InitArrayObject(results)
+}
+
+// The close method closes the connection. Its commands raise an error afterwards.
+//
+// @return [Boolean]
+//
+func closeClient(c *vm.Call) Object {
+	cl, err := getClient(c.Receiver)
+
+	if err != nil {
+		return c.Error(errors.InternalError, err.Error())
+	}
+
+	cl.close()
+	return vm.TRUE
+}
+
+func getClient(receiver Object) (*client, error) {
+	conn, _ := receiver.InstanceVariableGet("@conn")
+	goObj, ok := conn.(*vm.GoObject)
+
+	if !ok {
+		return nil, fmt.Errorf("Redis connection is nil")
+	}
+
+	c, ok := goObj.Value().(*client)
+
+	if !ok {
+		return nil, fmt.Errorf("Redis connection is nil")
+	}
+
+	return c, nil
+}
+
+func parseOptions(address string, optionPairs map[string]Object) (options, error) {
+	opts := options{network: "tcp", address: address}
+
+	switch {
+	case strings.HasPrefix(address, "redis://"):
+		u, err := url.Parse(address)
+
+		if err != nil {
+			return opts, err
+		}
+
+		opts.address = u.Host
+
+		if p, ok := u.User.Password(); ok {
+			opts.password = p
+		}
+
+		if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+			opts.db, err = strconv.Atoi(db)
+
+			if err != nil {
+				return opts, fmt.Errorf("Invalid database number: %s", db)
+			}
+		}
+	case strings.HasPrefix(address, "unix://"):
+		opts.network, opts.address = "unix", strings.TrimPrefix(address, "unix://")
+	case strings.HasPrefix(address, "/"):
+		opts.network = "unix"
+	}
+
+	if opts.network == "tcp" && !strings.Contains(opts.address, ":") {
+		opts.address += ":6379"
+	}
+
+	for k, value := range optionPairs {
+		if value == vm.NULL {
+			continue
+		}
+
+		switch k {
+		case "password":
+			password, ok := value.(*vm.StringObject)
+
+			if !ok {
+				return opts, fmt.Errorf("Expect password to be a String. got: %s", value.Class().Name)
+			}
+
+			opts.password = password.Value().(string)
+		case "db":
+			db, ok := value.(*vm.IntegerObject)
+
+			if !ok {
+				return opts, fmt.Errorf("Expect db to be an Integer. got: %s", value.Class().Name)
+			}
+
+			// Integers too big for an int are backed by a *big.Int
+			n, ok := db.Value().(int)
+
+			if !ok || n < 0 || n > math.MaxInt32 {
+				return opts, fmt.Errorf("Expect db to be between 0 and %d. got: %v", math.MaxInt32, db.Value())
+			}
+
+			opts.db = n
+		case "timeout":
+			var seconds float64
+
+			switch value := value.(type) {
+			case *vm.IntegerObject:
+				if i, ok := value.Value().(int); ok {
+					seconds = float64(i)
+				} else {
+					seconds = math.Inf(1)
+				}
+			case *vm.FloatObject:
+				seconds = value.Value().(float64)
+			default:
+				return opts, fmt.Errorf("Expect timeout to be an Integer or a Float. got: %s", value.Class().Name)
+			}
+
+			if math.IsNaN(seconds) || seconds < 0 || seconds > math.MaxInt64/float64(time.Second) {
+				return opts, fmt.Errorf("Expect timeout to be between 0 and %d seconds. got: %v", math.MaxInt64/int64(time.Second), value.Value())
+			}
+
+			opts.timeout = time.Duration(seconds * float64(time.Second))
+		default:
+			return opts, fmt.Errorf("Unknown option: %s", k)
+		}
+	}
+
+	return opts, nil
+}
+
+// dial opens a connection, and authenticates and selects the database if the options ask to.
+func dial(opts options) (net.Conn, *bufio.Reader, *bufio.Writer, error) {
+	timeout := opts.timeout
+
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
+	conn, err := net.DialTimeout(opts.network, opts.address, timeout)
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	reader, writer := bufio.NewReader(conn), bufio.NewWriter(conn)
+	setup := [][]string{}
+
+	if opts.password != "" {
+		setup = append(setup, []string{"AUTH", opts.password})
+	}
+
+	if opts.db != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(opts.db)})
+	}
+
+	if len(setup) > 0 {
+		conn.SetDeadline(time.Now().Add(timeout))
+		replies, err := roundTrip(reader, writer, setup)
+
+		if err == nil {
+			err = firstError(replies)
+		}
+
+		if err != nil {
+			conn.Close()
+			return nil, nil, nil, err
+		}
+
+		conn.SetDeadline(time.Time{})
+	}
+
+	return conn, reader, writer, nil
+}
+
+func (c *client) connect() (err error) {
+	c.conn, c.reader, c.writer, err = dial(c.options)
+	return
+}
+
+// do sends the commands and reads their replies.
+func (c *client) do(commands [][]string) ([]interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, fmt.Errorf("Redis connection is closed")
+	}
+
+	if c.conn == nil {
+		err := c.connect()
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.options.timeout > 0 {
+		c.conn.SetDeadline(time.Now().Add(c.options.timeout))
+	}
+
+	replies, err := roundTrip(c.reader, c.writer, commands)
+
+	if err != nil {
+		// The replies of the connection can't be matched to commands anymore
+		c.conn.Close()
+		c.conn = nil
+		return nil, err
+	}
+
+	return replies, nil
+}
+
+func (c *client) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
+	}
+
+	c.closed = true
+}
+
+func roundTrip(reader *bufio.Reader, writer *bufio.Writer, commands [][]string) ([]interface{}, error) {
+	for _, command := range commands {
+		err := writeCommand(writer, command)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := writer.Flush()
+
+	if err != nil {
+		return nil, err
+	}
+
+	replies := make([]interface{}, len(commands))
+
+	for i := range replies {
+		replies[i], err = readReply(reader)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return replies, nil
+}
+
+func firstError(replies []interface{}) error {
+	for _, reply := range replies {
+		if err, ok := reply.(respError); ok {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// commandArgs converts a command's Array into Strings. Integers and Floats are sent in their String form.
+func commandArgs(obj Object) ([]string, error) {
+	arr, ok := obj.(*vm.ArrayObject)
+
+	if !ok {
+		return nil, fmt.Errorf("Expect command to be an Array. got: %s", obj.Class().Name)
+	}
+
+	if len(arr.Elements) == 0 {
+		return nil, fmt.Errorf("Expect command to have a name")
+	}
+
+	args := []string{}
+
+	for _, elem := range arr.Elements {
+		switch elem := elem.(type) {
+		case *vm.StringObject:
+			args = append(args, elem.Value().(string))
+		case *vm.IntegerObject:
+			// Value is an int, or a *big.Int for Integers too big for one
+			args = append(args, fmt.Sprint(elem.Value()))
+		case *vm.FloatObject:
+			args = append(args, strconv.FormatFloat(elem.Value().(float64), 'f', -1, 64))
+		default:
+			return nil, fmt.Errorf("Can't send %s as a command argument", elem.Class().Name)
+		}
+	}
+
+	return args, nil
+}
+
+// replyObject converts a reply into a Goby object, or returns the error of an error reply.
+func replyObject(v *vm.VM, reply interface{}) (Object, error) {
+	switch reply := reply.(type) {
+	case nil:
+		return vm.NULL, nil
+	case respError:
+		return vm.NULL, reply
+	case string:
+		return v.InitStringObject(reply), nil
+	case []byte:
+		return v.InitStringObject(string(reply)), nil
+	case int64:
+		return v.InitIntegerObject(int(reply)), nil
+	case []interface{}:
+		elems := []Object{}
+
+		for _, elem := range reply {
+			obj, err := replyObject(v, elem)
+
+			if err != nil {
+				return vm.NULL, err
+			}
+
+			elems = append(elems, obj)
+		}
+
+		return v.InitArrayObject(elems), nil
+	default:
+		return vm.NULL, fmt.Errorf("Unknown reply %v", reply)
+	}
+}
@@ -0,0 +1,211 @@
+package redis
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/goby-lang/goby/vm"
+)
+
+func TestRedisCommands(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`redis.ping`, "PONG"},
+		{`
+			redis.set("greeting", "hello")
+			redis.get("greeting")
+			`, "hello"},
+		{`redis.get("missing")`, nil},
+		{`
+			redis.set("count", 10)
+			redis.incr("count")
+			redis.incrby("count", 5)
+			redis.decr("count")
+			redis.decrby("count", 2)
+			`, 13},
+		{`
+			redis.set("a", 1)
+			redis.set("b", 2)
+			[redis.del("a", "b", "c"), redis.exists?("a")]
+			`, []interface{}{2, false}},
+		{`
+			redis.set("session", "abc", ttl: 60)
+			redis.ttl("session")
+			`, 60},
+		{`
+			redis.set("session", "abc", ttl: 30.0)
+			redis.ttl("session")
+			`, 30},
+		{`
+			redis.set("session", "abc")
+			[redis.ttl("session"), redis.expire("session", 10), redis.ttl("session"), redis.expire("missing", 10), redis.ttl("missing")]
+			`, []interface{}{-1, true, 10, false, -2}},
+		{`
+			redis.hset("user:1", { name: "Stan", age: 30 })
+			redis.hset("user:1", "city", "Taipei")
+			h = redis.hgetall("user:1")
+			h.sorted_keys.map do |k| k + "=" + h[k] end
+			`, []interface{}{"age=30", "city=Taipei", "name=Stan"}},
+		{`
+			redis.hset("user:1", "name", "Stan", "age", 30)
+			[redis.hget("user:1", "name"), redis.hdel("user:1", "age", "city"), redis.hget("user:1", "age")]
+			`, []interface{}{"Stan", 1, nil}},
+		{`redis.hgetall("missing").length`, 0},
+		{`
+			redis.rpush("jobs", "b", "c")
+			redis.lpush("jobs", "a")
+			redis.lrange("jobs", 0, -1)
+			`, []interface{}{"a", "b", "c"}},
+		{`
+			redis.rpush("jobs", "a", "b", "c")
+			[redis.lpop("jobs"), redis.rpop("jobs"), redis.llen("jobs"), redis.lpop("missing")]
+			`, []interface{}{"a", "c", 1, nil}},
+		{`
+			redis.call("SET", "raw", 1.5)
+			redis.call("GET", "raw")
+			`, "1.5"},
+		{`
+			redis.set("big", 2 ** 70)
+			redis.get("big")
+			`, "1180591620717411303424"},
+	}
+
+	for i, tt := range tests {
+		_, address := startFakeServer(t, "")
+		evaluated := vm.ExecAndReturn(t, fmt.Sprintf(`
+		require "redis"
+
+		redis = Redis.new("%s")
+		`, address)+tt.input)
+		vm.VerifyExpected(t, i, evaluated, tt.expected)
+	}
+}
+
+func TestRedisPipelining(t *testing.T) {
+	server, address := startFakeServer(t, "")
+	evaluated := vm.ExecAndReturn(t, fmt.Sprintf(`
+	require "redis"
+
+	redis = Redis.new("%s")
+	redis.pipelined do |p|
+	  p.set("a", 1)
+	  p.incr("a")
+	  p.exists?("a")
+	  p.rpush("list", "x", "y")
+	  p.get("a")
+	end
+	`, address))
+	vm.VerifyExpected(t, 0, evaluated, []interface{}{"OK", 2, true, 2, "2"})
+
+	if server.commandCount("INCR") != 1 {
+		t.Fatalf("Expect the pipeline to send INCR once. got: %d", server.commandCount("INCR"))
+	}
+
+	// The commands after an error reply still run, and the error is raised once all replies are read
+	evaluated = vm.ExecAndReturn(t, fmt.Sprintf(`
+	require "redis"
+
+	redis = Redis.new("%s")
+	redis.pipelined do |p|
+	  p.set("name", "Stan")
+	  p.incr("name")
+	  p.set("after", "yes")
+	end
+	`, address))
+	err, ok := evaluated.(*vm.Error)
+
+	if !ok || !strings.HasPrefix(err.Message(), "InternalError: ERR value is not an integer or out of range") {
+		t.Fatalf("Expect the pipeline to raise the error reply. got: %v", evaluated)
+	}
+
+	evaluated = vm.ExecAndReturn(t, fmt.Sprintf(`
+	require "redis"
+
+	redis = Redis.new("%s")
+	[redis.get("after"), redis.pipelined do |p| end]
+	`, address))
+	vm.VerifyExpected(t, 1, evaluated, []interface{}{"yes", []interface{}{}})
+}
+
+func TestRedisSubscribe(t *testing.T) {
+	_, address := startFakeServer(t, "")
+	evaluated := vm.ExecAndReturn(t, fmt.Sprintf(`
+	require "redis"
+
+	redis = Redis.new("%s")
+	subscription = redis.subscribe("news", "weather")
+	receivers = redis.publish("news", "hello")
+	redis.publish("sports", "ignored")
+	redis.publish("weather", "sunny")
+
+	first = subscription.receive
+	second = subscription.channel.receive
+	subscription.close
+
+	[receivers, first[:channel], first[:message], second[:channel], second[:message]]
+	`, address))
+	vm.VerifyExpected(t, 0, evaluated, []interface{}{1, "news", "hello", "weather", "sunny"})
+}
+
+func TestRedisConnectionOptions(t *testing.T) {
+	server, address := startFakeServer(t, "secret")
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{fmt.Sprintf(`Redis.new("%s", password: "secret", db: 2, timeout: 1).ping`, address), "PONG"},
+		{fmt.Sprintf(`Redis.new("redis://:secret@%s/2").ping`, address), "PONG"},
+	}
+
+	for i, tt := range tests {
+		evaluated := vm.ExecAndReturn(t, `require "redis"`+"\n"+tt.input)
+		vm.VerifyExpected(t, i, evaluated, tt.expected)
+	}
+
+	if server.commandCount("SELECT") != 2 {
+		t.Fatalf("Expect the database to be selected twice. got: %d", server.commandCount("SELECT"))
+	}
+}
+
+func TestRedisFailures(t *testing.T) {
+	_, address := startFakeServer(t, "secret")
+	_, openAddress := startFakeServer(t, "")
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{fmt.Sprintf(`Redis.new("%s", password: "wrong")`, address), "InternalError: WRONGPASS"},
+		{fmt.Sprintf(`Redis.new("%s").ping`, address), "InternalError: NOAUTH"},
+		{fmt.Sprintf(`Redis.new("%s", timeout: "1")`, address), "ArgumentError: Expect timeout to be an Integer or a Float. got: String"},
+		{fmt.Sprintf(`Redis.new("%s", timeout: 2 ** 70)`, address), "ArgumentError: Expect timeout to be between 0 and 9223372036 seconds. got: 1180591620717411303424"},
+		{fmt.Sprintf(`Redis.new("%s", timeout: -1)`, address), "ArgumentError: Expect timeout to be between 0 and 9223372036 seconds. got: -1"},
+		{fmt.Sprintf(`Redis.new("%s", db: 2 ** 64)`, address), "ArgumentError: Expect db to be between 0 and 2147483647. got: 18446744073709551616"},
+		{fmt.Sprintf(`Redis.new("redis://%s/first")`, address), "ArgumentError: Invalid database number: first"},
+		{fmt.Sprintf(`
+			redis = Redis.new("%s")
+			redis.rpush("list", "a")
+			redis.get("list")
+			`, openAddress), "InternalError: WRONGTYPE"},
+		{fmt.Sprintf(`Redis.new("%s").set("key", [1])`, openAddress), "TypeError: Can't send Array as a command argument"},
+		{fmt.Sprintf(`Redis.new("%s").call("NOPE")`, openAddress), "InternalError: ERR unknown command 'NOPE'"},
+		{fmt.Sprintf(`
+			redis = Redis.new("%s")
+			redis.close
+			redis.ping
+			`, openAddress), "InternalError: Redis connection is closed"},
+	}
+
+	for i, tt := range tests {
+		evaluated := vm.ExecAndReturn(t, `require "redis"`+"\n"+tt.input)
+		err, ok := evaluated.(*vm.Error)
+
+		if !ok || !strings.HasPrefix(err.Message(), tt.expected) {
+			t.Fatalf("At test case %d: Expect error starting with %q. got: %v", i, tt.expected, evaluated)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package redis
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// RESP2 replies are decoded into:
+//
+// - simple strings into string
+// - errors into respError
+// - integers into int64
+// - bulk strings into []byte, or nil for the null bulk string
+// - arrays into []interface{}, or nil for the null array
+//
+// See https://redis.io/docs/reference/protocol-spec/
+type respError string
+
+func (e respError) Error() string {
+	return string(e)
+}
+
+// writeCommand encodes a command as an array of bulk strings, which is how clients send commands.
+func writeCommand(w *bufio.Writer, args []string) error {
+	_, err := fmt.Fprintf(w, "*%d\r\n", len(args))
+
+	if err != nil {
+		return err
+	}
+
+	for _, arg := range args {
+		_, err = fmt.Fprintf(w, "$%d\r\n%s\r\n", len(arg), arg)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := readLine(r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(line) == 0 {
+		return nil, fmt.Errorf("Invalid RESP reply: empty line")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return respError(line[1:]), nil
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid RESP bulk string length: %s", line[1:])
+		}
+
+		if size < 0 {
+			return nil, nil
+		}
+
+		// The bulk string is followed by CRLF
+		buf := make([]byte, size+2)
+		_, err = io.ReadFull(r, buf)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return buf[:size], nil
+	case '*':
+		count, err := strconv.Atoi(line[1:])
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid RESP array length: %s", line[1:])
+		}
+
+		if count < 0 {
+			return nil, nil
+		}
+
+		elems := make([]interface{}, count)
+
+		for i := range elems {
+			elems[i], err = readReply(r)
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return elems, nil
+	default:
+		return nil, fmt.Errorf("Invalid RESP reply type: %q", line[0])
+	}
+}
+
+// readLine reads a CRLF terminated line without its terminator.
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+
+	if err != nil {
+		return "", err
+	}
+
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", fmt.Errorf("Invalid RESP line: %q", line)
+	}
+
+	return line[:len(line)-2], nil
+}
@@ -0,0 +1,163 @@
+package redis
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/goby-lang/goby/vm"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// subscription owns the connection a SUBSCRIBE command was sent on, since Redis only accepts
+// subscription commands on it afterwards.
+type subscription struct {
+	conn net.Conn
+	done chan struct{}
+	once sync.Once
+}
+
+// The open_subscription method subscribes to an Array of Redis channels on a new connection, and
+// delivers their messages to the given Goby Channel as Hashes with `channel` and `message` keys.
+// It returns the subscription object. See Redis#subscribe in redis.gb file.
+//
+// @return [Object]
+//
+func openSubscription(c *vm.Call) Object {
+	cl, err := getClient(c.Receiver)
+
+	if err != nil {
+		return c.Error(errors.InternalError, err.Error())
+	}
+
+	channels, err := commandArgs(c.Arg(0))
+
+	if err != nil {
+		return c.Error(errors.TypeError, err.Error())
+	}
+
+	conn, reader, writer, err := dial(cl.options)
+
+	if err != nil {
+		return c.Error(errors.InternalError, err.Error())
+	}
+
+	err = subscribe(conn, reader, writer, channels, cl.options.timeout)
+
+	if err != nil {
+		conn.Close()
+		return c.Error(errors.InternalError, err.Error())
+	}
+
+	s := &subscription{conn: conn, done: make(chan struct{})}
+	go s.receive(c.VM(), reader, c.Arg(1).(*vm.ChannelObject))
+
+	return c.Return(s)
+}
+
+// The close_subscription method unsubscribes by closing the subscription's connection. Messages
+// that aren't received yet are dropped.
+//
+// @return [Boolean]
+//
+func closeSubscription(c *vm.Call) Object {
+	s, ok := c.GoValue(0).(*subscription)
+
+	if !ok {
+		return c.Error(errors.TypeError, "Expect a Redis subscription")
+	}
+
+	s.close()
+	return vm.TRUE
+}
+
+// subscribe sends the SUBSCRIBE command and waits for the server to confirm each channel.
+func subscribe(conn net.Conn, reader *bufio.Reader, writer *bufio.Writer, channels []string, timeout time.Duration) error {
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
+	conn.SetDeadline(time.Now().Add(timeout))
+	defer conn.SetDeadline(time.Time{})
+
+	err := writeCommand(writer, append([]string{"SUBSCRIBE"}, channels...))
+
+	if err == nil {
+		err = writer.Flush()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for range channels {
+		reply, err := readReply(reader)
+
+		if err != nil {
+			return err
+		}
+
+		if err, ok := reply.(respError); ok {
+			return err
+		}
+
+		if kind, _ := pushKind(reply); kind != "subscribe" {
+			return fmt.Errorf("Unexpected reply to SUBSCRIBE: %v", reply)
+		}
+	}
+
+	return nil
+}
+
+// receive delivers the subscription's messages until it's closed, or its connection or Goby channel is.
+func (s *subscription) receive(v *vm.VM, reader *bufio.Reader, ch *vm.ChannelObject) {
+	defer s.close()
+
+	for {
+		reply, err := readReply(reader)
+
+		if err != nil {
+			return
+		}
+
+		kind, elems := pushKind(reply)
+
+		if kind != "message" || len(elems) != 3 {
+			continue
+		}
+
+		channel, _ := replyObject(v, elems[1])
+		message, _ := replyObject(v, elems[2])
+		msg := v.InitHashObject(map[string]Object{"channel": channel, "message": message})
+
+		if !ch.Deliver(v, msg, s.done) {
+			return
+		}
+	}
+}
+
+func (s *subscription) close() {
+	s.once.Do(func() {
+		close(s.done)
+		s.conn.Close()
+	})
+}
+
+// pushKind returns the kind of a pushed message, like "subscribe" or "message", and its elements.
+func pushKind(reply interface{}) (string, []interface{}) {
+	elems, ok := reply.([]interface{})
+
+	if !ok || len(elems) == 0 {
+		return "", nil
+	}
+
+	kind, ok := elems[0].([]byte)
+
+	if !ok {
+		return "", nil
+	}
+
+	return string(kind), elems
+}
@@ -0,0 +1,6 @@
+CREATE TABLE users (
+  id   integer PRIMARY KEY,
+  name varchar(40),
+  admin boolean DEFAULT false
+);
+CREATE INDEX users_name ON users (name);
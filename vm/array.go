@@ -2,6 +2,9 @@ package vm
 
 import (
 	"bytes"
+	"math/big"
+	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/goby-lang/goby/vm/classes"
@@ -120,7 +123,13 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
 					}
 
-					return arr.concatenateCopies(t, copiesNumber)
+					copies, err := copiesNumber.intValue(t, sourceLine)
+
+					if err != nil {
+						return err
+					}
+
+					return arr.concatenateCopies(t, copies)
 				}
 			},
 		},
@@ -243,7 +252,12 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
 					}
 
-					indexValue := index.value
+					indexValue, err := index.intValue(t, sourceLine)
+
+					if err != nil {
+						return err
+					}
+
 					arr := receiver.(*ArrayObject)
 
 					// <Three Argument Case>
@@ -252,10 +266,10 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 					if len(args) == 3 {
 						// Negative index value too small
 						if indexValue < 0 {
-							if arr.normalizeIndex(index) == -1 {
+							if arr.normalizeIndex(indexValue) == -1 {
 								return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Index value %d too small for array. minimum: %d", indexValue, -arr.length())
 							}
-							indexValue = arr.normalizeIndex(index)
+							indexValue = arr.normalizeIndex(indexValue)
 						}
 
 						c := args[1]
@@ -266,7 +280,12 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 							return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[1].Class().Name)
 						}
 
-						countValue := count.value
+						countValue, err := count.intValue(t, sourceLine)
+
+						if err != nil {
+							return err
+						}
+
 						// Second argument must be a positive value
 						if countValue < 0 {
 							return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect second argument greater than or equal 0. got: %d", countValue)
@@ -290,10 +309,10 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 							return a
 						}
 
-						endValue := indexValue + countValue
-						// the case the addition of index and count is too large
-						if endValue > arr.length() {
-							endValue = arr.length()
+						endValue := arr.length()
+						// the addition of index and count can be too large, or even overflow
+						if countValue < endValue-indexValue {
+							endValue = indexValue + countValue
 						}
 
 						arr.Elements = append(arr.Elements[:indexValue], arr.Elements[endValue:]...)
@@ -314,7 +333,7 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 
 					// Negative index value condition
 					if indexValue < 0 {
-						if len(arr.Elements) < -indexValue {
+						if indexValue < -len(arr.Elements) {
 							return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Index value %d too small for array. minimum: %d", indexValue, -arr.length())
 						}
 						arr.Elements[len(arr.Elements)+indexValue] = args[1]
@@ -585,8 +604,14 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
 					}
 
+					indexValue, err := index.intValue(t, sourceLine)
+
+					if err != nil {
+						return err
+					}
+
 					arr := receiver.(*ArrayObject)
-					normalizedIndex := arr.normalizeIndex(index)
+					normalizedIndex := arr.normalizeIndex(indexValue)
 
 					if normalizedIndex == -1 {
 						return NULL
@@ -727,6 +752,117 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 				}
 			},
 		},
+		{
+			// Yields the elements in slices of the given size, the last of which can be shorter.
+			// Returns self. A block literal is required.
+			//
+			// ```ruby
+			// [1, 2, 3, 4, 5].each_slice(2) do |slice|
+			//   puts(slice)
+			// end
+			// #=> [1, 2]
+			// #=> [3, 4]
+			// #=> [5]
+			// ```
+			//
+			// @param size [Integer], block literal
+			// @return [Array]
+			Name: "each_slice",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					size, ok := args[0].(*IntegerObject)
+
+					if !ok {
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+					}
+
+					sizeValue, err := size.intValue(t, sourceLine)
+
+					if err != nil {
+						return err
+					}
+
+					if sizeValue <= 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect slice size to be positive. got: %d", sizeValue)
+					}
+
+					if blockFrame == nil {
+						return t.vm.InitErrorObject(errors.InternalError, sourceLine, errors.CantYieldWithoutBlockFormat)
+					}
+
+					arr := receiver.(*ArrayObject)
+					if blockIsEmpty(blockFrame) {
+						return arr
+					}
+
+					// If it's an empty array, pop the block's call frame
+					if len(arr.Elements) == 0 {
+						t.callFrameStack.pop()
+					}
+
+					for i := 0; i < len(arr.Elements); i += sizeValue {
+						end := i + sizeValue
+
+						if end > len(arr.Elements) {
+							end = len(arr.Elements)
+						}
+
+						slice := make([]Object, end-i)
+						copy(slice, arr.Elements[i:end])
+						t.builtinMethodYield(blockFrame, t.vm.InitArrayObject(slice))
+					}
+
+					return arr
+				}
+			},
+		},
+		{
+			// Works like #each, but also passes the index of the element to the block.
+			// Returns self. A block literal is required.
+			//
+			// ```ruby
+			// ["a", "b"].each_with_index do |e, i|
+			//   puts(e + i.to_s)
+			// end
+			// #=> "a0"
+			// #=> "b1"
+			// ```
+			//
+			// @param block literal
+			// @return [Array]
+			Name: "each_with_index",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					if blockFrame == nil {
+						return t.vm.InitErrorObject(errors.InternalError, sourceLine, errors.CantYieldWithoutBlockFormat)
+					}
+
+					arr := receiver.(*ArrayObject)
+					if blockIsEmpty(blockFrame) {
+						return arr
+					}
+
+					// If it's an empty array, pop the block's call frame
+					if len(arr.Elements) == 0 {
+						t.callFrameStack.pop()
+					}
+
+					for i, obj := range arr.Elements {
+						t.builtinMethodYield(blockFrame, obj, t.vm.InitIntegerObject(i))
+					}
+
+					return arr
+				}
+			},
+		},
 		{
 			// A predicate method.
 			// Returns if the array"s length is 0 or not.
@@ -756,6 +892,54 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 				}
 			},
 		},
+		{
+			// Returns the first element for which the block returns a truthy value, or `nil` if there
+			// isn't one.
+			//
+			// ```ruby
+			// [1, 2, 3, 4].find do |e|
+			//   e > 2
+			// end            #=> 3
+			// [1, 2].find do |e|
+			//   e > 2
+			// end            #=> nil
+			// ```
+			//
+			// @param block literal
+			// @return [Object]
+			Name: "find",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					if blockFrame == nil {
+						return t.vm.InitErrorObject(errors.InternalError, sourceLine, errors.CantYieldWithoutBlockFormat)
+					}
+
+					arr := receiver.(*ArrayObject)
+					if blockIsEmpty(blockFrame) {
+						return NULL
+					}
+
+					// If it's an empty array, pop the block's call frame
+					if len(arr.Elements) == 0 {
+						t.callFrameStack.pop()
+					}
+
+					for _, obj := range arr.Elements {
+						result := t.builtinMethodYield(blockFrame, obj)
+
+						if result.Target.isTruthy() {
+							return obj
+						}
+					}
+
+					return NULL
+				}
+			},
+		},
 		{
 			// Returns the first element of the array.
 			// If a count 'n' is provided as an argument, it returns the array of the first n elements.
@@ -792,17 +976,74 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
 					}
 
-					if arg.value < 1 {
-						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect argument to be positive value. got=%d", arg.value)
+					n, err := arg.intValue(t, sourceLine)
+
+					if err != nil {
+						return err
+					}
+
+					if n < 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect argument to be positive value. got=%d", n)
 					}
 
-					if arrLength > arg.value {
-						return t.vm.InitArrayObject(arr.Elements[:arg.value])
+					if arrLength > n {
+						return t.vm.InitArrayObject(arr.Elements[:n])
 					}
 					return arr
 				}
 			},
 		},
+		{
+			// Works like #map, but concatenates the arrays the block returns into one array.
+			//
+			// ```ruby
+			// [1, 2].flat_map do |e|
+			//   [e, e * 10]
+			// end            #=> [1, 10, 2, 20]
+			// [1, 2].flat_map do |e|
+			//   e
+			// end            #=> [1, 2]
+			// ```
+			//
+			// @param block literal
+			// @return [Array]
+			Name: "flat_map",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					if blockFrame == nil {
+						return t.vm.InitErrorObject(errors.InternalError, sourceLine, errors.CantYieldWithoutBlockFormat)
+					}
+
+					arr := receiver.(*ArrayObject)
+					elements := []Object{}
+
+					if blockIsEmpty(blockFrame) {
+						return t.vm.InitArrayObject(elements)
+					}
+
+					// If it's an empty array, pop the block's call frame
+					if len(arr.Elements) == 0 {
+						t.callFrameStack.pop()
+					}
+
+					for _, obj := range arr.Elements {
+						result := t.builtinMethodYield(blockFrame, obj)
+
+						if a, ok := result.Target.(*ArrayObject); ok {
+							elements = append(elements, a.Elements...)
+						} else {
+							elements = append(elements, result.Target)
+						}
+					}
+
+					return t.vm.InitArrayObject(elements)
+				}
+			},
+		},
 		{
 			// Returns a new array that is a one-dimensional flattening of self.
 			//
@@ -832,6 +1073,82 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 				}
 			},
 		},
+		{
+			// Groups the elements by the value the block returns, and returns a hash of the groups.
+			// Since hash keys are strings, a key that isn't a string is converted with `#to_s`.
+			//
+			// ```ruby
+			// a = ["apple", "avocado", "banana"]
+			// a.group_by do |e|
+			//   e[0]
+			// end            #=> { a: ["apple", "avocado"], b: ["banana"] }
+			// g = [1, 2, 3].group_by do |e|
+			//   e % 2
+			// end
+			// g["1"]         #=> [1, 3]
+			// ```
+			//
+			// @param block literal
+			// @return [Hash]
+			Name: "group_by",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					if blockFrame == nil {
+						return t.vm.InitErrorObject(errors.InternalError, sourceLine, errors.CantYieldWithoutBlockFormat)
+					}
+
+					arr := receiver.(*ArrayObject)
+					groups := map[string]Object{}
+
+					if blockIsEmpty(blockFrame) {
+						return t.vm.InitHashObject(groups)
+					}
+
+					// If it's an empty array, pop the block's call frame
+					if len(arr.Elements) == 0 {
+						t.callFrameStack.pop()
+					}
+
+					for _, obj := range arr.Elements {
+						key := t.builtinMethodYield(blockFrame, obj).Target.toString()
+						group, ok := groups[key].(*ArrayObject)
+
+						if !ok {
+							group = t.vm.InitArrayObject([]Object{})
+							groups[key] = group
+						}
+
+						group.Elements = append(group.Elements, obj)
+					}
+
+					return t.vm.InitHashObject(groups)
+				}
+			},
+		},
+		{
+			// Returns a hash of self from the hashes of its elements, including the ones of user-defined `hash`
+			// methods, so Arrays with the same elements in the same order have the same hash.
+			//
+			// ```ruby
+			// [1, "a"].hash == [1, "a"].hash # => true
+			// [1, "a"].hash == ["a", 1].hash # => false
+			// ```
+			// @return [Integer]
+			Name: "hash",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return t.hashValue(receiver, sourceLine)
+				}
+			},
+		},
 		{
 			// Returns a string by concatenating each element to string, separated by given separator.
 			// If the array is nested, they will be flattened and then concatenated.
@@ -907,12 +1224,18 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
 					}
 
-					if arg.value < 1 {
-						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect argument to be positive value. got=%d", arg.value)
+					n, err := arg.intValue(t, sourceLine)
+
+					if err != nil {
+						return err
+					}
+
+					if n < 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect argument to be positive value. got=%d", n)
 					}
 
-					if arrLength > arg.value {
-						return t.vm.InitArrayObject(arr.Elements[arrLength-arg.value : arrLength])
+					if arrLength > n {
+						return t.vm.InitArrayObject(arr.Elements[arrLength-n : arrLength])
 					}
 					return arr
 				}
@@ -994,60 +1317,221 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 			},
 		},
 		{
-			// A destructive method.
-			// Removes the last element in the array and returns it.
+			// Returns the largest element, or `nil` if the array is empty. Elements are compared like #sort
+			// compares them. A block that compares two elements like `<=>` can be given.
 			//
 			// ```ruby
-			// a = [1, 2, 3]
-			// a.pop #=> 3
-			// a     #=> [1, 2]
+			// [1, 5, 3].max      #=> 5
+			// ["b", "c", "a"].max #=> "c"
+			// ["aa", "b"].max do |a, b|
+			//   a.length - b.length
+			// end                #=> "aa"
+			// [].max             #=> nil
 			// ```
 			//
+			// @param block literal
 			// @return [Object]
-			Name: "pop",
+			Name: "max",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-
 					if len(args) != 0 {
-						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect 0 argument. got=%d", len(args))
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
 					}
 
 					arr := receiver.(*ArrayObject)
-					return arr.pop()
+					return arr.extremum(t, blockFrame, 1, sourceLine)
 				}
 			},
 		},
 		{
-			// A destructive method.
-			// Appends the given object to the array and returns the array.
-			// One or more arguments can be passed to the method.
-			// If no argument have been given, nothing will be added to the array,
-			// and returns the unchanged array.
-			// Even `nil` or empty strings `""` will be added to the array.
+			// Returns the element for which the block returns the largest value, or `nil` if the array is
+			// empty. The values are compared like #sort compares elements. The first of equal elements wins.
 			//
 			// ```ruby
-			// a = [1, 2, 3]
-			// a.push(4)       #=> [1, 2, 3, 4]
-			// a.push(5, 6, 7) #=> [1, 2, 3, 4, 5, 6, 7]
-			// a.push          #=> [1, 2, 3, 4, 5, 6, 7]
-			// a               #=> [1, 2, 3, 4, 5, 6, 7]
-			// a.push(nil, "") #=> [1, 2, 3, 4, 5, 6, 7, nil, ""]
+			// ["a", "ccc", "bb"].max_by do |e|
+			//   e.length
+			// end            #=> "ccc"
 			// ```
 			//
-			// @param object [Object]...
-			// @return [Array]
-			Name: "push",
+			// @param block literal
+			// @return [Object]
+			Name: "max_by",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					if blockFrame == nil {
+						return t.vm.InitErrorObject(errors.InternalError, sourceLine, errors.CantYieldWithoutBlockFormat)
+					}
 
 					arr := receiver.(*ArrayObject)
-					return arr.push(args)
+					return arr.extremumBy(t, blockFrame, 1, sourceLine)
 				}
 			},
 		},
 		{
-			// Accumulates the given argument and the results from evaluating each elements
-			// with the first block parameter of the given block.
+			// Returns the smallest element, or `nil` if the array is empty. Elements are compared like #sort
+			// compares them. A block that compares two elements like `<=>` can be given.
+			//
+			// ```ruby
+			// [3, 1, 5].min      #=> 1
+			// ["b", "c", "a"].min #=> "a"
+			// ["aa", "b"].min do |a, b|
+			//   a.length - b.length
+			// end                #=> "b"
+			// [].min             #=> nil
+			// ```
+			//
+			// @param block literal
+			// @return [Object]
+			Name: "min",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					arr := receiver.(*ArrayObject)
+					return arr.extremum(t, blockFrame, -1, sourceLine)
+				}
+			},
+		},
+		{
+			// Returns the element for which the block returns the smallest value, or `nil` if the array is
+			// empty. The values are compared like #sort compares elements. The first of equal elements wins.
+			//
+			// ```ruby
+			// ["a", "ccc", "bb"].min_by do |e|
+			//   e.length
+			// end            #=> "a"
+			// ```
+			//
+			// @param block literal
+			// @return [Object]
+			Name: "min_by",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					if blockFrame == nil {
+						return t.vm.InitErrorObject(errors.InternalError, sourceLine, errors.CantYieldWithoutBlockFormat)
+					}
+
+					arr := receiver.(*ArrayObject)
+					return arr.extremumBy(t, blockFrame, -1, sourceLine)
+				}
+			},
+		},
+		{
+			// Splits the array into two arrays: the elements for which the block returns a truthy value,
+			// and the rest.
+			//
+			// ```ruby
+			// [1, 2, 3, 4].partition do |e|
+			//   e % 2 == 0
+			// end            #=> [[2, 4], [1, 3]]
+			// ```
+			//
+			// @param block literal
+			// @return [Array]
+			Name: "partition",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					if blockFrame == nil {
+						return t.vm.InitErrorObject(errors.InternalError, sourceLine, errors.CantYieldWithoutBlockFormat)
+					}
+
+					arr := receiver.(*ArrayObject)
+					selected := []Object{}
+					rejected := []Object{}
+
+					if blockIsEmpty(blockFrame) {
+						rejected = append(rejected, arr.Elements...)
+						return t.vm.InitArrayObject([]Object{t.vm.InitArrayObject(selected), t.vm.InitArrayObject(rejected)})
+					}
+
+					// If it's an empty array, pop the block's call frame
+					if len(arr.Elements) == 0 {
+						t.callFrameStack.pop()
+					}
+
+					for _, obj := range arr.Elements {
+						result := t.builtinMethodYield(blockFrame, obj)
+
+						if result.Target.isTruthy() {
+							selected = append(selected, obj)
+						} else {
+							rejected = append(rejected, obj)
+						}
+					}
+
+					return t.vm.InitArrayObject([]Object{t.vm.InitArrayObject(selected), t.vm.InitArrayObject(rejected)})
+				}
+			},
+		},
+		{
+			// A destructive method.
+			// Removes the last element in the array and returns it.
+			//
+			// ```ruby
+			// a = [1, 2, 3]
+			// a.pop #=> 3
+			// a     #=> [1, 2]
+			// ```
+			//
+			// @return [Object]
+			Name: "pop",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect 0 argument. got=%d", len(args))
+					}
+
+					arr := receiver.(*ArrayObject)
+					return arr.pop()
+				}
+			},
+		},
+		{
+			// A destructive method.
+			// Appends the given object to the array and returns the array.
+			// One or more arguments can be passed to the method.
+			// If no argument have been given, nothing will be added to the array,
+			// and returns the unchanged array.
+			// Even `nil` or empty strings `""` will be added to the array.
+			//
+			// ```ruby
+			// a = [1, 2, 3]
+			// a.push(4)       #=> [1, 2, 3, 4]
+			// a.push(5, 6, 7) #=> [1, 2, 3, 4, 5, 6, 7]
+			// a.push          #=> [1, 2, 3, 4, 5, 6, 7]
+			// a               #=> [1, 2, 3, 4, 5, 6, 7]
+			// a.push(nil, "") #=> [1, 2, 3, 4, 5, 6, 7, nil, ""]
+			// ```
+			//
+			// @param object [Object]...
+			// @return [Array]
+			Name: "push",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+
+					arr := receiver.(*ArrayObject)
+					return arr.push(args)
+				}
+			},
+		},
+		{
+			// Accumulates the given argument and the results from evaluating each elements
+			// with the first block parameter of the given block.
 			// Takes one block with two block arguments (less than two block arguments are meaningless).
 			// The first block argument is to succeed the initial value or previous result,
 			// and the second block arguments is to enumerate the elements of the array.
@@ -1227,7 +1711,11 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 						if !ok {
 							return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
 						}
-						rotate = arg.value
+
+						var err *Error
+						if rotate, err = arg.intValue(t, sourceLine); err != nil {
+							return err
+						}
 					}
 
 					if rotate < 0 {
@@ -1319,6 +1807,303 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 				}
 			},
 		},
+		{
+			// Returns a new array with the elements sorted. Integers, Floats and Strings are compared by
+			// their values, and other objects with their `<=>` method, like the ones including Comparable.
+			// A block that compares two elements like `<=>` can be given instead, which returns a negative
+			// Integer, 0 or a positive Integer. The sort is stable: equal elements keep their order.
+			//
+			// ```ruby
+			// [3, 1, 2].sort   #=> [1, 2, 3]
+			// [1.5, 1, 3].sort #=> [1, 1.5, 3]
+			// ["b", "a"].sort  #=> ["a", "b"]
+			// [1, 2, 3].sort do |a, b|
+			//   b - a
+			// end              #=> [3, 2, 1]
+			// [1, "a"].sort    #=> ArgumentError
+			// ```
+			//
+			// @param block literal
+			// @return [Array]
+			Name: "sort",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					arr := receiver.(*ArrayObject)
+					elements, err := arr.sortedElements(t, blockFrame, sourceLine)
+
+					if err != nil {
+						return err
+					}
+
+					return t.vm.InitArrayObject(elements)
+				}
+			},
+		},
+		{
+			// A destructive method.
+			// Sorts the array in place like #sort does, and returns it.
+			//
+			// ```ruby
+			// a = [3, 1, 2]
+			// a.sort!
+			// a        #=> [1, 2, 3]
+			// a.sort! do |x, y|
+			//   y - x
+			// end      #=> [3, 2, 1]
+			// ```
+			//
+			// @param block literal
+			// @return [Array]
+			Name: "sort!",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					arr := receiver.(*ArrayObject)
+					elements, err := arr.sortedElements(t, blockFrame, sourceLine)
+
+					if err != nil {
+						return err
+					}
+
+					arr.Elements = elements
+					return arr
+				}
+			},
+		},
+		{
+			// Returns a new array with the elements sorted by the values the block returns for them,
+			// which are compared like #sort compares elements. The sort is stable.
+			//
+			// ```ruby
+			// ["ccc", "a", "bb"].sort_by do |e|
+			//   e.length
+			// end            #=> ["a", "bb", "ccc"]
+			// ```
+			//
+			// @param block literal
+			// @return [Array]
+			Name: "sort_by",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					if blockFrame == nil {
+						return t.vm.InitErrorObject(errors.InternalError, sourceLine, errors.CantYieldWithoutBlockFormat)
+					}
+
+					arr := receiver.(*ArrayObject)
+					elements := make([]Object, len(arr.Elements))
+					copy(elements, arr.Elements)
+
+					if blockIsEmpty(blockFrame) {
+						return t.vm.InitArrayObject(elements)
+					}
+
+					// If it's an empty array, pop the block's call frame
+					if len(arr.Elements) == 0 {
+						t.callFrameStack.pop()
+					}
+
+					keys := make([]Object, len(elements))
+
+					for i, obj := range elements {
+						keys[i] = t.builtinMethodYield(blockFrame, obj).Target
+					}
+
+					indexes := make([]int, len(elements))
+
+					for i := range indexes {
+						indexes[i] = i
+					}
+
+					var err *Error
+
+					sort.SliceStable(indexes, func(i, j int) bool {
+						if err != nil {
+							return false
+						}
+
+						var result int
+						result, err = t.compare(keys[indexes[i]], keys[indexes[j]], sourceLine)
+						return result < 0
+					})
+
+					if err != nil {
+						return err
+					}
+
+					for i, index := range indexes {
+						elements[i] = arr.Elements[index]
+					}
+
+					return t.vm.InitArrayObject(elements)
+				}
+			},
+		},
+		{
+			// Returns the sum of the elements, which are added to the initial value with `+`. The initial
+			// value defaults to 0. When a block is given, the values it returns for the elements are summed.
+			//
+			// ```ruby
+			// [1, 2, 3].sum          #=> 6
+			// [1, 2.5].sum           #=> 3.5
+			// [1, 2, 3].sum(10)      #=> 16
+			// ["a", "b"].sum("")     #=> "ab"
+			// ["a", "bb"].sum do |e|
+			//   e.length
+			// end                    #=> 3
+			// ```
+			//
+			// @param initial value [Object], block literal
+			// @return [Object]
+			Name: "sum",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) > 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRangeFormat, 0, 1, len(args))
+					}
+
+					arr := receiver.(*ArrayObject)
+					var sum Object = t.vm.InitIntegerObject(0)
+
+					if len(args) == 1 {
+						sum = args[0]
+					}
+
+					// If it's an empty array, pop the block's call frame
+					if blockFrame != nil && len(arr.Elements) == 0 {
+						t.callFrameStack.pop()
+					}
+
+					for _, obj := range arr.Elements {
+						if blockFrame != nil {
+							obj = t.builtinMethodYield(blockFrame, obj).Target
+						}
+
+						sum = t.add(sum, obj, sourceLine)
+					}
+
+					return sum
+				}
+			},
+		},
+		{
+			// Returns the elements before the first one for which the block returns a falsy value.
+			//
+			// ```ruby
+			// [1, 2, 3, 1].take_while do |e|
+			//   e < 3
+			// end            #=> [1, 2]
+			// ```
+			//
+			// @param block literal
+			// @return [Array]
+			Name: "take_while",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					if blockFrame == nil {
+						return t.vm.InitErrorObject(errors.InternalError, sourceLine, errors.CantYieldWithoutBlockFormat)
+					}
+
+					arr := receiver.(*ArrayObject)
+					elements := []Object{}
+
+					if blockIsEmpty(blockFrame) {
+						return t.vm.InitArrayObject(elements)
+					}
+
+					// If it's an empty array, pop the block's call frame
+					if len(arr.Elements) == 0 {
+						t.callFrameStack.pop()
+					}
+
+					for _, obj := range arr.Elements {
+						result := t.builtinMethodYield(blockFrame, obj)
+
+						if !result.Target.isTruthy() {
+							break
+						}
+
+						elements = append(elements, obj)
+					}
+
+					return t.vm.InitArrayObject(elements)
+				}
+			},
+		},
+		{
+			// Returns a new array without duplicated elements, keeping the first of them. When a block is
+			// given, elements are duplicates if the block returns equal values for them.
+			//
+			// ```ruby
+			// [1, 2, 1, "1"].uniq #=> [1, 2, "1"]
+			// ["a", "bb", "c"].uniq do |e|
+			//   e.length
+			// end                 #=> ["a", "bb"]
+			// ```
+			//
+			// @param block literal
+			// @return [Array]
+			Name: "uniq",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					arr := receiver.(*ArrayObject)
+					elements := []Object{}
+					// seen groups the kept elements' keys by class and string value, so only the keys
+					// in the same group need to be compared
+					seen := map[string][]Object{}
+
+					// If it's an empty array, pop the block's call frame
+					if blockFrame != nil && len(arr.Elements) == 0 {
+						t.callFrameStack.pop()
+					}
+
+				elements:
+					for _, obj := range arr.Elements {
+						key := obj
+
+						if blockFrame != nil {
+							key = t.builtinMethodYield(blockFrame, obj).Target
+						}
+
+						// Decimals are duplicates of the Integers of their values
+						if d, ok := key.(*DecimalObject); ok && d.value.IsInt() {
+							key = t.vm.initBigIntegerObject(new(big.Int).Set(d.value.Num()))
+						}
+
+						group := key.Class().Name + ":" + key.toString()
+
+						for _, k := range seen[group] {
+							if reflect.DeepEqual(k, key) {
+								continue elements
+							}
+						}
+
+						seen[group] = append(seen[group], key)
+						elements = append(elements, obj)
+					}
+
+					return t.vm.InitArrayObject(elements)
+				}
+			},
+		},
 		{
 			// A destructive method.
 			// Inserts one or more arguments at the first position of the array, and then returns the self.
@@ -1368,15 +2153,64 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 							return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, arg.Class().Name)
 						}
 
-						if index.value >= len(arr.Elements) {
-							elements[i] = NULL
-						} else if index.value < 0 && -index.value > len(arr.Elements) {
+						indexValue, err := index.intValue(t, sourceLine)
+
+						if err != nil {
+							return err
+						}
+
+						if normalized := arr.normalizeIndex(indexValue); normalized == -1 {
 							elements[i] = NULL
-						} else if index.value < 0 {
-							elements[i] = arr.Elements[len(arr.Elements)+index.value]
 						} else {
-							elements[i] = arr.Elements[index.value]
+							elements[i] = arr.Elements[normalized]
+						}
+					}
+
+					return t.vm.InitArrayObject(elements)
+				}
+			},
+		},
+		{
+			// Merges the elements of the given arrays into the elements at the same indexes, and returns
+			// an array of the resulting arrays. Missing elements are `nil`.
+			//
+			// ```ruby
+			// [1, 2].zip(["a", "b"])        #=> [[1, "a"], [2, "b"]]
+			// [1, 2].zip(["a"], [true, 3]) #=> [[1, "a", true], [2, nil, 3]]
+			// ```
+			//
+			// @param array [Array]...
+			// @return [Array]
+			Name: "zip",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					arr := receiver.(*ArrayObject)
+					others := make([]*ArrayObject, len(args))
+
+					for i, arg := range args {
+						other, ok := arg.(*ArrayObject)
+
+						if !ok {
+							return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.ArrayClass, arg.Class().Name)
 						}
+
+						others[i] = other
+					}
+
+					elements := make([]Object, len(arr.Elements))
+
+					for i, obj := range arr.Elements {
+						tuple := []Object{obj}
+
+						for _, other := range others {
+							if i < len(other.Elements) {
+								tuple = append(tuple, other.Elements[i])
+							} else {
+								tuple = append(tuple, NULL)
+							}
+						}
+
+						elements[i] = t.vm.InitArrayObject(tuple)
 					}
 
 					return t.vm.InitArrayObject(elements)
@@ -1402,6 +2236,7 @@ func (vm *VM) initArrayClass() *RClass {
 	ac := vm.initializeClass(classes.ArrayClass)
 	ac.setBuiltinMethods(builtinArrayInstanceMethods(), false)
 	ac.setBuiltinMethods(builtinArrayClassMethods(), true)
+	vm.libFiles = append(vm.libFiles, "enumerable.gb")
 	vm.libFiles = append(vm.libFiles, "array.gb")
 	vm.libFiles = append(vm.libFiles, "array_enumerator.gb")
 	vm.libFiles = append(vm.libFiles, "lazy_enumerator.gb")
@@ -1452,11 +2287,11 @@ func (a *ArrayObject) toJSON(t *Thread) string {
 }
 
 // concatenateCopies returns a array composed of N copies of the array
-func (a *ArrayObject) concatenateCopies(t *Thread, n *IntegerObject) Object {
+func (a *ArrayObject) concatenateCopies(t *Thread, n int) Object {
 	aLen := len(a.Elements)
-	result := make([]Object, 0, aLen*n.value)
+	result := make([]Object, 0, aLen*n)
 
-	for i := 0; i < n.value; i++ {
+	for i := 0; i < n; i++ {
 		result = append(result, a.Elements...)
 	}
 
@@ -1472,7 +2307,13 @@ func (a *ArrayObject) dig(t *Thread, keys []Object, sourceLine int) Object {
 		return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, currentKey.Class().Name)
 	}
 
-	normalizedIndex := a.normalizeIndex(intCurrentKey)
+	index, err := intCurrentKey.intValue(t, sourceLine)
+
+	if err != nil {
+		return err
+	}
+
+	normalizedIndex := a.normalizeIndex(index)
 
 	if normalizedIndex == -1 {
 		return NULL
@@ -1501,27 +2342,40 @@ func (a *ArrayObject) index(t *Thread, args []Object, sourceLine int) Object {
 	}
 
 	i := args[0]
-	index, ok := i.(*IntegerObject)
+	indexObject, ok := i.(*IntegerObject)
 	arrLength := a.length()
 
 	if !ok {
 		return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
 	}
 
-	if index.value < 0 && index.value < -arrLength {
-		return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Index value %d too small for array. minimum: %d", index.value, -arrLength)
+	index, err := indexObject.intValue(t, sourceLine)
+
+	if err != nil {
+		return err
+	}
+
+	if index < 0 && index < -arrLength {
+		return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Index value %d too small for array. minimum: %d", index, -arrLength)
 	}
 
+	var count int
+
 	/* Validation for the second argument if exists */
 	if len(args) == 2 {
 		j := args[1]
-		count, ok := j.(*IntegerObject)
+		countObject, ok := j.(*IntegerObject)
 
 		if !ok {
 			return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[1].Class().Name)
 		}
-		if count.value < 0 {
-			return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect second argument greater than or equal 0. got: %d", count.value)
+
+		if count, err = countObject.intValue(t, sourceLine); err != nil {
+			return err
+		}
+
+		if count < 0 {
+			return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect second argument greater than or equal 0. got: %d", count)
 		}
 
 		/*
@@ -1530,7 +2384,7 @@ func (a *ArrayObject) index(t *Thread, args []Object, sourceLine int) Object {
 		 *  a = [1, 2, 3, 4, 5]
 		 *  a[5, 5] #=> []
 		 */
-		if index.value > 0 && index.value == arrLength {
+		if index > 0 && index == arrLength {
 			return t.vm.InitArrayObject([]Object{})
 		}
 	}
@@ -1542,13 +2396,10 @@ func (a *ArrayObject) index(t *Thread, args []Object, sourceLine int) Object {
 	}
 
 	if len(args) == 2 {
-		j := args[1]
-		count, _ := j.(*IntegerObject)
-
-		if normalizedIndex+count.value > arrLength {
+		if count > arrLength-normalizedIndex {
 			return t.vm.InitArrayObject(a.Elements[normalizedIndex:])
 		}
-		return t.vm.InitArrayObject(a.Elements[normalizedIndex : normalizedIndex+count.value])
+		return t.vm.InitArrayObject(a.Elements[normalizedIndex : normalizedIndex+count])
 	}
 
 	return a.Elements[normalizedIndex]
@@ -1570,6 +2421,96 @@ func (a *ArrayObject) flatten() []Object {
 	return result
 }
 
+// extremum returns the largest element when sign is 1, or the smallest one when it's -1
+func (a *ArrayObject) extremum(t *Thread, blockFrame *normalCallFrame, sign int, sourceLine int) Object {
+	// The block is only yielded when there are elements to compare
+	if blockFrame != nil && len(a.Elements) < 2 {
+		t.callFrameStack.pop()
+	}
+
+	if len(a.Elements) == 0 {
+		return NULL
+	}
+
+	result := a.Elements[0]
+
+	for _, obj := range a.Elements[1:] {
+		c, err := t.compareElements(blockFrame, obj, result, sourceLine)
+
+		if err != nil {
+			return err
+		}
+
+		if c*sign > 0 {
+			result = obj
+		}
+	}
+
+	return result
+}
+
+// extremumBy returns the element for which the block returns the largest value when sign is 1, or the
+// smallest one when it's -1
+func (a *ArrayObject) extremumBy(t *Thread, blockFrame *normalCallFrame, sign int, sourceLine int) Object {
+	// An empty block gives every element the same key, so the first one wins
+	if blockIsEmpty(blockFrame) {
+		if len(a.Elements) == 0 {
+			return NULL
+		}
+
+		return a.Elements[0]
+	}
+
+	// If it's an empty array, pop the block's call frame
+	if len(a.Elements) == 0 {
+		t.callFrameStack.pop()
+		return NULL
+	}
+
+	result := a.Elements[0]
+	resultKey := t.builtinMethodYield(blockFrame, result).Target
+
+	for _, obj := range a.Elements[1:] {
+		key := t.builtinMethodYield(blockFrame, obj).Target
+		c, err := t.compare(key, resultKey, sourceLine)
+
+		if err != nil {
+			return err
+		}
+
+		if c*sign > 0 {
+			result, resultKey = obj, key
+		}
+	}
+
+	return result
+}
+
+// sortedElements returns the elements sorted by compareElements. The sort is stable.
+func (a *ArrayObject) sortedElements(t *Thread, blockFrame *normalCallFrame, sourceLine int) ([]Object, *Error) {
+	elements := make([]Object, len(a.Elements))
+	copy(elements, a.Elements)
+
+	// The block is only yielded when there are elements to compare
+	if blockFrame != nil && len(elements) < 2 {
+		t.callFrameStack.pop()
+	}
+
+	var err *Error
+
+	sort.SliceStable(elements, func(i, j int) bool {
+		if err != nil {
+			return false
+		}
+
+		var result int
+		result, err = t.compareElements(blockFrame, elements[i], elements[j], sourceLine)
+		return result < 0
+	})
+
+	return elements, err
+}
+
 // length returns the length of array's elements
 func (a *ArrayObject) length() int {
 	return len(a.Elements)
@@ -1580,9 +2521,8 @@ func (a *ArrayObject) length() int {
 // 1. if the index is between o and the index length, returns the index
 // 2. if it's a negative value (within bounds), returns the normalized positive version
 // 3. if it's out of bounds (either positive or negative), returns -1
-func (a *ArrayObject) normalizeIndex(objectIndex *IntegerObject) int {
+func (a *ArrayObject) normalizeIndex(index int) int {
 	aLength := len(a.Elements)
-	index := objectIndex.value
 
 	// out of bounds
 
@@ -1590,7 +2530,7 @@ func (a *ArrayObject) normalizeIndex(objectIndex *IntegerObject) int {
 		return -1
 	}
 
-	if index < 0 && -index > aLength {
+	if index < -aLength {
 		return -1
 	}
 
@@ -165,6 +165,12 @@ func TestArrayIndexFail(t *testing.T) {
 		{`
 		    [1, "a", 10, "b"][-5]
 		`, "ArgumentError: Index value -5 too small for array. minimum: -4", 1},
+		{`[1, 2][2 ** 64]`, "RangeError: 18446744073709551616 is out of range", 1},
+		{`[1, 2][0, 2 ** 64]`, "RangeError: 18446744073709551616 is out of range", 1},
+		{`
+			a = [1, 2]
+			a[-(2 ** 64)] = 1
+		`, "RangeError: -18446744073709551616 is out of range", 1},
 	}
 
 	for i, tt := range testsFail {
@@ -860,6 +866,7 @@ func TestArrayDigMethodFail(t *testing.T) {
 	testsFail := []errorTestCase{
 		{`[1, 2].dig`, "ArgumentError: Expected 1+ arguments, got 0", 1},
 		{`[1, 2].dig(0, 1)`, "TypeError: Expect target to be Diggable, got Integer", 1},
+		{`[1, 2].dig(2 ** 64)`, "RangeError: 18446744073709551616 is out of range", 1},
 	}
 
 	for i, tt := range testsFail {
@@ -1011,6 +1018,88 @@ func TestArrayEachIndexMethodFail(t *testing.T) {
 	}
 }
 
+func TestArrayEachSliceMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		slices = []
+		[1, 2, 3, 4, 5].each_slice(2) do |s|
+		  slices.push(s)
+		end
+		slices
+		`, []interface{}{[]interface{}{1, 2}, []interface{}{3, 4}, []interface{}{5}}},
+		{`
+		slices = []
+		[].each_slice(2) do |s|
+		  slices.push(s)
+		end
+		slices.length
+		`, 0},
+		{`
+		a = [1, 2].each_slice(5) do |s|
+		end
+		a.length
+		`, 2},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestArrayEachSliceMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`[1, 2].each_slice(2)`, "InternalError: Can't yield without a block", 1},
+		{`[1, 2].each_slice do end`, "ArgumentError: Expect 1 arguments. got: 0", 1},
+		{`[1, 2].each_slice("2") do end`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`[1, 2].each_slice(0) do end`, "ArgumentError: Expect slice size to be positive. got: 0", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestArrayEachWithIndexMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		result = []
+		["a", "b", "c"].each_with_index do |e, i|
+		  result.push(e + i.to_s)
+		end
+		result
+		`, []interface{}{"a0", "b1", "c2"}},
+		{`
+		sum = 0
+		[].each_with_index do |e, i|
+		  sum += i
+		end
+		sum
+		`, 0},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestArrayEmptyMethod(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -1059,6 +1148,45 @@ func TestArrayEmptyMethodFail(t *testing.T) {
 	}
 }
 
+func TestArrayFindMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		[1, 2, 3, 4].find do |e|
+		  e > 2
+		end
+		`, 3},
+		{`
+		[1, 2].find do |e|
+		  e > 2
+		end
+		`, nil},
+		{`
+		[].find do |e|
+		  true
+		end
+		`, nil},
+		{`
+		yielded = []
+		[1, 2, 3].find do |e|
+		  yielded.push(e)
+		  e == 2
+		end
+		yielded
+		`, []interface{}{1, 2}},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestArrayFirstMethod(t *testing.T) {
 	testsInt := []struct {
 		input    string
@@ -1135,6 +1263,7 @@ func TestArrayFirstMethodFail(t *testing.T) {
 		{`a = [1, 2]
 		a.first("a")
 		`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`[1, 2].first(2 ** 64)`, "RangeError: 18446744073709551616 is out of range", 1},
 		{`a = [1, 2]
 		a.first(1, 2, 3)
 		`, "ArgumentError: Expect 0..1 argument. got=3", 1},
@@ -1152,6 +1281,37 @@ func TestArrayFirstMethodFail(t *testing.T) {
 	}
 }
 
+func TestArrayFlatMapMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		[1, 2].flat_map do |e|
+		  [e, e * 10]
+		end
+		`, []interface{}{1, 10, 2, 20}},
+		{`
+		[1, [2]].flat_map do |e|
+		  e
+		end
+		`, []interface{}{1, 2}},
+		{`
+		[].flat_map do |e|
+		  [e]
+		end
+		`, []interface{}{}},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestArrayFlattenMethod(t *testing.T) {
 	testsArray := []struct {
 		input    string
@@ -1196,6 +1356,40 @@ func TestArrayFlattenMethodFail(t *testing.T) {
 	}
 }
 
+func TestArrayGroupByMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		groups = ["apple", "avocado", "banana"].group_by do |e|
+		  e[0]
+		end
+		[groups["a"], groups["b"]]
+		`, []interface{}{[]interface{}{"apple", "avocado"}, []interface{}{"banana"}}},
+		{`
+		groups = [1, 2, 3].group_by do |e|
+		  e % 2
+		end
+		groups.sorted_keys
+		`, []interface{}{"0", "1"}},
+		{`
+		groups = [].group_by do |e|
+		  e
+		end
+		groups.length
+		`, 0},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestArrayIncludeMethod(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -1218,6 +1412,41 @@ func TestArrayIncludeMethod(t *testing.T) {
 	}
 }
 
+func TestArrayHashMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`[1, "a"].hash.is_a?(Integer)`, true},
+		{`[1, "a"].hash == [1, "a"].hash`, true},
+		{`[1, "a"].hash == ["a", 1].hash`, false},
+		{`[1, [2]].hash == [1, [2]].hash`, true},
+		{`[].hash == [].hash`, true},
+		{`[["a, b"]].hash == [["a", "b"]].hash`, false},
+		{`
+		class Foo
+		  def initialize(value)
+		    @value = value
+		  end
+
+		  def hash
+		    @value
+		  end
+		end
+
+		[[Foo.new(1)].hash == [Foo.new(1)].hash, [Foo.new(1)].hash == [Foo.new(2)].hash]
+		`, []interface{}{true, false}},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestArrayJoinMethod(t *testing.T) {
 	testsInt := []struct {
 		input    string
@@ -1427,6 +1656,143 @@ func TestArrayMapMethod(t *testing.T) {
 	}
 }
 
+func TestArrayMaxAndMinMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`[1, 5, 3].max`, 5},
+		{`[3, 1.5, 2].min`, 1.5},
+		{`["b", "c", "a"].max`, "c"},
+		{`["b", "c", "a"].min`, "a"},
+		{`[].max`, nil},
+		{`[].min`, nil},
+		{`
+		["aa", "b", "ccc"].max do |a, b|
+		  a.length - b.length
+		end
+		`, "ccc"},
+		{`
+		["aa", "b", "ccc"].min do |a, b|
+		  a.length - b.length
+		end
+		`, "b"},
+		{`
+		[1].min do |a, b|
+		  a - b
+		end
+		`, 1},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestArrayMaxAndMinMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`[1, "a"].max`, "ArgumentError: Can't compare String with Integer", 1},
+		{`[1, 2].min(1)`, "ArgumentError: Expect 0 arguments. got: 1", 1},
+		{`
+		[1, 2].max do |a, b|
+		  "a"
+		end
+		`, "TypeError: Expect the block to return Integer. got: String", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestArrayMaxByAndMinByMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		["aa", "b", "ccc"].max_by do |s|
+		  s.length
+		end
+		`, "ccc"},
+		{`
+		["aa", "b", "ccc"].min_by do |s|
+		  s.length
+		end
+		`, "b"},
+		{`
+		[].max_by do |s|
+		  s.length
+		end
+		`, nil},
+		{`
+		[1, 2].min_by do |s|
+		end
+		`, 1},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestArrayMaxByAndMinByMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`[1, 2].max_by`, "InternalError: Can't yield without a block", 1},
+		{`
+		[1, 2].min_by do |x|
+		  Object.new
+		end
+		`, "ArgumentError: Can't compare Object with Object", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestArrayPartitionMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		[1, 2, 3, 4].partition do |e|
+		  e % 2 == 0
+		end
+		`, []interface{}{[]interface{}{2, 4}, []interface{}{1, 3}}},
+		{`
+		[].partition do |e|
+		  true
+		end
+		`, []interface{}{[]interface{}{}, []interface{}{}}},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestArrayPlusOperator(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -1934,6 +2300,170 @@ func TestArrayShiftMethodFail(t *testing.T) {
 	}
 }
 
+func TestArraySortMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`[3, 1, 2].sort`, []interface{}{1, 2, 3}},
+		{`[1, 3, 2].sort do |a, b| (b - a) * (2 ** 64) end`, []interface{}{3, 2, 1}},
+		{`[1.5, 1, 3].sort`, []interface{}{1, 1.5, 3}},
+		{`["b", "c", "a"].sort`, []interface{}{"a", "b", "c"}},
+		{`[].sort`, []interface{}{}},
+		{`
+		a = [3, 1, 2]
+		a.sort
+		a
+		`, []interface{}{3, 1, 2}},
+		{`
+		[1, 2, 3].sort do |a, b|
+		  b - a
+		end
+		`, []interface{}{3, 2, 1}},
+		{`
+		[1].sort do |a, b|
+		  b - a
+		end
+		`, []interface{}{1}},
+		// The sort is stable
+		{`
+		["bb", "a", "cc", "d"].sort do |a, b|
+		  a.length - b.length
+		end
+		`, []interface{}{"a", "d", "bb", "cc"}},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestArraySortMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`[1, "a"].sort`, "ArgumentError: Can't compare String with Integer", 1},
+		{`[1, nil].sort`, "ArgumentError: Can't compare Null with Integer", 1},
+		{`[1, 2].sort(1)`, "ArgumentError: Expect 0 arguments. got: 1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestArraySortBangMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		a = [3, 1, 2]
+		a.sort!
+		a
+		`, []interface{}{1, 2, 3}},
+		{`
+		a = [1, 2, 3]
+		b = a.sort! do |x, y|
+		  y - x
+		end
+		a.push(0)
+		b
+		`, []interface{}{3, 2, 1, 0}},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestArraySortBangMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`[Object.new, Object.new].sort!`, "ArgumentError: Can't compare Object with Object", 1},
+		{`[1, 2].sort!(1)`, "ArgumentError: Expect 0 arguments. got: 1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestArraySortByMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		["ccc", "a", "bb"].sort_by do |e|
+		  e.length
+		end
+		`, []interface{}{"a", "bb", "ccc"}},
+		{`
+		["bb", "a", "cc", "d"].sort_by do |e|
+		  e.length
+		end
+		`, []interface{}{"a", "d", "bb", "cc"}},
+		{`
+		[].sort_by do |e|
+		  e
+		end
+		`, []interface{}{}},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestArraySumMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`[1, 2, 3].sum`, 6},
+		{`[1, 2.5].sum`, 3.5},
+		{`[1, 2, 3].sum(10)`, 16},
+		{`["a", "b"].sum("")`, "ab"},
+		{`[].sum`, 0},
+		{`
+		["a", "bb"].sum do |e|
+		  e.length
+		end
+		`, 3},
+		{`
+		[].sum do |e|
+		  e.length
+		end
+		`, 0},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestArrayStarMethod(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -1971,6 +2501,7 @@ func TestArrayStarMethod(t *testing.T) {
 func TestArrayStarMethodFail(t *testing.T) {
 	testsFail := []errorTestCase{
 		{`[1, 2] * nil`, "TypeError: Expect argument to be Integer. got: Null", 1},
+		{`[1, 2] * (2 ** 64)`, "RangeError: 18446744073709551616 is out of range", 1},
 	}
 
 	for i, tt := range testsFail {
@@ -2004,6 +2535,64 @@ func TestArrayToEnumMethod(t *testing.T) {
 	v.checkSP(t, i, 1)
 }
 
+func TestArrayTakeWhileMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		[1, 2, 3, 1].take_while do |e|
+		  e < 3
+		end
+		`, []interface{}{1, 2}},
+		{`
+		[5, 1].take_while do |e|
+		  e < 3
+		end
+		`, []interface{}{}},
+		{`
+		[].take_while do |e|
+		  true
+		end
+		`, []interface{}{}},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestArrayUniqMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`[1, 2, 1, "1", 2].uniq`, []interface{}{1, 2, "1"}},
+		{`[[1, 2], [1, 2], [2]].uniq.length`, 2},
+		{`[1, 1.to_d].uniq.length`, 1},
+		{`[1.to_d, 1].uniq[0].class.name`, "Decimal"},
+		{`[2 ** 64, (2 ** 64).to_d, 1.5.to_d, 1.5].uniq.length`, 3},
+		{`[].uniq`, []interface{}{}},
+		{`
+		["a", "bb", "c"].uniq do |e|
+		  e.length
+		end
+		`, []interface{}{"a", "bb"}},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestArrayUnshiftMethod(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -2100,6 +2689,40 @@ func TestArrayValuesAtMethodFail(t *testing.T) {
 		{`a = ["a", "b", "c"]
 			a.values_at("-")
 		`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`["a"].values_at(0, 2 ** 64)`, "RangeError: 18446744073709551616 is out of range", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestArrayZipMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`[1, 2].zip(["a", "b"])`, []interface{}{[]interface{}{1, "a"}, []interface{}{2, "b"}}},
+		{`[1, 2].zip(["a"], [true, 3])`, []interface{}{[]interface{}{1, "a", true}, []interface{}{2, nil, 3}}},
+		{`[1, 2].zip`, []interface{}{[]interface{}{1}, []interface{}{2}}},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestArrayZipMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`[1, 2].zip(1)`, "TypeError: Expect argument to be Array. got: Integer", 1},
 	}
 
 	for i, tt := range testsFail {
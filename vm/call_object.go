@@ -100,6 +100,10 @@ func (co *callObject) assignNormalAndOptionedArguments(paramIndex int, stack []*
 }
 
 func (co *callObject) assignKeywordArguments(stack []*Pointer) (err error) {
+	if co.collectsKeywordArguments() {
+		return
+	}
+
 	for argIndex, argType := range co.argTypes() {
 		if argType == bytecode.RequiredKeywordArg || argType == bytecode.OptionalKeywordArg {
 			argName := co.argSet.Names()[argIndex]
@@ -116,17 +120,62 @@ func (co *callObject) assignKeywordArguments(stack []*Pointer) (err error) {
 	return
 }
 
-func (co *callObject) assignSplatArgument(stack []*Pointer, arr *ArrayObject) {
+// assignSplatArgument assigns the remaining positional arguments to the splat parameter. When the method has
+// no keyword parameters, keyword arguments are passed as a Hash at the end of it.
+func (co *callObject) assignSplatArgument(stack []*Pointer, arr *ArrayObject, keywords *HashObject) {
 	index := len(co.paramTypes()) - 1
+	argTypes := co.argTypes()
+	collectsKeywords := co.collectsKeywordArguments()
+	// Positional arguments taken by normal and optioned parameters
+	taken := 0
+
+	for _, paramType := range co.paramTypes() {
+		if paramType == bytecode.NormalArg || paramType == bytecode.OptionedArg {
+			taken++
+		}
+	}
+
+	// Arguments expanded from a splatted array have no type in the ArgSet.
+	for argIndex := 0; argIndex < co.argCount; argIndex++ {
+		if argIndex < len(argTypes) && (argTypes[argIndex] == bytecode.RequiredKeywordArg || argTypes[argIndex] == bytecode.OptionalKeywordArg) {
+			if collectsKeywords {
+				keywords.Pairs[co.argSet.Names()[argIndex]] = stack[co.argPtr()+argIndex].Target
+			}
+
+			continue
+		}
+
+		if taken > 0 {
+			taken--
+			continue
+		}
 
-	for co.argIndex < co.argCount {
-		arr.Elements = append(arr.Elements, stack[co.argPosition()].Target)
-		co.argIndex++
+		arr.Elements = append(arr.Elements, stack[co.argPtr()+argIndex].Target)
+	}
+
+	if len(keywords.Pairs) > 0 {
+		arr.Elements = append(arr.Elements, keywords)
 	}
 
 	co.callFrame.insertLCL(index, 0, arr)
 }
 
+// collectsKeywordArguments reports whether keyword arguments are passed to the splat parameter, which is the case
+// when the method has a splat parameter but no keyword parameters, like `def method_missing(name, *args)`.
+func (co *callObject) collectsKeywordArguments() bool {
+	if !co.method.isSplatArgIncluded() {
+		return false
+	}
+
+	for _, paramType := range co.paramTypes() {
+		if paramType == bytecode.RequiredKeywordArg || paramType == bytecode.OptionalKeywordArg {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (co *callObject) hasKeywordParam(name string) (index int, result bool) {
 	for paramIndex, paramType := range co.paramTypes() {
 		paramName := co.paramNames()[paramIndex]
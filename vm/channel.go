@@ -277,6 +277,30 @@ func (co *ChannelObject) copy() Object {
 	return newC
 }
 
+// Deliver sends an object to the channel from native code, like Channel#deliver does. It returns false
+// without delivering the object if the channel is closed, or if cancel is closed before it's received.
+func (co *ChannelObject) Deliver(v *VM, obj Object, cancel <-chan struct{}) (delivered bool) {
+	if co.ChannelState == chClosed {
+		return false
+	}
+
+	// The channel can be closed by Goby code while we're waiting
+	defer func() {
+		if recover() != nil {
+			delivered = false
+		}
+	}()
+
+	id := v.channelObjectMap.storeObj(obj)
+
+	select {
+	case co.Chan <- id:
+		return true
+	case <-cancel:
+		return false
+	}
+}
+
 // objectMap ==========================================================
 
 type objectMap struct {
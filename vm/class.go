@@ -2,6 +2,7 @@ package vm
 
 import (
 	"fmt"
+	"math/big"
 	"os"
 	"path"
 	"reflect"
@@ -29,6 +30,8 @@ type RClass struct {
 	constants             map[string]*Pointer
 	scope                 *RClass
 	inheritsMethodMissing bool
+	// origin is the module an include proxy stands for, see includeModule
+	origin *RClass
 	*baseObj
 }
 
@@ -55,7 +58,8 @@ func buildMethods(m map[string]MethodBuilder) []*BuiltinMethodObject {
 	return out
 }
 
-// ExternalClass helps define external go classes
+// ExternalClass helps define external go classes. The Goby library file at path, which is relative to
+// the lib directory, is executed after the class is defined. Extensions without one pass an empty path.
 func ExternalClass(name, path string, classMethods, instanceMethods map[string]MethodBuilder) ClassLoader {
 	return func(v *VM) error {
 		pg := v.initializeClass(name)
@@ -63,6 +67,10 @@ func ExternalClass(name, path string, classMethods, instanceMethods map[string]M
 		pg.setBuiltinMethods(buildMethods(instanceMethods), false)
 		v.objectClass.setClassConstant(pg)
 
+		if path == "" {
+			return nil
+		}
+
 		return v.mainThread.execGobyLib(path)
 	}
 }
@@ -160,6 +168,10 @@ func builtinModuleCommonClassMethods() []*BuiltinMethodObject {
 					ancestors := make([]Object, len(a))
 					for i := range a {
 						ancestors[i] = a[i]
+
+						if a[i].origin != nil {
+							ancestors[i] = a[i].origin
+						}
 					}
 					return t.vm.InitArrayObject(ancestors)
 				}
@@ -482,12 +494,7 @@ func builtinModuleCommonClassMethods() []*BuiltinMethodObject {
 
 					class = receiver.SingletonClass()
 
-					if class.alreadyInherit(module) {
-						return class
-					}
-
-					module.superClass = class.superClass
-					class.superClass = module
+					class.includeModule(module)
 
 					return class
 				}
@@ -555,12 +562,7 @@ func builtinModuleCommonClassMethods() []*BuiltinMethodObject {
 						class = r.SingletonClass()
 					}
 
-					if class.alreadyInherit(module) {
-						return class
-					}
-
-					module.superClass = class.superClass
-					class.superClass = module
+					class.includeModule(module)
 
 					return class
 				}
@@ -803,6 +805,64 @@ func builtinClassCommonInstanceMethods() []*BuiltinMethodObject {
 				}
 			},
 		},
+		{
+			// Converts the given object to an Integer. Strings are parsed strictly: the optional base defaults
+			// to the String's prefix (`0b`, `0o`, `0` or `0x`) and anything that isn't a valid Integer raises
+			// an ArgumentError. Floats and Decimals are truncated.
+			//
+			// ```ruby
+			// Integer("42")         # => 42
+			// Integer("0x1f")       # => 31
+			// Integer("-0b101")     # => -5
+			// Integer("1_000")      # => 1000
+			// Integer("ff", 16)     # => 255
+			// Integer(3.99)         # => 3
+			// Integer("abc")        # => ArgumentError
+			// Integer(nil)          # => TypeError
+			// ```
+			//
+			// @param value [Object], base [Integer]
+			// @return [Integer]
+			Name: "Integer",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) < 1 || len(args) > 2 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRangeFormat, 1, 2, len(args))
+					}
+
+					base := 0
+					if len(args) == 2 {
+						if _, ok := args[0].(*StringObject); !ok {
+							return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Base specified for non-String value")
+						}
+
+						b, err := radix(t, args[1], sourceLine)
+						if err != nil {
+							return err
+						}
+						base = b
+					}
+
+					switch value := args[0].(type) {
+					case *IntegerObject:
+						return value
+					case *FloatObject:
+						return value.toInteger(t, sourceLine)
+					case *DecimalObject:
+						return t.vm.initBigIntegerObject(new(big.Int).Quo(value.value.Num(), value.value.Denom()))
+					case *StringObject:
+						n, ok := parseInteger(value.value, base)
+						if !ok {
+							return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Invalid value for Integer(): %q", value.value)
+						}
+
+						return t.vm.initBigIntegerObject(n)
+					default:
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, "Can't convert %s into Integer", value.Class().Name)
+					}
+				}
+			},
+		},
 		{
 			// Returns true if a block is given in the current context and `yield` is ready to call.
 			//
@@ -889,7 +949,13 @@ func builtinClassCommonInstanceMethods() []*BuiltinMethodObject {
 							return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
 						}
 
-						os.Exit(exitCode.value)
+						code, err := exitCode.intValue(t, sourceLine)
+
+						if err != nil {
+							return err
+						}
+
+						os.Exit(code)
 					default:
 						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expected at most 1 argument; got: %d", len(args))
 					}
@@ -898,6 +964,72 @@ func builtinClassCommonInstanceMethods() []*BuiltinMethodObject {
 				}
 			},
 		},
+		{
+			// Returns the arguments formatted with the format string, like C's sprintf. A directive is written as
+			// `%[position$][flags][width][.precision]type`:
+			//
+			// - `position`: takes the nth argument instead of the next one, like `%2$s`.
+			// - `flags`: `-` to justify to the left, `+` or ` ` to sign positive numbers, `0` to pad numbers with zeros,
+			//   and `#` to prefix `x`, `o` and `b` with `0x`, `0` and `0b`.
+			// - `width` and `precision`: numbers, or `*` to take them from the arguments.
+			// - `type`: `d` for Integer, `x`, `o` and `b` for hexadecimal, octal and binary, `f`, `e` and `g` for Float,
+			//   `s` for String, `p` for quoted String, and `c` for a character.
+			//
+			// `%%` is a literal `%`. Decimals are formatted with their exact values.
+			// Raises an ArgumentError if the number of arguments doesn't match the format string.
+			//
+			// ```ruby
+			// format("%05.2f|%-4s|%x", 3.14159, "ab", 255) # => "03.14|ab  |ff"
+			// format("%2$s %1$s", "world", "hello")        # => "hello world"
+			// format("%+d%%", 20)                          # => "+20%"
+			// ```
+			//
+			// @param format [String], *args [Object]
+			// @return [String]
+			Name: "format",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					s, err := formatArguments(t, args, sourceLine)
+
+					if err != nil {
+						return err
+					}
+
+					return t.vm.InitStringObject(s)
+				}
+			},
+		},
+		{
+			// Returns a hash of the object. Builtin values like Floats, Hashes and Sets have the same hash when
+			// they're equal, and other objects have a hash of their own. A class can override it along with `eql?`
+			// to decide which of its objects are the same element of a Set.
+			//
+			// ```ruby
+			// class Point
+			//   def initialize(x, y)
+			//     @x = x
+			//     @y = y
+			//   end
+			//
+			//   def hash
+			//     [@x, @y].hash
+			//   end
+			// end
+			//
+			// Set.new([Point.new(1, 2), Point.new(1, 2)]).size # => 1
+			// ```
+			// @return [Integer]
+			Name: "hash",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return t.hashValue(receiver, sourceLine)
+				}
+			},
+		},
 		{
 			// Returns true if Object class is equal to the input argument class
 			//
@@ -1137,6 +1269,31 @@ func builtinClassCommonInstanceMethods() []*BuiltinMethodObject {
 				}
 			},
 		},
+		{
+			// Prints the arguments formatted with the format string to stdout, without a tailing line feed.
+			// See `format` for the directives.
+			//
+			// ```ruby
+			// printf("%s has %d items\n", "cart", 3)
+			// # => cart has 3 items
+			// ```
+			//
+			// @param format [String], *args [Object]
+			// @return [Null]
+			Name: "printf",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					s, err := formatArguments(t, args, sourceLine)
+
+					if err != nil {
+						return err
+					}
+
+					fmt.Print(s)
+					return NULL
+				}
+			},
+		},
 		{
 			// Puts string literals or objects into stdout with a tailing line feed, converting into String
 			// if needed.
@@ -1411,7 +1568,12 @@ func builtinClassCommonInstanceMethods() []*BuiltinMethodObject {
 					int, ok := args[0].(*IntegerObject)
 
 					if ok {
-						seconds := int.value
+						seconds, err := int.intValue(t, sourceLine)
+
+						if err != nil {
+							return err
+						}
+
 						time.Sleep(time.Duration(seconds) * time.Second)
 						return int
 					}
@@ -1711,7 +1873,7 @@ func (c *RClass) getClassConstant(constName string) (class *RClass) {
 }
 
 func (c *RClass) alreadyInherit(constant *RClass) bool {
-	if c.superClass == constant {
+	if c.superClass == constant || c.superClass.origin == constant {
 		return true
 	}
 
@@ -1722,6 +1884,38 @@ func (c *RClass) alreadyInherit(constant *RClass) bool {
 	return c.superClass.alreadyInherit(constant)
 }
 
+// includeModule inserts the module, and the modules it includes, above the class in its method lookup
+// chain. Since a module can be included by many classes, each class gets proxies of the modules, which
+// share the modules' methods and constants but have their own superclass.
+func (c *RClass) includeModule(module *RClass) {
+	modules := []*RClass{module}
+
+	for m := module.superClass; m != nil && m.origin != nil; m = m.superClass {
+		modules = append(modules, m.origin)
+	}
+
+	for i := len(modules) - 1; i >= 0; i-- {
+		if !c.alreadyInherit(modules[i]) {
+			c.superClass = modules[i].includeProxy(c.superClass)
+		}
+	}
+}
+
+func (c *RClass) includeProxy(superClass *RClass) *RClass {
+	return &RClass{
+		Name:                  c.Name,
+		Methods:               c.Methods,
+		pseudoSuperClass:      c.pseudoSuperClass,
+		superClass:            superClass,
+		isModule:              true,
+		constants:             c.constants,
+		scope:                 c.scope,
+		inheritsMethodMissing: c.inheritsMethodMissing,
+		origin:                c,
+		baseObj:               c.baseObj,
+	}
+}
+
 func (c *RClass) returnSuperClass() *RClass {
 	return c.pseudoSuperClass
 }
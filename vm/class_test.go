@@ -1198,6 +1198,62 @@ func TestExitMethodFail(t *testing.T) {
 	}
 }
 
+func TestIntegerMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Integer("42")`, 42},
+		{`Integer("0x1f")`, 31},
+		{`Integer(" -0b101 ")`, -5},
+		{`Integer("0o17")`, 15},
+		{`Integer("017")`, 15},
+		{`Integer("1_000")`, 1000},
+		{`Integer("ff", 16)`, 255},
+		{`Integer("0x1f", 16)`, 31},
+		{`Integer("1_0", 2)`, 2},
+		{`Integer(3.99)`, 3},
+		{`Integer(-3.99)`, -3},
+		{`Integer("7.5".to_d)`, 7},
+		{`Integer(5)`, 5},
+		{`Integer("18446744073709551616").to_s`, "18446744073709551616"},
+		{`
+	a = Integer("12") + 1
+	a
+	`, 13},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Integer("abc")`, "ArgumentError: Invalid value for Integer(): \"abc\"", 1},
+		{`Integer("")`, "ArgumentError: Invalid value for Integer(): \"\"", 1},
+		{`Integer("1__0")`, "ArgumentError: Invalid value for Integer(): \"1__0\"", 1},
+		{`Integer("12", 2)`, "ArgumentError: Invalid value for Integer(): \"12\"", 1},
+		{`Integer("1", 37)`, "ArgumentError: Invalid radix 37", 1},
+		{`Integer(1, 2)`, "ArgumentError: Base specified for non-String value", 1},
+		{`Integer(nil)`, "TypeError: Can't convert Null into Integer", 1},
+		{`Integer("Inf".to_f)`, "RangeError: Can't convert Infinity into Integer", 1},
+		{`Integer()`, "ArgumentError: Expect 1..2 arguments. got: 0", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestGeneralIsAMethod(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -1558,3 +1614,30 @@ func TestObjectIdMethod(t *testing.T) {
 		v.checkSP(t, i, 1)
 	}
 }
+
+func TestObjectHashMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Object.new.hash.is_a?(Integer)`, true},
+		{`a = Object.new; a.hash == a.hash`, true},
+		{`Object.new.hash == Object.new.hash`, false},
+		{`1.5.hash == 1.5.hash`, true},
+		{`0.0.hash == -0.0.hash`, true},
+		{`{ a: 1 }.hash == { a: 1 }.hash`, true},
+		{`{ a: 1 }.hash == { a: 2 }.hash`, false},
+		{`Set.new([1, 2]).hash == Set.new([2, 1]).hash`, true},
+		{`nil.hash == nil.hash`, true},
+		{`Integer.hash == Integer.hash`, true},
+		{`Integer.hash == String.hash`, false},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
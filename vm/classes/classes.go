@@ -22,4 +22,11 @@ const (
 	GoMapClass     = "GoMap"
 	DecimalClass   = "Decimal"
 	BlockClass     = "Block"
+	TimeClass      = "Time"
+	DateClass      = "Date"
+	DurationClass  = "Duration"
+	SetClass       = "Set"
+
+	ComparableModule = "Comparable"
+	MathModule       = "Math"
 )
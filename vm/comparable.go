@@ -0,0 +1,241 @@
+package vm
+
+import (
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// Comparable is a module that gives the classes including it comparison operators, `between?` and `clamp`,
+// all based on the class's `<=>` method.
+// `<=>` returns a negative Integer, 0 or a positive Integer when `self` is smaller than, equal to or larger
+// than the other object, and `nil` when the two can't be compared.
+// Array#sort, #min, #max and the like compare objects with `<=>` too.
+//
+// ```ruby
+// class Version
+//   include Comparable
+//
+//   attr_reader :number
+//
+//   def initialize(number)
+//     @number = number
+//   end
+//
+//   def <=>(other)
+//     @number <=> other.number
+//   end
+// end
+//
+// Version.new(1) < Version.new(2)                           # => true
+// Version.new(2).between?(Version.new(1), Version.new(3))   # => true
+// Version.new(7).clamp(Version.new(1), Version.new(3)).number # => 3
+// ```
+//
+
+// Instance methods -----------------------------------------------------
+func builtinComparableInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns true if `self <=> other` is negative.
+			// Raises an ArgumentError if `<=>` returns `nil`.
+			//
+			// ```ruby
+			// Version.new(1) < Version.new(2) # => true
+			// ```
+			//
+			// @param object [Object]
+			// @return [Boolean]
+			Name: "<",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					return comparisonResult(t, receiver, args, sourceLine, func(c int) bool { return c < 0 })
+				}
+			},
+		},
+		{
+			// Returns true if `self <=> other` is negative or 0.
+			// Raises an ArgumentError if `<=>` returns `nil`.
+			//
+			// ```ruby
+			// Version.new(2) <= Version.new(2) # => true
+			// ```
+			//
+			// @param object [Object]
+			// @return [Boolean]
+			Name: "<=",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					return comparisonResult(t, receiver, args, sourceLine, func(c int) bool { return c <= 0 })
+				}
+			},
+		},
+		{
+			// Returns true if `self <=> other` is 0. Unlike the other operators, returns false instead of
+			// raising an error when the objects can't be compared.
+			//
+			// ```ruby
+			// Version.new(2) == Version.new(2) # => true
+			// Version.new(2) == 2              # => false
+			// ```
+			//
+			// @param object [Object]
+			// @return [Boolean]
+			Name: "==",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					if receiver == args[0] {
+						return TRUE
+					}
+
+					result, ok := t.spaceship(receiver, args[0], sourceLine)
+					return toBooleanObject(ok && result == 0)
+				}
+			},
+		},
+		{
+			// Returns true if `self <=> other` is positive.
+			// Raises an ArgumentError if `<=>` returns `nil`.
+			//
+			// ```ruby
+			// Version.new(2) > Version.new(1) # => true
+			// ```
+			//
+			// @param object [Object]
+			// @return [Boolean]
+			Name: ">",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					return comparisonResult(t, receiver, args, sourceLine, func(c int) bool { return c > 0 })
+				}
+			},
+		},
+		{
+			// Returns true if `self <=> other` is positive or 0.
+			// Raises an ArgumentError if `<=>` returns `nil`.
+			//
+			// ```ruby
+			// Version.new(2) >= Version.new(2) # => true
+			// ```
+			//
+			// @param object [Object]
+			// @return [Boolean]
+			Name: ">=",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					return comparisonResult(t, receiver, args, sourceLine, func(c int) bool { return c >= 0 })
+				}
+			},
+		},
+		{
+			// Returns true if `self` is between the minimum and the maximum, inclusively.
+			//
+			// ```ruby
+			// Version.new(2).between?(Version.new(1), Version.new(3)) # => true
+			// Version.new(3).between?(Version.new(1), Version.new(3)) # => true
+			// Version.new(4).between?(Version.new(1), Version.new(3)) # => false
+			// ```
+			//
+			// @param min [Object], max [Object]
+			// @return [Boolean]
+			Name: "between?",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 2 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 2, len(args))
+					}
+
+					min, err := t.compare(receiver, args[0], sourceLine)
+
+					if err != nil {
+						return err
+					}
+
+					max, err := t.compare(receiver, args[1], sourceLine)
+
+					if err != nil {
+						return err
+					}
+
+					return toBooleanObject(min >= 0 && max <= 0)
+				}
+			},
+		},
+		{
+			// Returns the minimum if `self` is smaller than it, the maximum if `self` is larger than it,
+			// or `self` otherwise.
+			// Raises an ArgumentError if the minimum is larger than the maximum.
+			//
+			// ```ruby
+			// Version.new(7).clamp(Version.new(1), Version.new(3)).number # => 3
+			// Version.new(0).clamp(Version.new(1), Version.new(3)).number # => 1
+			// Version.new(2).clamp(Version.new(1), Version.new(3)).number # => 2
+			// ```
+			//
+			// @param min [Object], max [Object]
+			// @return [Object]
+			Name: "clamp",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 2 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 2, len(args))
+					}
+
+					order, err := t.compare(args[0], args[1], sourceLine)
+
+					if err != nil {
+						return err
+					}
+
+					if order > 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect min to be less than or equal to max")
+					}
+
+					if c, err := t.compare(receiver, args[0], sourceLine); err != nil {
+						return err
+					} else if c < 0 {
+						return args[0]
+					}
+
+					if c, err := t.compare(receiver, args[1], sourceLine); err != nil {
+						return err
+					} else if c > 0 {
+						return args[1]
+					}
+
+					return receiver
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initComparableModule() *RClass {
+	module := vm.initializeModule(classes.ComparableModule)
+	module.setBuiltinMethods(builtinComparableInstanceMethods(), false)
+	return module
+}
+
+// Other helper functions -----------------------------------------------
+
+// comparisonResult compares the receiver with the argument, and tells the comparison result to test.
+func comparisonResult(t *Thread, receiver Object, args []Object, sourceLine int, test func(int) bool) Object {
+	if len(args) != 1 {
+		return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+	}
+
+	result, err := t.compare(receiver, args[0], sourceLine)
+
+	if err != nil {
+		return err
+	}
+
+	return toBooleanObject(test(result))
+}
@@ -0,0 +1,136 @@
+package vm
+
+import (
+	"testing"
+)
+
+const comparableVersion = `
+class Version
+  include Comparable
+
+  attr_reader :number
+
+  def initialize(number)
+    @number = number
+  end
+
+  def <=>(other)
+    if other.is_a?(Version)
+      @number <=> other.number
+    end
+  end
+end
+
+v1 = Version.new(1)
+v2 = Version.new(2)
+v3 = Version.new(3)
+`
+
+func TestComparableOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`v1 < v2`, true},
+		{`v2 < v1`, false},
+		{`v2 <= Version.new(2)`, true},
+		{`v3 <= v2`, false},
+		{`v3 > v2`, true},
+		{`v1 > v1`, false},
+		{`v2 >= Version.new(2)`, true},
+		{`v1 >= v2`, false},
+		{`v2 == Version.new(2)`, true},
+		{`v2 == v3`, false},
+		{`v2 == 2`, false},
+		{`v2 != v3`, true},
+		{`Version.ancestors.to_s`, "[Version, Comparable, Object]"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, comparableVersion+tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestComparableBetweenAndClamp(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`v2.between?(v1, v3)`, true},
+		{`v3.between?(v1, v3)`, true},
+		{`Version.new(4).between?(v1, v3)`, false},
+		{`Version.new(7).clamp(v1, v3).number`, 3},
+		{`Version.new(0).clamp(v1, v3).number`, 1},
+		{`v2.clamp(v1, v3).number`, 2},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, comparableVersion+tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestComparableMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`v1 < 1`, "ArgumentError: Can't compare Version with Integer", 1},
+		{`v1 >= nil`, "ArgumentError: Can't compare Version with Null", 1},
+		{`v1.between?(v2)`, "ArgumentError: Expect 2 arguments. got: 1", 1},
+		{`v2.clamp(v3, v1)`, "ArgumentError: Expect min to be less than or equal to max", 1},
+		{`v2.clamp(1, v3)`, "ArgumentError: Can't compare Integer with Version", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, comparableVersion+tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestArrayMethodsWithComparableObjects(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		[v3, v1, v2].sort.map do |v|
+		  v.number
+		end
+		`, []interface{}{1, 2, 3}},
+		{`
+		a = [v3, v1, v2]
+		a.sort!
+		a.map do |v|
+		  v.number
+		end
+		`, []interface{}{1, 2, 3}},
+		{`[v3, v1, v2].max.number`, 3},
+		{`[v3, v1, v2].min.number`, 1},
+		{`
+		[v3, v1, v2].sort_by do |v|
+		  v
+		end.first.number
+		`, 1},
+		{`
+		[v1, v3, v2].max_by do |v|
+		  v
+		end.number
+		`, 3},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, comparableVersion+tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
@@ -12,35 +12,52 @@ import (
 //
 // We don't implement dig, as it has no concurrency guarantees.
 var ConcurrentArrayMethodsForwardingTable = map[string]bool{
-	"[]":           false,
-	"*":            false,
-	"+":            false,
-	"[]=":          true,
-	"any?":         false,
-	"at":           false,
-	"clear":        true,
-	"concat":       true,
-	"count":        false,
-	"delete_at":    true,
-	"each":         false,
-	"each_index":   false,
-	"empty?":       false,
-	"first":        false,
-	"flatten":      false,
-	"join":         false,
-	"last":         false,
-	"length":       false,
-	"map":          false,
-	"pop":          true,
-	"push":         true,
-	"reduce":       false,
-	"reverse":      false,
-	"reverse_each": false,
-	"rotate":       false,
-	"select":       false,
-	"shift":        true,
-	"unshift":      true,
-	"values_at":    false,
+	"[]":              false,
+	"*":               false,
+	"+":               false,
+	"[]=":             true,
+	"any?":            false,
+	"at":              false,
+	"clear":           true,
+	"concat":          true,
+	"count":           false,
+	"delete_at":       true,
+	"each":            false,
+	"each_index":      false,
+	"each_slice":      false,
+	"each_with_index": false,
+	"empty?":          false,
+	"find":            false,
+	"first":           false,
+	"flat_map":        false,
+	"flatten":         false,
+	"group_by":        false,
+	"join":            false,
+	"last":            false,
+	"length":          false,
+	"map":             false,
+	"max":             false,
+	"max_by":          false,
+	"min":             false,
+	"min_by":          false,
+	"partition":       false,
+	"pop":             true,
+	"push":            true,
+	"reduce":          false,
+	"reverse":         false,
+	"reverse_each":    false,
+	"rotate":          false,
+	"select":          false,
+	"shift":           true,
+	"sort":            false,
+	"sort!":           true,
+	"sort_by":         false,
+	"sum":             false,
+	"take_while":      false,
+	"uniq":            false,
+	"unshift":         true,
+	"values_at":       false,
+	"zip":             false,
 }
 
 // ConcurrentArrayObject is a thread-safe Array, implemented as a wrapper of an ArrayObject, coupled
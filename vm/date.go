@@ -0,0 +1,630 @@
+package vm
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// DateObject represents a calendar date without a time of day or a time zone.
+//
+// Adding or subtracting an Integer moves a Date by days, and `>>` and `<<` move it by months.
+// Subtracting two Dates returns the number of days between them.
+// Dates include Comparable, and are serialized to JSON like "2024-01-02".
+//
+// ```ruby
+// d = Date.new(2024, 1, 31)
+// d.to_s              # => "2024-01-31"
+// (d + 1).to_s        # => "2024-02-01"
+// (d >> 1).to_s       # => "2024-02-29"
+// d - Date.new(2024)  # => 30
+// d.strftime("%b %-d") # => "Jan 31"
+// ```
+type DateObject struct {
+	*baseObj
+	// value is the date at midnight in UTC
+	value time.Time
+}
+
+// Class methods --------------------------------------------------------
+func builtinDateClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns the Date of the given year, month and day. The month and the day default to 1.
+			//
+			// ```ruby
+			// Date.new(2024, 1, 2).to_s # => "2024-01-02"
+			// Date.new(2024).to_s       # => "2024-01-01"
+			// Date.new(2023, 2, 29)     # => ArgumentError
+			// ```
+			// @param year [Integer], month [Integer], day [Integer]
+			// @return [Date]
+			Name: "new",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) < 1 || len(args) > 3 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRangeFormat, 1, 3, len(args))
+					}
+
+					year, month, day, err := t.dateArgs(args, sourceLine)
+					if err != nil {
+						return err
+					}
+
+					if !validDate(year, month, day) {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Invalid date")
+					}
+
+					return t.vm.initDateObject(year, time.Month(month), day)
+				}
+			},
+		},
+		{
+			// Parses a String into a Date. Without a format, it accepts dates like "2024-01-02", "2024/01/02",
+			// "Jan 2 2024" and "January 2, 2024", and the formats `Time.parse` accepts. Otherwise the format
+			// uses the directives of `strftime`, like "%d/%m/%Y".
+			//
+			// ```ruby
+			// Date.parse("2024-01-02").to_s             # => "2024-01-02"
+			// Date.parse("02/01/2024", "%d/%m/%Y").to_s # => "2024-01-02"
+			// Date.parse("2024-02-30")                  # => ArgumentError
+			// ```
+			// @param date [String], format [String]
+			// @return [Date]
+			Name: "parse",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					tm, err := t.parseTimeArgs(args, dateLayouts, sourceLine)
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initDateObject(tm.Year(), tm.Month(), tm.Day())
+				}
+			},
+		},
+		{
+			// Returns the current date in the local time zone.
+			//
+			// ```ruby
+			// Date.today.to_s # => "2024-01-02"
+			// ```
+			// @return [Date]
+			Name: "today",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					now := t.vm.clock().In(time.Local)
+
+					return t.vm.initDateObject(now.Year(), now.Month(), now.Day())
+				}
+			},
+		},
+		{
+			// Returns true if the given year, month and day make a valid date.
+			//
+			// ```ruby
+			// Date.valid_date?(2024, 2, 29) # => true
+			// Date.valid_date?(2023, 2, 29) # => false
+			// ```
+			// @param year [Integer], month [Integer], day [Integer]
+			// @return [Boolean]
+			Name: "valid_date?",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 3 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 3, len(args))
+					}
+
+					year, month, day, err := t.dateArgs(args, sourceLine)
+					if err != nil {
+						return err
+					}
+
+					return toBooleanObject(validDate(year, month, day))
+				}
+			},
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinDateInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns the Date the given number of days after self.
+			//
+			// ```ruby
+			// (Date.new(2024, 2, 28) + 2).to_s # => "2024-03-01"
+			// ```
+			// @param days [Integer]
+			// @return [Date]
+			Name: "+",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					days, err := t.dateOffsetArg(args[0], sourceLine)
+					if err != nil {
+						return err
+					}
+
+					return receiver.(*DateObject).addDays(t, days)
+				}
+			},
+		},
+		{
+			// Returns the Date the given number of days before self, or the number of days between self
+			// and another Date.
+			//
+			// ```ruby
+			// (Date.new(2024, 3, 1) - 1).to_s        # => "2024-02-29"
+			// Date.new(2024, 3, 1) - Date.new(2024)  # => 60
+			// ```
+			// @param other [Integer/Date]
+			// @return [Date/Integer]
+			Name: "-",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					d := receiver.(*DateObject)
+
+					switch other := args[0].(type) {
+					case *DateObject:
+						return t.vm.InitIntegerObject(int((d.value.Unix() - other.value.Unix()) / 86400))
+					case *IntegerObject:
+						days, err := t.dateOffsetArg(other, sourceLine)
+						if err != nil {
+							return err
+						}
+
+						return d.addDays(t, -days)
+					default:
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Integer or Date", args[0].Class().Name)
+					}
+				}
+			},
+		},
+		{
+			// Returns the Date the given number of months before self. If the day doesn't exist in that month,
+			// it's the last day of the month.
+			//
+			// ```ruby
+			// (Date.new(2024, 3, 31) << 1).to_s # => "2024-02-29"
+			// ```
+			// @param months [Integer]
+			// @return [Date]
+			Name: "<<",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					months, err := t.dateOffsetArg(args[0], sourceLine)
+					if err != nil {
+						return err
+					}
+
+					return receiver.(*DateObject).addMonths(t, -months)
+				}
+			},
+		},
+		{
+			// Returns 1 if self is later than another Date, -1 if it's earlier, 0 if they're the same date,
+			// and nil for anything that isn't a Date. Dates include Comparable, so `<`, `==`, `between?`
+			// and the like are based on this method.
+			//
+			// ```ruby
+			// Date.new(2024, 1, 2) <=> Date.new(2024, 1, 3) # => -1
+			// Date.new(2024, 1, 2) == Date.parse("2024-01-02") # => true
+			// ```
+			// @param other [Date]
+			// @return [Integer]
+			Name: "<=>",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					other, ok := args[0].(*DateObject)
+					if !ok {
+						return NULL
+					}
+
+					return t.vm.InitIntegerObject(compareTimes(receiver.(*DateObject).value, other.value))
+				}
+			},
+		},
+		{
+			// Returns the Date the given number of months after self. If the day doesn't exist in that month,
+			// it's the last day of the month.
+			//
+			// ```ruby
+			// (Date.new(2024, 1, 31) >> 1).to_s  # => "2024-02-29"
+			// (Date.new(2024, 1, 31) >> 12).to_s # => "2025-01-31"
+			// ```
+			// @param months [Integer]
+			// @return [Date]
+			Name: ">>",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					months, err := t.dateOffsetArg(args[0], sourceLine)
+					if err != nil {
+						return err
+					}
+
+					return receiver.(*DateObject).addMonths(t, months)
+				}
+			},
+		},
+		{
+			// Returns the day of the month of self, from 1 to 31.
+			//
+			// ```ruby
+			// Date.new(2024, 1, 2).day # => 2
+			// ```
+			// @return [Integer]
+			Name: "day",
+			Fn:   dateField(func(tm time.Time) int { return tm.Day() }),
+		},
+		{
+			// Returns self in the ISO 8601 format, which is the same as `to_s`.
+			//
+			// ```ruby
+			// Date.new(2024, 1, 2).iso8601 # => "2024-01-02"
+			// ```
+			// @return [String]
+			Name: "iso8601",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return t.vm.InitStringObject(receiver.(*DateObject).toString())
+				}
+			},
+		},
+		{
+			// Returns true if the year of self is a leap year.
+			//
+			// ```ruby
+			// Date.new(2024).leap? # => true
+			// Date.new(2100).leap? # => false
+			// ```
+			// @return [Boolean]
+			Name: "leap?",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return toBooleanObject(daysIn(receiver.(*DateObject).value.Year(), time.February) == 29)
+				}
+			},
+		},
+		{
+			// Returns the month of the year of self, from 1 to 12.
+			//
+			// ```ruby
+			// Date.new(2024, 1, 2).month # => 1
+			// ```
+			// @return [Integer]
+			Name: "month",
+			Fn:   dateField(func(tm time.Time) int { return int(tm.Month()) }),
+		},
+		{
+			// Returns the Date the given number of days, which defaults to 1, after self.
+			//
+			// ```ruby
+			// Date.new(2024, 12, 31).next_day.to_s  # => "2025-01-01"
+			// Date.new(2024, 12, 31).next_day(2).to_s # => "2025-01-02"
+			// ```
+			// @param days [Integer]
+			// @return [Date]
+			Name: "next_day",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) > 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRangeFormat, 0, 1, len(args))
+					}
+
+					days := 1
+					if len(args) == 1 {
+						var err *Error
+						days, err = t.dateOffsetArg(args[0], sourceLine)
+						if err != nil {
+							return err
+						}
+					}
+
+					return receiver.(*DateObject).addDays(t, days)
+				}
+			},
+		},
+		{
+			// Returns the Date the given number of days, which defaults to 1, before self.
+			//
+			// ```ruby
+			// Date.new(2025, 1, 1).prev_day.to_s # => "2024-12-31"
+			// ```
+			// @param days [Integer]
+			// @return [Date]
+			Name: "prev_day",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) > 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRangeFormat, 0, 1, len(args))
+					}
+
+					days := 1
+					if len(args) == 1 {
+						var err *Error
+						days, err = t.dateOffsetArg(args[0], sourceLine)
+						if err != nil {
+							return err
+						}
+					}
+
+					return receiver.(*DateObject).addDays(t, -days)
+				}
+			},
+		},
+		{
+			// Formats self with the directives of `Time#strftime`. The time of day is midnight in UTC.
+			//
+			// ```ruby
+			// Date.new(2024, 1, 2).strftime("%A, %B %-d") # => "Tuesday, January 2"
+			// ```
+			// @param format [String]
+			// @return [String]
+			Name: "strftime",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					format, ok := args[0].(*StringObject)
+					if !ok {
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+					}
+
+					return t.vm.InitStringObject(strftime(receiver.(*DateObject).value, format.value))
+				}
+			},
+		},
+		{
+			// Returns self as a JSON string formatted like "2024-01-02".
+			//
+			// ```ruby
+			// Date.new(2024, 1, 2).to_json # => "\"2024-01-02\""
+			// ```
+			// @return [String]
+			Name: "to_json",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return t.vm.InitStringObject(receiver.(*DateObject).toJSON(t))
+				}
+			},
+		},
+		{
+			// Returns self formatted like "2024-01-02".
+			//
+			// ```ruby
+			// Date.new(2024, 1, 2).to_s # => "2024-01-02"
+			// ```
+			// @return [String]
+			Name: "to_s",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return t.vm.InitStringObject(receiver.(*DateObject).toString())
+				}
+			},
+		},
+		{
+			// Returns the Time at the beginning of self in the given time zone, or the local one.
+			//
+			// ```ruby
+			// Date.new(2024, 1, 2).to_time("UTC").to_s    # => "2024-01-02 00:00:00 UTC"
+			// Date.new(2024, 1, 2).to_time("+09:00").to_i # => 1704121200
+			// ```
+			// @param zone [String]
+			// @return [Time]
+			Name: "to_time",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) > 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRangeFormat, 0, 1, len(args))
+					}
+
+					loc, err := t.locationArg(args, 0, sourceLine)
+					if err != nil {
+						return err
+					}
+
+					d := receiver.(*DateObject).value
+
+					return t.vm.initTimeObject(time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc))
+				}
+			},
+		},
+		{
+			// Returns the day of the week of self, from 0 (Sunday) to 6 (Saturday).
+			//
+			// ```ruby
+			// Date.new(2024, 1, 2).wday # => 2
+			// ```
+			// @return [Integer]
+			Name: "wday",
+			Fn:   dateField(func(tm time.Time) int { return int(tm.Weekday()) }),
+		},
+		{
+			// Returns the day of the year of self, from 1 to 366.
+			//
+			// ```ruby
+			// Date.new(2024, 12, 31).yday # => 366
+			// ```
+			// @return [Integer]
+			Name: "yday",
+			Fn:   dateField(func(tm time.Time) int { return tm.YearDay() }),
+		},
+		{
+			// Returns the year of self.
+			//
+			// ```ruby
+			// Date.new(2024, 1, 2).year # => 2024
+			// ```
+			// @return [Integer]
+			Name: "year",
+			Fn:   dateField(func(tm time.Time) int { return tm.Year() }),
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initDateObject(year int, month time.Month, day int) *DateObject {
+	return &DateObject{
+		baseObj: &baseObj{class: vm.topLevelClass(classes.DateClass)},
+		value:   time.Date(year, month, day, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func (vm *VM) initDateClass() *RClass {
+	dc := vm.initializeClass(classes.DateClass)
+	dc.setBuiltinMethods(builtinDateInstanceMethods(), false)
+	dc.setBuiltinMethods(builtinDateClassMethods(), true)
+	return dc
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// Value returns the object
+func (d *DateObject) Value() interface{} {
+	return d.value
+}
+
+// toString returns the object's value formatted like "2024-01-02"
+func (d *DateObject) toString() string {
+	return d.value.Format("2006-01-02")
+}
+
+// toJSON returns the object's value formatted like "2024-01-02"
+func (d *DateObject) toJSON(t *Thread) string {
+	return strconv.Quote(d.toString())
+}
+
+// addDays returns the Date the given number of days after self.
+func (d *DateObject) addDays(t *Thread, days int) *DateObject {
+	return t.vm.initDateObject(d.value.Year(), d.value.Month(), d.value.Day()+days)
+}
+
+// addMonths returns the Date the given number of months after self, clamped to the end of the month.
+func (d *DateObject) addMonths(t *Thread, months int) *DateObject {
+	// Normalize the month with a date at the beginning of it, and clamp the day afterwards
+	first := time.Date(d.value.Year(), d.value.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+
+	day := d.value.Day()
+	if last := daysIn(first.Year(), first.Month()); day > last {
+		day = last
+	}
+
+	return t.vm.initDateObject(first.Year(), first.Month(), day)
+}
+
+// Other helper functions -----------------------------------------------
+
+// dateLayouts are the layouts `Date.parse` tries when it isn't given a format.
+var dateLayouts = append([]string{
+	"2006/01/02",
+	"Jan 2 2006",
+	"January 2, 2006",
+	"2 Jan 2006",
+}, timeLayouts...)
+
+// dateArgs returns the year, month and day in args, which default to 1.
+func (t *Thread) dateArgs(args []Object, sourceLine int) (int, int, int, *Error) {
+	fields := []int{0, 1, 1}
+
+	for i, arg := range args {
+		n, ok := arg.(*IntegerObject)
+		if !ok {
+			return 0, 0, 0, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, arg.Class().Name)
+		}
+
+		if n.bigValue != nil || n.value < math.MinInt32 || n.value > math.MaxInt32 {
+			return 0, 0, 0, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Invalid date")
+		}
+
+		fields[i] = n.value
+	}
+
+	return fields[0], fields[1], fields[2], nil
+}
+
+// dateOffsetArg returns the number of days or months to move a Date by.
+func (t *Thread) dateOffsetArg(arg Object, sourceLine int) (int, *Error) {
+	n, ok := arg.(*IntegerObject)
+	if !ok {
+		return 0, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, arg.Class().Name)
+	}
+
+	// Dates beyond about 5 million years would overflow Go's time
+	if n.bigValue != nil || n.value < -math.MaxInt32 || n.value > math.MaxInt32 {
+		return 0, t.vm.InitErrorObject(errors.RangeError, sourceLine, "%s is out of range for Date", n.toString())
+	}
+
+	return n.value, nil
+}
+
+// dateField returns the body of a Date method that returns an Integer field of the Date.
+func dateField(field func(tm time.Time) int) func(receiver Object, sourceLine int) builtinMethodBody {
+	return func(receiver Object, sourceLine int) builtinMethodBody {
+		return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+			}
+
+			return t.vm.InitIntegerObject(field(receiver.(*DateObject).value))
+		}
+	}
+}
+
+// validDate returns true if the year, month and day make a valid date.
+func validDate(year int, month int, day int) bool {
+	return month >= 1 && month <= 12 && day >= 1 && day <= daysIn(year, time.Month(month))
+}
+
+// daysIn returns the number of days in the month of the year.
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
@@ -0,0 +1,150 @@
+package vm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDateClassMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Date.new(2024, 1, 2).to_s`, "2024-01-02"},
+		{`Date.new(2024, 2).to_s`, "2024-02-01"},
+		{`Date.new(2024).to_s`, "2024-01-01"},
+		{`Date.new(2024, 2, 29).day`, 29},
+		{`Date.today.to_s`, "2024-01-02"},
+		{`Date.parse("2024-01-02").to_s`, "2024-01-02"},
+		{`Date.parse("2024/01/02").to_s`, "2024-01-02"},
+		{`Date.parse("Jan 2 2024").to_s`, "2024-01-02"},
+		{`Date.parse("January 2, 2024").to_s`, "2024-01-02"},
+		{`Date.parse("2 Jan 2024").to_s`, "2024-01-02"},
+		{`Date.parse("2024-01-02T23:04:05-05:00").to_s`, "2024-01-02"},
+		{`Date.parse("02/01/2024", "%d/%m/%Y").to_s`, "2024-01-02"},
+		{`Date.parse("2/1/24", "%d/%m/%y").to_s`, "2024-01-02"},
+		{`Date.valid_date?(2024, 2, 29)`, true},
+		{`Date.valid_date?(2023, 2, 29)`, false},
+		{`Date.valid_date?(2024, 0, 1)`, false},
+		{`Date.valid_date?(2024, 4, 31)`, false},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		v.clock = func() time.Time { return time.Date(2024, 1, 2, 12, 0, 0, 0, time.Local) }
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestDateClassMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Date.new(2023, 2, 29)`, "ArgumentError: Invalid date", 1},
+		{`Date.new(2024, 13, 1)`, "ArgumentError: Invalid date", 1},
+		{`Date.new(2024, 1, 0)`, "ArgumentError: Invalid date", 1},
+		{`Date.new(2024, "1")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`Date.new`, "ArgumentError: Expect 1..3 arguments. got: 0", 1},
+		{`Date.parse("2024-02-30")`, "ArgumentError: Can't parse \"2024-02-30\"", 1},
+		{`Date.parse("someday")`, "ArgumentError: Can't parse \"someday\"", 1},
+		{`Date.parse("2024-01-02", "%Y-%m-%d %H")`, "ArgumentError: Can't parse \"2024-01-02\" with format \"%Y-%m-%d %H\"", 1},
+		{`Date.valid_date?(2024, 1)`, "ArgumentError: Expect 3 arguments. got: 2", 1},
+		{`Date.today(1)`, "ArgumentError: Expect 0 arguments. got: 1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestDateInstanceMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Date.new(2024, 1, 2).year`, 2024},
+		{`Date.new(2024, 1, 2).month`, 1},
+		{`Date.new(2024, 1, 2).day`, 2},
+		{`Date.new(2024, 1, 2).wday`, 2},
+		{`Date.new(2024, 12, 31).yday`, 366},
+		{`Date.new(2024).leap?`, true},
+		{`Date.new(2100).leap?`, false},
+		{`Date.new(2000).leap?`, true},
+		{`Date.new(2024, 1, 2).iso8601`, "2024-01-02"},
+		{`Date.new(2024, 1, 2).strftime("%A, %B %-d %Y")`, "Tuesday, January 2 2024"},
+		{`Date.new(2024, 1, 2).to_json`, `"2024-01-02"`},
+		{`{ date: Date.new(2024, 1, 2) }.to_json`, `{"date":"2024-01-02"}`},
+		{`Date.new(2024, 1, 2).to_time("UTC").to_s`, "2024-01-02 00:00:00 UTC"},
+		{`Date.new(2024, 1, 2).to_time("+09:00").to_i`, 1704121200},
+		{`Date.new(2024, 12, 31).next_day.to_s`, "2025-01-01"},
+		{`Date.new(2024, 12, 31).next_day(2).to_s`, "2025-01-02"},
+		{`Date.new(2025, 1, 1).prev_day.to_s`, "2024-12-31"},
+		{`Date.new(2024, 3, 1).prev_day(-1).to_s`, "2024-03-02"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestDateArithmeticAndComparison(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`(Date.new(2024, 2, 28) + 2).to_s`, "2024-03-01"},
+		{`(Date.new(2024, 3, 1) - 1).to_s`, "2024-02-29"},
+		{`(Date.new(2024, 1, 1) + 366).to_s`, "2025-01-01"},
+		{`Date.new(2024, 3, 1) - Date.new(2024)`, 60},
+		{`Date.new(2024) - Date.new(2024, 3, 1)`, -60},
+		{`Date.new(2025) - Date.new(1970)`, 20089},
+		{`(Date.new(2024, 1, 31) >> 1).to_s`, "2024-02-29"},
+		{`(Date.new(2023, 1, 31) >> 1).to_s`, "2023-02-28"},
+		{`(Date.new(2024, 1, 31) >> 12).to_s`, "2025-01-31"},
+		{`(Date.new(2024, 1, 31) >> -1).to_s`, "2023-12-31"},
+		{`(Date.new(2024, 3, 31) << 1).to_s`, "2024-02-29"},
+		{`(Date.new(2024, 3, 31) << 14).to_s`, "2023-01-31"},
+		{`Date.new(2024, 1, 2) <=> Date.new(2024, 1, 3)`, -1},
+		{`Date.new(2024, 1, 2) <=> Date.new(2024, 1, 2)`, 0},
+		{`Date.new(2024, 1, 2) <=> "2024-01-02"`, nil},
+		{`Date.new(2024, 1, 2) == Date.parse("2024-01-02")`, true},
+		{`Date.new(2024, 1, 2) > Date.new(2023, 12, 31)`, true},
+		{`Date.new(2024, 6, 1).between?(Date.new(2024), Date.new(2025))`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestDateArithmeticFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Date.new(2024) + 1.5`, "TypeError: Expect argument to be Integer. got: Float", 1},
+		{`Date.new(2024) - "1"`, "TypeError: Expect argument to be Integer or Date. got: String", 1},
+		{`Date.new(2024) >> Date.new(2024)`, "TypeError: Expect argument to be Integer. got: Date", 1},
+		{`Date.new(2024) + 10000000000`, "RangeError: 10000000000 is out of range for Date", 1},
+		{`Date.new(2024).next_day("1")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`Date.new(2024) < Time.now`, "ArgumentError: Can't compare Date with Time", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
@@ -133,11 +133,12 @@ func builtinDecimalInstanceMethods() []*BuiltinMethodObject {
 		{
 			// Returns self squaring a decimal.
 			// If the second term is integer or float, they are converted into decimal and then perform calculation.
-			// Note that the calculation is via float64 (math.Pow) for now.
+			// An integral exponent is calculated exactly; other exponents are calculated via float64 (math.Pow) for now.
 			//
 			// ```Ruby
 			// "4.0".to_d ** "2.5".to_d     # => 32
 			// "4.0".to_d ** 2              # => 16
+			// "1.1".to_d ** 2              # => 1.21
 			// "4.0".to_d ** "2.5".to_f     # => 32
 			// "4.0".to_d ** "2.1".to_d
 			// #=> 18.379173679952561570871694129891693592071533203125
@@ -150,9 +151,7 @@ func builtinDecimalInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 					operation := func(leftValue *Decimal, rightValue *Decimal) *Decimal {
-						l, _ := leftValue.Float64()
-						r, _ := rightValue.Float64()
-						return new(Decimal).SetFloat64(math.Pow(l, r))
+						return decimalPower(leftValue, rightValue)
 					}
 
 					return receiver.(*DecimalObject).arithmeticOperation(t, args[0], operation, sourceLine, false)
@@ -373,16 +372,16 @@ func builtinDecimalInstanceMethods() []*BuiltinMethodObject {
 		{
 			// Returns if self is not equal to an Object.
 			// If the second term is integer or float, they are converted into decimal and then perform calculation.
-			// If the Object is not a Numeric the result is always false.
+			// If the Object is not a Numeric the result is always true.
 			//
 			// ```Ruby
-			// "1.0".to_d != 3           # => false
-			// "1.0".to_d != 1           # => true
-			// "1.0".to_d != "1".to_d    # => true
-			// "1.0".to_d != "1".to_f    # => false
-			// "1.0".to_d != "1.0".to_f  # => false
-			// "1.0".to_d != 'str'       # => false
-			// "1.0".to_d != Array       # => false
+			// "1.0".to_d != 3           # => true
+			// "1.0".to_d != 1           # => false
+			// "1.0".to_d != "1".to_d    # => false
+			// "1.0".to_d != "1".to_f    # => true
+			// "1.0".to_d != "1.0".to_f  # => true
+			// "1.0".to_d != 'str'       # => true
+			// "1.0".to_d != Array       # => true
 			// ```
 			//
 			// @return [Boolean]
@@ -544,13 +543,15 @@ func builtinDecimalInstanceMethods() []*BuiltinMethodObject {
 			// ```Ruby
 			// a = "355/113".to_d
 			// a.to_i # => 3
+			// "100000000000000000000.9".to_d.to_i # => 100000000000000000000
 			// ```
 			//
 			// @return [Integer]
 			Name: "to_i",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-					return t.vm.InitIntegerObject(receiver.(*DecimalObject).IntegerValue())
+					d := receiver.(*DecimalObject).value
+					return t.vm.initBigIntegerObject(new(big.Int).Quo(d.Num(), d.Denom()))
 				}
 			},
 		},
@@ -636,7 +637,12 @@ func (d *DecimalObject) arithmeticOperation(
 	}
 
 	leftValue := d.value
-	result = *decimalOperation(leftValue, rightValue)
+	r := decimalOperation(leftValue, rightValue)
+	if r == nil {
+		return t.vm.InitErrorObject(errors.ZeroDivisionError, sourceLine, errors.DividedByZero)
+	}
+
+	result = *r
 	return t.vm.initDecimalObject(&result)
 }
 
@@ -655,6 +661,8 @@ func (d *DecimalObject) equalityTest(
 	switch rightObject.(type) {
 	case *DecimalObject:
 		rightValue = rightObject.(*DecimalObject).value
+	case *IntegerObject:
+		rightValue = intToDecimal(rightObject)
 	default:
 		return toBooleanObject(nonInverse == false)
 	}
@@ -737,7 +745,31 @@ func assertNumeric(n Object) (v *Decimal, result bool) {
 
 // intToDecimal converts int to Decimal
 func intToDecimal(i Object) *Decimal {
-	return new(Decimal).SetInt64(int64(i.(*IntegerObject).value))
+	return new(Decimal).SetInt(i.(*IntegerObject).bigInt())
+}
+
+// decimalPower returns base raised to exponent, which is exact if the exponent is an integer.
+// It returns nil if base is 0 and exponent is negative.
+func decimalPower(base *Decimal, exponent *Decimal) *Decimal {
+	if !exponent.IsInt() || !exponent.Num().IsInt64() {
+		l, _ := base.Float64()
+		r, _ := exponent.Float64()
+		return new(Decimal).SetFloat64(math.Pow(l, r))
+	}
+
+	n := exponent.Num()
+	if n.Sign() < 0 {
+		if base.Sign() == 0 {
+			return nil
+		}
+
+		base = new(Decimal).Inv(base)
+		n = new(big.Int).Neg(n)
+	}
+
+	num := new(big.Int).Exp(base.Num(), n, nil)
+	denom := new(big.Int).Exp(base.Denom(), n, nil)
+	return new(Decimal).SetFrac(num, denom)
 }
 
 // floatToDecimal converts int to Decimal
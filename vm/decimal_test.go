@@ -192,7 +192,10 @@ func TestDecimalEquality(t *testing.T) {
 		input    string
 		expected interface{}
 	}{
-		{`'123'.to_d    ==  123`, false},
+		{`'123'.to_d    ==  123`, true},
+		{`'123.5'.to_d  ==  123`, false},
+		{`(2**64).to_d  ==  2**64`, true},
+		{`'123'.to_d    !=  123`, false},
 		{`'123'.to_d.to_i    ==  123`, true},
 		{`'123.5'.to_d  ==  '123.5'.to_d`, true},
 		{`'123.5'.to_d  ==  '124'.to_d`, false},
@@ -289,6 +292,31 @@ func TestDecimalToInteger(t *testing.T) {
 	}
 }
 
+func TestDecimalExactIntegerInterop(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`'100000000000000000000.9'.to_d.to_i.to_s`, "100000000000000000000"},
+		{`'-100000000000000000000.9'.to_d.to_i.to_s`, "-100000000000000000000"},
+		{`('1.1'.to_d ** 2).to_s`, "1.21"},
+		{`('2'.to_d ** -2).to_s`, "0.25"},
+		{`('0.5'.to_d ** 100).fraction`, "1/1267650600228229401496703205376"},
+		{`('1'.to_d + 2 ** 64).to_s`, "18446744073709551617"},
+		{`('0.5'.to_d * (2 ** 64)).to_s`, "9223372036854775808"},
+		{`'18446744073709551616'.to_d > 2 ** 64`, false},
+		{`'18446744073709551616'.to_d <=> 2 ** 64`, 0},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestDecimalToNumeric(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -0,0 +1,576 @@
+package vm
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// DurationObject represents an amount of elapsed time with nanosecond precision, backed by Go's `time.Duration`.
+// It's what subtracting two Times or `Time.measure` returns, and it can be added to Times.
+// A Duration can be about 292 years long at most.
+//
+// ```ruby
+// d = Duration.minutes(90)
+// d.to_s                      # => "1h30m0s"
+// d.to_i                      # => 5400
+// (d * 2).to_s                # => "3h0m0s"
+// Duration.parse("1.5s").to_f # => 1.5
+// ```
+//
+// - `Duration.new` is not supported.
+type DurationObject struct {
+	*baseObj
+	value time.Duration
+}
+
+// Class methods --------------------------------------------------------
+func builtinDurationClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns a Duration of the given number of hours.
+			//
+			// ```ruby
+			// Duration.hours(2).to_s   # => "2h0m0s"
+			// Duration.hours(0.5).to_s # => "30m0s"
+			// ```
+			// @param hours [Numeric]
+			// @return [Duration]
+			Name: "hours",
+			Fn:   durationConstructor(time.Hour),
+		},
+		{
+			// Returns a Duration of the given number of microseconds.
+			//
+			// ```ruby
+			// Duration.microseconds(1500).to_s # => "1.5ms"
+			// ```
+			// @param microseconds [Numeric]
+			// @return [Duration]
+			Name: "microseconds",
+			Fn:   durationConstructor(time.Microsecond),
+		},
+		{
+			// Returns a Duration of the given number of milliseconds.
+			//
+			// ```ruby
+			// Duration.milliseconds(1500).to_s # => "1.5s"
+			// ```
+			// @param milliseconds [Numeric]
+			// @return [Duration]
+			Name: "milliseconds",
+			Fn:   durationConstructor(time.Millisecond),
+		},
+		{
+			// Returns a Duration of the given number of minutes.
+			//
+			// ```ruby
+			// Duration.minutes(90).to_s # => "1h30m0s"
+			// ```
+			// @param minutes [Numeric]
+			// @return [Duration]
+			Name: "minutes",
+			Fn:   durationConstructor(time.Minute),
+		},
+		{
+			// Returns a Duration of the given number of nanoseconds.
+			//
+			// ```ruby
+			// Duration.nanoseconds(1500).to_s # => "1.5µs"
+			// ```
+			// @param nanoseconds [Integer]
+			// @return [Duration]
+			Name: "nanoseconds",
+			Fn:   durationConstructor(time.Nanosecond),
+		},
+		{
+			Name: "new",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					return t.vm.initUnsupportedMethodError(sourceLine, "#new", receiver)
+				}
+			},
+		},
+		{
+			// Parses a Duration like "1h30m", "1.5s" or "-300ms". The units are "ns", "us" (or "µs"), "ms",
+			// "s", "m" and "h".
+			//
+			// ```ruby
+			// Duration.parse("1h30m").to_i # => 5400
+			// Duration.parse("250ms").to_f # => 0.25
+			// Duration.parse("soon")       # => ArgumentError
+			// ```
+			// @param duration [String]
+			// @return [Duration]
+			Name: "parse",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					s, ok := args[0].(*StringObject)
+					if !ok {
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+					}
+
+					d, err := time.ParseDuration(s.value)
+					if err != nil {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Invalid duration: %q", s.value)
+					}
+
+					return t.vm.initDurationObject(d)
+				}
+			},
+		},
+		{
+			// Returns a Duration of the given number of seconds.
+			//
+			// ```ruby
+			// Duration.seconds(90).to_s  # => "1m30s"
+			// Duration.seconds(0.5).to_s # => "500ms"
+			// ```
+			// @param seconds [Numeric]
+			// @return [Duration]
+			Name: "seconds",
+			Fn:   durationConstructor(time.Second),
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinDurationInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns the sum of self and another Duration. Adding a Time returns a Time.
+			//
+			// ```ruby
+			// (Duration.seconds(1) + Duration.milliseconds(500)).to_s # => "1.5s"
+			// ```
+			// @param other [Duration/Time]
+			// @return [Duration/Time]
+			Name: "+",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					d := receiver.(*DurationObject)
+
+					switch other := args[0].(type) {
+					case *DurationObject:
+						return d.add(t, other.value, sourceLine)
+					case *TimeObject:
+						return t.vm.initTimeObject(other.value.Add(d.value))
+					default:
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Duration or Time", args[0].Class().Name)
+					}
+				}
+			},
+		},
+		{
+			// Returns the difference between self and another Duration.
+			//
+			// ```ruby
+			// (Duration.minutes(1) - Duration.seconds(15)).to_s # => "45s"
+			// ```
+			// @param other [Duration]
+			// @return [Duration]
+			Name: "-",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					other, ok := args[0].(*DurationObject)
+					if !ok {
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.DurationClass, args[0].Class().Name)
+					}
+
+					if other.value == math.MinInt64 {
+						return t.vm.InitErrorObject(errors.RangeError, sourceLine, "Duration is out of range")
+					}
+
+					return receiver.(*DurationObject).add(t, -other.value, sourceLine)
+				}
+			},
+		},
+		{
+			// Returns self multiplied by a Numeric.
+			//
+			// ```ruby
+			// (Duration.seconds(90) * 2).to_s   # => "3m0s"
+			// (Duration.seconds(90) * 0.5).to_s # => "45s"
+			// ```
+			// @param factor [Numeric]
+			// @return [Duration]
+			Name: "*",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					return t.vm.numericToDuration(args[0], receiver.(*DurationObject).value, sourceLine)
+				}
+			},
+		},
+		{
+			// Returns self divided by a Numeric, or the ratio of self and another Duration as a Float.
+			// Dividing by an Integer truncates to whole nanoseconds.
+			//
+			// ```ruby
+			// (Duration.minutes(3) / 2).to_s             # => "1m30s"
+			// Duration.minutes(3) / Duration.seconds(40) # => 4.5
+			// ```
+			// @param divisor [Numeric/Duration]
+			// @return [Duration/Float]
+			Name: "/",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					d := receiver.(*DurationObject).value
+
+					switch divisor := args[0].(type) {
+					case *DurationObject:
+						if divisor.value == 0 {
+							return t.vm.InitErrorObject(errors.ZeroDivisionError, sourceLine, errors.DividedByZero)
+						}
+
+						return t.vm.initFloatObject(float64(d) / float64(divisor.value))
+					case *IntegerObject:
+						if divisor.isZero() {
+							return t.vm.InitErrorObject(errors.ZeroDivisionError, sourceLine, errors.DividedByZero)
+						}
+
+						quotient := new(big.Int).Quo(big.NewInt(int64(d)), divisor.bigInt())
+						if !quotient.IsInt64() {
+							return t.vm.InitErrorObject(errors.RangeError, sourceLine, "Duration is out of range")
+						}
+
+						return t.vm.initDurationObject(time.Duration(quotient.Int64()))
+					case *FloatObject:
+						if divisor.value == 0 {
+							return t.vm.InitErrorObject(errors.ZeroDivisionError, sourceLine, errors.DividedByZero)
+						}
+
+						return t.vm.floatToDuration(float64(d)/divisor.value, sourceLine)
+					default:
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Numeric or Duration", args[0].Class().Name)
+					}
+				}
+			},
+		},
+		{
+			// Returns 1 if self is longer than another Duration, -1 if it's shorter, 0 if they're equal,
+			// and nil for anything that isn't a Duration. Durations include Comparable, so `<`, `==`,
+			// `between?` and the like are based on this method.
+			//
+			// ```ruby
+			// Duration.seconds(60) <=> Duration.minutes(1) # => 0
+			// Duration.seconds(1) < Duration.minutes(1)    # => true
+			// ```
+			// @param other [Duration]
+			// @return [Integer]
+			Name: "<=>",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					other, ok := args[0].(*DurationObject)
+					if !ok {
+						return NULL
+					}
+
+					d := receiver.(*DurationObject).value
+
+					switch {
+					case d < other.value:
+						return t.vm.InitIntegerObject(-1)
+					case d > other.value:
+						return t.vm.InitIntegerObject(1)
+					default:
+						return t.vm.InitIntegerObject(0)
+					}
+				}
+			},
+		},
+		{
+			// Returns the absolute value of self.
+			//
+			// ```ruby
+			// Duration.seconds(-5).abs.to_s # => "5s"
+			// ```
+			// @return [Duration]
+			Name: "abs",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					d := receiver.(*DurationObject).value
+					if d >= 0 {
+						return receiver
+					}
+
+					if d == math.MinInt64 {
+						return t.vm.InitErrorObject(errors.RangeError, sourceLine, "Duration is out of range")
+					}
+
+					return t.vm.initDurationObject(-d)
+				}
+			},
+		},
+		{
+			// Returns the number of whole milliseconds in self.
+			//
+			// ```ruby
+			// Duration.seconds(1.5).milliseconds # => 1500
+			// ```
+			// @return [Integer]
+			Name: "milliseconds",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return t.vm.InitIntegerObject(int(receiver.(*DurationObject).value / time.Millisecond))
+				}
+			},
+		},
+		{
+			// Returns the number of nanoseconds in self.
+			//
+			// ```ruby
+			// Duration.seconds(1.5).nanoseconds # => 1500000000
+			// ```
+			// @return [Integer]
+			Name: "nanoseconds",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return t.vm.initBigIntegerObject(big.NewInt(int64(receiver.(*DurationObject).value)))
+				}
+			},
+		},
+		{
+			// Returns true if self is shorter than zero.
+			//
+			// ```ruby
+			// Duration.seconds(-1).negative? # => true
+			// ```
+			// @return [Boolean]
+			Name: "negative?",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return toBooleanObject(receiver.(*DurationObject).value < 0)
+				}
+			},
+		},
+		{
+			// Returns the number of seconds in self as a Float.
+			//
+			// ```ruby
+			// Duration.milliseconds(250).to_f # => 0.25
+			// ```
+			// @return [Float]
+			Name: "to_f",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return t.vm.initFloatObject(receiver.(*DurationObject).value.Seconds())
+				}
+			},
+		},
+		{
+			// Returns the number of whole seconds in self.
+			//
+			// ```ruby
+			// Duration.milliseconds(2500).to_i # => 2
+			// ```
+			// @return [Integer]
+			Name: "to_i",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return t.vm.InitIntegerObject(int(receiver.(*DurationObject).value / time.Second))
+				}
+			},
+		},
+		{
+			// Returns the number of seconds in self as JSON.
+			//
+			// ```ruby
+			// Duration.milliseconds(1500).to_json # => "1.5"
+			// ```
+			// @return [String]
+			Name: "to_json",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return t.vm.InitStringObject(receiver.(*DurationObject).toJSON(t))
+				}
+			},
+		},
+		{
+			// Returns self formatted like "1h30m0s", which `Duration.parse` accepts.
+			//
+			// ```ruby
+			// Duration.seconds(5400).to_s     # => "1h30m0s"
+			// Duration.milliseconds(1.5).to_s # => "1.5ms"
+			// ```
+			// @return [String]
+			Name: "to_s",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return t.vm.InitStringObject(receiver.(*DurationObject).toString())
+				}
+			},
+		},
+		{
+			// Returns true if self is zero.
+			//
+			// ```ruby
+			// (Duration.seconds(1) - Duration.seconds(1)).zero? # => true
+			// ```
+			// @return [Boolean]
+			Name: "zero?",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return toBooleanObject(receiver.(*DurationObject).value == 0)
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initDurationObject(value time.Duration) *DurationObject {
+	return &DurationObject{
+		baseObj: &baseObj{class: vm.topLevelClass(classes.DurationClass)},
+		value:   value,
+	}
+}
+
+func (vm *VM) initDurationClass() *RClass {
+	dc := vm.initializeClass(classes.DurationClass)
+	// Class methods are set as instance methods too, so instance methods like `milliseconds` are set last
+	dc.setBuiltinMethods(builtinDurationClassMethods(), true)
+	dc.setBuiltinMethods(builtinDurationInstanceMethods(), false)
+	return dc
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// Value returns the object
+func (d *DurationObject) Value() interface{} {
+	return d.value
+}
+
+// toString returns the object's value formatted like "1h30m0s"
+func (d *DurationObject) toString() string {
+	return d.value.String()
+}
+
+// toJSON returns the number of seconds in the Duration
+func (d *DurationObject) toJSON(t *Thread) string {
+	return strconv.FormatFloat(d.value.Seconds(), 'f', -1, 64)
+}
+
+// add returns the sum of self and another duration, or a RangeError if it overflows.
+func (d *DurationObject) add(t *Thread, other time.Duration, sourceLine int) Object {
+	sum := d.value + other
+
+	if (sum > d.value) != (other > 0) {
+		return t.vm.InitErrorObject(errors.RangeError, sourceLine, "Duration is out of range")
+	}
+
+	return t.vm.initDurationObject(sum)
+}
+
+// Other helper functions -----------------------------------------------
+
+// durationConstructor returns the body of a Duration class method that returns a Duration of the given
+// number of units.
+func durationConstructor(unit time.Duration) func(receiver Object, sourceLine int) builtinMethodBody {
+	return func(receiver Object, sourceLine int) builtinMethodBody {
+		return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+			}
+
+			return t.vm.numericToDuration(args[0], unit, sourceLine)
+		}
+	}
+}
+
+// numericToDuration returns a Duration of n units, rounded to whole nanoseconds.
+// It returns a RangeError if the Duration is too long.
+func (vm *VM) numericToDuration(n Object, unit time.Duration, sourceLine int) Object {
+	switch n := n.(type) {
+	case *IntegerObject:
+		product := new(big.Int).Mul(n.bigInt(), big.NewInt(int64(unit)))
+		if !product.IsInt64() {
+			return vm.InitErrorObject(errors.RangeError, sourceLine, "Duration is out of range")
+		}
+
+		return vm.initDurationObject(time.Duration(product.Int64()))
+	case *FloatObject:
+		return vm.floatToDuration(n.value*float64(unit), sourceLine)
+	default:
+		return vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Numeric", n.Class().Name)
+	}
+}
+
+// floatToDuration returns a Duration of the given number of nanoseconds, rounded to a whole number.
+// It returns a RangeError if the Duration is too long.
+func (vm *VM) floatToDuration(nanoseconds float64, sourceLine int) Object {
+	rounded := math.Round(nanoseconds)
+
+	// float64(math.MaxInt64) rounds up to 2 ** 63, which doesn't fit
+	if math.IsNaN(rounded) || rounded >= math.MaxInt64 || rounded < math.MinInt64 {
+		return vm.InitErrorObject(errors.RangeError, sourceLine, "Duration is out of range")
+	}
+
+	return vm.initDurationObject(time.Duration(rounded))
+}
@@ -0,0 +1,119 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestDurationClassMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Duration.hours(2).to_s`, "2h0m0s"},
+		{`Duration.hours(0.5).to_s`, "30m0s"},
+		{`Duration.minutes(90).to_s`, "1h30m0s"},
+		{`Duration.seconds(90).to_s`, "1m30s"},
+		{`Duration.seconds(0.5).to_s`, "500ms"},
+		{`Duration.seconds(-5).to_s`, "-5s"},
+		{`Duration.milliseconds(1500).to_s`, "1.5s"},
+		{`Duration.microseconds(1500).to_s`, "1.5ms"},
+		{`Duration.nanoseconds(1500).to_s`, "1.5µs"},
+		{`Duration.parse("1h30m").to_i`, 5400},
+		{`Duration.parse("250ms").to_f`, 0.25},
+		{`Duration.parse("-1.5s").to_s`, "-1.5s"},
+		{`Duration.parse(Duration.minutes(90).to_s) == Duration.minutes(90)`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestDurationClassMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Duration.new`, "UnsupportedMethodError: Unsupported Method #new for Duration", 1},
+		{`Duration.seconds("1")`, "TypeError: Expect argument to be Numeric. got: String", 1},
+		{`Duration.seconds`, "ArgumentError: Expect 1 arguments. got: 0", 1},
+		{`Duration.hours(3000000)`, "RangeError: Duration is out of range", 1},
+		{`Duration.hours(3000000.0)`, "RangeError: Duration is out of range", 1},
+		{`Duration.seconds(Float::NAN)`, "RangeError: Duration is out of range", 1},
+		{`Duration.parse("soon")`, "ArgumentError: Invalid duration: \"soon\"", 1},
+		{`Duration.parse(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestDurationInstanceMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`(Duration.seconds(1) + Duration.milliseconds(500)).to_s`, "1.5s"},
+		{`(Duration.minutes(1) - Duration.seconds(15)).to_s`, "45s"},
+		{`(Duration.seconds(90) * 2).to_s`, "3m0s"},
+		{`(Duration.seconds(90) * 0.5).to_s`, "45s"},
+		{`(Duration.minutes(3) / 2).to_s`, "1m30s"},
+		{`(Duration.nanoseconds(3) / 2).nanoseconds`, 1},
+		{`(Duration.minutes(3) / 0.5).to_s`, "6m0s"},
+		{`Duration.minutes(3) / Duration.seconds(40)`, 4.5},
+		{`Duration.seconds(60) <=> Duration.minutes(1)`, 0},
+		{`Duration.seconds(1) <=> Duration.minutes(1)`, -1},
+		{`Duration.seconds(1) <=> 1`, nil},
+		{`Duration.seconds(1) < Duration.minutes(1)`, true},
+		{`Duration.seconds(60) == Duration.minutes(1)`, true},
+		{`Duration.seconds(-5).abs.to_s`, "5s"},
+		{`Duration.seconds(5).abs.to_s`, "5s"},
+		{`Duration.seconds(-1).negative?`, true},
+		{`Duration.seconds(0).negative?`, false},
+		{`(Duration.seconds(1) - Duration.seconds(1)).zero?`, true},
+		{`Duration.seconds(1.5).milliseconds`, 1500},
+		{`Duration.seconds(1.5).nanoseconds`, 1500000000},
+		{`Duration.milliseconds(2500).to_i`, 2},
+		{`Duration.milliseconds(-2500).to_i`, -2},
+		{`Duration.milliseconds(250).to_f`, 0.25},
+		{`Duration.milliseconds(1500).to_json`, "1.5"},
+		{`{ elapsed: Duration.seconds(90) }.to_json`, `{"elapsed":90}`},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestDurationInstanceMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Duration.seconds(1) + 1`, "TypeError: Expect argument to be Duration or Time. got: Integer", 1},
+		{`Duration.seconds(1) - 1`, "TypeError: Expect argument to be Duration. got: Integer", 1},
+		{`Duration.seconds(1) * "2"`, "TypeError: Expect argument to be Numeric. got: String", 1},
+		{`Duration.seconds(1) / "2"`, "TypeError: Expect argument to be Numeric or Duration. got: String", 1},
+		{`Duration.seconds(1) / 0`, "ZeroDivisionError: Divided by 0", 1},
+		{`Duration.seconds(1) / 0.0`, "ZeroDivisionError: Divided by 0", 1},
+		{`Duration.seconds(1) / Duration.seconds(0)`, "ZeroDivisionError: Divided by 0", 1},
+		{`Duration.hours(2000000) + Duration.hours(2000000)`, "RangeError: Duration is out of range", 1},
+		{`Duration.hours(2000000) * 2`, "RangeError: Duration is out of range", 1},
+		{`Duration.seconds(1) < 1`, "ArgumentError: Can't compare Duration with Integer", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
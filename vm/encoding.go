@@ -0,0 +1,144 @@
+package vm
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Encodings a String's bytes can be interpreted in. Strings are UTF-8 unless converted with
+// `encode` or relabeled with `force_encoding`.
+const (
+	utf8Encoding   = "UTF-8"
+	latin1Encoding = "ISO-8859-1"
+	asciiEncoding  = "US-ASCII"
+)
+
+var encodingAliases = map[string]string{
+	"UTF-8":      utf8Encoding,
+	"UTF8":       utf8Encoding,
+	"ISO-8859-1": latin1Encoding,
+	"ISO8859-1":  latin1Encoding,
+	"LATIN1":     latin1Encoding,
+	"US-ASCII":   asciiEncoding,
+	"ASCII":      asciiEncoding,
+}
+
+// lookupEncoding returns the canonical name of the given encoding name, which is case-insensitive.
+func lookupEncoding(name string) (string, bool) {
+	encoding, ok := encodingAliases[strings.ToUpper(name)]
+	return encoding, ok
+}
+
+// decodeRune returns the first character of s in the given encoding and its width in bytes.
+// An invalid byte is returned as utf8.RuneError with width 1.
+func decodeRune(s string, encoding string) (rune, int) {
+	switch encoding {
+	case latin1Encoding:
+		return rune(s[0]), 1
+	case asciiEncoding:
+		if s[0] >= utf8.RuneSelf {
+			return utf8.RuneError, 1
+		}
+		return rune(s[0]), 1
+	default:
+		return utf8.DecodeRuneInString(s)
+	}
+}
+
+// maxRune returns the largest code point the given encoding can represent.
+func maxRune(encoding string) rune {
+	switch encoding {
+	case latin1Encoding:
+		return 0xFF
+	case asciiEncoding:
+		return utf8.RuneSelf - 1
+	default:
+		return utf8.MaxRune
+	}
+}
+
+// encodeRunes converts the runes to the given encoding. It returns the first rune that can't be
+// represented in the encoding if there is one.
+func encodeRunes(runes []rune, encoding string) (string, rune, bool) {
+	if encoding == utf8Encoding {
+		return string(runes), 0, true
+	}
+
+	max := maxRune(encoding)
+	bytes := make([]byte, len(runes))
+	for i, r := range runes {
+		if r > max {
+			return "", r, false
+		}
+		bytes[i] = byte(r)
+	}
+
+	return string(bytes), 0, true
+}
+
+// validEncoding reports whether s is a valid byte sequence in the given encoding.
+func validEncoding(s string, encoding string) bool {
+	for i := 0; i < len(s); {
+		r, width := decodeRune(s[i:], encoding)
+		if r == utf8.RuneError && width == 1 {
+			return false
+		}
+		i += width
+	}
+
+	return true
+}
+
+// isASCIIOnly reports whether s only has ASCII characters, which are the same in all encodings.
+func isASCIIOnly(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+
+	return true
+}
+
+// compatibleEncoding returns the encoding of the concatenation of a and b. Strings of different encodings
+// can only be concatenated when one of them is ASCII-only, and the result is in the other one's encoding.
+func compatibleEncoding(a, b *StringObject) (string, bool) {
+	switch {
+	case a.encoding == b.encoding || isASCIIOnly(b.value):
+		return a.encoding, true
+	case isASCIIOnly(a.value):
+		return b.encoding, true
+	}
+
+	return "", false
+}
+
+// mapChars returns a copy of s with each character in the given encoding replaced by f's result, like
+// strings.Map. Invalid bytes, and characters whose replacement the encoding can't represent, are kept.
+func mapChars(s string, encoding string, f func(rune) rune) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		r, width := decodeRune(s[i:], encoding)
+		mapped := f(r)
+
+		switch {
+		case r == utf8.RuneError && width == 1, mapped > maxRune(encoding):
+			b.WriteString(s[i : i+width])
+		case encoding == utf8Encoding:
+			b.WriteRune(mapped)
+		default:
+			b.WriteByte(byte(mapped))
+		}
+		i += width
+	}
+
+	return b.String()
+}
+
+// defaultReplacement returns the replacement `scrub` uses for invalid bytes in the given encoding.
+func defaultReplacement(encoding string) string {
+	if encoding == utf8Encoding {
+		return "�"
+	}
+	return "?"
+}
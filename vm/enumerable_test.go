@@ -0,0 +1,346 @@
+package vm
+
+import (
+	"testing"
+)
+
+const enumerableNumberList = `
+class NumberList
+  include Enumerable
+
+  def initialize(*numbers)
+    @numbers = numbers
+  end
+
+  def each
+    @numbers.each do |n|
+      yield(n)
+    end
+  end
+end
+
+list = NumberList.new(3, 1, 2, 5)
+`
+
+func TestEnumerableMethodsOnUserClass(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`list.to_a`, []interface{}{3, 1, 2, 5}},
+		{`list.sort`, []interface{}{1, 2, 3, 5}},
+		{`
+		list.sort do |a, b|
+		  b - a
+		end
+		`, []interface{}{5, 3, 2, 1}},
+		{`
+		list.sort_by do |n|
+		  0 - n
+		end
+		`, []interface{}{5, 3, 2, 1}},
+		{`
+		list.map do |n|
+		  n * 2
+		end
+		`, []interface{}{6, 2, 4, 10}},
+		{`
+		list.flat_map do |n|
+		  [n, n]
+		end.length
+		`, 8},
+		{`
+		list.select do |n|
+		  n > 2
+		end
+		`, []interface{}{3, 5}},
+		{`
+		list.reject do |n|
+		  n > 2
+		end
+		`, []interface{}{1, 2}},
+		{`
+		list.find do |n|
+		  n < 3
+		end
+		`, 1},
+		{`
+		list.take_while do |n|
+		  n > 2
+		end
+		`, []interface{}{3}},
+		{`list.first`, 3},
+		{`list.first(2)`, []interface{}{3, 1}},
+		{`list.take(0)`, []interface{}{}},
+		{`list.include?(5)`, true},
+		{`list.include?(4)`, false},
+		{`
+		list.all? do |n|
+		  n > 0
+		end
+		`, true},
+		{`
+		list.any? do |n|
+		  n > 4
+		end
+		`, true},
+		{`
+		list.none? do |n|
+		  n > 4
+		end
+		`, false},
+		{`list.count`, 4},
+		{`
+		list.count do |n|
+		  n > 1
+		end
+		`, 3},
+		{`list.sum`, 11},
+		{`
+		list.sum(1) do |n|
+		  n * 10
+		end
+		`, 111},
+		{`list.min`, 1},
+		{`list.max`, 5},
+		{`
+		list.reduce(10) do |sum, n|
+		  sum + n
+		end
+		`, 21},
+		{`
+		list.reduce do |sum, n|
+		  sum + n
+		end
+		`, 11},
+		{`
+		list.partition do |n|
+		  n > 2
+		end
+		`, []interface{}{[]interface{}{3, 5}, []interface{}{1, 2}}},
+		{`
+		groups = list.group_by do |n|
+		  n % 2
+		end
+		groups["1"]
+		`, []interface{}{3, 1, 5}},
+		{`NumberList.new(1, 2).zip([3])`, []interface{}{[]interface{}{1, 3}, []interface{}{2, nil}}},
+		{`NumberList.new(1, 2, 1).uniq`, []interface{}{1, 2}},
+		{`
+		slices = []
+		list.each_slice(3) do |s|
+		  slices.push(s)
+		end
+		slices
+		`, []interface{}{[]interface{}{3, 1, 2}, []interface{}{5}}},
+		{`
+		result = []
+		list.each_with_index do |n, i|
+		  result.push(n * i)
+		end
+		result
+		`, []interface{}{0, 1, 4, 15}},
+		{`
+		list.each_with_object([]) do |n, memo|
+		  memo.push(n + 1)
+		end
+		`, []interface{}{4, 2, 3, 6}},
+		{`
+		list.lazy.map do |n|
+		  n + 1
+		end.first(2)
+		`, []interface{}{4, 2}},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, enumerableNumberList+tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestEnumerableMethodsWithoutBlockFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`list.each_entry`, "InternalError: 'Can't yield without a block'", 2},
+		{`list.all?`, "InternalError: 'Can't yield without a block'", 2},
+		{`list.any?`, "InternalError: 'Can't yield without a block'", 2},
+		{`list.none?`, "InternalError: 'Can't yield without a block'", 2},
+		{`list.each_slice(2)`, "InternalError: 'Can't yield without a block'", 2},
+		{`list.each_with_index`, "InternalError: 'Can't yield without a block'", 2},
+		{`list.each_with_object([])`, "InternalError: 'Can't yield without a block'", 2},
+		{`list.find`, "InternalError: 'Can't yield without a block'", 2},
+		{`list.flat_map`, "InternalError: 'Can't yield without a block'", 2},
+		{`list.group_by`, "InternalError: 'Can't yield without a block'", 2},
+		{`list.map`, "InternalError: 'Can't yield without a block'", 2},
+		{`list.max_by`, "InternalError: 'Can't yield without a block'", 2},
+		{`list.min_by`, "InternalError: 'Can't yield without a block'", 2},
+		{`list.partition`, "InternalError: 'Can't yield without a block'", 2},
+		{`list.reduce(0)`, "InternalError: 'Can't yield without a block'", 2},
+		{`list.reject`, "InternalError: 'Can't yield without a block'", 2},
+		{`list.select`, "InternalError: 'Can't yield without a block'", 2},
+		{`list.sort_by`, "InternalError: 'Can't yield without a block'", 2},
+		{`list.take_while`, "InternalError: 'Can't yield without a block'", 2},
+		{`(1..5).each_slice(2)`, "InternalError: 'Can't yield without a block'", 2},
+		{`{ a: 1 }.each_with_index`, "InternalError: 'Can't yield without a block'", 2},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, enumerableNumberList+tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+	}
+}
+
+func TestEnumerableStopsIteratingEarly(t *testing.T) {
+	input := `
+	class Naturals
+	  include Enumerable
+
+	  def each
+	    n = 0
+
+	    while true do
+	      n += 1
+	      yield(n)
+	    end
+	  end
+	end
+
+	naturals = Naturals.new
+	found = naturals.find do |n|
+	  n * n > 50
+	end
+
+	taken = naturals.take_while do |n|
+	  n < 4
+	end
+
+	[found, taken, naturals.first(2), naturals.include?(10)]
+	`
+
+	v := initTestVM()
+	evaluated := v.testEval(t, input, getFilename())
+	VerifyExpected(t, 0, evaluated, []interface{}{8, []interface{}{1, 2, 3}, []interface{}{1, 2}, true})
+	v.checkCFP(t, 0, 0)
+	v.checkSP(t, 0, 1)
+}
+
+func TestEnumerableIncludedByManyClasses(t *testing.T) {
+	input := `
+	class Letters
+	  include Enumerable
+
+	  def each
+	    yield("b")
+	    yield("a")
+	  end
+	end
+
+	class Pairs
+	  include Enumerable
+
+	  def each
+	    yield([1, 2])
+	    yield([3, 4])
+	  end
+	end
+
+	sums = Pairs.new.map do |a, b|
+	  a + b
+	end
+
+	[Letters.new.sort, sums, Letters.ancestors.include?(Enumerable), Pairs.ancestors.include?(Enumerable)]
+	`
+
+	v := initTestVM()
+	evaluated := v.testEval(t, input, getFilename())
+	VerifyExpected(t, 0, evaluated, []interface{}{[]interface{}{"a", "b"}, []interface{}{3, 7}, true, true})
+	v.checkCFP(t, 0, 0)
+	v.checkSP(t, 0, 1)
+}
+
+func TestEnumerableOnHash(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Hash.ancestors.include?(Enumerable)`, true},
+		{`
+		{ b: 2, a: 1 }.map do |k, v|
+		  k + v.to_s
+		end
+		`, []interface{}{"a1", "b2"}},
+		{`
+		{ b: 2, a: 1, c: 3 }.find do |k, v|
+		  v > 1
+		end
+		`, []interface{}{"b", 2}},
+		{`
+		{ b: 2, a: 1, c: 3 }.sort_by do |k, v|
+		  0 - v
+		end
+		`, []interface{}{[]interface{}{"c", 3}, []interface{}{"b", 2}, []interface{}{"a", 1}}},
+		{`{ b: 2, a: 1 }.first`, []interface{}{"a", 1}},
+		{`{ b: 2, a: 1 }.count`, 2},
+		{`
+		{ b: 2, a: 1 }.sum do |k, v|
+		  v
+		end
+		`, 3},
+		{`
+		{ b: 2, a: 1 }.each_with_index do |pair, i|
+		end
+		`, map[string]interface{}{"a": 1, "b": 2}},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+
+		if h, ok := tt.expected.(map[string]interface{}); ok {
+			verifyHashObject(t, i, evaluated, h)
+		} else {
+			VerifyExpected(t, i, evaluated, tt.expected)
+		}
+
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestEnumerableOnRange(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Range.ancestors.include?(Enumerable)`, true},
+		{`
+		(1..4).select do |i|
+		  i.even?
+		end
+		`, []interface{}{2, 4}},
+		{`
+		(1..4).find do |i|
+		  i > 2
+		end
+		`, 3},
+		{`
+		(1..4).sort_by do |i|
+		  0 - i
+		end
+		`, []interface{}{4, 3, 2, 1}},
+		{`(3..1).min`, 1},
+		{`(1..4).zip(["a"])`, []interface{}{[]interface{}{1, "a"}, []interface{}{2, nil}, []interface{}{3, nil}, []interface{}{4, nil}}},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
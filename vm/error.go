@@ -34,7 +34,12 @@ func (vm *VM) initUnsupportedMethodError(sourceLine int, methodName string, rece
 }
 
 func (vm *VM) InitErrorObject(errorType string, sourceLine int, format string, args ...interface{}) *Error {
-	errClass := vm.objectClass.getClassConstant(errorType)
+	errClass := vm.objectClass
+
+	// Namespaced error types like Encoding::CompatibilityError are looked up from the top level
+	for _, name := range strings.Split(errorType, "::") {
+		errClass = errClass.getClassConstant(name)
+	}
 
 	t := vm.mainThread
 	cf := t.callFrameStack.top()
@@ -58,12 +63,18 @@ func (vm *VM) InitErrorObject(errorType string, sourceLine int, format string, a
 }
 
 func (vm *VM) initErrorClasses() {
-	errTypes := []string{errors.InternalError, errors.ArgumentError, errors.NameError, errors.StopIteration, errors.TypeError, errors.UndefinedMethodError, errors.UnsupportedMethodError, errors.ConstantAlreadyInitializedError, errors.HTTPError, errors.ZeroDivisionError, errors.ChannelCloseError}
+	errTypes := []string{errors.InternalError, errors.ArgumentError, errors.NameError, errors.StopIteration, errors.TypeError, errors.UndefinedMethodError, errors.UnsupportedMethodError, errors.ConstantAlreadyInitializedError, errors.HTTPError, errors.ZeroDivisionError, errors.ChannelCloseError, errors.EncodingError, errors.RangeError, errors.DomainError}
 
 	for _, errType := range errTypes {
 		c := vm.initializeClass(errType)
 		vm.objectClass.setClassConstant(c)
 	}
+
+	encoding := vm.initializeModule("Encoding")
+	compatibilityError := vm.initializeClass("CompatibilityError")
+	compatibilityError.inherits(vm.objectClass.getClassConstant(errors.EncodingError))
+	encoding.setClassConstant(compatibilityError)
+	vm.objectClass.setClassConstant(encoding)
 }
 
 // Polymorphic helper functions -----------------------------------------
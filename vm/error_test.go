@@ -360,6 +360,13 @@ func TestKeywordArgumentError(t *testing.T) {
 		end
 
 		foo(y: 1)
+		`,
+			"ArgumentError: unknown key y for method foo", 1},
+		{`def foo(x: 10, *args)
+		  x
+		end
+
+		foo(1, y: 1)
 		`,
 			"ArgumentError: unknown key y for method foo", 1},
 		{`def foo(x: 10)
@@ -23,15 +23,26 @@ const (
 	ZeroDivisionError = "ZeroDivisionError"
 	// ChannelCloseError is for accessing to the closed channel
 	ChannelCloseError = "ChannelCloseError"
+	// EncodingError is for a string that can't be converted to or from an encoding
+	EncodingError = "EncodingError"
+	// CompatibilityError is for an operation on Strings whose encodings are incompatible. It's defined in the Encoding module
+	CompatibilityError = "Encoding::CompatibilityError"
+	// RangeError is for a number that is out of the range a conversion or an operation allows
+	RangeError = "RangeError"
+	// DomainError is for an argument that is out of a mathematical function's domain
+	DomainError = "DomainError"
 )
 
 /*
 	Here defines different error message formats for different types of errors
 */
 const (
-	WrongNumberOfArgumentFormat = "Expect %d arguments. got: %d"
-	WrongArgumentTypeFormat     = "Expect argument to be %s. got: %s"
-	CantYieldWithoutBlockFormat = "Can't yield without a block"
-	DividedByZero               = "Divided by 0"
-	ChannelIsClosed             = "The channel is already closed."
+	WrongNumberOfArgumentFormat      = "Expect %d arguments. got: %d"
+	WrongNumberOfArgumentRangeFormat = "Expect %d..%d arguments. got: %d"
+	WrongNumberOfArgumentMoreFormat  = "Expect at least %d arguments. got: %d"
+	WrongArgumentTypeFormat          = "Expect argument to be %s. got: %s"
+	CantYieldWithoutBlockFormat      = "Can't yield without a block"
+	CantCompareFormat                = "Can't compare %s with %s"
+	DividedByZero                    = "Divided by 0"
+	ChannelIsClosed                  = "The channel is already closed."
 )
@@ -673,6 +673,17 @@ func TestConstantNamespace(t *testing.T) {
 
 		Out::Mid::In.new.val
 		`, "mid"},
+		{`
+		class Foo
+		  VAL = 10
+
+		  def self.double(x)
+		    x * 2
+		  end
+		end
+
+		Foo.double(Foo::VAL)
+		`, 20},
 	}
 
 	for i, tt := range tests {
@@ -1413,6 +1424,15 @@ func TestMethodCallWithSplatArgument(t *testing.T) {
 
 		foo
 		`, []interface{}{}},
+		// Splatting an array doesn't change how it's passed later
+		{`
+		def foo(*a)
+		  a.length
+		end
+
+		arr = [1, 2, 3]
+		foo(*arr) * 10 + foo(arr)
+		`, 31},
 	}
 
 	for i, tt := range tests {
@@ -1607,6 +1627,96 @@ func TestMethodCallWithBlockArgument(t *testing.T) {
 		  10
 		end
 `, 30},
+		// A yielded block keeps the self of where it's defined
+		{`
+		class Foo
+		  def bar
+		    @value = 1
+		    yield
+		  end
+		end
+
+		class Baz
+		  def initialize
+		    @value = 10
+		  end
+
+		  def qux
+		    Foo.new.bar do
+		      @value + self.class.name.length
+		    end
+		  end
+		end
+
+		Baz.new.qux
+`, 13},
+		{`
+		def helper
+		  5
+		end
+
+		class Foo
+		  def helper
+		    1
+		  end
+
+		  def bar
+		    yield
+		  end
+		end
+
+		Foo.new.bar do
+		  helper
+		end
+`, 5},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestBlockArgumentsDestructuring(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		result = []
+		[[1, 2], [3, 4]].each do |a, b|
+		  result.push(a + b)
+		end
+		result
+		`, []interface{}{3, 7}},
+		{`
+		result = []
+		[[1, 2]].each do |a|
+		  result.push(a)
+		end
+		result
+		`, []interface{}{[]interface{}{1, 2}}},
+		{`
+		def foo
+		  yield([1, 2, 3])
+		end
+
+		foo do |a, b|
+		  [a, b]
+		end
+		`, []interface{}{1, 2}},
+		{`
+		def foo(*args)
+		  yield(*args)
+		end
+
+		foo(1, 2) do |a, b|
+		  a * 10 + b
+		end
+		`, 12},
 	}
 
 	for i, tt := range tests {
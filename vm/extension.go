@@ -0,0 +1,421 @@
+package vm
+
+import (
+	"strings"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// Native extensions ====================================================
+//
+// A native extension defines a Goby class in Go. Its package registers the class with
+// RegisterExternalClass in an init function, and Goby code loads it with `require`:
+//
+// ```go
+// package greeter
+//
+// import "github.com/goby-lang/goby/vm"
+//
+// func init() {
+// 	vm.RegisterExternalClass("greeter", vm.ExternalClass("Greeter", "greeter.gb",
+// 		map[string]vm.MethodBuilder{
+// 			"greet": vm.NativeMethod(vm.Sig(vm.ArgString, vm.Optional(vm.ArgHash)), greet),
+// 		},
+// 		map[string]vm.MethodBuilder{},
+// 	))
+// }
+//
+// func greet(c *vm.Call) vm.Object {
+// 	greeting := "Hello, " + c.String(0)
+//
+// 	if c.Hash(1)["shout"] == vm.TRUE {
+// 		greeting = strings.ToUpper(greeting)
+// 	}
+//
+// 	return c.Return(greeting)
+// }
+// ```
+//
+// NativeMethod checks the arguments against the method's signature before calling it, and raises an
+// ArgumentError or a TypeError for the Goby caller otherwise. So the method can read its arguments with
+// the Call helpers, which convert them into Go values. `goby ext new name` creates a new extension.
+
+// ArgType is the type of a native method's argument. See Sig.
+type ArgType struct {
+	name     string
+	accepts  func(Object) bool
+	optional bool
+	rest     bool
+	// ints is set when the argument is read with Call#Int, so Integers must fit in an int
+	ints bool
+}
+
+// The argument types of native methods. ArgInteger raises a RangeError for Integers which don't fit in
+// an int, unlike ArgNumber, which accepts Integers and Floats.
+var (
+	ArgAny     = ArgType{name: classes.ObjectClass, accepts: func(Object) bool { return true }}
+	ArgString  = classArgType(classes.StringClass, func(obj Object) bool { _, ok := obj.(*StringObject); return ok })
+	ArgInteger = ArgType{name: classes.IntegerClass, accepts: func(obj Object) bool { _, ok := obj.(*IntegerObject); return ok }, ints: true}
+	ArgFloat   = classArgType(classes.FloatClass, func(obj Object) bool { _, ok := obj.(*FloatObject); return ok })
+	ArgNumber  = ArgType{name: ArgInteger.name + " or " + ArgFloat.name, accepts: OneOf(ArgInteger, ArgFloat).accepts}
+	ArgBoolean = classArgType(classes.BooleanClass, func(obj Object) bool { _, ok := obj.(*BooleanObject); return ok })
+	ArgArray   = classArgType(classes.ArrayClass, func(obj Object) bool { _, ok := obj.(*ArrayObject); return ok })
+	ArgHash    = classArgType(classes.HashClass, func(obj Object) bool { _, ok := obj.(*HashObject); return ok })
+	ArgBlock   = classArgType(classes.BlockClass, func(obj Object) bool { _, ok := obj.(*BlockObject); return ok })
+	ArgChannel = classArgType(classes.ChannelClass, func(obj Object) bool { _, ok := obj.(*ChannelObject); return ok })
+	ArgGoValue = classArgType(classes.GoObjectClass, func(obj Object) bool { _, ok := obj.(*GoObject); return ok })
+	ArgNull    = classArgType(classes.NullClass, func(obj Object) bool { return obj == NULL })
+)
+
+func classArgType(name string, accepts func(Object) bool) ArgType {
+	return ArgType{name: name, accepts: accepts}
+}
+
+// Optional marks an argument which can be omitted. Optional arguments must follow the required ones.
+func Optional(t ArgType) ArgType {
+	t.optional = true
+	return t
+}
+
+// Rest accepts any number of arguments of the type. It must be the last argument.
+func Rest(t ArgType) ArgType {
+	t.rest = true
+	return t
+}
+
+// Nullable accepts nil as well as the type.
+func Nullable(t ArgType) ArgType {
+	return OneOf(t, ArgNull)
+}
+
+// OneOf accepts any of the types.
+func OneOf(types ...ArgType) ArgType {
+	names := []string{}
+	ints := false
+
+	for _, t := range types {
+		names = append(names, t.name)
+		ints = ints || t.ints
+	}
+
+	return ArgType{
+		name: strings.Join(names, " or "),
+		ints: ints,
+		accepts: func(obj Object) bool {
+			for _, t := range types {
+				if t.accepts(obj) {
+					return true
+				}
+			}
+
+			return false
+		},
+	}
+}
+
+// Signature describes the arguments a native method takes. See Sig.
+type Signature struct {
+	args          []ArgType
+	blockRequired bool
+}
+
+// Sig returns the signature of a native method taking arguments of the given types.
+//
+// ```go
+// vm.Sig()                                      // no arguments
+// vm.Sig(vm.ArgString, vm.Optional(vm.ArgHash)) // a String and an optional Hash
+// vm.Sig(vm.Nullable(vm.ArgInteger))            // an Integer or nil
+// vm.Sig(vm.ArgString, vm.Rest(vm.ArgAny))      // a String followed by any arguments
+// vm.Sig(vm.ArgArray).WithBlock()               // an Array and a block
+// ```
+func Sig(args ...ArgType) Signature {
+	for i, arg := range args {
+		if arg.rest && i != len(args)-1 {
+			panic("vm.Rest must be the last argument of a signature")
+		}
+
+		if !arg.optional && !arg.rest && i > 0 && (args[i-1].optional || args[i-1].rest) {
+			panic("vm.Optional arguments must follow the required ones")
+		}
+	}
+
+	return Signature{args: args}
+}
+
+// WithBlock returns the signature of a method which must be called with a block.
+func (s Signature) WithBlock() Signature {
+	s.blockRequired = true
+	return s
+}
+
+// minArgs and maxArgs return the range of the number of arguments. maxArgs is -1 with a Rest argument.
+func (s Signature) minArgs() int {
+	n := 0
+
+	for _, arg := range s.args {
+		if !arg.optional && !arg.rest {
+			n++
+		}
+	}
+
+	return n
+}
+
+func (s Signature) maxArgs() int {
+	if len(s.args) > 0 && s.args[len(s.args)-1].rest {
+		return -1
+	}
+
+	return len(s.args)
+}
+
+// check returns the error to raise if the arguments don't match the signature.
+func (s Signature) check(t *Thread, sourceLine int, args []Object) *Error {
+	min, max := s.minArgs(), s.maxArgs()
+
+	switch {
+	case min == max && len(args) != min:
+		return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, min, len(args))
+	case max == -1 && len(args) < min:
+		return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentMoreFormat, min, len(args))
+	case max != -1 && (len(args) < min || len(args) > max):
+		return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRangeFormat, min, max, len(args))
+	}
+
+	for i, arg := range args {
+		argType := s.args[len(s.args)-1]
+
+		if i < len(s.args) {
+			argType = s.args[i]
+		}
+
+		if !argType.accepts(arg) {
+			return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, argType.name, arg.Class().Name)
+		}
+
+		if n, ok := arg.(*IntegerObject); ok && argType.ints {
+			if _, err := n.intValue(t, sourceLine); err != nil {
+				return err
+			}
+		}
+	}
+
+	if s.blockRequired && !t.BlockGiven() {
+		return t.vm.InitErrorObject(errors.InternalError, sourceLine, errors.CantYieldWithoutBlockFormat)
+	}
+
+	return nil
+}
+
+// Call is a call of a native method. Its helpers read the arguments, which NativeMethod checked against
+// the method's signature already. Omitted optional arguments read as nil, or as the zero value of Go types.
+type Call struct {
+	Thread     *Thread
+	Receiver   Object
+	Args       []Object
+	SourceLine int
+}
+
+// NativeMethod returns a MethodBuilder, which raises an error if the arguments don't match the signature
+// and calls fn otherwise.
+func NativeMethod(sig Signature, fn func(c *Call) Object) MethodBuilder {
+	return func(receiver Object, sourceLine int) Method {
+		return func(t *Thread, args []Object) Object {
+			if err := sig.check(t, sourceLine, args); err != nil {
+				return err
+			}
+
+			return fn(&Call{Thread: t, Receiver: receiver, Args: args, SourceLine: sourceLine})
+		}
+	}
+}
+
+// VM returns the VM the method is called in.
+func (c *Call) VM() *VM {
+	return c.Thread.vm
+}
+
+// Arg returns the i-th argument, or nil if it's omitted.
+func (c *Call) Arg(i int) Object {
+	if i >= len(c.Args) {
+		return NULL
+	}
+
+	return c.Args[i]
+}
+
+// Given reports whether the i-th argument is given and isn't nil.
+func (c *Call) Given(i int) bool {
+	return c.Arg(i) != NULL
+}
+
+// String returns the i-th argument as a string.
+func (c *Call) String(i int) string {
+	if s, ok := c.Arg(i).(*StringObject); ok {
+		return s.value
+	}
+
+	return ""
+}
+
+// Int returns the i-th argument as an int. The signature's Integer type makes sure it fits in one.
+func (c *Call) Int(i int) int {
+	if n, ok := c.Arg(i).(*IntegerObject); ok {
+		return n.value
+	}
+
+	return 0
+}
+
+// Float returns the i-th argument, which is either an Integer or a Float, as a float64.
+func (c *Call) Float(i int) float64 {
+	switch n := c.Arg(i).(type) {
+	case *FloatObject:
+		return n.value
+	case *IntegerObject:
+		return n.floatValue()
+	default:
+		return 0
+	}
+}
+
+// Bool returns whether the i-th argument is truthy.
+func (c *Call) Bool(i int) bool {
+	arg := c.Arg(i)
+
+	return arg != NULL && arg != FALSE
+}
+
+// Array returns the elements of the i-th argument.
+func (c *Call) Array(i int) []Object {
+	if arr, ok := c.Arg(i).(*ArrayObject); ok {
+		return arr.Elements
+	}
+
+	return nil
+}
+
+// Hash returns a copy of the pairs of the i-th argument, so changing it doesn't change the caller's Hash.
+// Reading a missing key of the result returns a Go nil.
+func (c *Call) Hash(i int) map[string]Object {
+	pairs := map[string]Object{}
+
+	if h, ok := c.Arg(i).(*HashObject); ok {
+		for k, v := range h.Pairs {
+			pairs[k] = v
+		}
+	}
+
+	return pairs
+}
+
+// GoValue returns the value of the i-th argument, which is a GoObject wrapping a value returned by Return.
+func (c *Call) GoValue(i int) interface{} {
+	if obj, ok := c.Arg(i).(*GoObject); ok {
+		return obj.data
+	}
+
+	return nil
+}
+
+// Value converts the i-th argument into a Go value. See ToGoValue.
+func (c *Call) Value(i int) interface{} {
+	return ToGoValue(c.Arg(i))
+}
+
+// Rest returns the arguments from the i-th one, which are matched by a Rest argument.
+func (c *Call) Rest(i int) []Object {
+	if i >= len(c.Args) {
+		return []Object{}
+	}
+
+	return c.Args[i:]
+}
+
+// Return converts a Go value into a Goby object: strings, numbers, booleans, nil, slices and
+// maps are converted into their Goby counterparts, and other values are wrapped in GoObjects.
+func (c *Call) Return(value interface{}) Object {
+	if obj, ok := value.(Object); ok {
+		return obj
+	}
+
+	return c.VM().InitObjectFromGoType(value)
+}
+
+// Error returns an error of the class to raise, like `errors.ArgumentError`.
+func (c *Call) Error(errorType string, format string, args ...interface{}) *Error {
+	return c.VM().InitErrorObject(errorType, c.SourceLine, format, args...)
+}
+
+// BlockGiven returns true if the method is called with a block.
+func (c *Call) BlockGiven() bool {
+	return c.Thread.BlockGiven()
+}
+
+// Yield calls the method's block with the arguments and returns its result.
+func (c *Call) Yield(args ...Object) Object {
+	return c.Thread.Yield(args...)
+}
+
+// Block returns the method's block as a Block object, which can be kept to be called later, or nil
+// if the method is called without a block.
+func (c *Call) Block() *BlockObject {
+	return c.Thread.Block()
+}
+
+// Block returns the block passed to the native method that's being executed as a Block object, or
+// nil if the method is called without a block.
+func (t *Thread) Block() *BlockObject {
+	cf, ok := t.callFrameStack.top().(*goMethodCallFrame)
+
+	if !ok || cf.blockFrame == nil {
+		return nil
+	}
+
+	return t.vm.initBlockObject(cf.blockFrame.instructionSet, cf.blockFrame.ep, cf.blockFrame.self)
+}
+
+// Call calls the block with the arguments on the given thread and returns its result. Goroutines
+// must call blocks on their own thread, see VM.NewThread.
+func (b *BlockObject) Call(t *Thread, args ...Object) Object {
+	c := newNormalCallFrame(b.instructionSet, b.instructionSet.filename, 0)
+	c.ep = b.ep
+	c.self = b.self
+	c.isBlock = true
+
+	return t.builtinMethodYield(c, args...).Target
+}
+
+// NewThread returns a new thread, which goroutines use to call Goby blocks.
+func (vm *VM) NewThread() *Thread {
+	t := vm.newThread()
+	return &t
+}
+
+// ToGoValue converts Goby objects into plain Go values: Hashes into map[string]interface{}, Arrays into
+// []interface{}, nil into nil and other objects into their values, like int for Integers.
+func ToGoValue(obj Object) interface{} {
+	switch o := obj.(type) {
+	case *HashObject:
+		m := make(map[string]interface{}, len(o.Pairs))
+
+		for k, v := range o.Pairs {
+			m[k] = ToGoValue(v)
+		}
+
+		return m
+	case *ArrayObject:
+		elems := make([]interface{}, len(o.Elements))
+
+		for i, elem := range o.Elements {
+			elems[i] = ToGoValue(elem)
+		}
+
+		return elems
+	case *NullObject:
+		return nil
+	default:
+		return o.Value()
+	}
+}
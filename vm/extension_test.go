@@ -0,0 +1,152 @@
+package vm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+func init() {
+	RegisterExternalClass("extension_test", ExternalClass("ExtensionTest", "",
+		map[string]MethodBuilder{
+			"describe": NativeMethod(Sig(ArgString, Optional(Nullable(ArgInteger)), Rest(ArgNumber)), func(c *Call) Object {
+				parts := []string{c.String(0), fmt.Sprint(c.Given(1), c.Int(1))}
+
+				for i := range c.Rest(2) {
+					parts = append(parts, fmt.Sprint(c.Float(i+2)))
+				}
+
+				return c.Return(strings.Join(parts, " "))
+			}),
+			"each_pair": NativeMethod(Sig(ArgHash).WithBlock(), func(c *Call) Object {
+				pairs := c.Hash(0)
+				keys := []string{}
+
+				for k := range pairs {
+					keys = append(keys, k)
+				}
+
+				sort.Strings(keys)
+
+				for _, k := range keys {
+					c.Yield(c.Return(k), pairs[k])
+				}
+
+				return c.Return(len(keys))
+			}),
+			"without": NativeMethod(Sig(ArgHash, ArgString), func(c *Call) Object {
+				pairs := c.Hash(0)
+				delete(pairs, c.String(1))
+
+				return c.Return(len(pairs))
+			}),
+			"keep": NativeMethod(Sig(), func(c *Call) Object {
+				if !c.BlockGiven() {
+					return c.Error(errors.ArgumentError, "Expect a block to keep")
+				}
+
+				c.Receiver.InstanceVariableSet("@block", c.Block())
+				return TRUE
+			}),
+			"call_kept": NativeMethod(Sig(ArgAny), func(c *Call) Object {
+				block, _ := c.Receiver.InstanceVariableGet("@block")
+
+				return block.(*BlockObject).Call(c.Thread, c.Arg(0))
+			}),
+			"call_kept_in_goroutine": NativeMethod(Sig(ArgAny), func(c *Call) Object {
+				block, _ := c.Receiver.InstanceVariableGet("@block")
+				result := make(chan Object)
+
+				go func() {
+					result <- block.(*BlockObject).Call(c.VM().NewThread(), c.Arg(0))
+				}()
+
+				return <-result
+			}),
+			"to_go": NativeMethod(Sig(ArgAny), func(c *Call) Object {
+				return c.Return(fmt.Sprintf("%v", c.Value(0)))
+			}),
+		},
+		map[string]MethodBuilder{},
+	))
+}
+
+func TestNativeMethodCalls(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`ExtensionTest.describe("a")`, "a false 0"},
+		{`ExtensionTest.describe("a", nil)`, "a false 0"},
+		{`ExtensionTest.describe("a", 2, 1, 2.5)`, "a true 2 1 2.5"},
+		{`ExtensionTest.describe("a", nil, 2 ** 64)`, "a false 0 1.8446744073709552e+19"},
+		{`
+		keys = []
+		count = ExtensionTest.each_pair({ b: 2, a: 1 }) do |k, v|
+		  keys.push(k + v.to_s)
+		end
+		keys.push(count)
+		`, []interface{}{"a1", "b2", 2}},
+		{`
+		h = { a: 1, b: 2 }
+		[ExtensionTest.without(h, "a"), h.length]
+		`, []interface{}{1, 2}},
+		{`
+		n = 10
+		ExtensionTest.keep do |x|
+		  x + n
+		end
+		[ExtensionTest.call_kept(1), ExtensionTest.call_kept_in_goroutine(2)]
+		`, []interface{}{11, 12}},
+		{`ExtensionTest.to_go({ a: [1, "b", nil, true] })`, "map[a:[1 b <nil> true]]"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, `require "extension_test"`+"\n"+tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+	}
+}
+
+func TestNativeMethodArgumentErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`ExtensionTest.describe`, "ArgumentError: Expect at least 1 arguments. got: 0"},
+		{`ExtensionTest.describe(1)`, "TypeError: Expect argument to be String. got: Integer"},
+		{`ExtensionTest.describe("a", "b")`, "TypeError: Expect argument to be Integer or Null. got: String"},
+		{`ExtensionTest.describe("a", 1, 2, "3")`, "TypeError: Expect argument to be Integer or Float. got: String"},
+		{`ExtensionTest.describe("a", 2 ** 64)`, "RangeError: 18446744073709551616 is out of range"},
+		{`ExtensionTest.each_pair({})`, "InternalError: Can't yield without a block"},
+		{`ExtensionTest.each_pair({}, 1) do end`, "ArgumentError: Expect 1 arguments. got: 2"},
+		{`ExtensionTest.keep`, "ArgumentError: Expect a block to keep"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, `require "extension_test"`+"\n"+tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+	}
+}
+
+func TestSigPanicsOnInvalidSignatures(t *testing.T) {
+	for i, args := range [][]ArgType{
+		{Rest(ArgString), ArgString},
+		{Optional(ArgString), ArgString},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("At test case %d: Expect Sig to panic", i)
+				}
+			}()
+
+			Sig(args...)
+		}()
+	}
+}
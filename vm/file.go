@@ -56,7 +56,12 @@ func builtinFileClassMethods() []*BuiltinMethodObject {
 			Name: "chmod",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-					filemod := args[0].(*IntegerObject).value
+					filemod, err := args[0].(*IntegerObject).intValue(t, sourceLine)
+
+					if err != nil {
+						return err
+					}
+
 					for i := 1; i < len(args); i++ {
 						filename := args[i].(*StringObject).value
 						if !filepath.IsAbs(filename) {
@@ -181,7 +186,12 @@ func builtinFileClassMethods() []*BuiltinMethodObject {
 							perm = os.FileMode(0755)
 
 							if len(args) == 3 {
-								p := args[2].(*IntegerObject).value
+								p, err := args[2].(*IntegerObject).intValue(t, sourceLine)
+
+								if err != nil {
+									return err
+								}
+
 								perm = os.FileMode(p)
 							}
 						}
@@ -2,8 +2,9 @@ package vm
 
 import (
 	"math"
-
+	"math/big"
 	"strconv"
+	"strings"
 
 	"github.com/goby-lang/goby/vm/classes"
 	"github.com/goby-lang/goby/vm/errors"
@@ -225,46 +226,43 @@ func builtinFloatInstanceMethods() []*BuiltinMethodObject {
 			},
 		},
 		{
-			// Returns 1 if self is larger than a Numeric, -1 if smaller. Otherwise 0.
+			// Returns 1 if self is larger than a Numeric, -1 if smaller, 0 if they're equal,
+			// and nil if either of them is NaN.
 			//
 			// ```Ruby
-			// 1.5 <=> 3 # => -1
-			// 1.0 <=> 1 # => 0
-			// 3.5 <=> 1 # => 1
+			// 1.5 <=> 3          # => -1
+			// 1.0 <=> 1          # => 0
+			// 3.5 <=> 1          # => 1
+			// Float::NAN <=> 1.0 # => nil
 			// ```
-			// @return [Float]
+			// @return [Integer]
 			Name: "<=>",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-					rightNumeric, ok := args[0].(Numeric)
-
-					if !ok {
+					if _, ok := args[0].(Numeric); !ok {
 						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Numeric", args[0].Class().Name)
 					}
 
-					leftValue := receiver.(*FloatObject).value
-					rightValue := rightNumeric.floatValue()
-
-					if leftValue < rightValue {
-						return t.vm.InitIntegerObject(-1)
-					}
-					if leftValue > rightValue {
-						return t.vm.InitIntegerObject(1)
+					result, ok := receiver.(*FloatObject).compare(args[0])
+					if !ok {
+						return NULL
 					}
 
-					return t.vm.InitIntegerObject(0)
+					return t.vm.InitIntegerObject(result)
 				}
 			},
 		},
 		{
 			// Returns if self is equal to an Object.
 			// If the Object is a Numeric, a comparison is performed, otherwise, the
-			// result is always false.
+			// result is always false. Integers are compared exactly, and NaN isn't equal to anything.
 			//
 			// ```Ruby
-			// 1.0 == 3     # => false
-			// 1.0 == 1     # => true
-			// 1.0 == '1.0' # => false
+			// 1.0 == 3                    # => false
+			// 1.0 == 1                    # => true
+			// 1.0 == '1.0'                # => false
+			// (2.0 ** 53) == 2 ** 53 + 1  # => false
+			// Float::NAN == Float::NAN    # => false
 			// ```
 			// @return [Boolean]
 			Name: "==",
@@ -296,6 +294,193 @@ func builtinFloatInstanceMethods() []*BuiltinMethodObject {
 				}
 			},
 		},
+		{
+			// Returns the absolute value of self.
+			//
+			// ```Ruby
+			// -1.5.abs # => 1.5
+			// 1.5.abs  # => 1.5
+			// ```
+			// @return [Float]
+			Name: "abs",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return t.vm.initFloatObject(math.Abs(receiver.(*FloatObject).value))
+				}
+			},
+		},
+		{
+			// Returns the smallest number larger than or equal to self with the given number of decimal digits,
+			// which defaults to 0. It returns an Integer if the number of digits isn't positive, and a Float otherwise.
+			//
+			// ```Ruby
+			// 1.2.ceil        # => 2
+			// -1.2.ceil       # => -1
+			// 1.21.ceil(1)    # => 1.3
+			// 1234.5.ceil(-2) # => 1300
+			// ```
+			// @param digits [Integer]
+			// @return [Numeric]
+			Name: "ceil",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					ceil := func(r *big.Rat) *big.Int {
+						return new(big.Int).Neg(floorRat(new(big.Rat).Neg(r)))
+					}
+
+					return receiver.(*FloatObject).roundTo(t, args, ceil, sourceLine)
+				}
+			},
+		},
+		{
+			// Returns true if self is neither infinite nor NaN.
+			//
+			// ```Ruby
+			// 1.5.finite?             # => true
+			// Float::INFINITY.finite? # => false
+			// Float::NAN.finite?      # => false
+			// ```
+			// @return [Boolean]
+			Name: "finite?",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					value := receiver.(*FloatObject).value
+
+					return toBooleanObject(!math.IsNaN(value) && !math.IsInf(value, 0))
+				}
+			},
+		},
+		{
+			// Returns the largest number smaller than or equal to self with the given number of decimal digits,
+			// which defaults to 0. It returns an Integer if the number of digits isn't positive, and a Float otherwise.
+			//
+			// ```Ruby
+			// 1.8.floor        # => 1
+			// -1.2.floor       # => -2
+			// 1.29.floor(1)    # => 1.2
+			// 1299.5.floor(-2) # => 1200
+			// ```
+			// @param digits [Integer]
+			// @return [Numeric]
+			Name: "floor",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					return receiver.(*FloatObject).roundTo(t, args, floorRat, sourceLine)
+				}
+			},
+		},
+		{
+			// Returns 1 if self is positive infinity, -1 if it's negative infinity, and nil otherwise.
+			//
+			// ```Ruby
+			// Float::INFINITY.infinite?    # => 1
+			// (-Float::INFINITY).infinite? # => -1
+			// 1.5.infinite?                # => nil
+			// ```
+			// @return [Integer]
+			Name: "infinite?",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					value := receiver.(*FloatObject).value
+
+					switch {
+					case math.IsInf(value, 1):
+						return t.vm.InitIntegerObject(1)
+					case math.IsInf(value, -1):
+						return t.vm.InitIntegerObject(-1)
+					default:
+						return NULL
+					}
+				}
+			},
+		},
+		{
+			// Returns true if self is NaN, which isn't equal to any number, even itself.
+			//
+			// ```Ruby
+			// Float::NAN.nan? # => true
+			// 1.5.nan?        # => false
+			// ```
+			// @return [Boolean]
+			Name: "nan?",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return toBooleanObject(math.IsNaN(receiver.(*FloatObject).value))
+				}
+			},
+		},
+		{
+			// Returns self rounded to the given number of decimal digits, which defaults to 0. Halves are rounded
+			// away from zero. It returns an Integer if the number of digits isn't positive, and a Float otherwise.
+			// Rounding is based on the shortest decimal representation of self, so `1.005.round(2)` is `1.01`.
+			//
+			// ```Ruby
+			// 1.5.round        # => 2
+			// -1.5.round       # => -2
+			// 3.14159.round(2) # => 3.14
+			// 1.005.round(2)   # => 1.01
+			// 1250.0.round(-2) # => 1300
+			// ```
+			// @param digits [Integer]
+			// @return [Numeric]
+			Name: "round",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					round := func(r *big.Rat) *big.Int {
+						half := new(big.Rat).Add(new(big.Rat).Abs(r), big.NewRat(1, 2))
+						rounded := floorRat(half)
+
+						if r.Sign() < 0 {
+							rounded.Neg(rounded)
+						}
+
+						return rounded
+					}
+
+					return receiver.(*FloatObject).roundTo(t, args, round, sourceLine)
+				}
+			},
+		},
+		{
+			// Returns the `String` representation of self. It always has a decimal point or an exponent,
+			// and uses the exponent notation for numbers smaller than 1e-4 or not smaller than 1e16.
+			//
+			// ```Ruby
+			// 2.0.to_s             # => "2.0"
+			// 0.1.to_s             # => "0.1"
+			// (10.0 ** 20).to_s    # => "1.0e+20"
+			// 0.00001.to_s         # => "1.0e-05"
+			// Float::INFINITY.to_s # => "Infinity"
+			// Float::NAN.to_s      # => "NaN"
+			// ```
+			// @return [String]
+			Name: "to_s",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return t.vm.InitStringObject(receiver.(*FloatObject).toString())
+				}
+			},
+		},
 		{
 			// Converts the Integer object into Decimal object and returns it.
 			// Each digit of the float is literally transferred to the corresponding digit
@@ -333,15 +518,13 @@ func builtinFloatInstanceMethods() []*BuiltinMethodObject {
 			//
 			// ```Ruby
 			// 100.1.to_i # => 100
+			// 1e20.to_i  # => 100000000000000000000
 			// ```
 			// @return [Integer]
 			Name: "to_i",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-					r := receiver.(*FloatObject)
-					newInt := t.vm.InitIntegerObject(int(r.value))
-					newInt.flag = i
-					return newInt
+					return receiver.(*FloatObject).toInteger(t, sourceLine)
 				}
 			},
 		},
@@ -375,6 +558,16 @@ func (vm *VM) initFloatClass() *RClass {
 	return ic
 }
 
+// initFloatConstants sets the Float class's constants, which needs the Float class to be initialized.
+func (vm *VM) initFloatConstants() {
+	fc := vm.topLevelClass(classes.FloatClass)
+	fc.constants["INFINITY"] = &Pointer{Target: vm.initFloatObject(math.Inf(1))}
+	fc.constants["NAN"] = &Pointer{Target: vm.initFloatObject(math.NaN())}
+	fc.constants["EPSILON"] = &Pointer{Target: vm.initFloatObject(math.Nextafter(1, 2) - 1)}
+	fc.constants["MAX"] = &Pointer{Target: vm.initFloatObject(math.MaxFloat64)}
+	fc.constants["MIN"] = &Pointer{Target: vm.initFloatObject(math.SmallestNonzeroFloat64)}
+}
+
 // Polymorphic helper functions -----------------------------------------
 
 // Value returns the object
@@ -411,16 +604,13 @@ func (f *FloatObject) arithmeticOperation(t *Thread, rightObject Object, operati
 // Apply an equality test, returning true if the objects are considered equal,
 // and false otherwise.
 func (f *FloatObject) equalityTest(rightObject Object) bool {
-	rightNumeric, ok := rightObject.(Numeric)
-
-	if !ok {
+	if _, ok := rightObject.(Numeric); !ok {
 		return false
 	}
 
-	leftValue := f.value
-	rightValue := rightNumeric.floatValue()
+	result, ok := f.compare(rightObject)
 
-	return leftValue == rightValue
+	return ok && result == 0
 }
 
 // TODO: Remove instruction argument
@@ -432,22 +622,79 @@ func (f *FloatObject) numericComparison(t *Thread, rightObject Object, operation
 		return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Numeric", rightObject.Class().Name)
 	}
 
-	leftValue := f.value
-	rightValue := rightNumeric.floatValue()
+	result, ok := f.compare(rightObject)
+	if !ok {
+		// Every comparison with NaN is false
+		return toBooleanObject(operation(f.value, rightNumeric.floatValue()))
+	}
 
-	result := operation(leftValue, rightValue)
+	// Comparing the result with 0 is the same as comparing the numbers
+	return toBooleanObject(operation(float64(result), 0))
+}
 
-	return toBooleanObject(result)
+// compare returns -1, 0 or 1 if self is smaller than, equal to or larger than the Numeric.
+// Integers are compared exactly. It returns false if either of them is NaN.
+func (f *FloatObject) compare(rightObject Object) (int, bool) {
+	if i, ok := rightObject.(*IntegerObject); ok {
+		return compareFloatToInteger(f.value, i)
+	}
+
+	rightValue := rightObject.(Numeric).floatValue()
+	if math.IsNaN(f.value) || math.IsNaN(rightValue) {
+		return 0, false
+	}
+
+	return compareFloats(f.value, rightValue), true
 }
 
-// toString returns the object's value as the string format, in non
-// exponential format (straight number, without exponent `E<exp>`).
+// toString returns the object's value as the string format. Like Ruby, it always has a decimal point
+// or an exponent, and uses the exponent notation for numbers smaller than 1e-4 or not smaller than 1e16.
 func (f *FloatObject) toString() string {
-	return strconv.FormatFloat(f.value, 'f', -1, 64) // fmt.Sprintf("%f", f.value)
+	switch {
+	case math.IsNaN(f.value):
+		return "NaN"
+	case math.IsInf(f.value, 1):
+		return "Infinity"
+	case math.IsInf(f.value, -1):
+		return "-Infinity"
+	}
+
+	abs := math.Abs(f.value)
+	if abs != 0 && (abs < 1e-4 || abs >= 1e16) {
+		s := strconv.FormatFloat(f.value, 'e', -1, 64)
+		mantissa, exponent := s[:strings.IndexByte(s, 'e')], s[strings.IndexByte(s, 'e'):]
+
+		if !strings.Contains(mantissa, ".") {
+			mantissa += ".0"
+		}
+
+		return mantissa + exponent
+	}
+
+	s := strconv.FormatFloat(f.value, 'f', -1, 64)
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+
+	return s
+}
+
+// toInteger truncates the Float to an Integer. NaN and infinities raise a RangeError.
+func (f *FloatObject) toInteger(t *Thread, sourceLine int) Object {
+	if math.IsNaN(f.value) || math.IsInf(f.value, 0) {
+		return t.vm.InitErrorObject(errors.RangeError, sourceLine, "Can't convert %s into Integer", f.toString())
+	}
+
+	n, _ := big.NewFloat(f.value).Int(nil)
+	return t.vm.initBigIntegerObject(n)
 }
 
-// toJSON just delegates to toString
+// toJSON delegates to toString, except NaN and infinities, which JSON can't represent
 func (f *FloatObject) toJSON(t *Thread) string {
+	if math.IsNaN(f.value) || math.IsInf(f.value, 0) {
+		return "null"
+	}
+
 	return f.toString()
 }
 
@@ -455,3 +702,87 @@ func (f *FloatObject) toJSON(t *Thread) string {
 func (f *FloatObject) equal(e *FloatObject) bool {
 	return f.value == e.value
 }
+
+// roundTo rounds self to the number of decimal digits given in args, which defaults to 0, by applying
+// the rounding function to self scaled by 10 ** digits. It returns an Integer if the number of digits
+// isn't positive, and a Float otherwise.
+func (f *FloatObject) roundTo(t *Thread, args []Object, round func(r *big.Rat) *big.Int, sourceLine int) Object {
+	if len(args) > 1 {
+		return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRangeFormat, 0, 1, len(args))
+	}
+
+	digits := 0
+	if len(args) == 1 {
+		d, ok := args[0].(*IntegerObject)
+		if !ok {
+			return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+		}
+
+		// No Float has more than a few hundred decimal digits, so larger numbers of digits give the same result
+		switch {
+		case d.bigInt().Cmp(big.NewInt(maxFloatDigits)) > 0:
+			digits = maxFloatDigits
+		case d.bigInt().Cmp(big.NewInt(-maxFloatDigits)) < 0:
+			digits = -maxFloatDigits
+		default:
+			digits = d.value
+		}
+	}
+
+	if math.IsNaN(f.value) || math.IsInf(f.value, 0) {
+		if digits > 0 {
+			return f
+		}
+
+		return f.toInteger(t, sourceLine)
+	}
+
+	// The shortest decimal representation is what users see, so `1.005` is rounded as if it were exact.
+	// Whole numbers are exact already, and their shortest representation could lose digits.
+	r := new(big.Rat).SetFloat64(f.value)
+	if f.value != math.Trunc(f.value) {
+		r.SetString(strconv.FormatFloat(f.value, 'f', -1, 64))
+	}
+	exponent := digits
+	if exponent < 0 {
+		exponent = -exponent
+	}
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil))
+
+	if digits >= 0 {
+		r.Mul(r, scale)
+	} else {
+		r.Quo(r, scale)
+	}
+
+	rounded := new(big.Rat).SetInt(round(r))
+
+	if digits > 0 {
+		value, _ := rounded.Quo(rounded, scale).Float64()
+		return t.vm.initFloatObject(value)
+	}
+
+	return t.vm.initBigIntegerObject(rounded.Mul(rounded, scale).Num())
+}
+
+// maxFloatDigits is more than the number of decimal digits any Float has.
+const maxFloatDigits = 400
+
+// floorRat returns the largest integer smaller than or equal to r.
+func floorRat(r *big.Rat) *big.Int {
+	// Div rounds towards negative infinity for a positive divisor, and a Rat's denominator is always positive
+	return new(big.Int).Div(r.Num(), r.Denom())
+}
+
+// compareFloatToInteger compares f with i exactly, instead of converting i to a float.
+// It returns false if f is NaN.
+func compareFloatToInteger(f float64, i *IntegerObject) (int, bool) {
+	switch {
+	case math.IsNaN(f):
+		return 0, false
+	case math.IsInf(f, 0):
+		return int(math.Copysign(1, f)), true
+	}
+
+	return big.NewFloat(f).Cmp(new(big.Float).SetInt(i.bigInt())), true
+}
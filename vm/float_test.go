@@ -246,6 +246,172 @@ func TestFloatEdgeCases(t *testing.T) {
 	}
 }
 
+func TestFloatRoundingMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`1.5.round`, 2},
+		{`1.5.round.class.name`, "Integer"},
+		{`-2.5.round`, -3},
+		{`1.4.round`, 1},
+		{`3.14159.round(2)`, 3.14},
+		{`1.005.round(2)`, 1.01},
+		{`1250.0.round(-2)`, 1300},
+		{`1249.9.round(-2)`, 1200},
+		{`1.0.round(400)`, 1.0},
+		{`1.0.round(-400)`, 0},
+		{`Float::NAN.round(2).nan?`, true},
+		{`1.2.ceil`, 2},
+		{`-1.2.ceil`, -1},
+		{`1.21.ceil(1)`, 1.3},
+		{`1234.5.ceil(-2)`, 1300},
+		{`1.8.floor`, 1},
+		{`-1.2.floor`, -2},
+		{`1.15.floor(2)`, 1.15},
+		{`1299.5.floor(-2)`, 1200},
+		{`(2.0 ** 70).floor.to_s`, "1180591620717411303424"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestFloatRoundingMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`1.5.round("1")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`1.5.floor(1, 2)`, "ArgumentError: Expect 0..1 arguments. got: 2", 1},
+		{`Float::NAN.round`, "RangeError: Can't convert NaN into Integer", 1},
+		{`Float::INFINITY.ceil`, "RangeError: Can't convert Infinity into Integer", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestFloatPredicateMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`-1.5.abs`, 1.5},
+		{`1.5.abs`, 1.5},
+		{`Float::NAN.nan?`, true},
+		{`1.5.nan?`, false},
+		{`Float::INFINITY.infinite?`, 1},
+		{`(-Float::INFINITY).infinite?`, -1},
+		{`1.5.infinite?.nil?`, true},
+		{`1.5.finite?`, true},
+		{`Float::INFINITY.finite?`, false},
+		{`Float::NAN.finite?`, false},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestFloatToSMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`2.0.to_s`, "2.0"},
+		{`0.1.to_s`, "0.1"},
+		{`-2.5.to_s`, "-2.5"},
+		{`(10.0 ** 20).to_s`, "1.0e+20"},
+		{`(10.0 ** 15).to_s`, "1000000000000000.0"},
+		{`0.00001.to_s`, "1.0e-05"},
+		{`0.0001.to_s`, "0.0001"},
+		{`Float::INFINITY.to_s`, "Infinity"},
+		{`(-Float::INFINITY).to_s`, "-Infinity"},
+		{`Float::NAN.to_s`, "NaN"},
+		{`[1.0, 2.5].to_s`, "[1.0, 2.5]"},
+		{`{ b: Float::NAN }.to_json`, `{"b":null}`},
+		{`{ a: 2.0 }.to_json`, `{"a":2.0}`},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestFloatNaNAndInfinity(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Float::NAN == Float::NAN`, false},
+		{`Float::NAN != Float::NAN`, true},
+		{`Float::NAN == 1`, false},
+		{`Float::NAN < 1`, false},
+		{`Float::NAN >= 1`, false},
+		{`1 > Float::NAN`, false},
+		{`1 == Float::NAN`, false},
+		{`(Float::NAN <=> 1).nil?`, true},
+		{`(1 <=> Float::NAN).nil?`, true},
+		{`Float::INFINITY == Float::INFINITY`, true},
+		{`Float::INFINITY > 2 ** 100`, true},
+		{`-Float::INFINITY < -(2 ** 100)`, true},
+		{`(Float::EPSILON + 1) > 1`, true},
+		{`Float::MAX > 2 ** 1000`, true},
+		{`Float::MIN > 0`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestFloatIntegerExactComparison(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`(2 ** 53 + 1) == (2 ** 53 + 1).to_f`, false},
+		{`(2.0 ** 53) == 2 ** 53`, true},
+		{`(2.0 ** 53) < 2 ** 53 + 1`, true},
+		{`2 ** 53 + 1 > 2.0 ** 53`, true},
+		{`(2.0 ** 64) == 2 ** 64`, true},
+		{`2 ** 64 == 2.0 ** 64`, true},
+		{`(2.0 ** 53) <=> 2 ** 53 + 1`, -1},
+		{`2 ** 53 + 1 <=> 2.0 ** 53`, 1},
+		{`[2 ** 53 + 1, 2.0 ** 53].sort[0].class.name`, "Float"},
+		{`1 == 1.0`, true},
+		{`1.0 == 1`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestFloatZeroDivisionFail(t *testing.T) {
 	testsFail := []errorTestCase{
 		{`6.0 / 0`, "ZeroDivisionError: Divided by 0", 1},
@@ -0,0 +1,444 @@
+package vm
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// The format engine behind `String.fmt`, `String#%`, `Kernel#format` and `Kernel#printf`.
+// A format string is plain text mixed with directives, which are written as:
+//
+// ```
+// %[position$][flags][width][.precision]type
+// ```
+//
+// - `position`: takes the nth argument (starting from 1) instead of the next one. Numbered and unnumbered
+//   directives can't be mixed in one format string.
+// - `flags`: `-` justifies to the left, `+` and ` ` (space) add a sign to positive numbers, `0` pads numbers
+//   with zeros, and `#` adds `0x`, `0` or `0b` prefixes to `x`, `o` and `b`.
+// - `width` and `precision`: numbers up to 1000000, or `*` to take them from the next argument.
+// - `type`:
+//   - `d`, `i` and `u`: Integer. Floats and Decimals are floored.
+//   - `x`, `X`, `o` and `b`: Integer in hexadecimal, octal or binary.
+//   - `f`, `e`, `E`, `g` and `G`: Float. Decimals are formatted with their exact value.
+//   - `s`: the argument's `to_s`, where the precision is the maximum length.
+//   - `p`: like `s`, but Strings are quoted.
+//   - `c`: a character, which can be an Integer code point or the first character of a String.
+//
+// `%%` is a literal `%`.
+//
+// ```ruby
+// format("%05.2f|%-4s|%x", 3.14159, "ab", 255) # => "03.14|ab  |ff"
+// format("%2$s %1$s", "world", "hello")        # => "hello world"
+// format("%+d%%", 20)                          # => "+20%"
+// ```
+//
+
+// maxFormatWidth is the biggest width or precision a directive can have, which is also the limit of
+// Go's fmt package.
+const maxFormatWidth = 1000000
+
+// formatDirective is a single `%` directive in a format string.
+type formatDirective struct {
+	flags     string
+	width     int
+	precision int
+	verb      byte
+	// The indexes of the arguments to format, and to take the width and the precision from (-1 if none)
+	argIndex       int
+	widthIndex     int
+	precisionIndex int
+}
+
+// formatSegment is either a literal text or a directive of a format string.
+type formatSegment struct {
+	text      string
+	directive *formatDirective
+}
+
+// Internal functions ===================================================
+
+// format formats the arguments with the format string.
+func (t *Thread) format(format string, args []Object, sourceLine int) (string, *Error) {
+	segments, argCount, numbered, msg := parseFormat(format)
+
+	if msg != "" {
+		return "", t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "%s", msg)
+	}
+
+	// Unlike numbered arguments, every unnumbered argument should be formatted
+	if len(args) < argCount || (!numbered && len(args) > argCount) {
+		return "", t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect %d arguments for the format string. got: %d", argCount, len(args))
+	}
+
+	var out strings.Builder
+
+	for _, segment := range segments {
+		d := segment.directive
+
+		if d == nil {
+			out.WriteString(segment.text)
+			continue
+		}
+
+		if d.widthIndex >= 0 {
+			width, ok := args[d.widthIndex].(*IntegerObject)
+
+			if !ok {
+				return "", t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[d.widthIndex].Class().Name)
+			}
+
+			if width.bigValue != nil || width.value > maxFormatWidth || width.value < -maxFormatWidth {
+				return "", t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Width too big: %s", width.toString())
+			}
+
+			d.width = width.value
+
+			// Like in C, a negative width justifies to the left
+			if d.width < 0 {
+				d.flags += "-"
+				d.width = -d.width
+			}
+		}
+
+		if d.precisionIndex >= 0 {
+			precision, ok := args[d.precisionIndex].(*IntegerObject)
+
+			if !ok {
+				return "", t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[d.precisionIndex].Class().Name)
+			}
+
+			if precision.bigValue != nil || precision.value > maxFormatWidth {
+				return "", t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Precision too big: %s", precision.toString())
+			}
+
+			d.precision = precision.value
+		}
+
+		s, err := t.formatArgument(d, args[d.argIndex], sourceLine)
+
+		if err != nil {
+			return "", err
+		}
+
+		out.WriteString(s)
+	}
+
+	return out.String(), nil
+}
+
+// parseFormat splits the format string into segments. It also returns the number of arguments the format
+// string takes, whether the arguments are numbered, and an error message if the format string is malformed.
+func parseFormat(format string) (segments []*formatSegment, argCount int, numbered bool, msg string) {
+	unnumbered := false
+	start := 0
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+
+		if i > start {
+			segments = append(segments, &formatSegment{text: format[start:i]})
+		}
+
+		i++
+
+		if i >= len(format) {
+			return nil, 0, false, "Incomplete format specifier; use %% (double %) instead"
+		}
+
+		if format[i] == '%' {
+			segments = append(segments, &formatSegment{text: "%"})
+			start = i + 1
+			continue
+		}
+
+		d := &formatDirective{width: -1, precision: -1, widthIndex: -1, precisionIndex: -1}
+		directiveStart := i - 1
+
+		// The argument's position
+		digits := readDigits(format, i)
+		position := 0
+
+		if digits != "" && i+len(digits) < len(format) && format[i+len(digits)] == '$' {
+			position, _ = strconv.Atoi(digits)
+			i += len(digits) + 1
+		}
+
+		for i < len(format) && strings.IndexByte("-+ 0#", format[i]) >= 0 {
+			d.flags += string(format[i])
+			i++
+		}
+
+		// nextArg returns the index of the next unnumbered argument
+		nextArg := func() int {
+			unnumbered = true
+			argCount++
+			return argCount - 1
+		}
+
+		if i < len(format) && format[i] == '*' {
+			d.widthIndex = nextArg()
+			i++
+		} else if digits := readDigits(format, i); digits != "" {
+			width, err := strconv.Atoi(digits)
+
+			if err != nil || width > maxFormatWidth {
+				return nil, 0, false, fmt.Sprintf("Width too big: %s", digits)
+			}
+
+			d.width = width
+			i += len(digits)
+		}
+
+		if i < len(format) && format[i] == '.' {
+			i++
+
+			if i < len(format) && format[i] == '*' {
+				d.precisionIndex = nextArg()
+				i++
+			} else {
+				digits := readDigits(format, i)
+				precision, err := strconv.Atoi("0" + digits)
+
+				if err != nil || precision > maxFormatWidth {
+					return nil, 0, false, fmt.Sprintf("Precision too big: %s", digits)
+				}
+
+				d.precision = precision
+				i += len(digits)
+			}
+		}
+
+		if i >= len(format) {
+			return nil, 0, false, "Incomplete format specifier; use %% (double %) instead"
+		}
+
+		d.verb = format[i]
+
+		if strings.IndexByte("diuxXobfeEgGspc", d.verb) < 0 {
+			return nil, 0, false, fmt.Sprintf("Malformed format string - %s", format[directiveStart:i+1])
+		}
+
+		if position > 0 {
+			numbered = true
+			d.argIndex = position - 1
+
+			if position > argCount {
+				argCount = position
+			}
+		} else {
+			d.argIndex = nextArg()
+		}
+
+		if numbered && unnumbered {
+			return nil, 0, false, "Can't mix numbered and unnumbered arguments in a format string"
+		}
+
+		segments = append(segments, &formatSegment{directive: d})
+		start = i + 1
+	}
+
+	if start < len(format) {
+		segments = append(segments, &formatSegment{text: format[start:]})
+	}
+
+	return segments, argCount, numbered, ""
+}
+
+// formatArgument formats a single argument with the directive.
+func (t *Thread) formatArgument(d *formatDirective, arg Object, sourceLine int) (string, *Error) {
+	switch d.verb {
+	case 's', 'p':
+		s := arg.toString()
+
+		switch arg := arg.(type) {
+		case *StringObject:
+			if d.verb == 'p' {
+				s = "\"" + s + "\""
+			}
+		case *RObject:
+			// Respect the user-defined `to_s`
+			result := t.callMethod(arg, "to_s", sourceLine)
+
+			if err, ok := result.(*Error); ok {
+				return "", err
+			}
+
+			s = result.toString()
+		}
+
+		return fmt.Sprintf(d.spec('s', "+ 0#"), s), nil
+	case 'c':
+		var s string
+
+		switch arg := arg.(type) {
+		case *IntegerObject:
+			code, err := arg.intValue(t, sourceLine)
+
+			if err != nil {
+				return "", err
+			}
+
+			s = string(rune(code))
+		case *StringObject:
+			r, _ := utf8.DecodeRuneInString(arg.value)
+
+			if r == utf8.RuneError {
+				return "", t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "%%c requires a character")
+			}
+
+			s = string(r)
+		default:
+			return "", t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Integer or String", arg.Class().Name)
+		}
+
+		d.precision = -1
+		return fmt.Sprintf(d.spec('s', "+ 0#"), s), nil
+	case 'f', 'e', 'E', 'g', 'G':
+		switch arg := arg.(type) {
+		case *IntegerObject:
+			return fmt.Sprintf(d.spec(d.verb, ""), arg.floatValue()), nil
+		case *FloatObject:
+			return fmt.Sprintf(d.spec(d.verb, ""), arg.value), nil
+		case *DecimalObject:
+			if d.verb == 'f' {
+				return d.formatDecimal(arg.value), nil
+			}
+
+			// A 256-bit mantissa keeps many more digits than any practical precision
+			f := new(big.Float).SetPrec(256).SetRat(arg.value)
+			return fmt.Sprintf(d.spec(d.verb, "#"), f), nil
+		default:
+			return "", t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Integer, Float or Decimal", arg.Class().Name)
+		}
+	default:
+		var n *big.Int
+
+		switch arg := arg.(type) {
+		case *IntegerObject:
+			n = arg.bigInt()
+		case *FloatObject:
+			if math.IsNaN(arg.value) || math.IsInf(arg.value, 0) {
+				return "", t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Can't convert %s into Integer", arg.toString())
+			}
+
+			n, _ = big.NewFloat(math.Floor(arg.value)).Int(nil)
+		case *DecimalObject:
+			// The denominator is always positive, so Euclidean division floors
+			n = new(big.Int).Div(arg.value.Num(), arg.value.Denom())
+		default:
+			return "", t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Integer, Float or Decimal", arg.Class().Name)
+		}
+
+		verb := d.verb
+
+		if verb == 'i' || verb == 'u' {
+			verb = 'd'
+		}
+
+		return fmt.Sprintf(d.spec(verb, ""), n), nil
+	}
+}
+
+// formatDecimal formats the decimal in the `%f` format with its exact value, rounding the last digit.
+func (d *formatDirective) formatDecimal(value *Decimal) string {
+	precision := d.precision
+
+	if precision < 0 {
+		precision = 6
+	}
+
+	s := value.FloatString(precision)
+
+	if precision == 0 && strings.Contains(d.flags, "#") {
+		s += "."
+	}
+
+	if !strings.HasPrefix(s, "-") {
+		if strings.Contains(d.flags, "+") {
+			s = "+" + s
+		} else if strings.Contains(d.flags, " ") {
+			s = " " + s
+		}
+	}
+
+	padding := d.width - len(s)
+
+	switch {
+	case padding <= 0:
+		return s
+	case strings.Contains(d.flags, "-"):
+		return s + strings.Repeat(" ", padding)
+	case strings.Contains(d.flags, "0"):
+		// Zeros go between the sign and the digits
+		sign := ""
+
+		if s[0] == '-' || s[0] == '+' || s[0] == ' ' {
+			sign, s = s[:1], s[1:]
+		}
+
+		return sign + strings.Repeat("0", padding) + s
+	default:
+		return strings.Repeat(" ", padding) + s
+	}
+}
+
+// spec returns the directive as a format for Go's fmt package, without the given flags.
+func (d *formatDirective) spec(verb byte, ignoredFlags string) string {
+	var b strings.Builder
+	b.WriteByte('%')
+
+	for i := 0; i < len(d.flags); i++ {
+		if strings.IndexByte(ignoredFlags, d.flags[i]) < 0 {
+			b.WriteByte(d.flags[i])
+		}
+	}
+
+	if d.width >= 0 {
+		b.WriteString(strconv.Itoa(d.width))
+	}
+
+	if d.precision >= 0 {
+		b.WriteByte('.')
+		b.WriteString(strconv.Itoa(d.precision))
+	}
+
+	b.WriteByte(verb)
+	return b.String()
+}
+
+// readDigits returns the digits in s from the index i.
+func readDigits(s string, i int) string {
+	start := i
+
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+
+	return s[start:i]
+}
+
+// formatArguments formats the arguments with the first argument as the format string,
+// like `Kernel#format` takes them.
+func formatArguments(t *Thread, args []Object, sourceLine int) (string, *Error) {
+	if len(args) < 1 {
+		return "", t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentMoreFormat, 1, len(args))
+	}
+
+	format, ok := args[0].(*StringObject)
+
+	if !ok {
+		return "", t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+	}
+
+	return t.format(format.value, args[1:], sourceLine)
+}
@@ -0,0 +1,104 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestFormatMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		// Strings
+		{`format("%s and %s", "a", 1)`, "a and 1"},
+		{`format("[%5s|%-5s]", "ab", "cd")`, "[   ab|cd   ]"},
+		{`format("[%05s]", "ab")`, "[   ab]"},
+		{`format("%.2s", "abc")`, "ab"},
+		{`format("%5s|", "日本")`, "   日本|"},
+		{`format("%p %p %p", "a", 1, nil)`, `"a" 1 nil`},
+		{`format("%s", [1, "a"])`, `[1, "a"]`},
+		{`format("%c%c", 71, "oby")`, "Go"},
+		{`format("100%%")`, "100%"},
+		// Integers
+		{`format("%d", 42)`, "42"},
+		{`format("%i|%u", -1, 2)`, "-1|2"},
+		{`format("%05d|%-5d|%+d|% d", 42, 42, 42, 42)`, "00042|42   |+42| 42"},
+		{`format("%.3d", 7)`, "007"},
+		{`format("%x %X %o %b", 255, 255, 8, 5)`, "ff FF 10 101"},
+		{`format("%#x %#o %#b", 255, 8, 5)`, "0xff 010 0b101"},
+		{`format("%d %d", 3.7, -3.5)`, "3 -4"},
+		{`format("%d %d", "7.9".to_d, "-7.9".to_d)`, "7 -8"},
+		// Floats
+		{`format("%f", 1.5)`, "1.500000"},
+		{`format("%.2f|%08.3f|%+.1f", 3.14159, -1.5, 2)`, "3.14|-001.500|+2.0"},
+		{`format("%-8.2f|", 2.5)`, "2.50    |"},
+		{`format("%e|%.2E", 12345.678, 0.00012)`, "1.234568e+04|1.20E-04"},
+		{`format("%g|%G", 0.0001, 0.00001234)`, "0.0001|1.234E-05"},
+		// Decimals are formatted with their exact values
+		{`format("%.2f", "2.675".to_d)`, "2.68"},
+		{`format("%.20f", "1.1".to_d)`, "1.10000000000000000000"},
+		{`format("%010.3f|%-8.1f|%+f", "-1.5".to_d, "0.25".to_d, "1".to_d)`, "-00001.500|0.3     |+1.000000"},
+		{`format("%#.0f", "3".to_d)`, "3."},
+		{`format("%.3e", "0.5".to_d)`, "5.000e-01"},
+		// Positions and widths from arguments
+		{`format("%2$s %1$s", "world", "hello")`, "hello world"},
+		{`format("%1$s %1$s", "echo")`, "echo echo"},
+		{`format("%*d|%-*d|%.*f", 5, 1, 4, 2, 2, 3.14159)`, "    1|2   |3.14"},
+		{`format("%*d|", -3, 1)`, "1  |"},
+		// String#%
+		{`"%05d" % 42`, "00042"},
+		{`"%s is %.1f" % ["pi", 3.14159]`, "pi is 3.1"},
+		{`
+		class Foo
+		  def to_s
+		    "foo!"
+		  end
+		end
+
+		"[%s]" % Foo.new
+		`, "[foo!]"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestFormatMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`format`, "ArgumentError: Expect at least 1 arguments. got: 0", 1},
+		{`format(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`format("%d")`, "ArgumentError: Expect 1 arguments for the format string. got: 0", 1},
+		{`format("%d", 1, 2)`, "ArgumentError: Expect 1 arguments for the format string. got: 2", 1},
+		{`format("%2$s", 1)`, "ArgumentError: Expect 2 arguments for the format string. got: 1", 1},
+		{`format("%1$s %s", 1, 2)`, "ArgumentError: Can't mix numbered and unnumbered arguments in a format string", 1},
+		{`format("%z", 1)`, "ArgumentError: Malformed format string - %z", 1},
+		{`format("100%")`, "ArgumentError: Incomplete format specifier; use %% (double %) instead", 1},
+		{`format("%d", "1")`, "TypeError: Expect argument to be Integer, Float or Decimal. got: String", 1},
+		{`format("%f", nil)`, "TypeError: Expect argument to be Integer, Float or Decimal. got: Null", 1},
+		{`format("%c", 1.5)`, "TypeError: Expect argument to be Integer or String. got: Float", 1},
+		{`format("%c", 2 ** 64)`, "RangeError: 18446744073709551616 is out of range", 1},
+		{`format("%*d", "1", 1)`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`format("%.100000000000d", 1)`, "ArgumentError: Precision too big: 100000000000", 1},
+		{`format("%99999999999999999999d", 1)`, "ArgumentError: Width too big: 99999999999999999999", 1},
+		{`format("%*d", 2 ** 63, 1)`, "ArgumentError: Width too big: 9223372036854775808", 1},
+		{`format("%*d", -(2 ** 63) - 1, 1)`, "ArgumentError: Width too big: -9223372036854775809", 1},
+		{`format("%.*f", 10000001, 1.5)`, "ArgumentError: Precision too big: 10000001", 1},
+		{`format("%d", "Inf".to_f)`, "ArgumentError: Can't convert Infinity into Integer", 1},
+		{`"%d %d" % [1]`, "ArgumentError: Expect 2 arguments for the format string. got: 1", 1},
+		{`"%d" % 1 % 2`, "ArgumentError: Expect 0 arguments for the format string. got: 1", 1},
+		{`String.fmt("%s")`, "ArgumentError: Expect 1 arguments for the format string. got: 0", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
@@ -0,0 +1,138 @@
+package vm
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:generate go run grapheme_gen.go
+
+// graphemeProperty is the Grapheme_Cluster_Break property of a code point.
+type graphemeProperty int
+
+const (
+	gcbOther graphemeProperty = iota
+	gcbCR
+	gcbLF
+	gcbControl
+	gcbExtend
+	gcbZWJ
+	gcbRegionalIndicator
+	gcbPrepend
+	gcbSpacingMark
+	gcbL
+	gcbV
+	gcbT
+	gcbLV
+	gcbLVT
+)
+
+const zeroWidthJoiner = '\u200d'
+
+func graphemeBreakProperty(r rune) graphemeProperty {
+	switch {
+	case r == '\r':
+		return gcbCR
+	case r == '\n':
+		return gcbLF
+	case r == zeroWidthJoiner:
+		return gcbZWJ
+	case r >= 0x1F1E6 && r <= 0x1F1FF:
+		return gcbRegionalIndicator
+	case r >= 0xAC00 && r <= 0xD7A3:
+		// Every 28th precomposed Hangul syllable has no trailing consonant
+		if (r-0xAC00)%28 == 0 {
+			return gcbLV
+		}
+		return gcbLVT
+	case unicode.Is(graphemeControl, r):
+		return gcbControl
+	case unicode.Is(graphemeExtend, r):
+		return gcbExtend
+	case unicode.Is(graphemePrepend, r):
+		return gcbPrepend
+	case unicode.Is(graphemeSpacingMark, r):
+		return gcbSpacingMark
+	case unicode.Is(graphemeL, r):
+		return gcbL
+	case unicode.Is(graphemeV, r):
+		return gcbV
+	case unicode.Is(graphemeT, r):
+		return gcbT
+	}
+
+	return gcbOther
+}
+
+// graphemeClusters splits s into extended grapheme clusters, the user-perceived characters defined by
+// Unicode Standard Annex #29, using the Unicode 14.0 properties in grapheme_tables.go. Invalid bytes are
+// clusters of their own.
+func graphemeClusters(s string) []string {
+	clusters := []string{}
+	start := 0
+	prev := gcbOther
+	// emojiZWJ is set when prev is a zero width joiner that follows an emoji and its extenders
+	emoji, emojiZWJ := false, false
+	regionalIndicators := 0
+
+	for i := 0; i < len(s); {
+		r, width := utf8.DecodeRuneInString(s[i:])
+		next := graphemeBreakProperty(r)
+		pictographic := unicode.Is(extendedPictographic, r)
+
+		if r == utf8.RuneError && width == 1 {
+			next, pictographic = gcbControl, false
+		}
+
+		if i > 0 && graphemeBoundary(prev, next, emojiZWJ && pictographic, regionalIndicators) {
+			clusters = append(clusters, s[start:i])
+			start = i
+		}
+
+		emojiZWJ = emoji && next == gcbZWJ
+		emoji = pictographic || (emoji && next == gcbExtend)
+
+		if next == gcbRegionalIndicator {
+			regionalIndicators++
+		} else {
+			regionalIndicators = 0
+		}
+
+		prev = next
+		i += width
+	}
+
+	if start < len(s) {
+		clusters = append(clusters, s[start:])
+	}
+
+	return clusters
+}
+
+// graphemeBoundary reports whether the rules of UAX #29 break between code points with the prev and next
+// properties. joinsEmoji is true when next is an emoji following a zero width joiner sequence, and
+// regionalIndicators is the number of regional indicators in a row that end with prev.
+func graphemeBoundary(prev, next graphemeProperty, joinsEmoji bool, regionalIndicators int) bool {
+	switch {
+	case prev == gcbCR && next == gcbLF: // GB3
+		return false
+	case prev == gcbCR || prev == gcbLF || prev == gcbControl: // GB4
+		return true
+	case next == gcbCR || next == gcbLF || next == gcbControl: // GB5
+		return true
+	case prev == gcbL && (next == gcbL || next == gcbV || next == gcbLV || next == gcbLVT): // GB6
+		return false
+	case (prev == gcbLV || prev == gcbV) && (next == gcbV || next == gcbT): // GB7
+		return false
+	case (prev == gcbLVT || prev == gcbT) && next == gcbT: // GB8
+		return false
+	case next == gcbExtend || next == gcbZWJ || next == gcbSpacingMark || prev == gcbPrepend: // GB9, GB9a, GB9b
+		return false
+	case prev == gcbZWJ && joinsEmoji: // GB11
+		return false
+	case prev == gcbRegionalIndicator && next == gcbRegionalIndicator: // GB12, GB13
+		return regionalIndicators%2 == 0
+	}
+
+	return true // GB999
+}
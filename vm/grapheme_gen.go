@@ -0,0 +1,206 @@
+//go:build ignore
+
+// This program generates grapheme_tables.go from the Grapheme_Cluster_Break and Extended_Pictographic
+// properties of the Unicode Character Database. Run it with `go generate` in the vm directory.
+// The -ucd flag takes the URL or the local directory the data files are read from.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const unicodeVersion = "14.0.0"
+
+var ucd = flag.String("ucd", "https://www.unicode.org/Public/"+unicodeVersion+"/ucd", "URL or directory of the Unicode Character Database")
+
+// The properties that get a table. CR, LF, ZWJ, Regional_Indicator and the LV and LVT Hangul syllables
+// are simple enough to be checked directly, see graphemeBreakProperty.
+var tables = []struct {
+	name     string
+	file     string
+	property string
+}{
+	{"graphemeControl", "auxiliary/GraphemeBreakProperty.txt", "Control"},
+	{"graphemeExtend", "auxiliary/GraphemeBreakProperty.txt", "Extend"},
+	{"graphemePrepend", "auxiliary/GraphemeBreakProperty.txt", "Prepend"},
+	{"graphemeSpacingMark", "auxiliary/GraphemeBreakProperty.txt", "SpacingMark"},
+	{"graphemeL", "auxiliary/GraphemeBreakProperty.txt", "L"},
+	{"graphemeV", "auxiliary/GraphemeBreakProperty.txt", "V"},
+	{"graphemeT", "auxiliary/GraphemeBreakProperty.txt", "T"},
+	{"extendedPictographic", "emoji/emoji-data.txt", "Extended_Pictographic"},
+}
+
+func main() {
+	flag.Parse()
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by grapheme_gen.go from the Unicode %s Character Database. DO NOT EDIT.\n\n", unicodeVersion)
+	fmt.Fprintf(&out, "package vm\n\nimport \"unicode\"\n")
+
+	for _, t := range tables {
+		ranges, err := readProperty(t.file, t.property)
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if t.property == "Extended_Pictographic" {
+			fmt.Fprintf(&out, "\n// %s holds the Extended_Pictographic code points.\n", t.name)
+		} else {
+			fmt.Fprintf(&out, "\n// %s holds the code points whose Grapheme_Cluster_Break is %s.\n", t.name, t.property)
+		}
+		writeTable(&out, t.name, ranges)
+	}
+
+	src, err := format.Source(out.Bytes())
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile("grapheme_tables.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// readProperty returns the sorted and merged code point ranges of the property in the data file.
+func readProperty(file string, property string) ([][2]rune, error) {
+	r, err := open(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Close()
+
+	ranges := [][2]rune{}
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+
+		fields := strings.Split(line, ";")
+
+		if len(fields) != 2 || strings.TrimSpace(fields[1]) != property {
+			continue
+		}
+
+		bounds := strings.Split(strings.TrimSpace(fields[0]), "..")
+		lo, err := strconv.ParseUint(bounds[0], 16, 32)
+
+		if err != nil {
+			return nil, err
+		}
+
+		hi := lo
+
+		if len(bounds) == 2 {
+			if hi, err = strconv.ParseUint(bounds[1], 16, 32); err != nil {
+				return nil, err
+			}
+		}
+
+		ranges = append(ranges, [2]rune{rune(lo), rune(hi)})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("no %s code points in %s", property, file)
+	}
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] < ranges[j][0] })
+	merged := ranges[:1]
+
+	for _, rg := range ranges[1:] {
+		last := &merged[len(merged)-1]
+
+		if rg[0] <= last[1]+1 {
+			if rg[1] > last[1] {
+				last[1] = rg[1]
+			}
+			continue
+		}
+
+		merged = append(merged, rg)
+	}
+
+	return merged, nil
+}
+
+func open(file string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(*ucd, "http") {
+		return os.Open(filepath.Join(*ucd, filepath.FromSlash(file)))
+	}
+
+	resp, err := http.Get(*ucd + "/" + file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %s/%s: %s", *ucd, file, resp.Status)
+	}
+
+	return resp.Body, nil
+}
+
+func writeTable(out io.Writer, name string, ranges [][2]rune) {
+	var r16, r32 []string
+	latinOffset := 0
+
+	for _, rg := range ranges {
+		// Ranges crossing 0xFFFF are split, as R16 only holds 16-bit code points
+		if rg[0] <= 0xFFFF && rg[1] > 0xFFFF {
+			r16 = append(r16, fmt.Sprintf("{0x%04x, 0xffff, 1},", rg[0]))
+			rg[0] = 0x10000
+		}
+
+		if rg[1] <= 0xFFFF {
+			r16 = append(r16, fmt.Sprintf("{0x%04x, 0x%04x, 1},", rg[0], rg[1]))
+
+			if rg[1] <= unicode.MaxLatin1 {
+				latinOffset++
+			}
+		} else {
+			r32 = append(r32, fmt.Sprintf("{0x%x, 0x%x, 1},", rg[0], rg[1]))
+		}
+	}
+
+	fmt.Fprintf(out, "var %s = &unicode.RangeTable{\n", name)
+
+	if len(r16) > 0 {
+		fmt.Fprintf(out, "R16: []unicode.Range16{\n%s\n},\n", strings.Join(r16, "\n"))
+	}
+
+	if len(r32) > 0 {
+		fmt.Fprintf(out, "R32: []unicode.Range32{\n%s\n},\n", strings.Join(r32, "\n"))
+	}
+
+	if latinOffset > 0 {
+		fmt.Fprintf(out, "LatinOffset: %d,\n", latinOffset)
+	}
+
+	fmt.Fprintf(out, "}\n")
+}
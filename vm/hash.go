@@ -475,6 +475,49 @@ func builtinHashInstanceMethods() []*BuiltinMethodObject {
 				}
 			},
 		},
+		{
+			// Calls block once for each key in the hash (in sorted key order), passing the
+			// key-value pair as an array. Enumerable methods iterate the hash with it, so a block
+			// with two parameters gets the key and the value.
+			// Returns `self`.
+			//
+			// ```Ruby
+			// h = { b: "2", a: 1 }
+			// h.each_entry do |pair|
+			//   puts pair
+			// end
+			// # => ["a", 1]
+			// # => ["b", "2"]
+			// ```
+			//
+			// @return [Hash]
+			Name: "each_entry",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect 0 arguments. got: %d", len(args))
+					}
+
+					if blockFrame == nil {
+						return t.vm.InitErrorObject(errors.InternalError, sourceLine, errors.CantYieldWithoutBlockFormat)
+					}
+
+					h := receiver.(*HashObject)
+
+					if len(h.Pairs) == 0 {
+						t.callFrameStack.pop()
+					} else {
+						for _, k := range h.sortedKeys() {
+							pair := []Object{t.vm.InitStringObject(k), h.Pairs[k]}
+
+							t.builtinMethodYield(blockFrame, t.vm.InitArrayObject(pair))
+						}
+					}
+
+					return h
+				}
+			},
+		},
 		{
 			// Loop through keys of the hash with given block frame. It also returns array of
 			// keys in alphabetical order.
@@ -1221,6 +1264,7 @@ func (vm *VM) initHashClass() *RClass {
 	hc := vm.initializeClass(classes.HashClass)
 	hc.setBuiltinMethods(builtinHashInstanceMethods(), false)
 	hc.setBuiltinMethods(builtinHashClassMethods(), true)
+	vm.libFiles = append(vm.libFiles, "hash.gb")
 	return hc
 }
 
@@ -640,6 +640,43 @@ func TestHashEachMethodFail(t *testing.T) {
 	}
 }
 
+func TestHashEachEntryMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+			output = []
+			{ b: "2", a: 1 }.each_entry do |pair|
+				output.push(pair)
+			end
+			output
+		`, []interface{}{[]interface{}{"a", 1}, []interface{}{"b", "2"}}},
+		{`
+			output = []
+			{ b: "2", a: 1 }.each_entry do |k, v|
+				output.push(k)
+			end
+			output
+		`, []interface{}{"a", "b"}},
+		{`
+			output = []
+			{}.each_entry do |pair|
+				output.push(pair)
+			end
+			output
+		`, []interface{}{}},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestHashEachKeyMethod(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -1,6 +1,10 @@
 package vm
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -8,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/goby-lang/goby/vm/classes"
 	"github.com/goby-lang/goby/vm/errors"
 )
 
@@ -21,156 +26,328 @@ var (
 func builtinHTTPClassMethods() []*BuiltinMethodObject {
 	return []*BuiltinMethodObject{
 		{
-			// Sends a GET request to the target and returns the HTTP response as a string. Will error on non-200 responses, for more control over http requests look at the `start` method.
+			// Sends a GET request to the target and returns the HTTP response as a string. Will error on non-2xx responses unless
+			// `raise_on_error: false` is given in the trailing options Hash, for more control over http requests look at the `start` method.
+			//
+			// ```ruby
+			// Net::HTTP.get("http://example.com", "users", "1")
+			// Net::HTTP.get("http://example.com/missing", { raise_on_error: false })
+			// ```
 			Name: "get",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-					arg0, ok := args[0].(*StringObject)
-					if !ok {
-						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect argument 0 to be string, got: %s", args[0].Class().Name)
+					args, opts := splitHTTPOptions(args)
+
+					uri, err := httpClassURL(args)
+					if err != nil {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, err.Error())
 					}
 
-					uri, err := url.Parse(arg0.value)
+					return httpClassRequest(t, sourceLine, newRequestFunc(http.MethodGet, uri, "", ""), opts, httpResponseBody)
+				}
+			},
+		}, {
+			// Sends a POST request to the target with type header and body. Returns the HTTP response as a string. Will error on non-2xx responses unless
+			// `raise_on_error: false` is given in the trailing options Hash, for more control over http requests look at the `start` method.
+			Name: "post",
+			Fn:   httpClassBodyMethod(http.MethodPost),
+		}, {
+			// Sends a PUT request to the target with type header and body. Returns the HTTP response as a string.
+			//
+			// ```ruby
+			// Net::HTTP.put("http://example.com/users/1", "application/json", '{"name":"Stan"}')
+			// ```
+			Name: "put",
+			Fn:   httpClassBodyMethod(http.MethodPut),
+		}, {
+			// Sends a PATCH request to the target with type header and body. Returns the HTTP response as a string.
+			Name: "patch",
+			Fn:   httpClassBodyMethod(http.MethodPatch),
+		}, {
+			// Sends a DELETE request to the target and returns the HTTP response as a string.
+			//
+			// ```ruby
+			// Net::HTTP.delete("http://example.com/users/1")
+			// ```
+			Name: "delete",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					args, opts := splitHTTPOptions(args)
 
-					if len(args) > 1 {
-						var arr []string
+					uri, err := httpClassURL(args)
+					if err != nil {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, err.Error())
+					}
 
-						for i, v := range args[1:] {
-							argn, ok := v.(*StringObject)
-							if !ok {
-								return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Splat arguments must be a string, got: %s for argument %d", v.Class().Name, i)
-							}
-							arr = append(arr, argn.value)
-						}
+					return httpClassRequest(t, sourceLine, newRequestFunc(http.MethodDelete, uri, "", ""), opts, httpResponseBody)
+				}
+			},
+		}, {
+			// Sends a HEAD request to the target with type header and body. Returns the HTTP headers as a map[string]string, where multiple values
+			// of a header are joined with " ". Will error on non-2xx responses unless
+			// `raise_on_error: false` is given in the trailing options Hash, for more control over http requests look at the `start` method.
+			Name: "head",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					args, opts := splitHTTPOptions(args)
 
-						uri.Path = path.Join(arr...)
+					uri, err := httpClassURL(args)
+					if err != nil {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, err.Error())
 					}
 
-					resp, err := http.Get(uri.String())
+					return httpClassRequest(t, sourceLine, newRequestFunc(http.MethodHead, uri, "", ""), opts, httpHeadResponseHeaders)
+				}
+			},
+		}, {
+			// Sends an OPTIONS request to the target and returns the HTTP headers as a Hash.
+			//
+			// ```ruby
+			// Net::HTTP.options("http://example.com")["Allow"] # => "GET, POST"
+			// ```
+			Name: "options",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					args, opts := splitHTTPOptions(args)
+
+					uri, err := httpClassURL(args)
 					if err != nil {
-						return t.vm.InitErrorObject(errors.HTTPError, sourceLine, "Could not complete request, %s", err)
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, err.Error())
 					}
-					if resp.StatusCode != http.StatusOK {
-						return t.vm.InitErrorObject(errors.HTTPError, sourceLine, "Non-200 response, %s (%d)", resp.Status, resp.StatusCode)
+
+					return httpClassRequest(t, sourceLine, newRequestFunc(http.MethodOptions, uri, "", ""), opts, httpResponseHeaders)
+				}
+			},
+		}, {
+			// Starts an HTTP client. This method requires a block which takes a Net::HTTP::Client object. The return value of this method is the last evaluated value of the provided block.
+			Name: "start",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect 0 arguments. got=%v", strconv.Itoa(len(args)))
 					}
 
-					content, err := ioutil.ReadAll(resp.Body)
-					resp.Body.Close()
+					gobyClient := httpClientClass.initializeInstance()
 
-					if err != nil {
-						return t.vm.InitErrorObject(errors.InternalError, sourceLine, err.Error())
+					result := t.builtinMethodYield(blockFrame, gobyClient)
+
+					if err, ok := result.Target.(*Error); ok {
+						return err //an Error object
 					}
 
-					return t.vm.InitStringObject(string(content))
+					return result.Target
 				}
 			},
-		}, {
-			// Sends a POST request to the target with type header and body. Returns the HTTP response as a string. Will error on non-200 responses, for more control over http requests look at the `start` method.
-			Name: "post",
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinHTTPResponseInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Serializes the given object with `to_json` and sets it as the response body.
+			// The `Content-Type` header is set to `application/json`.
+			//
+			// ```ruby
+			// server.get "/users/1" do |req, res|
+			//   res.json({ id: 1, name: "Stan" })
+			// end
+			// ```
+			//
+			// Without arguments, parses the body of a received response as JSON and returns a Hash or an Array.
+			//
+			// ```ruby
+			// res = client.get("http://example.com/users/1")
+			// res.json["name"] # => "Stan"
+			// ```
+			//
+			// @param object [Object]
+			// @return [Response]
+			Name: "json",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-					if len(args) != 3 {
-						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 3, len(args))
+					if len(args) == 0 {
+						body, _ := receiver.InstanceVariableGet("@body")
+						b, ok := body.(*StringObject)
+						if !ok {
+							return t.vm.InitErrorObject(errors.TypeError, sourceLine, "Expect response body to be a String. got: %s", body.Class().Name)
+						}
+
+						obj, err := parseJSON(t, b.value)
+						if err != nil {
+							return t.vm.InitErrorObject(errors.InternalError, sourceLine, "Can't parse response body as json: %s", err.Error())
+						}
+
+						return obj
 					}
 
-					arg0, ok := args[0].(*StringObject)
-					if !ok {
-						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect argument 0 to be string, got: %s", args[0].Class().Name)
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
 					}
-					host := arg0.value
 
-					arg1, ok := args[1].(*StringObject)
-					if !ok {
-						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect argument 1 to be string, got: %s", args[0].Class().Name)
+					receiver.InstanceVariableSet("@body", t.vm.InitStringObject(args[0].toJSON(t)))
+					setObjectHeader(t, receiver, "Content-Type", "application/json")
+
+					return receiver
+				}
+			},
+		},
+		{
+			// Renders the html template at the given path with an optional Hash of locals
+			// and sets the result as the response body.
+			//
+			// ```ruby
+			// server.get "/" do |req, res|
+			//   res.render("views/index.html", { name: "Stan" }) # <h1>Hello {{.name}}</h1>
+			// end
+			// ```
+			//
+			// @param path [String], locals [Hash]
+			// @return [Response]
+			Name: "render",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) < 1 || len(args) > 2 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect 1 or 2 arguments. got: %d", len(args))
 					}
-					contentType := arg1.value
 
-					arg2, ok := args[2].(*StringObject)
+					path, ok := args[0].(*StringObject)
 					if !ok {
-						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect argument 2 to be string, got: %s", args[0].Class().Name)
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
 					}
-					body := arg2.value
 
-					resp, err := http.Post(host, contentType, strings.NewReader(body))
-					if err != nil {
-						return t.vm.InitErrorObject(errors.HTTPError, sourceLine, "Could not complete request, %s", err)
+					var locals interface{}
+
+					if len(args) == 2 {
+						h, ok := args[1].(*HashObject)
+						if !ok {
+							return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.HashClass, args[1].Class().Name)
+						}
+
+						locals = ToGoValue(h)
 					}
-					if resp.StatusCode != http.StatusOK {
-						return t.vm.InitErrorObject(errors.HTTPError, sourceLine, "Non-200 response, %s (%d)", resp.Status, resp.StatusCode)
+
+					tmpl, err := template.ParseFiles(path.value)
+					if err != nil {
+						return t.vm.InitErrorObject(errors.InternalError, sourceLine, "Can't parse template %s: %s", path.value, err.Error())
 					}
 
-					content, err := ioutil.ReadAll(resp.Body)
-					resp.Body.Close()
+					var out bytes.Buffer
 
+					err = tmpl.Execute(&out, locals)
 					if err != nil {
-						return t.vm.InitErrorObject(errors.InternalError, sourceLine, err.Error())
+						return t.vm.InitErrorObject(errors.InternalError, sourceLine, "Can't render template %s: %s", path.value, err.Error())
 					}
 
-					return t.vm.InitStringObject(string(content))
+					receiver.InstanceVariableSet("@body", t.vm.InitStringObject(out.String()))
+					setObjectHeader(t, receiver, "Content-Type", "text/html; charset=utf-8")
+
+					return receiver
 				}
 			},
-		}, {
-			// Sends a HEAD request to the target with type header and body. Returns the HTTP headers as a map[string]string. Will error on non-200 responses, for more control over http requests look at the `start` method.
-			Name: "head",
+		},
+	}
+}
+
+func builtinHTTPRequestInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Sets the `Authorization` header of the request with the given basic auth credentials.
+			//
+			// ```ruby
+			// req.basic_auth("stan", "secret")
+			// ```
+			//
+			// @param username [String], password [String]
+			// @return [Request]
+			Name: "basic_auth",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-					arg0, ok := args[0].(*StringObject)
-					if !ok {
-						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect argument 0 to be string, got: %s", args[0].Class().Name)
+					if len(args) != 2 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 2, len(args))
 					}
 
-					uri, err := url.Parse(arg0.value)
-
-					if len(args) > 1 {
-						var arr []string
-
-						for i, v := range args[1:] {
-							argn, ok := v.(*StringObject)
-							if !ok {
-								return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Splat arguments must be a string, got: %s for argument %d", v.Class().Name, i)
-							}
-							arr = append(arr, argn.value)
-						}
-
-						uri.Path = path.Join(arr...)
+					user, ok := args[0].(*StringObject)
+					if !ok {
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
 					}
 
-					resp, err := http.Head(uri.String())
-					if err != nil {
-						return t.vm.InitErrorObject(errors.HTTPError, sourceLine, "Could not complete request, %s", err)
-					}
-					if resp.StatusCode != http.StatusOK {
-						return t.vm.InitErrorObject(errors.HTTPError, sourceLine, "Non-200 response, %s (%d)", resp.Status, resp.StatusCode)
+					password, ok := args[1].(*StringObject)
+					if !ok {
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, args[1].Class().Name)
 					}
 
-					ret := t.vm.InitHashObject(map[string]Object{})
+					setObjectHeader(t, receiver, "Authorization", basicAuthorization(user.value, password.value))
 
-					for k, v := range resp.Header {
-						ret.Pairs[k] = t.vm.InitStringObject(strings.Join(v, " "))
+					return receiver
+				}
+			},
+		},
+		{
+			// Serializes the given object with `to_json` and sets it as the request body.
+			// The `Content-Type` header is set to `application/json`.
+			//
+			// ```ruby
+			// req.set_json({ name: "Stan" })
+			// ```
+			//
+			// @param object [Object]
+			// @return [Request]
+			Name: "set_json",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
 					}
 
-					return ret
+					receiver.InstanceVariableSet("@body", t.vm.InitStringObject(args[0].toJSON(t)))
+					setObjectHeader(t, receiver, "Content-Type", "application/json")
+
+					return receiver
 				}
 			},
-		}, {
-			// Starts an HTTP client. This method requires a block which takes a Net::HTTP::Client object. The return value of this method is the last evaluated value of the provided block.
-			Name: "start",
+		},
+		{
+			// Sets a `multipart/form-data` body built from a Hash of form fields and a Hash of files to upload.
+			//
+			// ```ruby
+			// req.set_multipart({ user: "stan" }, { avatar: "stan.png" })
+			// ```
+			//
+			// @param fields [Hash], files [Hash]
+			// @return [Request]
+			Name: "set_multipart",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 && len(args) != 2 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 2, len(args))
+					}
 
-					if len(args) != 0 {
-						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect 0 arguments. got=%v", strconv.Itoa(len(args)))
+					fields, ok := args[0].(*HashObject)
+					if !ok {
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.HashClass, args[0].Class().Name)
 					}
 
-					gobyClient := httpClientClass.initializeInstance()
+					files := &HashObject{Pairs: map[string]Object{}}
 
-					result := t.builtinMethodYield(blockFrame, gobyClient)
+					if len(args) == 2 {
+						files, ok = args[1].(*HashObject)
+						if !ok {
+							return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.HashClass, args[1].Class().Name)
+						}
+					}
 
-					if err, ok := result.Target.(*Error); ok {
-						return err //an Error object
+					contentType, body, err := multipartBody(fields, files)
+					if err != nil {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, err.Error())
 					}
 
-					return result.Target
+					receiver.InstanceVariableSet("@body", t.vm.InitStringObject(body))
+					setObjectHeader(t, receiver, "Content-Type", contentType)
+
+					return receiver
 				}
 			},
 		},
@@ -194,14 +371,186 @@ func initHTTPClass(vm *VM) {
 	// Use Goby code to extend request and response classes.
 	vm.mainThread.execGobyLib("net/http/response.gb")
 	vm.mainThread.execGobyLib("net/http/request.gb")
+	vm.mainThread.execGobyLib("net/http/client.gb")
+}
+
+// defaultHTTPClient sends the requests of the `Net::HTTP` class methods, which raise on non-2xx responses by default.
+var defaultHTTPClient = newDefaultHTTPClient()
+
+func newDefaultHTTPClient() *httpClient {
+	c, _ := newHTTPClient(map[string]Object{})
+	c.raiseOnError = true
+
+	return c
+}
+
+// splitHTTPOptions separates the trailing options Hash from the other arguments.
+func splitHTTPOptions(args []Object) ([]Object, []Object) {
+	if len(args) > 0 {
+		if _, ok := args[len(args)-1].(*HashObject); ok {
+			return args[:len(args)-1], args[len(args)-1:]
+		}
+	}
+
+	return args, nil
+}
+
+// httpClassURL builds the target url from the first argument, joining the remaining ones as the path.
+func httpClassURL(args []Object) (string, error) {
+	if len(args) == 0 {
+		return "", fmt.Errorf("Expect at least 1 argument. got: 0")
+	}
+
+	arg0, ok := args[0].(*StringObject)
+	if !ok {
+		return "", fmt.Errorf("Expect argument 0 to be string, got: %s", args[0].Class().Name)
+	}
+
+	uri, err := url.Parse(arg0.value)
+	if err != nil {
+		return "", err
+	}
+
+	if len(args) > 1 {
+		var arr []string
+
+		for i, v := range args[1:] {
+			argn, ok := v.(*StringObject)
+			if !ok {
+				return "", fmt.Errorf("Splat arguments must be a string, got: %s for argument %d", v.Class().Name, i)
+			}
+			arr = append(arr, argn.value)
+		}
+
+		uri.Path = path.Join(arr...)
+	}
+
+	return uri.String(), nil
+}
+
+func httpClassBodyMethod(method string) func(receiver Object, sourceLine int) builtinMethodBody {
+	return func(receiver Object, sourceLine int) builtinMethodBody {
+		return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			args, opts := splitHTTPOptions(args)
+
+			if len(args) != 3 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 3, len(args))
+			}
+
+			var values []string
+
+			for i, arg := range args {
+				s, ok := arg.(*StringObject)
+				if !ok {
+					return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect argument %d to be string, got: %s", i, arg.Class().Name)
+				}
+
+				values = append(values, s.value)
+			}
+
+			return httpClassRequest(t, sourceLine, newRequestFunc(method, values[0], values[1], values[2]), opts, httpResponseBody)
+		}
+	}
+}
+
+// httpClassRequest sends the request with the default client and converts the response with the given function.
+func httpClassRequest(t *Thread, sourceLine int, newRequest func() (*http.Request, error), opts []Object, convert func(*Thread, *http.Response) (Object, error)) Object {
+	c := defaultHTTPClient
+
+	if len(opts) > 0 {
+		var err error
+
+		c, err = c.withOverrides(opts[0].(*HashObject).Pairs)
+		if err != nil {
+			return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, err.Error())
+		}
+	}
+
+	resp, err := c.do(newRequest)
+	if err != nil {
+		return httpRequestError(t, sourceLine, err)
+	}
+
+	defer resp.Body.Close()
+
+	result, err := convert(t, resp)
+	if err != nil {
+		return t.vm.InitErrorObject(errors.InternalError, sourceLine, err.Error())
+	}
+
+	return result
+}
+
+func httpResponseBody(t *Thread, resp *http.Response) (Object, error) {
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.vm.InitStringObject(string(content)), nil
+}
+
+func httpResponseHeaders(t *Thread, resp *http.Response) (Object, error) {
+	return headersGoToGoby(t, resp.Header), nil
+}
+
+// httpHeadResponseHeaders converts the headers of a `head` response like httpResponseHeaders, but joins multiple
+// values of the same header with " " as `head` always has, so existing callers keep working.
+func httpHeadResponseHeaders(t *Thread, resp *http.Response) (Object, error) {
+	headers := map[string]Object{}
+
+	for k, v := range resp.Header {
+		headers[k] = t.vm.InitStringObject(strings.Join(v, " "))
+	}
+
+	return t.vm.InitHashObject(headers), nil
+}
+
+// parseJSON converts a JSON document into Goby objects. Objects become Hashes and arrays become Arrays.
+func parseJSON(t *Thread, s string) (Object, error) {
+	var v interface{}
+
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return nil, err
+	}
+
+	return jsonValueToObject(t, v), nil
+}
+
+func jsonValueToObject(t *Thread, v interface{}) Object {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return t.vm.convertJSONToHashObj(v)
+	case []interface{}:
+		elems := []Object{}
+
+		for _, elem := range v {
+			elems = append(elems, jsonValueToObject(t, elem))
+		}
+
+		return t.vm.InitArrayObject(elems)
+	default:
+		return t.vm.InitObjectFromGoType(v)
+	}
+}
+
+// setObjectHeader sets a header on a Goby request or response object.
+func setObjectHeader(t *Thread, res Object, key, value string) {
+	headers, ok := res.InstanceVariableGet("@headers")
+	h, isHash := headers.(*HashObject)
+
+	if !ok || !isHash {
+		h = t.vm.InitHashObject(map[string]Object{})
+		res.InstanceVariableSet("@headers", h)
+	}
+
+	h.Pairs[key] = t.vm.InitStringObject(value)
 }
 
 func initRequestClass(vm *VM, hc *RClass) *RClass {
 	requestClass := vm.initializeClass("Request")
 	hc.setClassConstant(requestClass)
-	builtinHTTPRequestInstanceMethods := []*BuiltinMethodObject{}
-
-	requestClass.setBuiltinMethods(builtinHTTPRequestInstanceMethods, false)
+	requestClass.setBuiltinMethods(builtinHTTPRequestInstanceMethods(), false)
 
 	httpRequestClass = requestClass
 	return requestClass
@@ -210,9 +559,7 @@ func initRequestClass(vm *VM, hc *RClass) *RClass {
 func initResponseClass(vm *VM, hc *RClass) *RClass {
 	responseClass := vm.initializeClass("Response")
 	hc.setClassConstant(responseClass)
-	builtinHTTPResponseInstanceMethods := []*BuiltinMethodObject{}
-
-	responseClass.setBuiltinMethods(builtinHTTPResponseInstanceMethods, false)
+	responseClass.setBuiltinMethods(builtinHTTPResponseInstanceMethods(), false)
 
 	httpResponseClass = responseClass
 	return responseClass
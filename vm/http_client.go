@@ -1,10 +1,20 @@
 package vm
 
 import (
+	"bytes"
+	"crypto/tls"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"math"
+	"mime/multipart"
 	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/goby-lang/goby/vm/classes"
 	"github.com/goby-lang/goby/vm/errors"
@@ -13,104 +23,195 @@ import (
 // Instance methods --------------------------------------------------------
 
 func builtinHTTPClientInstanceMethods() []*BuiltinMethodObject {
-	//TODO: cookie jar and mutable client
-	goClient := http.DefaultClient
-
 	return []*BuiltinMethodObject{
 		{
-			// Sends a GET request to the target and returns a `Net::HTTP::Response` object.
-			Name: "get",
+			// Builds the underlying Go client from the given options Hash. It's called by
+			// `Net::HTTP::Client.new`, which accepts the options as keyword arguments:
+			//
+			// ```ruby
+			// client = Net::HTTP::Client.new(timeout: 5, max_redirects: 3, proxy: "http://proxy:8080", cookie_jar: true, retries: 2)
+			// ```
+			//
+			// Supported options are timeout, follow_redirects, max_redirects, proxy, tls_verify, cookie_jar,
+			// retries, retry_backoff, max_idle_conns, max_idle_conns_per_host, max_conns_per_host, idle_conn_timeout
+			// and raise_on_error. Timeouts and backoffs are in seconds.
+			Name: "configure",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 					if len(args) != 1 {
 						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
 					}
 
-					u, ok := args[0].(*StringObject)
+					opts, ok := args[0].(*HashObject)
 					if !ok {
-						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, u.Class().Name)
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.HashClass, args[0].Class().Name)
 					}
 
-					resp, err := goClient.Get(u.value)
+					c, err := newHTTPClient(opts.Pairs)
 					if err != nil {
-						return t.vm.InitErrorObject(errors.HTTPError, sourceLine, "Could not complete request, %s", err)
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, err.Error())
 					}
 
-					gobyResp, err := responseGoToGoby(t, resp)
-					if err != nil {
-						return t.vm.InitErrorObject(errors.InternalError, sourceLine, err.Error())
-					}
+					receiver.InstanceVariableSet("@go_client", t.vm.initGoObject(c))
 
-					return gobyResp
+					return receiver
 				}
 			},
-		}, {
-			// Sends a POST request to the target and returns a `Net::HTTP::Response` object.
-			Name: "post",
+		},
+		// Sends a GET request to the target and returns a `Net::HTTP::Response` object.
+		// The optional Hash accepts timeout, retries, follow_redirects, max_redirects, raise_on_error, headers and query.
+		//
+		// ```ruby
+		// client.get("http://example.com/users", { query: { page: 2 }, timeout: 1 })
+		// ```
+		httpClientVerbMethod("get", http.MethodGet),
+		// Sends a HEAD request to the target and returns a `Net::HTTP::Response` object.
+		httpClientVerbMethod("head", http.MethodHead),
+		// Sends a DELETE request to the target and returns a `Net::HTTP::Response` object.
+		httpClientVerbMethod("delete", http.MethodDelete),
+		// Sends an OPTIONS request to the target and returns a `Net::HTTP::Response` object.
+		httpClientVerbMethod("options", http.MethodOptions),
+		// Sends a POST request with the given content type and body, and returns a `Net::HTTP::Response` object.
+		//
+		// ```ruby
+		// client.post("http://example.com/users", "text/plain", "Stan", { raise_on_error: true })
+		// ```
+		httpClientBodyVerbMethod("post", http.MethodPost),
+		// Sends a PUT request with the given content type and body, and returns a `Net::HTTP::Response` object.
+		httpClientBodyVerbMethod("put", http.MethodPut),
+		// Sends a PATCH request with the given content type and body, and returns a `Net::HTTP::Response` object.
+		httpClientBodyVerbMethod("patch", http.MethodPatch),
+		// Serializes the object with `to_json` and POSTs it as `application/json`.
+		//
+		// ```ruby
+		// res = client.post_json("http://example.com/users", { name: "Stan" })
+		// res.json["id"]
+		// ```
+		httpClientJSONVerbMethod("post_json", http.MethodPost),
+		// Serializes the object with `to_json` and PUTs it as `application/json`.
+		httpClientJSONVerbMethod("put_json", http.MethodPut),
+		// Serializes the object with `to_json` and PATCHes it as `application/json`.
+		httpClientJSONVerbMethod("patch_json", http.MethodPatch),
+		{
+			// Sends a `multipart/form-data` POST request. The first Hash contains form fields and
+			// the second one maps field names to the paths of the files to upload.
+			//
+			// ```ruby
+			// client.post_multipart("http://example.com/avatars", { user: "stan" }, { avatar: "stan.png" })
+			// ```
+			//
+			// @param url [String], fields [Hash], files [Hash], options [Hash]
+			// @return [Response]
+			Name: "post_multipart",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-					if len(args) != 3 {
-						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 3, len(args))
+					if len(args) < 2 || len(args) > 4 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect 2 to 4 arguments. got: %d", len(args))
 					}
 
 					u, ok := args[0].(*StringObject)
 					if !ok {
-						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, u.Class().Name)
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
 					}
 
-					contentType, ok := args[1].(*StringObject)
+					fields, ok := args[1].(*HashObject)
 					if !ok {
-						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, u.Class().Name)
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.HashClass, args[1].Class().Name)
 					}
 
-					body, ok := args[2].(*StringObject)
-					if !ok {
-						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, u.Class().Name)
+					files := &HashObject{Pairs: map[string]Object{}}
+					var overrides []Object
+
+					if len(args) > 2 {
+						files, ok = args[2].(*HashObject)
+						if !ok {
+							return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.HashClass, args[2].Class().Name)
+						}
 					}
 
-					bodyR := strings.NewReader(body.value)
+					if len(args) > 3 {
+						overrides = args[3:]
+					}
 
-					resp, err := goClient.Post(u.value, contentType.value, bodyR)
+					contentType, body, err := multipartBody(fields, files)
 					if err != nil {
-						return t.vm.InitErrorObject(errors.HTTPError, sourceLine, "Could not complete request, %s", err)
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, err.Error())
+					}
+
+					return sendHTTPRequest(t, receiver, sourceLine, newRequestFunc(http.MethodPost, u.value, contentType, body), overrides)
+				}
+			},
+		},
+		{
+			// Sends every request of this client with the given basic auth credentials.
+			//
+			// ```ruby
+			// client.basic_auth("stan", "secret")
+			// ```
+			//
+			// @param username [String], password [String]
+			// @return [Client]
+			Name: "basic_auth",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 2 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 2, len(args))
+					}
+
+					user, ok := args[0].(*StringObject)
+					if !ok {
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+					}
+
+					password, ok := args[1].(*StringObject)
+					if !ok {
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, args[1].Class().Name)
 					}
 
-					gobyResp, err := responseGoToGoby(t, resp)
+					c, err := httpClientFor(t, receiver, nil)
 					if err != nil {
-						return t.vm.InitErrorObject(errors.InternalError, sourceLine, err.Error())
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, err.Error())
 					}
 
-					return gobyResp
+					c.headers.Set("Authorization", basicAuthorization(user.value, password.value))
+
+					return receiver
 				}
 			},
-		}, {
-			// Sends a HEAD request to the target and returns a `Net::HTTP::Response` object.
-			Name: "head",
+		},
+		{
+			// Sends every request of this client with the given bearer token.
+			//
+			// ```ruby
+			// client.bearer_auth("my-token")
+			// ```
+			//
+			// @param token [String]
+			// @return [Client]
+			Name: "bearer_auth",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 					if len(args) != 1 {
 						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
 					}
 
-					u, ok := args[0].(*StringObject)
+					token, ok := args[0].(*StringObject)
 					if !ok {
-						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, u.Class().Name)
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
 					}
 
-					resp, err := goClient.Head(u.value)
+					c, err := httpClientFor(t, receiver, nil)
 					if err != nil {
-						return t.vm.InitErrorObject(errors.HTTPError, sourceLine, "Could not complete request, %s", err)
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, err.Error())
 					}
 
-					gobyResp, err := responseGoToGoby(t, resp)
-					if err != nil {
-						return t.vm.InitErrorObject(errors.InternalError, sourceLine, err.Error())
-					}
+					c.headers.Set("Authorization", "Bearer "+token.value)
 
-					return gobyResp
+					return receiver
 				}
 			},
-		}, {
+		},
+		{
 			// Returns a blank `Net::HTTP::Request` object to be sent with the`exec` method
 			Name: "request",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
@@ -119,11 +220,12 @@ func builtinHTTPClientInstanceMethods() []*BuiltinMethodObject {
 				}
 			},
 		}, {
-			// Sends a passed `Net::HTTP::Request` object and returns a `Net::HTTP::Response` object
+			// Sends a passed `Net::HTTP::Request` object and returns a `Net::HTTP::Response` object.
+			// An optional Hash overrides the client's options for this request.
 			Name: "exec",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-					if len(args) != 1 {
+					if len(args) != 1 && len(args) != 2 {
 						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
 					}
 
@@ -131,23 +233,9 @@ func builtinHTTPClientInstanceMethods() []*BuiltinMethodObject {
 						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "HTTP Response", args[0].Class().Name)
 					}
 
-					goReq, err := requestGobyToGo(args[0])
-					if err != nil {
-						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, err.Error())
-					}
-
-					goResp, err := goClient.Do(goReq)
-					if err != nil {
-						return t.vm.InitErrorObject(errors.HTTPError, sourceLine, "Could not complete request, %s", err)
-					}
-
-					gobyResp, err := responseGoToGoby(t, goResp)
-
-					if err != nil {
-						return t.vm.InitErrorObject(errors.InternalError, sourceLine, err.Error())
-					}
-
-					return gobyResp
+					return sendHTTPRequest(t, receiver, sourceLine, func() (*http.Request, error) {
+						return requestGobyToGo(args[0])
+					}, args[1:])
 				}
 			},
 		},
@@ -170,38 +258,556 @@ func initClientClass(vm *VM, hc *RClass) *RClass {
 
 // Other helper functions -----------------------------------------------
 
-func requestGobyToGo(gobyReq Object) (*http.Request, error) {
-	//:method, :protocol, :body, :content_length, :transfer_encoding, :host, :path, :url, :params
-	uObj, ok := gobyReq.InstanceVariableGet("@url")
+// httpClient wraps a Go http.Client with the settings applied to every request. Every Net::HTTP::Client gets
+// its own http.Transport so connection pools aren't shared between clients.
+type httpClient struct {
+	client          *http.Client
+	retries         int
+	backoff         time.Duration
+	headers         http.Header
+	query           url.Values
+	raiseOnError    bool
+	followRedirects bool
+	maxRedirects    int
+}
+
+// requestBuildError is returned when a Goby request object can't be converted into a Go request.
+type requestBuildError struct {
+	error
+}
+
+// statusError is returned for non-2xx responses when raise_on_error is enabled.
+type statusError struct {
+	resp *http.Response
+}
+
+func (e statusError) Error() string {
+	return fmt.Sprintf("Non-200 response, %s (%d)", e.resp.Status, e.resp.StatusCode)
+}
+
+func newHTTPClient(opts map[string]Object) (*httpClient, error) {
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+	c := &httpClient{
+		client:          &http.Client{Transport: transport, Timeout: 30 * time.Second},
+		backoff:         100 * time.Millisecond,
+		headers:         http.Header{},
+		query:           url.Values{},
+		followRedirects: true,
+		maxRedirects:    10,
+	}
+
+	for k, v := range opts {
+		if v == NULL {
+			continue
+		}
+
+		var err error
+
+		switch k {
+		case "timeout":
+			c.client.Timeout, err = toDuration(v)
+		case "retry_backoff":
+			c.backoff, err = toDuration(v)
+		case "idle_conn_timeout":
+			transport.IdleConnTimeout, err = toDuration(v)
+		case "retries":
+			c.retries, err = toNonNegativeInt(v)
+		case "max_redirects":
+			c.maxRedirects, err = toNonNegativeInt(v)
+		case "max_idle_conns":
+			transport.MaxIdleConns, err = toNonNegativeInt(v)
+		case "max_idle_conns_per_host":
+			transport.MaxIdleConnsPerHost, err = toNonNegativeInt(v)
+		case "max_conns_per_host":
+			transport.MaxConnsPerHost, err = toNonNegativeInt(v)
+		case "follow_redirects":
+			c.followRedirects = v.isTruthy()
+		case "raise_on_error":
+			c.raiseOnError = v.isTruthy()
+		case "tls_verify":
+			if !v.isTruthy() {
+				transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+			}
+		case "cookie_jar":
+			if v.isTruthy() {
+				c.client.Jar, _ = cookiejar.New(nil)
+			}
+		case "proxy":
+			p, ok := v.(*StringObject)
+			if !ok {
+				return nil, fmt.Errorf("Expect proxy to be a String. got: %s", v.Class().Name)
+			}
+
+			proxyURL, parseErr := url.Parse(p.value)
+			if parseErr != nil {
+				return nil, fmt.Errorf("Invalid proxy url %s: %s", p.value, parseErr.Error())
+			}
+
+			transport.Proxy = http.ProxyURL(proxyURL)
+		default:
+			return nil, fmt.Errorf("Unknown client option: %s", k)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("Expect %s to be %s", k, err.Error())
+		}
+	}
+
+	c.client.CheckRedirect = redirectPolicy(c.followRedirects, c.maxRedirects)
+
+	return c, nil
+}
+
+// withOverrides returns a copy of the client with per-request options applied.
+// The copy shares the transport and the cookie jar with the original client.
+func (c *httpClient) withOverrides(opts map[string]Object) (*httpClient, error) {
+	client := *c.client
+	overridden := &httpClient{
+		client:          &client,
+		retries:         c.retries,
+		backoff:         c.backoff,
+		headers:         cloneHeader(c.headers),
+		query:           url.Values{},
+		raiseOnError:    c.raiseOnError,
+		followRedirects: c.followRedirects,
+		maxRedirects:    c.maxRedirects,
+	}
+
+	for k, vs := range c.query {
+		overridden.query[k] = append([]string{}, vs...)
+	}
+
+	for k, v := range opts {
+		if v == NULL {
+			continue
+		}
+
+		var err error
+
+		switch k {
+		case "timeout":
+			client.Timeout, err = toDuration(v)
+		case "retries":
+			overridden.retries, err = toNonNegativeInt(v)
+		case "follow_redirects":
+			overridden.followRedirects = v.isTruthy()
+		case "max_redirects":
+			overridden.maxRedirects, err = toNonNegativeInt(v)
+		case "raise_on_error":
+			overridden.raiseOnError = v.isTruthy()
+		case "headers":
+			err = eachHashValue(v, func(key, value string) { overridden.headers.Set(key, value) })
+		case "query":
+			err = eachHashValue(v, func(key, value string) { overridden.query.Add(key, value) })
+		default:
+			return nil, fmt.Errorf("Unknown request option: %s", k)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("Expect %s to be %s", k, err.Error())
+		}
+	}
+
+	client.CheckRedirect = redirectPolicy(overridden.followRedirects, overridden.maxRedirects)
+
+	return overridden, nil
+}
+
+// do sends the request built by newRequest with the client's headers and query parameters. Idempotent requests
+// are retried with exponential backoff when they fail with a network error or a 502, 503 or 504 response.
+func (c *httpClient) do(newRequest func() (*http.Request, error)) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return nil, requestBuildError{err}
+		}
+
+		for k, vs := range c.headers {
+			if req.Header.Get(k) == "" {
+				req.Header[k] = vs
+			}
+		}
+
+		if len(c.query) > 0 {
+			q := req.URL.Query()
+
+			for k, vs := range c.query {
+				for _, v := range vs {
+					q.Add(k, v)
+				}
+			}
+
+			req.URL.RawQuery = q.Encode()
+		}
+
+		resp, err := c.client.Do(req)
+
+		if attempt >= c.retries || !isIdempotent(req.Method) || !shouldRetry(resp, err) {
+			if err == nil && c.raiseOnError && !isSuccessful(resp) {
+				resp.Body.Close()
+				return nil, statusError{resp}
+			}
+
+			return resp, err
+		}
+
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		time.Sleep(c.backoff * time.Duration(1<<uint(attempt)))
+	}
+}
+
+func redirectPolicy(follow bool, max int) func(*http.Request, []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if !follow {
+			return http.ErrUseLastResponse
+		}
+
+		if len(via) > max {
+			return fmt.Errorf("stopped after %d redirects", max)
+		}
+
+		return nil
+	}
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func isSuccessful(resp *http.Response) bool {
+	return resp.StatusCode >= 200 && resp.StatusCode < 300
+}
+
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+func toNonNegativeInt(obj Object) (int, error) {
+	i, ok := obj.(*IntegerObject)
+
+	if !ok || i.value < 0 {
+		return 0, fmt.Errorf("a non-negative Integer. got: %s", obj.toString())
+	}
+
+	if i.bigValue != nil {
+		return 0, fmt.Errorf("at most %d. got: %s", math.MaxInt, obj.toString())
+	}
+
+	return i.value, nil
+}
+
+func cloneHeader(h http.Header) http.Header {
+	cloned := http.Header{}
+
+	for k, vs := range h {
+		cloned[k] = append([]string{}, vs...)
+	}
+
+	return cloned
+}
+
+// eachHashValue calls fn with every pair of the Hash. Array values call fn once per element.
+func eachHashValue(obj Object, fn func(key, value string)) error {
+	h, ok := obj.(*HashObject)
 	if !ok {
-		return nil, fmt.Errorf("could not get url")
+		return fmt.Errorf("a Hash. got: %s", obj.Class().Name)
+	}
+
+	for k, v := range h.Pairs {
+		if arr, ok := v.(*ArrayObject); ok {
+			for _, elem := range arr.Elements {
+				fn(k, stringValue(elem))
+			}
+
+			continue
+		}
+
+		fn(k, stringValue(v))
+	}
+
+	return nil
+}
+
+// stringValue returns a String's value without quotes and other objects' string format.
+func stringValue(obj Object) string {
+	if s, ok := obj.(*StringObject); ok {
+		return s.value
+	}
+
+	return obj.toString()
+}
+
+func basicAuthorization(user, password string) string {
+	req := &http.Request{Header: http.Header{}}
+	req.SetBasicAuth(user, password)
+
+	return req.Header.Get("Authorization")
+}
+
+func multipartBody(fields, files *HashObject) (string, string, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	err := eachHashValue(fields, func(key, value string) { w.WriteField(key, value) })
+	if err != nil {
+		return "", "", err
 	}
 
-	u := uObj.(*StringObject).value
+	for field, p := range files.Pairs {
+		path, ok := p.(*StringObject)
+		if !ok {
+			return "", "", fmt.Errorf("Expect file path of %s to be a String. got: %s", field, p.Class().Name)
+		}
+
+		f, err := os.Open(path.value)
+		if err != nil {
+			return "", "", err
+		}
+
+		part, err := w.CreateFormFile(field, filepath.Base(path.value))
+		if err == nil {
+			_, err = io.Copy(part, f)
+		}
 
-	methodObj, ok := gobyReq.InstanceVariableGet("@method")
+		f.Close()
+
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return "", "", err
+	}
+
+	return w.FormDataContentType(), buf.String(), nil
+}
+
+// newRequestFunc returns a function building a fresh request on every call, so the body can be re-sent on retries.
+func newRequestFunc(method, u, contentType, body string) func() (*http.Request, error) {
+	return func() (*http.Request, error) {
+		req, err := http.NewRequest(method, u, strings.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(contentType) > 0 {
+			req.Header.Set("Content-Type", contentType)
+		}
+
+		return req, nil
+	}
+}
+
+// httpClientFor returns the client configured for the Goby client object. Clients created without
+// `Net::HTTP::Client.new`, like the ones yielded by `Net::HTTP.start`, get the default options.
+// The optional overrides Hash is applied on top of the client's own options.
+func httpClientFor(t *Thread, receiver Object, overrides []Object) (*httpClient, error) {
+	var c *httpClient
+
+	if obj, ok := receiver.InstanceVariableGet("@go_client"); ok {
+		c, _ = obj.Value().(*httpClient)
+	}
+
+	if c == nil {
+		var err error
+		c, err = newHTTPClient(map[string]Object{})
+		if err != nil {
+			return nil, err
+		}
+
+		receiver.InstanceVariableSet("@go_client", t.vm.initGoObject(c))
+	}
+
+	if len(overrides) == 0 || overrides[0] == NULL {
+		return c, nil
+	}
+
+	opts, ok := overrides[0].(*HashObject)
 	if !ok {
-		return nil, fmt.Errorf("could not get method")
+		return nil, fmt.Errorf("Expect request options to be a Hash. got: %s", overrides[0].Class().Name)
 	}
 
-	method := methodObj.(*StringObject).value
+	return c.withOverrides(opts.Pairs)
+}
+
+func sendHTTPRequest(t *Thread, receiver Object, sourceLine int, newRequest func() (*http.Request, error), overrides []Object) Object {
+	c, err := httpClientFor(t, receiver, overrides)
+	if err != nil {
+		return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, err.Error())
+	}
+
+	resp, err := c.do(newRequest)
+	if err != nil {
+		return httpRequestError(t, sourceLine, err)
+	}
+
+	gobyResp, err := responseGoToGoby(t, resp)
+	if err != nil {
+		return t.vm.InitErrorObject(errors.InternalError, sourceLine, err.Error())
+	}
+
+	return gobyResp
+}
+
+func httpRequestError(t *Thread, sourceLine int, err error) *Error {
+	switch err := err.(type) {
+	case requestBuildError:
+		return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, err.Error())
+	case statusError:
+		return t.vm.InitErrorObject(errors.HTTPError, sourceLine, err.Error())
+	default:
+		return t.vm.InitErrorObject(errors.HTTPError, sourceLine, "Could not complete request, %s", err)
+	}
+}
+
+func httpClientVerbMethod(name, method string) *BuiltinMethodObject {
+	return &BuiltinMethodObject{
+		Name: name,
+		Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+			return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+				if len(args) != 1 && len(args) != 2 {
+					return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+				}
+
+				u, ok := args[0].(*StringObject)
+				if !ok {
+					return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+				}
+
+				return sendHTTPRequest(t, receiver, sourceLine, newRequestFunc(method, u.value, "", ""), args[1:])
+			}
+		},
+	}
+}
+
+func httpClientBodyVerbMethod(name, method string) *BuiltinMethodObject {
+	return &BuiltinMethodObject{
+		Name: name,
+		Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+			return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+				if len(args) != 3 && len(args) != 4 {
+					return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 3, len(args))
+				}
+
+				for i, arg := range args[:3] {
+					if _, ok := arg.(*StringObject); !ok {
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, args[i].Class().Name)
+					}
+				}
+
+				u, contentType, body := args[0].(*StringObject), args[1].(*StringObject), args[2].(*StringObject)
+
+				return sendHTTPRequest(t, receiver, sourceLine, newRequestFunc(method, u.value, contentType.value, body.value), args[3:])
+			}
+		},
+	}
+}
+
+func httpClientJSONVerbMethod(name, method string) *BuiltinMethodObject {
+	return &BuiltinMethodObject{
+		Name: name,
+		Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+			return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+				if len(args) != 2 && len(args) != 3 {
+					return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 2, len(args))
+				}
+
+				u, ok := args[0].(*StringObject)
+				if !ok {
+					return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+				}
+
+				body := args[1].toJSON(t)
+
+				return sendHTTPRequest(t, receiver, sourceLine, newRequestFunc(method, u.value, "application/json", body), args[2:])
+			}
+		},
+	}
+}
+
+func requestGobyToGo(gobyReq Object) (*http.Request, error) {
+	//:method, :protocol, :body, :content_length, :transfer_encoding, :host, :path, :url, :params, :query
+	uObj, _ := gobyReq.InstanceVariableGet("@url")
+	u, ok := uObj.(*StringObject)
+	if !ok {
+		return nil, fmt.Errorf("Expect request url to be a String. got: %s", uObj.Class().Name)
+	}
+
+	method := http.MethodGet
+
+	if methodObj, ok := gobyReq.InstanceVariableGet("@method"); ok && methodObj != NULL {
+		m, ok := methodObj.(*StringObject)
+		if !ok {
+			return nil, fmt.Errorf("Expect request method to be a String. got: %s", methodObj.Class().Name)
+		}
+
+		method = strings.ToUpper(m.value)
+	}
 
 	var body string
-	if !(method == "GET" || method == "HEAD") {
-		bodyObj, ok := gobyReq.InstanceVariableGet("@body")
+
+	if bodyObj, ok := gobyReq.InstanceVariableGet("@body"); ok && bodyObj != NULL {
+		b, ok := bodyObj.(*StringObject)
 		if !ok {
-			return nil, fmt.Errorf("could not get body")
+			return nil, fmt.Errorf("Expect request body to be a String. got: %s", bodyObj.Class().Name)
 		}
 
-		body = bodyObj.(*StringObject).value
+		body = b.value
 	}
 
-	return http.NewRequest(method, u, strings.NewReader(body))
+	req, err := http.NewRequest(method, u.value, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	if h, ok := gobyReq.InstanceVariableGet("@headers"); ok && h != NULL {
+		err := eachHashValue(h, func(key, value string) { req.Header.Add(key, value) })
+		if err != nil {
+			return nil, fmt.Errorf("Expect request headers to be %s", err.Error())
+		}
+	}
+
+	if q, ok := gobyReq.InstanceVariableGet("@query"); ok && q != NULL {
+		query := req.URL.Query()
+
+		err := eachHashValue(q, func(key, value string) { query.Add(key, value) })
+		if err != nil {
+			return nil, fmt.Errorf("Expect request query to be %s", err.Error())
+		}
 
+		req.URL.RawQuery = query.Encode()
+	}
+
+	return req, nil
 }
 
 func responseGoToGoby(t *Thread, goResp *http.Response) (Object, error) {
 	gobyResp := httpResponseClass.initializeInstance()
+	defer goResp.Body.Close()
 
 	//attr_accessor :body, :status, :status_code, :protocol, :transfer_encoding, :http_version, :request_http_version, :request
 	//attr_reader :headers
@@ -216,14 +822,18 @@ func responseGoToGoby(t *Thread, goResp *http.Response) (Object, error) {
 	gobyResp.InstanceVariableSet("@status", t.vm.InitObjectFromGoType(goResp.Status))
 	gobyResp.InstanceVariableSet("@protocol", t.vm.InitObjectFromGoType(goResp.Proto))
 	gobyResp.InstanceVariableSet("@transfer_encoding", t.vm.InitObjectFromGoType(goResp.TransferEncoding))
+	gobyResp.InstanceVariableSet("@headers", headersGoToGoby(t, goResp.Header))
 
-	underHeaders := map[string]Object{}
+	return gobyResp, nil
+}
 
-	for k, v := range goResp.Header {
-		underHeaders[k] = t.vm.InitObjectFromGoType(v)
-	}
+// headersGoToGoby converts headers into a Hash of Strings. Multiple values of the same header are joined with ", ".
+func headersGoToGoby(t *Thread, header http.Header) *HashObject {
+	headers := map[string]Object{}
 
-	gobyResp.InstanceVariableSet("@headers", t.vm.InitHashObject(underHeaders))
+	for k, v := range header {
+		headers[k] = t.vm.InitStringObject(strings.Join(v, ", "))
+	}
 
-	return gobyResp, nil
+	return t.vm.InitHashObject(headers)
 }
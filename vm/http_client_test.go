@@ -1,6 +1,17 @@
 package vm
 
-import "testing"
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
 
 func TestHTTPClientObject(t *testing.T) {
 
@@ -100,3 +111,280 @@ func TestHTTPClientObjectFail(t *testing.T) {
 		v.checkSP(t, i, 2)
 	}
 }
+
+func TestHTTPClientOptions(t *testing.T) {
+	var attempts int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slow":
+			time.Sleep(300 * time.Millisecond)
+			fmt.Fprint(w, "slow")
+		case "/redirect":
+			http.Redirect(w, r, "/target", http.StatusFound)
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		case "/target":
+			fmt.Fprint(w, "target")
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "goby"})
+		case "/me":
+			c, err := r.Cookie("session")
+			if err != nil {
+				fmt.Fprint(w, "anonymous")
+				return
+			}
+			fmt.Fprint(w, c.Value)
+		case "/flaky":
+			if atomic.AddInt32(&attempts, 1)%3 != 0 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprint(w, "recovered")
+		}
+	}))
+	defer ts.Close()
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "proxied %s", r.URL.Path)
+	}))
+	defer proxy.Close()
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		client = Net::HTTP::Client.new(follow_redirects: false)
+		client.get("%[1]s/redirect").status_code
+		`, 302},
+		{`
+		client = Net::HTTP::Client.new
+		client.get("%[1]s/redirect").body
+		`, "target"},
+		{`
+		client = Net::HTTP::Client.new
+		client.get("%[1]s/redirect", { follow_redirects: false }).status_code
+		`, 302},
+		{`
+		client = Net::HTTP::Client.new(cookie_jar: true)
+		client.get("%[1]s/login")
+		client.get("%[1]s/me").body
+		`, "goby"},
+		{`
+		client = Net::HTTP::Client.new
+		client.get("%[1]s/login")
+		client.get("%[1]s/me").body
+		`, "anonymous"},
+		{`
+		client = Net::HTTP::Client.new(retries: 2, retry_backoff: 0.01)
+		client.get("%[1]s/flaky").body
+		`, "recovered"},
+		{`
+		client = Net::HTTP::Client.new(retries: 2, retry_backoff: 0.01)
+		client.post("%[1]s/flaky", "text/plain", "").status_code
+		`, 503},
+		{`
+		client = Net::HTTP::Client.new(proxy: "%[2]s")
+		client.get("http://goby.test/users").body
+		`, "proxied /users"},
+		{`
+		client = Net::HTTP::Client.new(max_idle_conns: 10, max_idle_conns_per_host: 2, idle_conn_timeout: 5)
+		client.get("%[1]s/target").body
+		`, "target"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		input := "require \"net/http\"\n" + fmt.Sprintf(tt.input, ts.URL, proxy.URL)
+		evaluated := v.testEval(t, input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{`
+		client = Net::HTTP::Client.new(timeout: 0.05)
+		client.get("%[1]s/slow")
+		`, "Client.Timeout exceeded"},
+		{`
+		client = Net::HTTP::Client.new
+		client.get("%[1]s/slow", { timeout: 0.05 })
+		`, "Client.Timeout exceeded"},
+		{`
+		client = Net::HTTP::Client.new(max_redirects: 2)
+		client.get("%[1]s/loop")
+		`, "stopped after 2 redirects"},
+		{`
+		client = Net::HTTP::Client.new(max_redirects: 5)
+		client.get("%[1]s/loop", { max_redirects: 1 })
+		`, "stopped after 1 redirects"},
+		{`
+		client = Net::HTTP::Client.new(max_redirects: 1)
+		client.get("%[1]s/loop", { follow_redirects: true })
+		`, "stopped after 1 redirects"},
+		{`
+		Net::HTTP::Client.new(retries: -1).get("%[1]s/target")
+		`, "ArgumentError: Expect retries to be a non-negative Integer. got: -1"},
+		{`
+		Net::HTTP::Client.new(retries: 2 ** 64).get("%[1]s/target")
+		`, "ArgumentError: Expect retries to be at most 9223372036854775807. got: 18446744073709551616"},
+		{`
+		Net::HTTP::Client.new(timeout: -1).get("%[1]s/target")
+		`, "ArgumentError: Expect timeout to be a non-negative number of seconds. got: -1"},
+	}
+
+	for i, tt := range errorTests {
+		v := initTestVM()
+		input := "require \"net/http\"\n" + fmt.Sprintf(tt.input, ts.URL)
+		evaluated := v.testEval(t, input, getFilename())
+		err, ok := evaluated.(*Error)
+
+		if !ok || !strings.Contains(err.message, tt.expected) {
+			t.Fatalf("At test case %d: Expect error containing %q. got=%s", i, tt.expected, evaluated.toString())
+		}
+	}
+}
+
+func TestHTTPClientVerbsAndHelpers(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+		case "/upload":
+			file, header, err := r.FormFile("avatar")
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			defer file.Close()
+
+			content, _ := ioutil.ReadAll(file)
+			fmt.Fprintf(w, "%s %s %s", r.FormValue("user"), header.Filename, content)
+		default:
+			body, _ := ioutil.ReadAll(r.Body)
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Add("X-Multi", "a")
+			w.Header().Add("X-Multi", "b")
+			fmt.Fprintf(w, `{"method": %q, "query": %q, "auth": %q, "type": %q, "body": %q}`,
+				r.Method, r.URL.RawQuery, r.Header.Get("Authorization"), r.Header.Get("Content-Type"), body)
+		}
+	}))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "goby-http")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	avatar := filepath.Join(dir, "stan.png")
+	if err := ioutil.WriteFile(avatar, []byte("PNG"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Net::HTTP::Client.new.put("%[1]s/users", "text/plain", "Stan").json["method"]`, "PUT"},
+		{`Net::HTTP::Client.new.patch("%[1]s/users", "text/plain", "Stan").json["body"]`, "Stan"},
+		{`Net::HTTP::Client.new.delete("%[1]s/users").json["method"]`, "DELETE"},
+		{`Net::HTTP::Client.new.options("%[1]s/users").json["method"]`, "OPTIONS"},
+		{`Net::HTTP::Client.new.head("%[1]s/users").headers["Content-Type"]`, "application/json"},
+		{`Net::HTTP::Client.new.get("%[1]s/users").headers["X-Multi"]`, "a, b"},
+		{`Net::HTTP::Client.new.get("%[1]s/users", { query: { page: 2 } }).json["query"]`, "page=2"},
+		{`Net::HTTP::Client.new.get("%[1]s/users?a=1", { query: { tag: ["x", "y"] } }).json["query"]`, "a=1&tag=x&tag=y"},
+		{`Net::HTTP::Client.new.get("%[1]s/users", { headers: { Authorization: "Bearer t" } }).json["auth"]`, "Bearer t"},
+		{`Net::HTTP::Client.new.post_json("%[1]s/users", { name: "Stan" }).json["body"]`, `{"name":"Stan"}`},
+		{`Net::HTTP::Client.new.put_json("%[1]s/users", [1, 2]).json["type"]`, "application/json"},
+		{`Net::HTTP::Client.new.patch_json("%[1]s/users", { id: 1 }).json["method"]`, "PATCH"},
+		{`Net::HTTP::Client.new.post_multipart("%[1]s/upload", { user: "stan" }, { avatar: "%[2]s" }).body`, "stan stan.png PNG"},
+		{`Net::HTTP::Client.new.basic_auth("stan", "secret").get("%[1]s/users").json["auth"]`, "Basic c3RhbjpzZWNyZXQ="},
+		{`Net::HTTP::Client.new.bearer_auth("token").get("%[1]s/users").json["auth"]`, "Bearer token"},
+		{`Net::HTTP::Client.new.get("%[1]s/missing").status_code`, 404},
+		{`Net::HTTP::Client.new.get("%[1]s/missing").success?`, false},
+		{`Net::HTTP::Client.new.get("%[1]s/users").success?`, true},
+		{`Net::HTTP::Client.new(raise_on_error: true).get("%[1]s/missing", { raise_on_error: false }).status_code`, 404},
+		{`
+		client = Net::HTTP::Client.new
+		req = client.request
+		req.method = "post"
+		req.url = "%[1]s/users"
+		req.set_query({ page: 1 })
+		req.set_json({ name: "Stan" })
+		req.bearer_auth("token")
+		res = client.exec(req).json
+		res["method"] + " " + res["query"] + " " + res["body"] + " " + res["auth"]
+		`, `POST page=1 {"name":"Stan"} Bearer token`},
+		{`
+		client = Net::HTTP::Client.new
+		req = client.request
+		req.url = "%[1]s/upload"
+		req.method = "POST"
+		req.set_multipart({ user: "stan" }, { avatar: "%[2]s" })
+		client.exec(req).body
+		`, "stan stan.png PNG"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		input := "require \"net/http\"\n" + fmt.Sprintf(tt.input, ts.URL, avatar)
+		evaluated := v.testEval(t, input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+
+	testsFail := []errorTestCase{
+		{`Net::HTTP::Client.new(raise_on_error: true).get("%[1]s/missing")`, "HTTPError: Non-200 response, 404 Not Found (404)", 1},
+		{`Net::HTTP::Client.new.get("%[1]s/missing", { raise_on_error: true })`, "HTTPError: Non-200 response, 404 Not Found (404)", 1},
+		{`Net::HTTP::Client.new.get("%[1]s/users", { query: 1 })`, "ArgumentError: Expect query to be a Hash. got: Integer", 1},
+		{`Net::HTTP::Client.new.get("%[1]s/users", { verbose: true })`, "ArgumentError: Unknown request option: verbose", 1},
+		{`Net::HTTP::Client.new.post_json(1, "%[1]s")`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`Net::HTTP::Client.new.post_multipart("%[1]s/upload", {}, { avatar: "/no/such/file" })`, "ArgumentError: open /no/such/file: no such file or directory", 1},
+		{`Net::HTTP::Client.new.bearer_auth(1).get("%[1]s")`, "TypeError: Expect argument to be String. got: Integer", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		input := "require \"net/http\"\n" + fmt.Sprintf(tt.input, ts.URL)
+		evaluated := v.testEval(t, input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestHTTPClientOverridesDontLeak(t *testing.T) {
+	c, err := newHTTPClient(map[string]Object{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	c.query.Add("tag", "a")
+	c.headers.Add("X-Tag", "a")
+
+	v := initTestVM()
+	overridden, err := c.withOverrides(map[string]Object{
+		"query":   v.InitHashObject(map[string]Object{"tag": v.InitStringObject("b")}),
+		"headers": v.InitHashObject(map[string]Object{"X-Tag": v.InitStringObject("b")}),
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if got := overridden.query["tag"]; len(got) != 2 {
+		t.Fatalf("Expect overridden query to have 2 tags. got: %v", got)
+	}
+
+	if got := c.query["tag"]; len(got) != 1 || got[0] != "a" {
+		t.Fatalf("Expect original query to be unchanged. got: %v", got)
+	}
+
+	if got := c.headers.Get("X-Tag"); got != "a" {
+		t.Fatalf("Expect original headers to be unchanged. got: %s", got)
+	}
+}
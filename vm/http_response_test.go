@@ -26,6 +26,25 @@ func TestHTTPResponseObject(t *testing.T) {
 	v.checkSP(t, 0, 1)
 }
 
+func TestHTTPResponseSuccessMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Net::HTTP::Response.new.success?`, false},
+		{`res = Net::HTTP::Response.new; res.status_code = 204; res.success?`, true},
+		{`res = Net::HTTP::Response.new; res.status_code = 301; res.success?`, false},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, "require \"net/http\"\n"+tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestNormalGetResponse(t *testing.T) {
 	expected := "Hello, client"
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
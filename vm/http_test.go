@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 )
 
 func TestHTTPRequest(t *testing.T) {
@@ -174,3 +175,59 @@ func startTestServer(c chan bool) {
 
 	http.ListenAndServe(":3000", m)
 }
+
+func TestHTTPClassVerbs(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("Allow", "GET, PUT")
+		w.Header().Add("X-Multi", "a")
+		w.Header().Add("X-Multi", "b")
+		fmt.Fprintf(w, "%s %s", r.Method, body)
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Net::HTTP.put("%s/users", "text/plain", "Stan")`, "PUT Stan"},
+		{`Net::HTTP.patch("%s/users", "text/plain", "Stan")`, "PATCH Stan"},
+		{`Net::HTTP.delete("%s/users")`, "DELETE "},
+		{`Net::HTTP.options("%s/users")["Allow"]`, "GET, PUT"},
+		{`Net::HTTP.options("%s/users")["X-Multi"]`, "a, b"},
+		{`Net::HTTP.head("%s/users")["X-Multi"]`, "a b"},
+		{`Net::HTTP.get("%s/missing", { raise_on_error: false })`, "404 page not found\n"},
+		{`Net::HTTP.delete("%s/missing", { raise_on_error: false })`, "404 page not found\n"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		input := "require \"net/http\"\n" + fmt.Sprintf(tt.input, ts.URL)
+		evaluated := v.testEval(t, input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+
+	testsFail := []errorTestCase{
+		{`Net::HTTP.put("%s/missing", "text/plain", "Stan")`, "HTTPError: Non-200 response, 404 Not Found (404)", 1},
+		{`Net::HTTP.delete("%s/missing")`, "HTTPError: Non-200 response, 404 Not Found (404)", 1},
+		{`Net::HTTP.options("%s/missing")`, "HTTPError: Non-200 response, 404 Not Found (404)", 1},
+		{`Net::HTTP.patch("%s/users", "text/plain")`, "ArgumentError: Expect 3 arguments. got: 2", 1},
+		{`Net::HTTP.get("%s/users", { retry: 1 })`, "ArgumentError: Unknown request option: retry", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		input := "require \"net/http\"\n" + fmt.Sprintf(tt.input, ts.URL)
+		evaluated := v.testEval(t, input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
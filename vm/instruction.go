@@ -2,8 +2,10 @@ package vm
 
 import (
 	"fmt"
+	"math/big"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/goby-lang/goby/compiler/bytecode"
 	"github.com/goby-lang/goby/vm/classes"
@@ -36,6 +38,22 @@ type instructionSet struct {
 	paramTypes   *bytecode.ArgSet
 }
 
+// destructureBlockArgs spreads an Array, which is the only argument given to a block with several
+// parameters, into the parameters. So `|key, value|` can receive pairs like `[key, value]`.
+func (is *instructionSet) destructureBlockArgs(args []Object) []Object {
+	if len(args) != 1 || is.paramTypes == nil || len(is.paramTypes.Types()) < 2 {
+		return args
+	}
+
+	arr, ok := args[0].(*ArrayObject)
+
+	if !ok {
+		return args
+	}
+
+	return arr.Elements
+}
+
 func (is *instructionSet) define(line int, a *action, params ...interface{}) *instruction {
 	i := &instruction{action: a, Params: params, Line: line}
 	is.instructions = append(is.instructions, i)
@@ -66,7 +84,16 @@ var builtinActions = map[operationType]*action{
 	bytecode.PutObject: {
 		name: bytecode.PutObject,
 		operation: func(t *Thread, sourceLine int, cf *normalCallFrame, args ...interface{}) {
-			object := t.vm.InitObjectFromGoType(args[0])
+			var object Object
+
+			// Integer literals too large for an int are left as their digits
+			if digits, ok := args[0].(string); ok {
+				n, _ := new(big.Int).SetString(digits, 10)
+				object = t.vm.initBigIntegerObject(n)
+			} else {
+				object = t.vm.InitObjectFromGoType(args[0])
+			}
+
 			t.Stack.Push(&Pointer{Target: object})
 		},
 	},
@@ -80,13 +107,14 @@ var builtinActions = map[operationType]*action{
 				t.pushErrorObject(errors.NameError, sourceLine, "uninitialized constant %s", constName)
 			}
 
-			c.isNamespace = args[1].(string) == "true"
+			// The constant's pointer is shared, so flagging it would also flag the same constant already on the stack
+			p := &Pointer{Target: c.Target, isNamespace: args[1].(string) == "true"}
 
 			if t.Stack.top() != nil && t.Stack.top().isNamespace {
 				t.Stack.Pop()
 			}
 
-			t.Stack.Push(c)
+			t.Stack.Push(p)
 		},
 	},
 	bytecode.GetLocal: {
@@ -197,10 +225,17 @@ var builtinActions = map[operationType]*action{
 	bytecode.NewRange: {
 		name: bytecode.NewRange,
 		operation: func(t *Thread, sourceLine int, cf *normalCallFrame, args ...interface{}) {
-			rangeEnd := t.Stack.Pop().Target.(*IntegerObject).value
-			rangeStart := t.Stack.Pop().Target.(*IntegerObject).value
+			rangeEnd := t.Stack.Pop().Target.(*IntegerObject)
+			rangeStart := t.Stack.Pop().Target.(*IntegerObject)
 
-			t.Stack.Push(&Pointer{Target: t.vm.initRangeObject(rangeStart, rangeEnd)})
+			// A Range's ends are ints
+			for _, n := range []*IntegerObject{rangeStart, rangeEnd} {
+				if n.bigValue != nil {
+					t.pushErrorObject(errors.RangeError, sourceLine, "%s is out of range", n.toString())
+				}
+			}
+
+			t.Stack.Push(&Pointer{Target: t.vm.initRangeObject(rangeStart.value, rangeEnd.value)})
 		},
 	},
 	bytecode.NewArray: {
@@ -256,7 +291,11 @@ var builtinActions = map[operationType]*action{
 				return
 			}
 
-			arr.splat = true
+			// Mark a copy, so the array doesn't stay splatted when it's used again
+			splatted := t.vm.InitArrayObject(arr.Elements)
+			splatted.splat = true
+			t.Stack.Pop()
+			t.Stack.Push(&Pointer{Target: splatted})
 		},
 	},
 	bytecode.NewHash: {
@@ -340,10 +379,20 @@ var builtinActions = map[operationType]*action{
 			if cf.IsBlock() {
 				/*
 				  1. Remove block execution frame
-				  2. Remove method call frame
+				  2. Remove method call frame, and the frames it yields the block through,
+				     like a Goby method's blocks that yield to this block
 				  3. Remove block source frame
 				*/
-				for i := 0; i < 3; i++ {
+				count := 3
+
+				for i := t.callFrameStack.pointer - 1; i >= 0; i-- {
+					if t.callFrameStack.callFrames[i] == callFrame(cf.blockFrame) {
+						count = t.callFrameStack.pointer - i
+						break
+					}
+				}
+
+				for i := 0; i < count; i++ {
 					frame := t.callFrameStack.pop()
 					frame.stopExecution()
 					frame.setAsRemoved()
@@ -364,6 +413,19 @@ var builtinActions = map[operationType]*action{
 			t.Stack.Push(&Pointer{Target: object})
 		},
 	},
+	bytecode.PutRegexp: {
+		name: bytecode.PutRegexp,
+		operation: func(t *Thread, sourceLine int, cf *normalCallFrame, args ...interface{}) {
+			pattern := args[0].(string)
+			object := t.vm.initRegexpObject(pattern, regexpOptions(args[1].(string)))
+
+			if object == nil {
+				t.pushErrorObject(errors.ArgumentError, sourceLine, "Invalid regexp: %v", pattern)
+			}
+
+			t.Stack.Push(&Pointer{Target: object})
+		},
+	},
 	bytecode.PutFloat: {
 		name: bytecode.PutFloat,
 		operation: func(t *Thread, sourceLine int, cf *normalCallFrame, args ...interface{}) {
@@ -521,6 +583,7 @@ var builtinActions = map[operationType]*action{
 
 					t.Stack.Set(argPr, &Pointer{Target: t.vm.InitStringObject(methodName)})
 					argCount++
+					argSet = argSet.Prepend("", bytecode.NormalArg)
 
 					method = mm
 				}
@@ -551,9 +614,19 @@ var builtinActions = map[operationType]*action{
 		name: bytecode.InvokeBlock,
 		operation: func(t *Thread, sourceLine int, cf *normalCallFrame, args ...interface{}) {
 			argCount := args[0].(int)
+
+			// Deal with splat arguments
+			if arr, ok := t.Stack.top().Target.(*ArrayObject); ok && arr.splat {
+				t.Stack.Pop()
+				argCount = argCount - 1 + len(arr.Elements)
+
+				for _, elem := range arr.Elements {
+					t.Stack.Push(&Pointer{Target: elem})
+				}
+			}
+
 			argPr := t.Stack.pointer - argCount
 			receiverPr := argPr - 1
-			receiver := t.Stack.data[receiverPr].Target
 
 			if cf.blockFrame == nil {
 				t.pushErrorObject(errors.InternalError, sourceLine, "Can't yield without a block")
@@ -587,14 +660,28 @@ var builtinActions = map[operationType]*action{
 				blockFrame = cf.blockFrame.ep.blockFrame
 			}
 
+			// The block was left with `break`
+			if blockFrame.IsRemoved() {
+				t.Stack.Set(receiverPr, &Pointer{Target: NULL})
+				t.Stack.pointer = receiverPr + 1
+				return
+			}
+
 			c := newNormalCallFrame(blockFrame.instructionSet, blockFrame.instructionSet.filename, sourceLine)
 			c.blockFrame = blockFrame
 			c.ep = blockFrame.ep
-			c.self = receiver
+			// The block runs with the self of where it's defined, not the self of the yielding method
+			c.self = blockFrame.self
 			c.isBlock = true
 
+			blockArgs := make([]Object, argCount)
+
 			for i := 0; i < argCount; i++ {
-				c.locals[i] = t.Stack.data[argPr+i]
+				blockArgs[i] = t.Stack.data[argPr+i].Target
+			}
+
+			for i, arg := range blockFrame.instructionSet.destructureBlockArgs(blockArgs) {
+				c.insertLCL(i, 0, arg)
 			}
 
 			t.callFrameStack.push(c)
@@ -663,6 +750,12 @@ func (v *VM) InitObjectFromGoType(value interface{}) Object {
 		}
 
 		return v.InitArrayObject(objects)
+	case map[string]interface{}:
+		return v.convertJSONToHashObj(val)
+	case *Decimal:
+		return v.initDecimalObject(val)
+	case time.Time:
+		return v.initTimeObject(val)
 	default:
 		return v.initGoObject(value)
 	}
@@ -106,6 +106,8 @@ func (it *instructionTranslator) transferInstruction(is *instructionSet, i *byte
 		params = append(params, it.parseBooleanParam(i.Params[0]))
 	case bytecode.PutString:
 		params = append(params, i.Params[0])
+	case bytecode.PutRegexp:
+		params = append(params, i.Params[0], i.Params[1])
 	case bytecode.BranchUnless, bytecode.BranchIf, bytecode.Jump:
 		line, err := i.AnchorLine()
 
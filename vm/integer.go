@@ -2,17 +2,23 @@ package vm
 
 import (
 	"math"
+	"math/big"
 	"strconv"
+	"strings"
 
 	"github.com/goby-lang/goby/vm/classes"
 	"github.com/goby-lang/goby/vm/errors"
 )
 
 // IntegerObject represents number objects which can bring into mathematical calculations.
+// Integers have arbitrary precision: a result that doesn't fit in a machine integer is promoted to
+// a big integer, and a big result that fits again is demoted back.
 //
 // ```ruby
-// 1 + 1 # => 2
-// 2 * 2 # => 4
+// 1 + 1     # => 2
+// 2 * 2     # => 4
+// 2 ** 64   # => 18446744073709551616
+// 2 ** 64 - 2 ** 64 + 1 # => 1
 // ```
 //
 // - `Integer.new` is not supported.
@@ -20,8 +26,14 @@ type IntegerObject struct {
 	*baseObj
 	value int
 	flag  int
+	// bigValue holds the value of an Integer that doesn't fit in an int, and is nil otherwise
+	bigValue *big.Int
 }
 
+// maxIntegerBits is the largest number of bits of an Integer made by `<<` or `**`, which could use up
+// the memory with a single call otherwise.
+const maxIntegerBits = 1 << 24
+
 /*
 This is enum defined for integer's flag
 */
@@ -54,6 +66,37 @@ func builtinIntegerClassMethods() []*BuiltinMethodObject {
 				}
 			},
 		},
+		{
+			// Returns the integer square root of the given non-negative Integer,
+			// which is the largest Integer whose square is not larger than it.
+			//
+			// ```Ruby
+			// Integer.sqrt(24)       # => 4
+			// Integer.sqrt(25)       # => 5
+			// Integer.sqrt(10 ** 40) # => 100000000000000000000
+			// ```
+			// @param n [Integer]
+			// @return [Integer]
+			Name: "sqrt",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					n, ok := args[0].(*IntegerObject)
+					if !ok {
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+					}
+
+					if n.bigInt().Sign() < 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect a non-negative Integer. got: %s", n.toString())
+					}
+
+					return t.vm.initBigIntegerObject(new(big.Int).Sqrt(n.bigInt()))
+				}
+			},
+		},
 	}
 }
 
@@ -70,35 +113,36 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			Name: "+",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-					intOperation := func(leftValue int, rightValue int) int {
-						return leftValue + rightValue
-					}
 					floatOperation := func(leftValue float64, rightValue float64) float64 {
 						return leftValue + rightValue
 					}
+					decimalOperation := func(leftValue *Decimal, rightValue *Decimal) *Decimal {
+						return new(Decimal).Add(leftValue, rightValue)
+					}
 
-					return receiver.(*IntegerObject).arithmeticOperation(t, args[0], intOperation, floatOperation, sourceLine, false)
+					return receiver.(*IntegerObject).arithmeticOperation(t, args[0], addInts, (*big.Int).Add, floatOperation, decimalOperation, sourceLine, false)
 				}
 			},
 		},
 		{
-			// Divides left hand operand by right hand operand and returns remainder.
+			// Divides left hand operand by right hand operand and returns the modulus, which has the sign of
+			// the right hand operand.
 			//
 			// ```Ruby
-			// 5 % 2 # => 1
+			// 5 % 2    # => 1
+			// -5 % 2   # => 1
+			// 5 % -2   # => -1
+			// 5 % -2.0 # => -1.0
 			// ```
 			// @return [Numeric]
 			Name: "%",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-					intOperation := func(leftValue int, rightValue int) int {
-						return leftValue % rightValue
-					}
-					floatOperation := func(leftValue float64, rightValue float64) float64 {
-						return math.Mod(leftValue, rightValue)
+					intOperation := func(leftValue int, rightValue int) (int, bool) {
+						return floorMod(leftValue, rightValue), true
 					}
 
-					return receiver.(*IntegerObject).arithmeticOperation(t, args[0], intOperation, floatOperation, sourceLine, true)
+					return receiver.(*IntegerObject).arithmeticOperation(t, args[0], intOperation, bigFloorMod, floatFloorMod, nil, sourceLine, true)
 				}
 			},
 		},
@@ -112,14 +156,14 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			Name: "-",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-					intOperation := func(leftValue int, rightValue int) int {
-						return leftValue - rightValue
-					}
 					floatOperation := func(leftValue float64, rightValue float64) float64 {
 						return leftValue - rightValue
 					}
+					decimalOperation := func(leftValue *Decimal, rightValue *Decimal) *Decimal {
+						return new(Decimal).Sub(leftValue, rightValue)
+					}
 
-					return receiver.(*IntegerObject).arithmeticOperation(t, args[0], intOperation, floatOperation, sourceLine, false)
+					return receiver.(*IntegerObject).arithmeticOperation(t, args[0], subtractInts, (*big.Int).Sub, floatOperation, decimalOperation, sourceLine, false)
 				}
 			},
 		},
@@ -133,14 +177,14 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			Name: "*",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-					intOperation := func(leftValue int, rightValue int) int {
-						return leftValue * rightValue
-					}
 					floatOperation := func(leftValue float64, rightValue float64) float64 {
 						return leftValue * rightValue
 					}
+					decimalOperation := func(leftValue *Decimal, rightValue *Decimal) *Decimal {
+						return new(Decimal).Mul(leftValue, rightValue)
+					}
 
-					return receiver.(*IntegerObject).arithmeticOperation(t, args[0], intOperation, floatOperation, sourceLine, false)
+					return receiver.(*IntegerObject).arithmeticOperation(t, args[0], multiplyInts, (*big.Int).Mul, floatOperation, decimalOperation, sourceLine, false)
 				}
 			},
 		},
@@ -154,36 +198,203 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			Name: "**",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-					intOperation := func(leftValue int, rightValue int) int {
-						return int(math.Pow(float64(leftValue), float64(rightValue)))
+					i := receiver.(*IntegerObject)
+
+					if exponent, ok := args[0].(*IntegerObject); ok {
+						// Only 0, 1 and -1 stay small, and the result of others has at least
+						// (bits of the base - 1) * exponent bits
+						bits := new(big.Int).Abs(i.bigInt()).BitLen() - 1
+
+						if bits > 0 && exponent.bigInt().Sign() > 0 && (exponent.bigValue != nil || exponent.value > maxIntegerBits/bits) {
+							return t.vm.InitErrorObject(errors.RangeError, sourceLine, "Exponent is too large: %s", exponent.toString())
+						}
+
+						// A negative exponent gives a fraction, which is truncated
+						if exponent.bigInt().Sign() < 0 {
+							return t.vm.InitIntegerObject(int(math.Pow(i.floatValue(), exponent.floatValue())))
+						}
+					}
+
+					bigOperation := func(z *big.Int, leftValue *big.Int, rightValue *big.Int) *big.Int {
+						return z.Exp(leftValue, rightValue, nil)
 					}
 					floatOperation := func(leftValue float64, rightValue float64) float64 {
 						return math.Pow(leftValue, rightValue)
 					}
+					decimalOperation := func(leftValue *Decimal, rightValue *Decimal) *Decimal {
+						return decimalPower(leftValue, rightValue)
+					}
+
+					return i.arithmeticOperation(t, args[0], powerInts, bigOperation, floatOperation, decimalOperation, sourceLine, false)
+				}
+			},
+		},
+		{
+			// Returns self shifted left by the given number of bits, or right if it's negative.
+			//
+			// ```Ruby
+			// 1.send("<<", 4)  # => 16
+			// 1.send("<<", 64) # => 18446744073709551616
+			// 16.send("<<", -2) # => 4
+			// ```
+			// @return [Integer]
+			Name: "<<",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					n, ok := args[0].(*IntegerObject)
+					if !ok {
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+					}
+
+					if n.bigValue != nil {
+						return t.vm.InitErrorObject(errors.RangeError, sourceLine, "Shift width is too large: %s", n.toString())
+					}
+
+					return t.shiftInteger(receiver.(*IntegerObject), n.value, sourceLine)
+				}
+			},
+		},
+		{
+			// Returns self shifted right by the given number of bits, or left if it's negative.
+			// The result is rounded towards negative infinity.
+			//
+			// ```Ruby
+			// 256 >> 4 # => 16
+			// -5 >> 1  # => -3
+			// 1 >> -64 # => 18446744073709551616
+			// ```
+			// @return [Integer]
+			Name: ">>",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					n, ok := args[0].(*IntegerObject)
+					if !ok {
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+					}
+
+					if n.bigValue != nil || n.value == math.MinInt {
+						return t.vm.InitErrorObject(errors.RangeError, sourceLine, "Shift width is too large: %s", n.toString())
+					}
+
+					return t.shiftInteger(receiver.(*IntegerObject), -n.value, sourceLine)
+				}
+			},
+		},
+		{
+			// Returns the bitwise AND of self and another Integer.
+			// Negative Integers behave as two's complement numbers with infinitely many leading ones.
+			//
+			// ```Ruby
+			// 6 & 3   # => 2
+			// -1 & 15 # => 15
+			// ```
+			// @return [Integer]
+			Name: "&",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					intOperation := func(leftValue int, rightValue int) (int, bool) {
+						return leftValue & rightValue, true
+					}
 
-					return receiver.(*IntegerObject).arithmeticOperation(t, args[0], intOperation, floatOperation, sourceLine, false)
+					return receiver.(*IntegerObject).bitwiseOperation(t, args, intOperation, (*big.Int).And, sourceLine)
 				}
 			},
 		},
 		{
-			// Returns self divided by another Numeric.
+			// Returns the bitwise OR of self and another Integer.
 			//
 			// ```Ruby
-			// 6 / 3 # => 2
+			// 6 | 3      # => 7
+			// 1 | 2 ** 64 # => 18446744073709551617
+			// ```
+			// @return [Integer]
+			Name: "|",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					intOperation := func(leftValue int, rightValue int) (int, bool) {
+						return leftValue | rightValue, true
+					}
+
+					return receiver.(*IntegerObject).bitwiseOperation(t, args, intOperation, (*big.Int).Or, sourceLine)
+				}
+			},
+		},
+		{
+			// Returns the bitwise exclusive OR of self and another Integer.
+			//
+			// ```Ruby
+			// 6 ^ 3  # => 5
+			// 5 ^ -1 # => -6
+			// ```
+			// @return [Integer]
+			Name: "^",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					intOperation := func(leftValue int, rightValue int) (int, bool) {
+						return leftValue ^ rightValue, true
+					}
+
+					return receiver.(*IntegerObject).bitwiseOperation(t, args, intOperation, (*big.Int).Xor, sourceLine)
+				}
+			},
+		},
+		{
+			// Returns the bitwise complement of self, which is `-self - 1`.
+			//
+			// ```Ruby
+			// ~5  # => -6
+			// ~-1 # => 0
+			// ```
+			// @return [Integer]
+			Name: "~",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					i := receiver.(*IntegerObject)
+					if i.bigValue != nil {
+						return t.vm.initBigIntegerObject(new(big.Int).Not(i.bigValue))
+					}
+
+					return t.vm.InitIntegerObject(^i.value)
+				}
+			},
+		},
+		{
+			// Returns self divided by another Numeric. The quotient of Integers is rounded towards negative infinity.
+			//
+			// ```Ruby
+			// 6 / 3   # => 2
+			// 7 / 2   # => 3
+			// -7 / 2  # => -4
+			// 7 / 2.0 # => 3.5
 			// ```
 			// @return [Numeric]
 			Name: "/",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-
-					intOperation := func(leftValue int, rightValue int) int {
-						return leftValue / rightValue
+					intOperation := func(leftValue int, rightValue int) (int, bool) {
+						// The only quotient that overflows is math.MinInt / -1
+						return floorDiv(leftValue, rightValue), leftValue != math.MinInt || rightValue != -1
 					}
 					floatOperation := func(leftValue float64, rightValue float64) float64 {
 						return leftValue / rightValue
 					}
+					decimalOperation := func(leftValue *Decimal, rightValue *Decimal) *Decimal {
+						return new(Decimal).Quo(leftValue, rightValue)
+					}
 
-					return receiver.(*IntegerObject).arithmeticOperation(t, args[0], intOperation, floatOperation, sourceLine, true)
+					return receiver.(*IntegerObject).arithmeticOperation(t, args[0], intOperation, bigFloorDiv, floatOperation, decimalOperation, sourceLine, true)
 				}
 			},
 		},
@@ -277,11 +488,13 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 		},
 		{
 			// Returns 1 if self is larger than the incoming Numeric, -1 if smaller. Otherwise 0.
+			// Floats are compared exactly, and comparing with NaN returns nil.
 			//
 			// ```Ruby
-			// 1 <=> 3 # => -1
-			// 1 <=> 1 # => 0
-			// 3 <=> 1 # => 1
+			// 1 <=> 3          # => -1
+			// 1 <=> 1          # => 0
+			// 3 <=> 1          # => 1
+			// 1 <=> Float::NAN # => nil
 			// ```
 			// @return [Integer]
 			Name: "<=>",
@@ -291,29 +504,16 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 
 					switch rightObject.(type) {
 					case *IntegerObject:
-						leftValue := receiver.(*IntegerObject).value
-						rightValue := rightObject.(*IntegerObject).value
-
-						if leftValue < rightValue {
-							return t.vm.InitIntegerObject(-1)
-						}
-						if leftValue > rightValue {
-							return t.vm.InitIntegerObject(1)
-						}
-
-						return t.vm.InitIntegerObject(0)
+						return t.vm.InitIntegerObject(compareIntegers(receiver.(*IntegerObject), rightObject.(*IntegerObject)))
+					case *DecimalObject:
+						return t.vm.InitIntegerObject(intToDecimal(receiver).Cmp(rightObject.(*DecimalObject).value))
 					case *FloatObject:
-						leftValue := float64(receiver.(*IntegerObject).value)
-						rightValue := rightObject.(*FloatObject).value
-
-						if leftValue < rightValue {
-							return t.vm.InitIntegerObject(-1)
-						}
-						if leftValue > rightValue {
-							return t.vm.InitIntegerObject(1)
+						result, ok := compareFloatToInteger(rightObject.(*FloatObject).value, receiver.(*IntegerObject))
+						if !ok {
+							return NULL
 						}
 
-						return t.vm.InitIntegerObject(0)
+						return t.vm.InitIntegerObject(-result)
 					default:
 						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Numeric", rightObject.Class().Name)
 					}
@@ -326,9 +526,10 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			// result is always false.
 			//
 			// ```Ruby
-			// 1 == 3   # => false
-			// 1 == 1   # => true
-			// 1 == '1' # => false
+			// 1 == 3      # => false
+			// 1 == 1      # => true
+			// 1 == 1.to_d # => true
+			// 1 == '1'    # => false
 			// ```
 			// @return [Boolean]
 			Name: "==",
@@ -346,9 +547,10 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			// result is always true.
 			//
 			// ```Ruby
-			// 1 != 3   # => true
-			// 1 != 1   # => false
-			// 1 != '1' # => true
+			// 1 != 3      # => true
+			// 1 != 1      # => false
+			// 1 != 1.to_d # => false
+			// 1 != '1'    # => true
 			// ```
 			// @return [Boolean]
 			Name: "!=",
@@ -360,6 +562,129 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 				}
 			},
 		},
+		{
+			// Returns the absolute value of self.
+			//
+			// ```Ruby
+			// -12.abs # => 12
+			// 12.abs  # => 12
+			// ```
+			// @return [Integer]
+			Name: "abs",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					i := receiver.(*IntegerObject)
+					if i.bigValue == nil && i.value >= 0 {
+						return i
+					}
+
+					return t.vm.initBigIntegerObject(new(big.Int).Abs(i.bigInt()))
+				}
+			},
+		},
+		{
+			// Returns an array of the digits of self in the given base, which defaults to 10,
+			// from the least significant digit. Self must not be negative.
+			//
+			// ```Ruby
+			// 1234.digits    # => [4, 3, 2, 1]
+			// 255.digits(16) # => [15, 15]
+			// 0.digits       # => [0]
+			// ```
+			// @param base [Integer]
+			// @return [Array]
+			Name: "digits",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) > 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRangeFormat, 0, 1, len(args))
+					}
+
+					base := big.NewInt(10)
+					if len(args) == 1 {
+						b, ok := args[0].(*IntegerObject)
+						if !ok {
+							return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+						}
+
+						if b.bigInt().Cmp(big.NewInt(2)) < 0 {
+							return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Invalid radix %s", b.toString())
+						}
+						base = b.bigInt()
+					}
+
+					i := receiver.(*IntegerObject)
+					if i.bigInt().Sign() < 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect a non-negative Integer. got: %s", i.toString())
+					}
+
+					n := new(big.Int).Set(i.bigInt())
+					digits := []Object{}
+					digit := new(big.Int)
+
+					for {
+						n.QuoRem(n, base, digit)
+						digits = append(digits, t.vm.initBigIntegerObject(new(big.Int).Set(digit)))
+
+						if n.Sign() == 0 {
+							return t.vm.InitArrayObject(digits)
+						}
+					}
+				}
+			},
+		},
+		{
+			// Returns an array of the quotient and the modulus of self divided by another Numeric, which are
+			// the results of `/` and `%`. The quotient is rounded towards negative infinity, so the modulus
+			// has the sign of the divisor.
+			//
+			// ```Ruby
+			// 13.divmod(4)   # => [3, 1]
+			// -13.divmod(4)  # => [-4, 3]
+			// 13.divmod(-4)  # => [-4, -3]
+			// 13.divmod(4.0) # => [3, 1.0]
+			// ```
+			// @param divisor [Numeric]
+			// @return [Array]
+			Name: "divmod",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					i := receiver.(*IntegerObject)
+
+					switch divisor := args[0].(type) {
+					case *IntegerObject:
+						if divisor.isZero() {
+							return t.vm.InitErrorObject(errors.ZeroDivisionError, sourceLine, errors.DividedByZero)
+						}
+
+						quotient := bigFloorDiv(new(big.Int), i.bigInt(), divisor.bigInt())
+						modulus := bigFloorMod(new(big.Int), i.bigInt(), divisor.bigInt())
+
+						return t.vm.InitArrayObject([]Object{t.vm.initBigIntegerObject(quotient), t.vm.initBigIntegerObject(modulus)})
+					case *FloatObject:
+						if divisor.value == 0 {
+							return t.vm.InitErrorObject(errors.ZeroDivisionError, sourceLine, errors.DividedByZero)
+						}
+
+						quotient := math.Floor(i.floatValue() / divisor.value)
+						modulus := floatFloorMod(i.floatValue(), divisor.value)
+						q, _ := big.NewFloat(quotient).Int(nil)
+
+						return t.vm.InitArrayObject([]Object{t.vm.initBigIntegerObject(q), t.vm.initFloatObject(modulus)})
+					default:
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Integer or Float", args[0].Class().Name)
+					}
+				}
+			},
+		},
 		{
 			// Returns if self is even.
 			//
@@ -373,7 +698,7 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 
 					i := receiver.(*IntegerObject)
-					even := i.value%2 == 0
+					even := i.bigInt().Bit(0) == 0
 
 					if even {
 						return TRUE
@@ -412,7 +737,7 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 					r := receiver.(*IntegerObject)
-					newFloat := t.vm.initFloatObject(float64(r.value))
+					newFloat := t.vm.initFloatObject(r.floatValue())
 					return newFloat
 				}
 			},
@@ -432,19 +757,116 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			},
 		},
 		{
-			// Returns a `String` representation of self.
+			// Returns a `String` representation of self in the given base, which defaults to 10.
+			// The base must be between 2 and 36.
 			//
 			// ```Ruby
-			// 100.to_s # => "100"
+			// 100.to_s      # => "100"
+			// 255.to_s(2)   # => "11111111"
+			// 255.to_s(16)  # => "ff"
+			// -255.to_s(36) # => "-73"
 			// ```
+			// @param base [Integer]
 			// @return [String]
 			Name: "to_s",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) > 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRangeFormat, 0, 1, len(args))
+					}
 
 					int := receiver.(*IntegerObject)
+					if len(args) == 0 {
+						return t.vm.InitStringObject(int.toString())
+					}
+
+					base, err := radix(t, args[0], sourceLine)
+					if err != nil {
+						return err
+					}
+
+					return t.vm.InitStringObject(int.bigInt().Text(base))
+				}
+			},
+		},
+		{
+			// Returns the greatest common divisor of self and another Integer, which is never negative.
+			//
+			// ```Ruby
+			// 12.gcd(18)  # => 6
+			// -12.gcd(18) # => 6
+			// 5.gcd(0)    # => 5
+			// ```
+			// @param other [Integer]
+			// @return [Integer]
+			Name: "gcd",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
 
-					return t.vm.InitStringObject(strconv.Itoa(int.value))
+					other, ok := args[0].(*IntegerObject)
+					if !ok {
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+					}
+
+					return t.vm.initBigIntegerObject(gcd(receiver.(*IntegerObject).bigInt(), other.bigInt()))
+				}
+			},
+		},
+		{
+			// Returns a hash of self, which is the same for equal Integers, and Decimals of the same value.
+			// Objects can define `hash` from the hashes of their Integer attributes to be the same element of a Set.
+			//
+			// ```Ruby
+			// 2.hash == (1 + 1).hash # => true
+			// 2.hash == 2.to_d.hash  # => true
+			// 2.hash == 2.0.hash     # => false
+			// ```
+			// @return [Integer]
+			Name: "hash",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return t.hashValue(receiver, sourceLine)
+				}
+			},
+		},
+		{
+			// Returns the least common multiple of self and another Integer, which is never negative.
+			//
+			// ```Ruby
+			// 4.lcm(6)  # => 12
+			// -4.lcm(6) # => 12
+			// 4.lcm(0)  # => 0
+			// ```
+			// @param other [Integer]
+			// @return [Integer]
+			Name: "lcm",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					other, ok := args[0].(*IntegerObject)
+					if !ok {
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+					}
+
+					a, b := receiver.(*IntegerObject).bigInt(), other.bigInt()
+					if a.Sign() == 0 || b.Sign() == 0 {
+						return t.vm.InitIntegerObject(0)
+					}
+
+					lcm := new(big.Int).Mul(a, b)
+					lcm.Abs(lcm).Quo(lcm, gcd(a, b))
+
+					return t.vm.initBigIntegerObject(lcm)
 				}
 			},
 		},
@@ -459,7 +881,7 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 					i := receiver.(*IntegerObject)
-					return t.vm.InitIntegerObject(i.value + 1)
+					return t.vm.integerOperation(i, t.vm.InitIntegerObject(1), addInts, (*big.Int).Add)
 				}
 			},
 		},
@@ -476,7 +898,7 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 
 					i := receiver.(*IntegerObject)
-					odd := i.value%2 != 0
+					odd := i.bigInt().Bit(0) == 1
 					if odd {
 						return TRUE
 					}
@@ -496,7 +918,62 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 					i := receiver.(*IntegerObject)
-					return t.vm.InitIntegerObject(i.value - 1)
+					return t.vm.integerOperation(i, t.vm.InitIntegerObject(1), subtractInts, (*big.Int).Sub)
+				}
+			},
+		},
+		{
+			// Returns self rounded to the given number of decimal digits, which defaults to 0.
+			// A negative number of digits rounds to a power of ten, with halves rounded away from zero.
+			//
+			// ```Ruby
+			// 1234.round       # => 1234
+			// 1234.round(2)    # => 1234
+			// 1250.round(-2)   # => 1300
+			// -1250.round(-2)  # => -1300
+			// 1249.round(-2)   # => 1200
+			// ```
+			// @param digits [Integer]
+			// @return [Integer]
+			Name: "round",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) > 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRangeFormat, 0, 1, len(args))
+					}
+
+					i := receiver.(*IntegerObject)
+					if len(args) == 0 {
+						return i
+					}
+
+					digits, ok := args[0].(*IntegerObject)
+					if !ok {
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+					}
+
+					if digits.bigInt().Sign() >= 0 {
+						return i
+					}
+
+					abs := new(big.Int).Abs(i.bigInt())
+					// Rounding to more digits than self has always gives 0
+					if digits.bigValue != nil || -digits.value > len(abs.String()) {
+						return t.vm.InitIntegerObject(0)
+					}
+
+					unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-digits.value)), nil)
+					quotient, remainder := new(big.Int).QuoRem(abs, unit, new(big.Int))
+					if remainder.Lsh(remainder, 1).Cmp(unit) >= 0 {
+						quotient.Add(quotient, big.NewInt(1))
+					}
+
+					result := quotient.Mul(quotient, unit)
+					if i.bigInt().Sign() < 0 {
+						result.Neg(result)
+					}
+
+					return t.vm.initBigIntegerObject(result)
 				}
 			},
 		},
@@ -515,8 +992,12 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 					n := receiver.(*IntegerObject)
 
-					if n.value < 0 {
-						return t.vm.InitErrorObject(errors.InternalError, sourceLine, "Expect integer greater than or equal 0. got: %d", n.value)
+					if n.bigInt().Sign() < 0 {
+						return t.vm.InitErrorObject(errors.InternalError, sourceLine, "Expect integer greater than or equal 0. got: %s", n.toString())
+					}
+
+					if n.bigValue != nil {
+						return t.vm.InitErrorObject(errors.RangeError, sourceLine, "%s is too large to iterate", n.toString())
 					}
 
 					if blockFrame == nil {
@@ -535,10 +1016,7 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			Name: "to_int",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-					r := receiver.(*IntegerObject)
-					newInt := t.vm.InitIntegerObject(r.value)
-					newInt.flag = i
-					return newInt
+					return receiver.(*IntegerObject).toGoType(t, i, sourceLine)
 				}
 			},
 		},
@@ -546,10 +1024,7 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			Name: "to_int8",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-					r := receiver.(*IntegerObject)
-					newInt := t.vm.InitIntegerObject(r.value)
-					newInt.flag = i8
-					return newInt
+					return receiver.(*IntegerObject).toGoType(t, i8, sourceLine)
 				}
 			},
 		},
@@ -557,10 +1032,7 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			Name: "to_int16",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-					r := receiver.(*IntegerObject)
-					newInt := t.vm.InitIntegerObject(r.value)
-					newInt.flag = i16
-					return newInt
+					return receiver.(*IntegerObject).toGoType(t, i16, sourceLine)
 				}
 			},
 		},
@@ -568,10 +1040,7 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			Name: "to_int32",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-					r := receiver.(*IntegerObject)
-					newInt := t.vm.InitIntegerObject(r.value)
-					newInt.flag = i32
-					return newInt
+					return receiver.(*IntegerObject).toGoType(t, i32, sourceLine)
 				}
 			},
 		},
@@ -579,10 +1048,7 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			Name: "to_int64",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-					r := receiver.(*IntegerObject)
-					newInt := t.vm.InitIntegerObject(r.value)
-					newInt.flag = i64
-					return newInt
+					return receiver.(*IntegerObject).toGoType(t, i64, sourceLine)
 				}
 			},
 		},
@@ -590,10 +1056,7 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			Name: "to_uint",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-					r := receiver.(*IntegerObject)
-					newInt := t.vm.InitIntegerObject(r.value)
-					newInt.flag = ui
-					return newInt
+					return receiver.(*IntegerObject).toGoType(t, ui, sourceLine)
 				}
 			},
 		},
@@ -601,10 +1064,7 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			Name: "to_uint8",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-					r := receiver.(*IntegerObject)
-					newInt := t.vm.InitIntegerObject(r.value)
-					newInt.flag = ui8
-					return newInt
+					return receiver.(*IntegerObject).toGoType(t, ui8, sourceLine)
 				}
 			},
 		},
@@ -612,10 +1072,7 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			Name: "to_uint16",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-					r := receiver.(*IntegerObject)
-					newInt := t.vm.InitIntegerObject(r.value)
-					newInt.flag = ui16
-					return newInt
+					return receiver.(*IntegerObject).toGoType(t, ui16, sourceLine)
 				}
 			},
 		},
@@ -623,10 +1080,7 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			Name: "to_uint32",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-					r := receiver.(*IntegerObject)
-					newInt := t.vm.InitIntegerObject(r.value)
-					newInt.flag = ui32
-					return newInt
+					return receiver.(*IntegerObject).toGoType(t, ui32, sourceLine)
 				}
 			},
 		},
@@ -634,10 +1088,7 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			Name: "to_uint64",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-					r := receiver.(*IntegerObject)
-					newInt := t.vm.InitIntegerObject(r.value)
-					newInt.flag = ui64
-					return newInt
+					return receiver.(*IntegerObject).toGoType(t, ui64, sourceLine)
 				}
 			},
 		},
@@ -645,10 +1096,7 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			Name: "to_float32",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-					r := receiver.(*IntegerObject)
-					newInt := t.vm.InitIntegerObject(r.value)
-					newInt.flag = f32
-					return newInt
+					return receiver.(*IntegerObject).toGoType(t, f32, sourceLine)
 				}
 			},
 		},
@@ -656,10 +1104,7 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			Name: "to_float64",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-					r := receiver.(*IntegerObject)
-					newInt := t.vm.InitIntegerObject(r.value)
-					newInt.flag = f64
-					return newInt
+					return receiver.(*IntegerObject).toGoType(t, f64, sourceLine)
 				}
 			},
 		},
@@ -668,6 +1113,11 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 					r := receiver.(*IntegerObject)
+
+					if _, err := r.intValue(t, sourceLine); err != nil {
+						return err
+					}
+
 					return t.vm.initGoObject(&r.value)
 				}
 			},
@@ -687,6 +1137,17 @@ func (vm *VM) InitIntegerObject(value int) *IntegerObject {
 	}
 }
 
+// initBigIntegerObject returns an Integer of the value, which is demoted to an int if it fits in one.
+func (vm *VM) initBigIntegerObject(value *big.Int) *IntegerObject {
+	if value.IsInt64() && value.Int64() >= math.MinInt && value.Int64() <= math.MaxInt {
+		return vm.InitIntegerObject(int(value.Int64()))
+	}
+
+	i := vm.InitIntegerObject(0)
+	i.bigValue = value
+	return i
+}
+
 func (vm *VM) initIntegerClass() *RClass {
 	ic := vm.initializeClass(classes.IntegerClass)
 	ic.setBuiltinMethods(builtinIntegerInstanceMethods(), false)
@@ -696,40 +1157,77 @@ func (vm *VM) initIntegerClass() *RClass {
 
 // Polymorphic helper functions -----------------------------------------
 
-// Value returns the object
+// Value returns the object, which is a *big.Int if it doesn't fit in an int
 func (i *IntegerObject) Value() interface{} {
+	if i.bigValue != nil {
+		return i.bigValue
+	}
 	return i.value
 }
 
 // Numeric interface
 func (i *IntegerObject) floatValue() float64 {
+	if i.bigValue != nil {
+		f, _ := new(big.Float).SetInt(i.bigValue).Float64()
+		return f
+	}
 	return float64(i.value)
 }
 
+// intValue returns the value as an int, or a RangeError if the Integer is too large for one.
+// Native methods read Integer arguments they use as Go ints, such as indexes and counts, with it.
+func (i *IntegerObject) intValue(t *Thread, sourceLine int) (int, *Error) {
+	if i.bigValue != nil {
+		return 0, t.vm.InitErrorObject(errors.RangeError, sourceLine, "%s is out of range", i.toString())
+	}
+	return i.value, nil
+}
+
+// comparisonValue returns the value as an int if it fits in one, or its sign otherwise. It reads the
+// results of `<=>` and of comparison blocks, where only the sign matters.
+func (i *IntegerObject) comparisonValue() int {
+	if i.bigValue != nil {
+		return i.bigValue.Sign()
+	}
+	return i.value
+}
+
+// bigInt returns the value as a big.Int, which must not be modified
+func (i *IntegerObject) bigInt() *big.Int {
+	if i.bigValue != nil {
+		return i.bigValue
+	}
+	return big.NewInt(int64(i.value))
+}
+
+func (i *IntegerObject) isZero() bool {
+	return i.bigValue == nil && i.value == 0
+}
+
 // TODO: Remove instruction argument
 // Apply the passed arithmetic operation, while performing type conversion.
+// Integers are calculated with intOperation, or with bigOperation if either of them is a big integer
+// or intOperation overflows. A Decimal is calculated exactly with decimalOperation, if there is one.
 func (i *IntegerObject) arithmeticOperation(
 	t *Thread,
 	rightObject Object,
-	intOperation func(leftValue int, rightValue int) int,
+	intOperation func(leftValue int, rightValue int) (int, bool),
+	bigOperation func(z *big.Int, leftValue *big.Int, rightValue *big.Int) *big.Int,
 	floatOperation func(leftValue float64, rightValue float64) float64,
+	decimalOperation func(leftValue *Decimal, rightValue *Decimal) *Decimal,
 	sourceLine int,
 	division bool,
 ) Object {
-	switch rightObject.(type) {
+	switch right := rightObject.(type) {
 	case *IntegerObject:
-		leftValue := i.value
-		rightValue := rightObject.(*IntegerObject).value
-		if division && rightValue == 0 {
+		if division && right.isZero() {
 			return t.vm.InitErrorObject(errors.ZeroDivisionError, sourceLine, errors.DividedByZero)
 		}
 
-		result := intOperation(leftValue, rightValue)
-
-		return t.vm.InitIntegerObject(result)
+		return t.vm.integerOperation(i, right, intOperation, bigOperation)
 	case *FloatObject:
-		leftValue := float64(i.value)
-		rightValue := rightObject.(*FloatObject).value
+		leftValue := i.floatValue()
+		rightValue := right.value
 
 		if division && rightValue == 0 {
 			return t.vm.InitErrorObject(errors.ZeroDivisionError, sourceLine, errors.DividedByZero)
@@ -738,9 +1236,25 @@ func (i *IntegerObject) arithmeticOperation(
 		result := floatOperation(leftValue, rightValue)
 
 		return t.vm.initFloatObject(result)
-	default:
-		return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Numeric", rightObject.Class().Name)
+	case *DecimalObject:
+		if decimalOperation == nil {
+			break
+		}
+
+		if division && right.value.Sign() == 0 {
+			return t.vm.InitErrorObject(errors.ZeroDivisionError, sourceLine, errors.DividedByZero)
+		}
+
+		result := decimalOperation(intToDecimal(i), right.value)
+
+		if result == nil {
+			return t.vm.InitErrorObject(errors.ZeroDivisionError, sourceLine, errors.DividedByZero)
+		}
+
+		return t.vm.initDecimalObject(result)
 	}
+
+	return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Numeric", rightObject.Class().Name)
 }
 
 // Apply an equality test, returning true if the objects are considered equal,
@@ -749,15 +1263,13 @@ func (i *IntegerObject) arithmeticOperation(
 func (i *IntegerObject) equalityTest(rightObject Object) bool {
 	switch rightObject.(type) {
 	case *IntegerObject:
-		leftValue := i.value
-		rightValue := rightObject.(*IntegerObject).value
-
-		return leftValue == rightValue
+		return i.equal(rightObject.(*IntegerObject))
 	case *FloatObject:
-		leftValue := i.floatValue()
-		rightValue := rightObject.(*FloatObject).value
+		result, ok := compareFloatToInteger(rightObject.(*FloatObject).value, i)
 
-		return leftValue == rightValue
+		return ok && result == 0
+	case *DecimalObject:
+		return intToDecimal(i).Cmp(rightObject.(*DecimalObject).value) == 0
 	default:
 		return false
 	}
@@ -776,26 +1288,60 @@ func (i *IntegerObject) numericComparison(
 ) Object {
 	switch rightObject.(type) {
 	case *IntegerObject:
-		leftValue := i.value
-		rightValue := rightObject.(*IntegerObject).value
+		right := rightObject.(*IntegerObject)
+
+		if i.bigValue != nil || right.bigValue != nil {
+			// Comparing the result of Cmp with 0 is the same as comparing the integers
+			return toBooleanObject(intComparison(compareIntegers(i, right), 0))
+		}
 
-		result := intComparison(leftValue, rightValue)
+		result := intComparison(i.value, right.value)
 
 		return toBooleanObject(result)
+	case *DecimalObject:
+		return toBooleanObject(intComparison(intToDecimal(i).Cmp(rightObject.(*DecimalObject).value), 0))
 	case *FloatObject:
-		leftValue := i.floatValue()
 		rightValue := rightObject.(*FloatObject).value
 
-		result := floatComparison(leftValue, rightValue)
+		result, ok := compareFloatToInteger(rightValue, i)
+		if !ok {
+			// Every comparison with NaN is false
+			return toBooleanObject(floatComparison(i.floatValue(), rightValue))
+		}
 
-		return toBooleanObject(result)
+		return toBooleanObject(intComparison(-result, 0))
 	default:
 		return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Numeric", rightObject.Class().Name)
 	}
 }
 
+// toGoType returns a copy of self flagged to be converted to the flag's Go type when it's passed to a Go
+// function. It returns a RangeError if self doesn't fit in the type.
+func (i *IntegerObject) toGoType(t *Thread, flag int, sourceLine int) Object {
+	goType := goIntegerTypes[flag]
+	n := i.bigInt()
+
+	if n.Cmp(goType.min) < 0 || n.Cmp(goType.max) > 0 {
+		return t.vm.InitErrorObject(errors.RangeError, sourceLine, "%s is out of range for %s", n.String(), goType.name)
+	}
+
+	newInt := t.vm.InitIntegerObject(i.value)
+	newInt.flag = flag
+
+	if i.bigValue != nil {
+		// Only a uint or uint64 holds an Integer that doesn't fit in an int, so value keeps its bits
+		newInt.value = int(n.Uint64())
+		newInt.bigValue = i.bigValue
+	}
+
+	return newInt
+}
+
 // toString returns the object's name as the string format
 func (i *IntegerObject) toString() string {
+	if i.bigValue != nil {
+		return i.bigValue.String()
+	}
 	return strconv.Itoa(i.value)
 }
 
@@ -806,5 +1352,266 @@ func (i *IntegerObject) toJSON(t *Thread) string {
 
 // equal checks if the integer values between receiver and argument are equal
 func (i *IntegerObject) equal(e *IntegerObject) bool {
+	if i.bigValue != nil || e.bigValue != nil {
+		return compareIntegers(i, e) == 0
+	}
 	return i.value == e.value
 }
+
+// Other helper functions -----------------------------------------------
+
+// goIntegerTypes maps the flags to the names and ranges of the Go types they stand for.
+// Floats are converted from the int value, so they share its range.
+var goIntegerTypes = map[int]struct {
+	name     string
+	min, max *big.Int
+}{
+	i:    {"int", big.NewInt(math.MinInt), big.NewInt(math.MaxInt)},
+	i8:   {"int8", big.NewInt(math.MinInt8), big.NewInt(math.MaxInt8)},
+	i16:  {"int16", big.NewInt(math.MinInt16), big.NewInt(math.MaxInt16)},
+	i32:  {"int32", big.NewInt(math.MinInt32), big.NewInt(math.MaxInt32)},
+	i64:  {"int64", big.NewInt(math.MinInt64), big.NewInt(math.MaxInt64)},
+	ui:   {"uint", big.NewInt(0), new(big.Int).SetUint64(math.MaxUint)},
+	ui8:  {"uint8", big.NewInt(0), big.NewInt(math.MaxUint8)},
+	ui16: {"uint16", big.NewInt(0), big.NewInt(math.MaxUint16)},
+	ui32: {"uint32", big.NewInt(0), big.NewInt(math.MaxUint32)},
+	ui64: {"uint64", big.NewInt(0), new(big.Int).SetUint64(math.MaxUint64)},
+	f32:  {"float32", big.NewInt(math.MinInt), big.NewInt(math.MaxInt)},
+	f64:  {"float64", big.NewInt(math.MinInt), big.NewInt(math.MaxInt)},
+}
+
+// integerOperation applies intOperation to the Integers, or bigOperation if either of them is a big
+// integer or intOperation overflows. Big results are demoted if they fit in an int.
+func (vm *VM) integerOperation(
+	left *IntegerObject,
+	right *IntegerObject,
+	intOperation func(leftValue int, rightValue int) (int, bool),
+	bigOperation func(z *big.Int, leftValue *big.Int, rightValue *big.Int) *big.Int,
+) *IntegerObject {
+	if left.bigValue == nil && right.bigValue == nil {
+		if result, ok := intOperation(left.value, right.value); ok {
+			return vm.InitIntegerObject(result)
+		}
+	}
+
+	return vm.initBigIntegerObject(bigOperation(new(big.Int), left.bigInt(), right.bigInt()))
+}
+
+// floorDiv returns the quotient of a and b rounded towards negative infinity.
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// floorMod returns the modulus of a and b, which has the sign of b.
+func floorMod(a, b int) int {
+	m := a % b
+	if m != 0 && (m < 0) != (b < 0) {
+		m += b
+	}
+	return m
+}
+
+// bigFloorDiv sets z to the quotient of x and y rounded towards negative infinity, and returns z.
+func bigFloorDiv(z, x, y *big.Int) *big.Int {
+	m := new(big.Int)
+	z.QuoRem(x, y, m)
+	if m.Sign() != 0 && m.Sign() != y.Sign() {
+		z.Sub(z, big.NewInt(1))
+	}
+	return z
+}
+
+// bigFloorMod sets z to the modulus of x and y, which has the sign of y, and returns z.
+func bigFloorMod(z, x, y *big.Int) *big.Int {
+	z.Rem(x, y)
+	if z.Sign() != 0 && z.Sign() != y.Sign() {
+		z.Add(z, y)
+	}
+	return z
+}
+
+// floatFloorMod returns the modulus of a and b, which has the sign of b.
+func floatFloorMod(a, b float64) float64 {
+	m := math.Mod(a, b)
+	if m != 0 && (m < 0) != (b < 0) {
+		m += b
+	}
+	return m
+}
+
+// bitwiseOperation applies a bitwise operation to self and the Integer argument.
+func (i *IntegerObject) bitwiseOperation(
+	t *Thread,
+	args []Object,
+	intOperation func(leftValue int, rightValue int) (int, bool),
+	bigOperation func(z *big.Int, leftValue *big.Int, rightValue *big.Int) *big.Int,
+	sourceLine int,
+) Object {
+	if len(args) != 1 {
+		return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+	}
+
+	right, ok := args[0].(*IntegerObject)
+	if !ok {
+		return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+	}
+
+	return t.vm.integerOperation(i, right, intOperation, bigOperation)
+}
+
+// shiftInteger returns i shifted left by n bits, or right if n is negative. A right shift rounds
+// towards negative infinity. It returns a RangeError if the result would have more than maxIntegerBits bits.
+func (t *Thread) shiftInteger(i *IntegerObject, n int, sourceLine int) Object {
+	vm := t.vm
+
+	if n < 0 {
+		if i.bigValue != nil {
+			return vm.initBigIntegerObject(new(big.Int).Rsh(i.bigValue, uint(-n)))
+		}
+
+		if n <= -strconv.IntSize {
+			n = 1 - strconv.IntSize
+		}
+
+		return vm.InitIntegerObject(i.value >> uint(-n))
+	}
+
+	if i.bigValue == nil && n < strconv.IntSize {
+		shifted := i.value << uint(n)
+
+		if shifted>>uint(n) == i.value {
+			return vm.InitIntegerObject(shifted)
+		}
+	}
+
+	if !i.isZero() && n > maxIntegerBits-i.bigInt().BitLen() {
+		return vm.InitErrorObject(errors.RangeError, sourceLine, "Shift width is too large: %d", n)
+	}
+
+	return vm.initBigIntegerObject(new(big.Int).Lsh(i.bigInt(), uint(n)))
+}
+
+// compareIntegers returns -1, 0 or 1 if a is smaller than, equal to or larger than b.
+func compareIntegers(a, b *IntegerObject) int {
+	if a.bigValue == nil && b.bigValue == nil {
+		switch {
+		case a.value < b.value:
+			return -1
+		case a.value > b.value:
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	return a.bigInt().Cmp(b.bigInt())
+}
+
+// gcd returns the non-negative greatest common divisor of a and b.
+func gcd(a, b *big.Int) *big.Int {
+	return new(big.Int).GCD(nil, nil, new(big.Int).Abs(a), new(big.Int).Abs(b))
+}
+
+// integerPrefixes maps the bases to the prefixes a String converted with `Integer()` may have.
+var integerPrefixes = map[int][]string{
+	2:  {"0b", "0B"},
+	8:  {"0o", "0O", "0"},
+	16: {"0x", "0X"},
+}
+
+// parseInteger parses s as an Integer in the given base. With base 0 the base is taken from the
+// prefix of s, which can be `0b`, `0o`, `0` or `0x`. Surrounding whitespace and single underscores
+// between digits are allowed.
+func parseInteger(s string, base int) (*big.Int, bool) {
+	s = strings.TrimSpace(s)
+
+	if base == 0 {
+		return new(big.Int).SetString(s, 0)
+	}
+
+	sign := ""
+	if len(s) > 0 && (s[0] == '+' || s[0] == '-') {
+		sign, s = s[:1], s[1:]
+	}
+
+	for _, prefix := range integerPrefixes[base] {
+		if len(s) > len(prefix) && strings.HasPrefix(s, prefix) {
+			s = s[len(prefix):]
+			break
+		}
+	}
+
+	if strings.HasPrefix(s, "_") || strings.HasSuffix(s, "_") || strings.Contains(s, "__") || strings.ContainsAny(s, "+-") {
+		return nil, false
+	}
+
+	return new(big.Int).SetString(sign+strings.Replace(s, "_", "", -1), base)
+}
+
+// radix returns the base given to methods like `Integer#to_s`, which must be between 2 and 36.
+func radix(t *Thread, arg Object, sourceLine int) (int, *Error) {
+	base, ok := arg.(*IntegerObject)
+	if !ok {
+		return 0, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, arg.Class().Name)
+	}
+
+	if base.bigValue != nil || base.value < 2 || base.value > 36 {
+		return 0, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Invalid radix %s", base.toString())
+	}
+
+	return base.value, nil
+}
+
+// addInts returns the sum of the ints, and false if it overflows.
+func addInts(a, b int) (int, bool) {
+	sum := a + b
+	return sum, (sum > a) == (b > 0)
+}
+
+// subtractInts returns the difference of the ints, and false if it overflows.
+func subtractInts(a, b int) (int, bool) {
+	difference := a - b
+	return difference, (difference < a) == (b > 0)
+}
+
+// multiplyInts returns the product of the ints, and false if it overflows.
+func multiplyInts(a, b int) (int, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+
+	product := a * b
+	if (a == -1 && b == math.MinInt) || (b == -1 && a == math.MinInt) {
+		return product, false
+	}
+
+	return product, product/b == a
+}
+
+// powerInts returns base raised to the non-negative exponent, and false if it overflows.
+func powerInts(base, exponent int) (int, bool) {
+	result := 1
+
+	for exponent > 0 {
+		var ok bool
+
+		if exponent&1 == 1 {
+			if result, ok = multiplyInts(result, base); !ok {
+				return 0, false
+			}
+		}
+
+		exponent >>= 1
+		if exponent > 0 {
+			if base, ok = multiplyInts(base, base); !ok {
+				return 0, false
+			}
+		}
+	}
+
+	return result, true
+}
@@ -33,6 +33,14 @@ func TestIntegerArithmeticOperationWithInteger(t *testing.T) {
 		{`13  %  3`, 1},
 		{`13  /  3`, 4},
 		{`13  ** 3`, 2197},
+		{`7   /  -2`, -4},
+		{`-7  /  2`, -4},
+		{`-7  /  -2`, 3},
+		{`-6  /  2`, -3},
+		{`7   %  -2`, -1},
+		{`-7  %  2`, 1},
+		{`-7  %  -2`, -1},
+		{`-6  %  2`, 0},
 	}
 
 	for i, tt := range tests {
@@ -54,6 +62,9 @@ func TestIntegerArithmeticOperationWithFloat(t *testing.T) {
 		{`13  *  '3.5'.to_f`, 45.5},
 		{`13  %  '3.5'.to_f`, 2.5},
 		{`13  /  '6.5'.to_f`, 2.0},
+		{`-13 /  '2.0'.to_f`, -6.5},
+		{`-13 %  '3.5'.to_f`, 1.0},
+		{`13  %  '-3.5'.to_f`, -1.0},
 		{`4   ** '3.5'.to_f`, 128.0},
 	}
 
@@ -66,6 +77,516 @@ func TestIntegerArithmeticOperationWithFloat(t *testing.T) {
 	}
 }
 
+func TestIntegerArithmeticOperationWithDecimal(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`(1 + '0.1'.to_d).to_s`, "1.1"},
+		{`(1 - '0.1'.to_d).to_s`, "0.9"},
+		{`(3 * '0.1'.to_d).to_s`, "0.3"},
+		{`(1 / '3'.to_d).fraction`, "1/3"},
+		{`(2 ** '3'.to_d).to_s`, "8"},
+		{`(1 + '0.1'.to_d).class.name`, "Decimal"},
+		{`((2 ** 64) + '0.5'.to_d).to_s`, "18446744073709551616.5"},
+		{`1 < '1.1'.to_d`, true},
+		{`(2 ** 64) > '18446744073709551615.9'.to_d`, true},
+		{`1 <=> '0.9'.to_d`, 1},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerBigIntegerPromotion(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`(2 ** 64).to_s`, "18446744073709551616"},
+		{`(2 ** 64).class.name`, "Integer"},
+		{`(9223372036854775807 + 1).to_s`, "9223372036854775808"},
+		{`(-9223372036854775807 - 2).to_s`, "-9223372036854775809"},
+		{`(4294967296 * 4294967296).to_s`, "18446744073709551616"},
+		{`(3 ** 40).to_s`, "12157665459056928801"},
+		{`(-2 ** 63).to_s`, "-9223372036854775808"},
+		{`9223372036854775807.next.to_s`, "9223372036854775808"},
+		{`(-9223372036854775807 - 1).pred.to_s`, "-9223372036854775809"},
+		{`
+		f = 1
+		(1..25).each do |n|
+		  f = f * n
+		end
+		f.to_s
+		`, "15511210043330985984000000"},
+		{`2 ** 64 - 2 ** 64 + 1`, 1},
+		{`(2 ** 64) / (2 ** 60)`, 16},
+		{`(2 ** 64 + 5) % (2 ** 32)`, 5},
+		{`((0 - 2 ** 64 - 1) / (2 ** 32)).to_s`, "-4294967297"},
+		{`(0 - 2 ** 64 - 1) % (2 ** 32)`, 4294967295},
+		{`((2 ** 64 + 1) / -(2 ** 32)).to_s`, "-4294967297"},
+		{`((2 ** 64 + 1) % -(2 ** 32))`, -4294967295},
+		{`((-9223372036854775807 - 1) / -1).to_s`, "9223372036854775808"},
+		{`(2 ** 64 - 1) - (2 ** 64)`, -1},
+		{`0 - (2 ** 64) + (2 ** 64)`, 0},
+		{`(2 ** 64).to_f`, 18446744073709551616.0},
+		{`(2 ** 64 + 0.5).class.name`, "Float"},
+		{`(2 ** 64).even?`, true},
+		{`(2 ** 64 + 1).odd?`, true},
+		{`(2 ** 64).to_d.to_s`, "18446744073709551616"},
+		{`[2 ** 64, 2 ** 64].sum.to_s`, "36893488147419103232"},
+		{`"123456789012345678901234567890".to_i.to_s`, "123456789012345678901234567890"},
+		{`"1e20".to_f.to_i.to_s`, "100000000000000000000"},
+		{`2 ** -1`, 0},
+		{`100000000000000000000.to_s`, "100000000000000000000"},
+		{`(-100000000000000000000 + 1).to_s`, "-99999999999999999999"},
+		{`9223372036854775808.to_s`, "9223372036854775808"},
+		{`(1 ** (2 ** 64)).to_s`, "1"},
+		{`((-1) ** (2 ** 64 + 1)).to_s`, "-1"},
+		{`(1 << 1000).to_s.size`, 302},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerBigIntegerComparison(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`(2 ** 64) == (2 ** 64)`, true},
+		{`(2 ** 64) == (2 ** 64 + 1)`, false},
+		{`(2 ** 64) != 0`, true},
+		{`(2 ** 64) > (2 ** 63)`, true},
+		{`(2 ** 64) < 1`, false},
+		{`-(2 ** 64) < 1`, true},
+		{`(2 ** 64) >= (2 ** 64)`, true},
+		{`(2 ** 64) <= 1.0`, false},
+		{`(2 ** 64) <=> (2 ** 65)`, -1},
+		{`(2 ** 65) <=> (2 ** 64)`, 1},
+		{`1 <=> (2 ** 64)`, -1},
+		{`[2 ** 65, 3, 2 ** 64].sort.last == 2 ** 65`, true},
+		{`[2 ** 65, 3, 2 ** 64 + 1].max - 2 ** 65`, 0},
+		{`[2 ** 64, 2 ** 64].count(2 ** 64)`, 2},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerShiftLeftMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`1.send("<<", 4)`, 16},
+		{`1.send("<<", 62)`, 4611686018427387904},
+		{`1.send("<<", 63).to_s`, "9223372036854775808"},
+		{`1.send("<<", 64).to_s`, "18446744073709551616"},
+		{`(2 ** 64).send("<<", 1).to_s`, "36893488147419103232"},
+		{`16.send("<<", -2)`, 4},
+		{`-16.send("<<", -100)`, -1},
+		{`(2 ** 70).send("<<", -70)`, 1},
+		{`0.send("<<", 100)`, 0},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerShiftLeftMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`1.send("<<")`, "ArgumentError: Expect 1 arguments. got: 0", 2},
+		{`1.send("<<", "1")`, "TypeError: Expect argument to be Integer. got: String", 2},
+		{`1.send("<<", 2 ** 64)`, "RangeError: Shift width is too large: 18446744073709551616", 2},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerBitwiseOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`6 & 3`, 2},
+		{`6 | 3`, 7},
+		{`6 ^ 3`, 5},
+		{`~5`, -6},
+		{`~-1`, 0},
+		{`-1 & 15`, 15},
+		{`5 ^ -1`, -6},
+		{`1 << 4`, 16},
+		{`256 >> 4`, 16},
+		{`-5 >> 1`, -3},
+		{`1 >> 64`, 0},
+		{`-1 >> 64`, -1},
+		{`(1 >> -64).to_s`, "18446744073709551616"},
+		{`(1 | 2 ** 64).to_s`, "18446744073709551617"},
+		{`(2 ** 64 + 5) & 7`, 5},
+		{`(~(2 ** 64)).to_s`, "-18446744073709551617"},
+		{`((2 ** 64) ^ (2 ** 64))`, 0},
+		{`1 + 2 << 3`, 24},
+		{`1 | 2 & 3`, 3},
+		{`5 & 4 == 4`, true},
+		{`
+	a = 0
+	[1, 2, 4].each do |x|
+	  a = a | x
+	end
+	a
+	`, 7},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerBitwiseOperatorsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`1 & "a"`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`1 | 1.0`, "TypeError: Expect argument to be Integer. got: Float", 1},
+		{`1 ^ nil`, "TypeError: Expect argument to be Integer. got: Null", 1},
+		{`1 >> "1"`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`1 >> -(2 ** 64)`, "RangeError: Shift width is too large: -18446744073709551616", 1},
+		{`1 << 100000000000`, "RangeError: Shift width is too large: 100000000000", 1},
+		{`(2 ** 64) << 16777200`, "RangeError: Shift width is too large: 16777200", 1},
+		{`1.send("~", 1)`, "ArgumentError: Expect 0 arguments. got: 1", 2},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerAbsMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`12.abs`, 12},
+		{`-12.abs`, 12},
+		{`0.abs`, 0},
+		{`(-(2 ** 64)).abs.to_s`, "18446744073709551616"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerDigitsMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`1234.digits.to_s`, "[4, 3, 2, 1]"},
+		{`255.digits(16).to_s`, "[15, 15]"},
+		{`0.digits.to_s`, "[0]"},
+		{`(2 ** 64).digits.length`, 20},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerDigitsMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`-1.digits`, "ArgumentError: Expect a non-negative Integer. got: -1", 1},
+		{`1.digits(1)`, "ArgumentError: Invalid radix 1", 1},
+		{`1.digits("2")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`1.digits(2, 3)`, "ArgumentError: Expect 0..1 arguments. got: 2", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerDivmodMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`13.divmod(4).to_s`, "[3, 1]"},
+		{`-13.divmod(4).to_s`, "[-4, 3]"},
+		{`13.divmod(-4).to_s`, "[-4, -3]"},
+		{`-13.divmod(-4).to_s`, "[3, -1]"},
+		{`13.divmod(4.0)[1]`, 1.0},
+		{`-13.divmod(4.0)[0]`, -4},
+		{`(2 ** 64).divmod(2 ** 63).to_s`, "[2, 0]"},
+		{`7.divmod(-2).to_s`, "[-4, -1]"},
+		{`7.divmod(-2) == [7 / -2, 7 % -2]`, true},
+		{`-7.divmod(2) == [-7 / 2, -7 % 2]`, true},
+		{`(0 - 2 ** 64 - 1).divmod(2 ** 32) == [(0 - 2 ** 64 - 1) / (2 ** 32), (0 - 2 ** 64 - 1) % (2 ** 32)]`, true},
+		{`-13.divmod(4.0)[1] == -13 % 4.0`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerDivmodMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`1.divmod(0)`, "ZeroDivisionError: Divided by 0", 1},
+		{`1.divmod(0.0)`, "ZeroDivisionError: Divided by 0", 1},
+		{`1.divmod("1")`, "TypeError: Expect argument to be Integer or Float. got: String", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerGcdAndLcmMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`12.gcd(18)`, 6},
+		{`-12.gcd(18)`, 6},
+		{`5.gcd(0)`, 5},
+		{`0.gcd(0)`, 0},
+		{`(2 ** 64).gcd(2 ** 70).to_s`, "18446744073709551616"},
+		{`4.lcm(6)`, 12},
+		{`-4.lcm(6)`, 12},
+		{`4.lcm(0)`, 0},
+		{`(2 ** 40).lcm(3 ** 30).to_s`, "226379693794030958489370624"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerGcdAndLcmMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`1.gcd("1")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`1.gcd`, "ArgumentError: Expect 1 arguments. got: 0", 1},
+		{`1.lcm(1.5)`, "TypeError: Expect argument to be Integer. got: Float", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerHashMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`1.hash.is_a?(Integer)`, true},
+		{`2.hash == (1 + 1).hash`, true},
+		{`1.hash == 2.hash`, false},
+		{`1.hash == 1.0.hash`, false},
+		{`1.hash == 1.to_d.hash`, true},
+		{`1.hash == '1.5'.to_d.hash`, false},
+		{`(2 ** 64).hash == (2 ** 64).to_d.hash`, true},
+		{`1.hash == "1".hash`, false},
+		{`(2 ** 100).hash == (2 ** 100).hash`, true},
+		{`((2 ** 64) / (2 ** 64)).hash == 1.hash`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerHashMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`1.hash(1)`, "ArgumentError: Expect 0 arguments. got: 1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerRoundMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`1234.round`, 1234},
+		{`1234.round(2)`, 1234},
+		{`1250.round(-2)`, 1300},
+		{`-1250.round(-2)`, -1300},
+		{`1249.round(-2)`, 1200},
+		{`5.round(-1)`, 10},
+		{`5.round(-3)`, 0},
+		{`(2 ** 64).round(-19).to_s`, "20000000000000000000"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerRoundMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`1.round("1")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`1.round(1, 2)`, "ArgumentError: Expect 0..1 arguments. got: 2", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerToSMethodWithBase(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`255.to_s(2)`, "11111111"},
+		{`255.to_s(16)`, "ff"},
+		{`-255.to_s(36)`, "-73"},
+		{`(2 ** 64).to_s(16)`, "10000000000000000"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerToSMethodWithBaseFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`1.to_s(1)`, "ArgumentError: Invalid radix 1", 1},
+		{`1.to_s(37)`, "ArgumentError: Invalid radix 37", 1},
+		{`1.to_s("2")`, "TypeError: Expect argument to be Integer. got: String", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerSqrtClassMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Integer.sqrt(24)`, 4},
+		{`Integer.sqrt(25)`, 5},
+		{`Integer.sqrt(0)`, 0},
+		{`Integer.sqrt(10 ** 40).to_s`, "100000000000000000000"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerSqrtClassMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Integer.sqrt(-1)`, "ArgumentError: Expect a non-negative Integer. got: -1", 1},
+		{`Integer.sqrt(2.0)`, "TypeError: Expect argument to be Integer. got: Float", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestIntegerArithmeticOperationsPriority(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -193,6 +714,11 @@ func TestIntegerEquality(t *testing.T) {
 		{`4  ==  4`, true},
 		{`4  ==  '4'.to_f`, true},
 		{`4  ==  '5'.to_f`, false},
+		{`4  ==  4.to_d`, true},
+		{`4  ==  '4.5'.to_d`, false},
+		{`(2**64)  ==  (2**64).to_d`, true},
+		{`(4 <=> 4.to_d) == 0`, true},
+		{`((2**64) <=> (2**64).to_d) == 0`, true},
 		{`4  ==  '4'`, false},
 		{`4  ==  (1..3)`, false},
 		{`4  ==  { a: 1 }`, false},
@@ -201,6 +727,8 @@ func TestIntegerEquality(t *testing.T) {
 		{`4  !=  4`, false},
 		{`4  !=  '4'.to_f`, false},
 		{`4  !=  '5'.to_f`, true},
+		{`4  !=  4.to_d`, false},
+		{`4  !=  '4.5'.to_d`, true},
 		{`4  !=  '4'`, true},
 		{`4  !=  (1..3)`, true},
 		{`4  !=  { a: 1 }`, true},
@@ -234,6 +762,11 @@ func TestIntegerConversion(t *testing.T) {
 		{`-100.to_d.numerator.to_i`, -100},
 		{`-100.to_d.denominator.to_i`, 1},
 		{`100.to_d.class.name`, "Decimal"},
+		{`127.to_int8`, 127},
+		{`-128.to_int8`, -128},
+		{`255.to_uint8`, 255},
+		{`9223372036854775807.to_int64`, 9223372036854775807},
+		{`(2 ** 64 - 1).to_uint64.to_s`, "18446744073709551615"},
 	}
 
 	for i, tt := range tests {
@@ -248,6 +781,20 @@ func TestIntegerConversion(t *testing.T) {
 func TestIntegerConversonFail(t *testing.T) {
 	testsFail := []errorTestCase{
 		{`100.to_d 1`, "ArgumentError: Expect 0 arguments. got: 1", 1},
+		{`(2 ** 64).to_int64`, "RangeError: 18446744073709551616 is out of range for int64", 1},
+		{`(2 ** 64).to_int`, "RangeError: 18446744073709551616 is out of range for int", 1},
+		{`(2 ** 64).to_uint64`, "RangeError: 18446744073709551616 is out of range for uint64", 1},
+		{`-1.to_uint64`, "RangeError: -1 is out of range for uint64", 1},
+		{`128.to_int8`, "RangeError: 128 is out of range for int8", 1},
+		{`-129.to_int8`, "RangeError: -129 is out of range for int8", 1},
+		{`256.to_uint8`, "RangeError: 256 is out of range for uint8", 1},
+		{`65536.to_uint16`, "RangeError: 65536 is out of range for uint16", 1},
+		{`2147483648.to_int32`, "RangeError: 2147483648 is out of range for int32", 1},
+		{`(2 ** 64).to_float64`, "RangeError: 18446744073709551616 is out of range for float64", 1},
+		{`(2 ** 64).times do end`, "RangeError: 18446744073709551616 is too large to iterate", 1},
+		{`2 ** (2 ** 64)`, "RangeError: Exponent is too large: 18446744073709551616", 1},
+		{`2 ** 10000000000`, "RangeError: Exponent is too large: 10000000000", 1},
+		{`(2 ** 64) ** 1000000`, "RangeError: Exponent is too large: 1000000", 1},
 	}
 
 	for i, tt := range testsFail {
@@ -152,3 +152,48 @@ func TestLazyEnumeratorFunctional(t *testing.T) {
 	v.checkCFP(t, i, 0)
 	v.checkSP(t, i, 1)
 }
+
+func TestLazyEnumeratorFilteringMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		(1..20).lazy.select do |i|
+		  i % 3 == 0
+		end.map do |i|
+		  i * 10
+		end.first(3)
+		`, []interface{}{30, 60, 90}},
+		{`
+		[1, 2, 3, 4].lazy.reject do |i|
+		  i.even?
+		end.force
+		`, []interface{}{1, 3}},
+		{`
+		[1, 2, 3, 4, 1].lazy.take_while do |i|
+		  i < 3
+		end.to_a
+		`, []interface{}{1, 2}},
+		{`
+		iterated_values = []
+
+		result = [1, 2, 3, 4].lazy.select do |i|
+		  iterated_values.push(i)
+		  i > 1
+		end.first(2)
+
+		[iterated_values, result]
+		`, []interface{}{[]interface{}{1, 2, 3}, []interface{}{2, 3}}},
+		{`[4, 5].lazy.first`, 4},
+		{`[3, 1, 2].lazy.sort`, []interface{}{1, 2, 3}},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
@@ -2,6 +2,8 @@ package vm
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/dlclark/regexp2"
 	"github.com/goby-lang/goby/vm/classes"
@@ -17,6 +19,11 @@ import (
 //
 // 'abcd'.match(Regexp.new('a(?<first>b)(?<second>c)'))
 // #=> #<MatchData 0:"abc" first:"b" second:"c">
+//
+// m = "2018-05-01".match(/(?<year>\d+)-(?<month>\d+)/)
+// m["year"]    #=> "2018"
+// m[2]         #=> "05"
+// m.post_match #=> "-01"
 // ```
 //
 // - `MatchData.new` is not supported.
@@ -24,6 +31,7 @@ type Match = regexp2.Match
 type MatchDataObject struct {
 	*baseObj
 	match *Match
+	text  string
 }
 
 // Class methods --------------------------------------------------------
@@ -43,6 +51,46 @@ func builtInMatchDataClassMethods() []*BuiltinMethodObject {
 // Instance methods -----------------------------------------------------
 func builtinMatchDataInstanceMethods() []*BuiltinMethodObject {
 	return []*BuiltinMethodObject{
+		{
+			// Returns the capture of the group with the number or the name, or nil if the group didn't match.
+			//
+			// ```ruby
+			// m = "2018-05".match(/(?<year>\d+)-(?<month>\d+)/)
+			// m[0]       #=> "2018-05"
+			// m[1]       #=> "2018"
+			// m["month"] #=> "05"
+			// m[3]       #=> nil
+			// ```
+			//
+			// @param group [Integer/String]
+			// @return [String]
+			Name: "[]",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					m := receiver.(*MatchDataObject)
+					var group *regexp2.Group
+
+					switch arg := args[0].(type) {
+					case *IntegerObject:
+						group = m.match.GroupByNumber(arg.value)
+					case *StringObject:
+						group = m.match.GroupByName(arg.value)
+
+						if group == nil || !isGroupName(arg.value) {
+							return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Undefined group name reference: %s", arg.value)
+						}
+					default:
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Integer or String", arg.Class().Name)
+					}
+
+					return t.vm.initCaptureObject(group)
+				}
+			},
+		},
 		{
 			// Returns the array of captures; equivalent to `match.to_a[1..-1]`.
 			//
@@ -73,6 +121,73 @@ func builtinMatchDataInstanceMethods() []*BuiltinMethodObject {
 				}
 			},
 		},
+		{
+			// Returns the names of the named captures.
+			//
+			// ```ruby
+			// "2018-05".match(/(?<year>\d+)-(?<month>\d+)/).names # => ["year", "month"]
+			// ```
+			//
+			// @return [Array]
+			Name: "names",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					names := []Object{}
+
+					for _, g := range receiver.(*MatchDataObject).match.Groups() {
+						if isGroupName(g.Name) {
+							names = append(names, t.vm.InitStringObject(g.Name))
+						}
+					}
+
+					return t.vm.InitArrayObject(names)
+				}
+			},
+		},
+		{
+			// Returns the part of the string after the match.
+			//
+			// ```ruby
+			// "Hello Goby!".match(/Go+/).post_match # => "by!"
+			// ```
+			//
+			// @return [String]
+			Name: "post_match",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					m := receiver.(*MatchDataObject)
+					return t.vm.InitStringObject(postMatch(m.match, []rune(m.text)))
+				}
+			},
+		},
+		{
+			// Returns the part of the string before the match.
+			//
+			// ```ruby
+			// "Hello Goby!".match(/Go+/).pre_match # => "Hello "
+			// ```
+			//
+			// @return [String]
+			Name: "pre_match",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					m := receiver.(*MatchDataObject)
+					return t.vm.InitStringObject(preMatch(m.match, []rune(m.text)))
+				}
+			},
+		},
 		{
 			// Returns the array of captures.
 			//
@@ -168,9 +283,19 @@ func (vm *VM) initMatchDataObject(match *Match, pattern, text string) *MatchData
 	return &MatchDataObject{
 		baseObj: &baseObj{class: vm.topLevelClass(classes.MatchDataClass)},
 		match:   match,
+		text:    text,
 	}
 }
 
+// initCaptureObject returns the group's capture as a String, or nil if the group didn't match.
+func (vm *VM) initCaptureObject(group *regexp2.Group) Object {
+	if group == nil || len(group.Captures) == 0 {
+		return NULL
+	}
+
+	return vm.InitStringObject(group.String())
+}
+
 func (vm *VM) initMatchDataClass() *RClass {
 	klass := vm.initializeClass(classes.MatchDataClass)
 	klass.setBuiltinMethods(builtinMatchDataInstanceMethods(), false)
@@ -178,6 +303,64 @@ func (vm *VM) initMatchDataClass() *RClass {
 	return klass
 }
 
+// Other helper functions -----------------------------------------------
+
+// preMatch returns the text before the match. Match indexes count runes instead of bytes.
+func preMatch(m *Match, text []rune) string {
+	return string(text[:m.Index])
+}
+
+// postMatch returns the text after the match.
+func postMatch(m *Match, text []rune) string {
+	return string(text[m.Index+m.Length:])
+}
+
+// expandReplacement returns the replacement with its references replaced by the match: `\0` or `\&`
+// for the whole match, `\1` to `\9` for the numbered groups, `\k<name>` for the named groups,
+// a backslash and a backtick or a quote for the text before or after the match, and `\\` for a backslash.
+func expandReplacement(replacement string, m *Match, text []rune) string {
+	var out strings.Builder
+
+	for i := 0; i < len(replacement); i++ {
+		if replacement[i] != '\\' || i+1 == len(replacement) {
+			out.WriteByte(replacement[i])
+			continue
+		}
+
+		i++
+
+		switch c := replacement[i]; {
+		case c >= '0' && c <= '9':
+			n, _ := strconv.Atoi(string(c))
+
+			if g := m.GroupByNumber(n); g != nil {
+				out.WriteString(g.String())
+			}
+		case c == '&':
+			out.WriteString(m.String())
+		case c == '`':
+			out.WriteString(preMatch(m, text))
+		case c == '\'':
+			out.WriteString(postMatch(m, text))
+		case c == '\\':
+			out.WriteByte('\\')
+		case c == 'k' && strings.HasPrefix(replacement[i+1:], "<") && strings.Contains(replacement[i+1:], ">"):
+			end := i + 1 + strings.Index(replacement[i+1:], ">")
+
+			if g := m.GroupByName(replacement[i+2 : end]); g != nil {
+				out.WriteString(g.String())
+			}
+
+			i = end
+		default:
+			out.WriteByte('\\')
+			out.WriteByte(c)
+		}
+	}
+
+	return out.String()
+}
+
 // Polymorphic helper functions -----------------------------------------
 
 // redirects to toString()
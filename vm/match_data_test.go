@@ -141,3 +141,63 @@ func TestMatchDataToHMethod(t *testing.T) {
 		vm.checkSP(t, i, 1)
 	}
 }
+
+func TestMatchDataBracketMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"2018-05".match(/(?<year>\d+)-(?<month>\d+)/)[0]`, "2018-05"},
+		{`"2018-05".match(/(?<year>\d+)-(?<month>\d+)/)[1]`, "2018"},
+		{`"2018-05".match(/(?<year>\d+)-(?<month>\d+)/)["month"]`, "05"},
+		{`"2018-05".match(/(?<year>\d+)-(?<month>\d+)/)[3]`, nil},
+		{`"a".match(/(a)(b)?/)[2]`, nil},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestMatchDataBracketMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`"ab".match(/(?<x>a)/)["y"]`, "ArgumentError: Undefined group name reference: y", 1},
+		{`"ab".match(/(a)/)["1"]`, "ArgumentError: Undefined group name reference: 1", 1},
+		{`"ab".match(/(a)/)[nil]`, "TypeError: Expect argument to be Integer or String. got: Null", 1},
+		{`"ab".match(/(a)/)[]`, "ArgumentError: Expect 1 arguments. got: 0", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestMatchDataPreAndPostMatchAndNamesMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"Hello Goby!".match(/Go+/).pre_match`, "Hello "},
+		{`"Hello Goby!".match(/Go+/).post_match`, "by!"},
+		{`"日本語テキスト".match(/語/).pre_match`, "日本"},
+		{`"日本語テキスト".match(/語/).post_match`, "テキスト"},
+		{`"2018-05".match(/(?<year>\d+)-(?<month>\d+)/).names`, []interface{}{"year", "month"}},
+		{`"2018-05".match(/(\d+)/).names`, []interface{}{}},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
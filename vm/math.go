@@ -0,0 +1,275 @@
+package vm
+
+import (
+	"math"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// Math is a module of mathematical constants and functions. The functions take Numerics,
+// return Floats, and can be called on the module or on classes including it.
+// Arguments out of a function's domain raise a DomainError.
+//
+// ```ruby
+// Math.sqrt(16)    # => 4.0
+// Math.hypot(3, 4) # => 5.0
+// Math.log(8, 2)   # => 3.0
+// Math::PI         # => 3.141592653589793
+// Math.sqrt(-1)    # => DomainError
+// ```
+//
+
+// Class methods --------------------------------------------------------
+func builtinMathClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns the arc cosine of the Numeric, which must be between -1 and 1.
+			//
+			// ```ruby
+			// Math.acos(1) # => 0.0
+			// ```
+			// @param x [Numeric]
+			// @return [Float]
+			Name: "acos",
+			Fn: mathFunction("acos", 1, 1, func(x []float64) (float64, bool) {
+				return math.Acos(x[0]), !(x[0] < -1 || x[0] > 1)
+			}),
+		},
+		{
+			// Returns the arc sine of the Numeric, which must be between -1 and 1.
+			//
+			// ```ruby
+			// Math.asin(1) # => 1.5707963267948966
+			// ```
+			// @param x [Numeric]
+			// @return [Float]
+			Name: "asin",
+			Fn: mathFunction("asin", 1, 1, func(x []float64) (float64, bool) {
+				return math.Asin(x[0]), !(x[0] < -1 || x[0] > 1)
+			}),
+		},
+		{
+			// Returns the arc tangent of the Numeric.
+			//
+			// ```ruby
+			// Math.atan(1) # => 0.7853981633974483
+			// ```
+			// @param x [Numeric]
+			// @return [Float]
+			Name: "atan",
+			Fn: mathFunction("atan", 1, 1, func(x []float64) (float64, bool) {
+				return math.Atan(x[0]), true
+			}),
+		},
+		{
+			// Returns the arc tangent of y / x, using the signs of both to find the quadrant.
+			//
+			// ```ruby
+			// Math.atan2(1, -1) # => 2.356194490192345
+			// ```
+			// @param y [Numeric], x [Numeric]
+			// @return [Float]
+			Name: "atan2",
+			Fn: mathFunction("atan2", 2, 2, func(x []float64) (float64, bool) {
+				return math.Atan2(x[0], x[1]), true
+			}),
+		},
+		{
+			// Returns the cube root of the Numeric.
+			//
+			// ```ruby
+			// Math.cbrt(27) # => 3.0
+			// Math.cbrt(-8) # => -2.0
+			// ```
+			// @param x [Numeric]
+			// @return [Float]
+			Name: "cbrt",
+			Fn: mathFunction("cbrt", 1, 1, func(x []float64) (float64, bool) {
+				return math.Cbrt(x[0]), true
+			}),
+		},
+		{
+			// Returns the cosine of the Numeric in radians.
+			//
+			// ```ruby
+			// Math.cos(0)        # => 1.0
+			// Math.cos(Math::PI) # => -1.0
+			// ```
+			// @param x [Numeric]
+			// @return [Float]
+			Name: "cos",
+			Fn: mathFunction("cos", 1, 1, func(x []float64) (float64, bool) {
+				return math.Cos(x[0]), true
+			}),
+		},
+		{
+			// Returns e raised to the power of the Numeric.
+			//
+			// ```ruby
+			// Math.exp(0) # => 1.0
+			// Math.exp(1) # => 2.718281828459045
+			// ```
+			// @param x [Numeric]
+			// @return [Float]
+			Name: "exp",
+			Fn: mathFunction("exp", 1, 1, func(x []float64) (float64, bool) {
+				return math.Exp(x[0]), true
+			}),
+		},
+		{
+			// Returns the length of the hypotenuse of a right triangle with the given sides, which is
+			// `Math.sqrt(x ** 2 + y ** 2)` without overflowing or underflowing.
+			//
+			// ```ruby
+			// Math.hypot(3, 4) # => 5.0
+			// ```
+			// @param x [Numeric], y [Numeric]
+			// @return [Float]
+			Name: "hypot",
+			Fn: mathFunction("hypot", 2, 2, func(x []float64) (float64, bool) {
+				return math.Hypot(x[0], x[1]), true
+			}),
+		},
+		{
+			// Returns the natural logarithm of the Numeric, or its logarithm in the given base.
+			// The Numeric must not be negative.
+			//
+			// ```ruby
+			// Math.log(1)       # => 0.0
+			// Math.log(Math::E) # => 1.0
+			// Math.log(8, 2)    # => 3.0
+			// Math.log(0)       # => -Infinity
+			// ```
+			// @param x [Numeric], base [Numeric]
+			// @return [Float]
+			Name: "log",
+			Fn: mathFunction("log", 1, 2, func(x []float64) (float64, bool) {
+				if len(x) == 2 {
+					return math.Log(x[0]) / math.Log(x[1]), !(x[0] < 0 || x[1] < 0)
+				}
+
+				return math.Log(x[0]), !(x[0] < 0)
+			}),
+		},
+		{
+			// Returns the base 10 logarithm of the Numeric, which must not be negative.
+			//
+			// ```ruby
+			// Math.log10(1000) # => 3.0
+			// ```
+			// @param x [Numeric]
+			// @return [Float]
+			Name: "log10",
+			Fn: mathFunction("log10", 1, 1, func(x []float64) (float64, bool) {
+				return math.Log10(x[0]), !(x[0] < 0)
+			}),
+		},
+		{
+			// Returns the base 2 logarithm of the Numeric, which must not be negative.
+			//
+			// ```ruby
+			// Math.log2(8) # => 3.0
+			// ```
+			// @param x [Numeric]
+			// @return [Float]
+			Name: "log2",
+			Fn: mathFunction("log2", 1, 1, func(x []float64) (float64, bool) {
+				return math.Log2(x[0]), !(x[0] < 0)
+			}),
+		},
+		{
+			// Returns the sine of the Numeric in radians.
+			//
+			// ```ruby
+			// Math.sin(0)            # => 0.0
+			// Math.sin(Math::PI / 2) # => 1.0
+			// ```
+			// @param x [Numeric]
+			// @return [Float]
+			Name: "sin",
+			Fn: mathFunction("sin", 1, 1, func(x []float64) (float64, bool) {
+				return math.Sin(x[0]), true
+			}),
+		},
+		{
+			// Returns the square root of the Numeric, which must not be negative.
+			//
+			// ```ruby
+			// Math.sqrt(16) # => 4.0
+			// Math.sqrt(2)  # => 1.4142135623730951
+			// ```
+			// @param x [Numeric]
+			// @return [Float]
+			Name: "sqrt",
+			Fn: mathFunction("sqrt", 1, 1, func(x []float64) (float64, bool) {
+				return math.Sqrt(x[0]), !(x[0] < 0)
+			}),
+		},
+		{
+			// Returns the tangent of the Numeric in radians.
+			//
+			// ```ruby
+			// Math.tan(0) # => 0.0
+			// ```
+			// @param x [Numeric]
+			// @return [Float]
+			Name: "tan",
+			Fn: mathFunction("tan", 1, 1, func(x []float64) (float64, bool) {
+				return math.Tan(x[0]), true
+			}),
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initMathModule() *RClass {
+	module := vm.initializeModule(classes.MathModule)
+	module.setBuiltinMethods(builtinMathClassMethods(), true)
+	return module
+}
+
+// initMathConstants sets the Math module's constants, which needs the Float class to be initialized.
+func (vm *VM) initMathConstants() {
+	module := vm.topLevelClass(classes.MathModule)
+	module.constants["PI"] = &Pointer{Target: vm.initFloatObject(math.Pi)}
+	module.constants["E"] = &Pointer{Target: vm.initFloatObject(math.E)}
+}
+
+// Other helper functions -----------------------------------------------
+
+// mathFunction returns the body of a Math method, which converts its Numeric arguments to floats and
+// returns the result of fn as a Float. fn returns false if the arguments are out of its domain.
+func mathFunction(name string, minArgs int, maxArgs int, fn func(x []float64) (float64, bool)) func(receiver Object, sourceLine int) builtinMethodBody {
+	return func(receiver Object, sourceLine int) builtinMethodBody {
+		return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) < minArgs || len(args) > maxArgs {
+				if minArgs == maxArgs {
+					return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, minArgs, len(args))
+				}
+
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRangeFormat, minArgs, maxArgs, len(args))
+			}
+
+			x := make([]float64, len(args))
+			for i, arg := range args {
+				n, ok := arg.(Numeric)
+				if !ok {
+					return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Numeric", arg.Class().Name)
+				}
+
+				x[i] = n.floatValue()
+			}
+
+			result, ok := fn(x)
+			if !ok {
+				return t.vm.InitErrorObject(errors.DomainError, sourceLine, "Numerical argument is out of domain - %s", name)
+			}
+
+			return t.vm.initFloatObject(result)
+		}
+	}
+}
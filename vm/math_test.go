@@ -0,0 +1,92 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestMathModuleFunctions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Math.sqrt(16)`, 4.0},
+		{`Math.sqrt(2.25)`, 1.5},
+		{`Math.cbrt(-8)`, -2.0},
+		{`Math.hypot(3, 4)`, 5.0},
+		{`Math.log(1)`, 0.0},
+		{`Math.log(Math::E)`, 1.0},
+		{`Math.log(8, 2)`, 3.0},
+		{`Math.log(0).infinite?`, -1},
+		{`Math.log2(8)`, 3.0},
+		{`Math.log10(1000)`, 3.0},
+		{`Math.exp(0)`, 1.0},
+		{`Math.sin(0)`, 0.0},
+		{`Math.sin(Math::PI / 2)`, 1.0},
+		{`Math.cos(Math::PI)`, -1.0},
+		{`Math.tan(0)`, 0.0},
+		{`Math.asin(1) * 2 == Math::PI`, true},
+		{`Math.acos(1)`, 0.0},
+		{`Math.atan(1) * 4 == Math::PI`, true},
+		{`Math.atan2(0, -1) == Math::PI`, true},
+		{`Math.sqrt(Float::NAN).nan?`, true},
+		{`
+	class Circle
+	  include Math
+
+	  def area(r)
+	    Math::PI * sqrt(r ** 4)
+	  end
+	end
+
+	Circle.new.area(1) == Math::PI
+	`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestMathModuleFunctionsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Math.sqrt(-1)`, "DomainError: Numerical argument is out of domain - sqrt", 1},
+		{`Math.log(-1)`, "DomainError: Numerical argument is out of domain - log", 1},
+		{`Math.log(8, -2)`, "DomainError: Numerical argument is out of domain - log", 1},
+		{`Math.acos(2)`, "DomainError: Numerical argument is out of domain - acos", 1},
+		{`Math.sqrt("4")`, "TypeError: Expect argument to be Numeric. got: String", 1},
+		{`Math.sqrt("4".to_d)`, "TypeError: Expect argument to be Numeric. got: Decimal", 1},
+		{`Math.hypot(3)`, "ArgumentError: Expect 2 arguments. got: 1", 1},
+		{`Math.log(1, 2, 3)`, "ArgumentError: Expect 1..2 arguments. got: 3", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestMathModuleConstants(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Math::PI`, 3.141592653589793},
+		{`Math::E`, 2.718281828459045},
+		{`Math.class.name`, "Module"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
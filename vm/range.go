@@ -203,7 +203,9 @@ func builtinRangeInstanceMethods() []*BuiltinMethodObject {
 								start = mid + 1
 							}
 						case *IntegerObject:
-							if r.value == 0 {
+							sign := r.comparisonValue()
+
+							if sign == 0 {
 								return t.vm.InitIntegerObject(mid)
 							}
 
@@ -211,7 +213,7 @@ func builtinRangeInstanceMethods() []*BuiltinMethodObject {
 								return NULL
 							}
 
-							if r.value > 0 {
+							if sign > 0 {
 								start = mid + 1
 							} else {
 								end = mid - 1
@@ -307,7 +309,14 @@ func builtinRangeInstanceMethods() []*BuiltinMethodObject {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 					ran := receiver.(*RangeObject)
 
-					value := args[0].(*IntegerObject).value
+					n := args[0].(*IntegerObject)
+
+					// A Range's ends are ints, so it can't include a larger Integer
+					if n.bigValue != nil {
+						return FALSE
+					}
+
+					value := n.value
 					ascendRangeBool := ran.Start <= ran.End && value >= ran.Start && value <= ran.End
 					descendRangeBool := ran.End <= ran.Start && value <= ran.Start && value >= ran.End
 
@@ -440,7 +449,12 @@ func builtinRangeInstanceMethods() []*BuiltinMethodObject {
 						return t.vm.InitErrorObject(errors.InternalError, sourceLine, errors.CantYieldWithoutBlockFormat)
 					}
 
-					stepValue := args[0].(*IntegerObject).value
+					stepValue, err := args[0].(*IntegerObject).intValue(t, sourceLine)
+
+					if err != nil {
+						return err
+					}
+
 					if stepValue == 0 {
 						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Step can't be 0")
 					} else if stepValue < 0 {
@@ -470,6 +484,56 @@ func builtinRangeInstanceMethods() []*BuiltinMethodObject {
 				}
 			},
 		},
+		{
+			// Returns the sum of the values of the range, added to the initial value, which defaults to 0.
+			// When a block is given, the values it returns are summed. Without a block, the sum is
+			// calculated without iterating the range.
+			//
+			// ```ruby
+			// (1..100).sum   # => 5050
+			// (1..3).sum(10) # => 16
+			// (-1..-3).sum   # => -6
+			// (1..3).sum do |i|
+			//   i * 2
+			// end            # => 12
+			// ```
+			//
+			// @return [Object]
+			Name: "sum",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) > 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRangeFormat, 0, 1, len(args))
+					}
+
+					ran := receiver.(*RangeObject)
+					var sum Object = t.vm.InitIntegerObject(0)
+
+					if len(args) == 1 {
+						sum = args[0]
+					}
+
+					if blockFrame == nil {
+						count := ran.End - ran.Start
+
+						if count < 0 {
+							count = -count
+						}
+
+						return t.add(sum, t.vm.InitIntegerObject((ran.Start+ran.End)*(count+1)/2), sourceLine)
+					}
+
+					ran.each(func(i int) error {
+						result := t.builtinMethodYield(blockFrame, t.vm.InitIntegerObject(i))
+						sum = t.add(sum, result.Target, sourceLine)
+
+						return nil
+					})
+
+					return sum
+				}
+			},
+		},
 		{
 			// Returns an Array object that contains the values of the range.
 			//
@@ -63,6 +63,11 @@ func TestRangeBsearchMethod(t *testing.T) {
 		input    string
 		expected interface{}
 	}{
+		{`
+		(0..4).bsearch do |i|
+			(3 - i) * (2 ** 64)
+		end
+		`, 3},
 		{`
 		ary = [0, 4, 7, 10, 12]
 		(0..4).bsearch do |i|
@@ -326,6 +331,9 @@ func TestRangeIncludeMethod(t *testing.T) {
 		(5..10).include?(4)
 		`, false},
 		{`
+		(5..10).include?(2 ** 64)
+		`, false},
+		{`
 		(-5..1).include?(-2)
 		`, true},
 		{`
@@ -470,6 +478,32 @@ func TestRangeStepMethod(t *testing.T) {
 	}
 }
 
+func TestRangeSumMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`(1..100).sum`, 5050},
+		{`(1..3).sum(10)`, 16},
+		{`(-1..-3).sum`, -6},
+		{`(2..2).sum`, 2},
+		{`(1..2).sum(0.5)`, 3.5},
+		{`
+		(1..3).sum do |i|
+		  i * 2
+		end
+		`, 12},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestRangeToStringMethod(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -577,6 +611,21 @@ func TestRangeMapMethodFail(t *testing.T) {
 	}
 }
 
+func TestRangeIntegerLimitsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`(0..2 ** 64)`, "RangeError: 18446744073709551616 is out of range", 1},
+		{`(0..4).step(2 ** 64) do |i| end`, "RangeError: 18446744073709551616 is out of range", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestRangeToEnumMethod(t *testing.T) {
 	input := `
 	iterated_values = []
@@ -1,6 +1,9 @@
 package vm
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/dlclark/regexp2"
 	"github.com/goby-lang/goby/vm/classes"
 	"github.com/goby-lang/goby/vm/errors"
@@ -24,8 +27,15 @@ import (
 // c = Regexp.new("居(ら(?=れ)|さ(?=せ)|る|ろ|れ(?=[ばる])|よ|(?=な[いかくけそ]|ま[しすせ]|そう|た|て))")
 // c.match?("居られればいいのに")  #=> true
 // c.match?("居ずまいを正す")      #=> false
+//
+// d = /goby (?<version>\d+)/i
+// d.match?("GOBY 1")         #=> true
 // ```
 //
+// Regexp literals take the options `i` (ignore case), `m` (`.` matches newlines) and `x` (ignore whitespace
+// and comments in the pattern), which are `Regexp::IGNORECASE`, `Regexp::MULTILINE` and `Regexp::EXTENDED`
+// for `Regexp.new`.
+//
 // **Note:**
 //
 // - Currently, manipulations are based upon Golang's Unicode manipulations.
@@ -33,32 +43,63 @@ import (
 // - `Regexp.new` is exceptionally supported.
 //
 // **To Goby maintainers**: avoid using Go's standard regexp package (slow and not rich). Consider the faster `Trim` or `Split` etc in Go's "strings" package first, or just use the dlclark/regexp2 instead.
-// ToDo: Regexp literals with '/.../'
 type Regexp = regexp2.Regexp
 type RegexpObject struct {
 	*baseObj
-	regexp *Regexp
+	regexp  *Regexp
+	options int
 }
 
+// The options of Regexp, which have the same values as Ruby's
+const (
+	regexpIgnoreCase = 1
+	regexpExtended   = 2
+	regexpMultiline  = 4
+)
+
 // Class methods --------------------------------------------------------
 func builtInRegexpClassMethods() []*BuiltinMethodObject {
 	return []*BuiltinMethodObject{
 		{
+			// Returns a Regexp with the pattern. The options can be an Integer of `Regexp::IGNORECASE`,
+			// `Regexp::EXTENDED` and `Regexp::MULTILINE` added together, or a String of `i`, `x` and `m`.
+			//
+			// ```ruby
+			// Regexp.new("goby[0-9]+")
+			// Regexp.new("goby", Regexp::IGNORECASE).match?("GOBY") # => true
+			// Regexp.new("goby", "i").match?("GOBY")                # => true
+			// ```
+			//
+			// @param pattern [String], options [Integer/String]
+			// @return [Regexp]
 			Name: "new",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-					if len(args) != 1 {
-						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect 1 argument. got=%d", len(args))
+					if len(args) < 1 || len(args) > 2 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRangeFormat, 1, 2, len(args))
 					}
 
 					arg, ok := args[0].(*StringObject)
 					if !ok {
-						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, arg.Class().Name)
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
 					}
 
-					r := t.vm.initRegexpObject(args[0].toString())
+					options := 0
+
+					if len(args) == 2 {
+						switch o := args[1].(type) {
+						case *IntegerObject:
+							options = o.value
+						case *StringObject:
+							options = regexpOptions(o.value)
+						default:
+							return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Integer or String", args[1].Class().Name)
+						}
+					}
+
+					r := t.vm.initRegexpObject(arg.value, options)
 					if r == nil {
-						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Invalid regexp: %v", args[0].toString())
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Invalid regexp: %v", arg.value)
 					}
 					return r
 				}
@@ -101,7 +142,7 @@ func builtinRegexpInstanceMethods() []*BuiltinMethodObject {
 
 					left := receiver.(*RegexpObject)
 
-					if left.Value() == right.Value() {
+					if left.Value() == right.Value() && left.options == right.options {
 						return TRUE
 					}
 					return FALSE
@@ -140,6 +181,75 @@ func builtinRegexpInstanceMethods() []*BuiltinMethodObject {
 				}
 			},
 		},
+		{
+			// Returns the names of the named captures.
+			//
+			// ```ruby
+			// /(?<year>\d+)-(?<month>\d+)/.names # => ["year", "month"]
+			// /(\d+)/.names                      # => []
+			// ```
+			//
+			// @return [Array]
+			Name: "names",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					names := []Object{}
+
+					for _, name := range receiver.(*RegexpObject).regexp.GetGroupNames() {
+						if isGroupName(name) {
+							names = append(names, t.vm.InitStringObject(name))
+						}
+					}
+
+					return t.vm.InitArrayObject(names)
+				}
+			},
+		},
+		{
+			// Returns the options as an Integer, which is `Regexp::IGNORECASE`, `Regexp::EXTENDED` and
+			// `Regexp::MULTILINE` added together.
+			//
+			// ```ruby
+			// /goby/.options                           # => 0
+			// /goby/i.options                          # => 1
+			// /goby/mix.options == Regexp::IGNORECASE + Regexp::EXTENDED + Regexp::MULTILINE # => true
+			// ```
+			//
+			// @return [Integer]
+			Name: "options",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return t.vm.InitIntegerObject(receiver.(*RegexpObject).options)
+				}
+			},
+		},
+		{
+			// Returns the pattern.
+			//
+			// ```ruby
+			// /goby[0-9]+/i.source # => "goby[0-9]+"
+			// ```
+			//
+			// @return [String]
+			Name: "source",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return t.vm.InitStringObject(receiver.(*RegexpObject).regexp.String())
+				}
+			},
+		},
 	}
 }
 
@@ -147,14 +257,28 @@ func builtinRegexpInstanceMethods() []*BuiltinMethodObject {
 
 // Functions for initialization -----------------------------------------
 
-func (vm *VM) initRegexpObject(regexp string) *RegexpObject {
-	r, err := regexp2.Compile(regexp, 0)
+func (vm *VM) initRegexpObject(regexp string, options int) *RegexpObject {
+	var compileOptions regexp2.RegexOptions
+
+	if options&regexpIgnoreCase != 0 {
+		compileOptions |= regexp2.IgnoreCase
+	}
+	if options&regexpExtended != 0 {
+		compileOptions |= regexp2.IgnorePatternWhitespace
+	}
+	// Ruby's multiline mode is .NET's single line mode, where `.` matches newlines
+	if options&regexpMultiline != 0 {
+		compileOptions |= regexp2.Singleline
+	}
+
+	r, err := regexp2.Compile(regexp, compileOptions)
 	if err != nil {
 		return nil
 	}
 	return &RegexpObject{
 		baseObj: &baseObj{class: vm.topLevelClass(classes.RegexpClass)},
 		regexp:  r,
+		options: options,
 	}
 }
 
@@ -165,6 +289,66 @@ func (vm *VM) initRegexpClass() *RClass {
 	return rc
 }
 
+// initRegexpOptions sets the options' constants, which needs the Integer class to be initialized.
+func (vm *VM) initRegexpOptions() {
+	rc := vm.topLevelClass(classes.RegexpClass)
+	rc.constants["IGNORECASE"] = &Pointer{Target: vm.InitIntegerObject(regexpIgnoreCase)}
+	rc.constants["EXTENDED"] = &Pointer{Target: vm.InitIntegerObject(regexpExtended)}
+	rc.constants["MULTILINE"] = &Pointer{Target: vm.InitIntegerObject(regexpMultiline)}
+}
+
+// Other helper functions -----------------------------------------------
+
+// regexpOptions converts the options of a regexp literal, like "im", to an Integer.
+func regexpOptions(flags string) int {
+	options := 0
+
+	if strings.Contains(flags, "i") {
+		options |= regexpIgnoreCase
+	}
+	if strings.Contains(flags, "x") {
+		options |= regexpExtended
+	}
+	if strings.Contains(flags, "m") {
+		options |= regexpMultiline
+	}
+
+	return options
+}
+
+// isGroupName tells if a group name is given by the pattern, instead of being the group's number.
+func isGroupName(name string) bool {
+	_, err := strconv.Atoi(name)
+	return err != nil
+}
+
+// toRegexp returns the pattern as a regexp. A String pattern matches itself literally.
+func toRegexp(pattern Object) (*Regexp, bool) {
+	switch pattern := pattern.(type) {
+	case *RegexpObject:
+		return pattern.regexp, true
+	case *StringObject:
+		return regexp2.MustCompile(regexp2.Escape(pattern.value), 0), true
+	}
+
+	return nil, false
+}
+
+// findMatches returns at most limit non-overlapping matches of the regexp in the text, or all of them
+// if limit is negative.
+func findMatches(re *Regexp, text string, limit int) []*Match {
+	var matches []*Match
+
+	m, _ := re.FindStringMatch(text)
+
+	for m != nil && (limit < 0 || len(matches) < limit) {
+		matches = append(matches, m)
+		m, _ = re.FindNextMatch(m)
+	}
+
+	return matches
+}
+
 // Polymorphic helper functions -----------------------------------------
 
 // Value returns the object
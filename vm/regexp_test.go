@@ -183,3 +183,92 @@ func TestRegexpMatchMethodFail(t *testing.T) {
 		v.checkSP(t, i, 1)
 	}
 }
+
+func TestRegexpLiteral(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`/goby[0-9]+/.class.name`, "Regexp"},
+		{`/o/.match?("Goby")`, true},
+		{`/O/.match?("Goby")`, false},
+		{`/O/i.match?("Goby")`, true},
+		{`/a.b/.match?("a\nb")`, false},
+		{`/a.b/m.match?("a\nb")`, true},
+		{`/a b # comment
+		/x.match?("ab")`, true},
+		{`/a\/b/.source`, "a/b"},
+		{`/\d+/ == Regexp.new("\\d+")`, true},
+		{`/a/i == /a/`, false},
+		{`
+		r = nil
+		if "Goby" =~ /b/
+		  r = /b/
+		end
+		r.source
+		`, "b"},
+		{`["a1", "b"].map do |s| /\d/.match?(s) end`, []interface{}{true, false}},
+		// Slashes are still divisions after values
+		{`10 / 2`, 5},
+		{`
+		a = [10, 2]
+		a[0] / a[1] / 5
+		`, 1},
+		{`(6 / 3)`, 2},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestRegexpSourceOptionsAndNamesMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`/goby[0-9]+/i.source`, "goby[0-9]+"},
+		{`/goby/.options`, 0},
+		{`/goby/i.options`, 1},
+		{`/goby/x.options`, 2},
+		{`/goby/m.options`, 4},
+		{`/goby/mix.options == Regexp::IGNORECASE + Regexp::EXTENDED + Regexp::MULTILINE`, true},
+		{`Regexp.new("goby", Regexp::IGNORECASE).match?("GOBY")`, true},
+		{`Regexp.new("go by", Regexp::EXTENDED).match?("goby")`, true},
+		{`Regexp.new("goby", "i") == /goby/i`, true},
+		{`Regexp.new("goby", 5).options`, 5},
+		{`/(?<year>\d+)-(?<month>\d+)/.names`, []interface{}{"year", "month"}},
+		{`/(\d+)/.names`, []interface{}{}},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestRegexpClassCreationFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Regexp.new`, "ArgumentError: Expect 1..2 arguments. got: 0", 1},
+		{`Regexp.new(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`Regexp.new("a", nil)`, "TypeError: Expect argument to be Integer or String. got: Null", 1},
+		{`Regexp.new("(")`, "ArgumentError: Invalid regexp: (", 1},
+		{`/(/`, "ArgumentError: Invalid regexp: (", 1},
+		{`/a/.source(1)`, "ArgumentError: Expect 0 arguments. got: 1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
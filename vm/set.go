@@ -0,0 +1,1005 @@
+package vm
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// SetObject is an unordered collection of unique objects. It iterates its elements in the order they were added,
+// and includes Enumerable.
+//
+// Unlike Hash keys, elements can be any objects. Integers, Floats, Strings, Arrays, Hashes, Sets and the other
+// builtin values are the same element when they're of the same class and their values are equal, so `[1, "a"]`
+// is only added once, but `1` and `1.0` are different elements.
+// Objects of classes that define a `hash` method returning an Integer, like `def hash; [@x, @y].hash; end`, are the
+// same element when their hashes are equal and `eql?` returns true, or `==` if they don't define `eql?`. Any other
+// object is only the same element as itself.
+//
+// ```ruby
+// s = Set.new([1, 2, 2, 3])
+// s.size                     # => 3
+// s.add(4).include?(4)       # => true
+// (s | Set.new([5])).to_a    # => [1, 2, 3, 4, 5]
+// (s & Set.new([2, 9])).to_a # => [2]
+// (s - Set.new([1])).to_a    # => [2, 3, 4]
+// Set.new([1]).subset?(s)    # => true
+// s.map do |x| x * 2 end     # => [2, 4, 6, 8]
+// ```
+//
+// Elements that are mutated after being added, like Arrays, keep the key of their value at the time.
+type SetObject struct {
+	*baseObj
+	// buckets are the elements by their keys. Elements with the same key that aren't eql?, which only
+	// user-defined `hash` methods produce, share a bucket.
+	buckets map[string][]Object
+	// keys are the keys of the buckets in the order they were added
+	keys []string
+}
+
+// Class methods --------------------------------------------------------
+func builtinSetClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns a Set of the elements of an Array, a Set, or an object that has `to_a`, like a Range.
+			// Without an argument, it returns an empty Set.
+			//
+			// ```ruby
+			// Set.new.size               # => 0
+			// Set.new([1, 1, 2]).to_a    # => [1, 2]
+			// Set.new(1..3).to_a         # => [1, 2, 3]
+			// ```
+			// @param elements [Array/Set/Object]
+			// @return [Set]
+			Name: "new",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) > 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRangeFormat, 0, 1, len(args))
+					}
+
+					var elements []Object
+
+					if len(args) == 1 {
+						switch arg := args[0].(type) {
+						case *ArrayObject:
+							elements = arg.Elements
+						case *SetObject:
+							elements = arg.elements()
+						default:
+							if arg.findMethod("to_a") == nil {
+								return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Enumerable", arg.Class().Name)
+							}
+
+							result := t.callMethod(arg, "to_a", sourceLine)
+							if err, ok := result.(*Error); ok {
+								return err
+							}
+
+							arr, ok := result.(*ArrayObject)
+							if !ok {
+								return t.vm.InitErrorObject(errors.TypeError, sourceLine, "Expect to_a to return Array. got: %s", result.Class().Name)
+							}
+
+							elements = arr.Elements
+						}
+					}
+
+					return t.initSetObject(elements, sourceLine)
+				}
+			},
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinSetInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns a new Set of the elements in both self and another Set or Array.
+			//
+			// ```ruby
+			// (Set.new([1, 2, 3]) & Set.new([2, 3, 4])).to_a # => [2, 3]
+			// (Set.new([1, 2, 3]) & [3, 1]).to_a             # => [1, 3]
+			// ```
+			// @param other [Set/Array]
+			// @return [Set]
+			Name: "&",
+			Fn: setOperation(func(t *Thread, s *SetObject, other *SetObject, sourceLine int) Object {
+				return s.filter(t, sourceLine, func(element Object) (bool, *Error) {
+					return other.contains(t, element, sourceLine)
+				})
+			}),
+		},
+		{
+			// Returns a new Set of the elements in self that aren't in another Set or Array.
+			//
+			// ```ruby
+			// (Set.new([1, 2, 3]) - Set.new([2])).to_a # => [1, 3]
+			// ```
+			// @param other [Set/Array]
+			// @return [Set]
+			Name: "-",
+			Fn: setOperation(func(t *Thread, s *SetObject, other *SetObject, sourceLine int) Object {
+				return s.filter(t, sourceLine, func(element Object) (bool, *Error) {
+					found, err := other.contains(t, element, sourceLine)
+					return !found, err
+				})
+			}),
+		},
+		{
+			// Adds an object to self and returns self. Adding an element that's already in self does nothing.
+			//
+			// ```ruby
+			// s = Set.new
+			// s << 1 << 1 << 2
+			// s.to_a # => [1, 2]
+			// ```
+			// @param element [Object]
+			// @return [Set]
+			Name: "<<",
+			Fn:   setAdd,
+		},
+		{
+			// Returns true if every element of self is in another Set.
+			//
+			// ```ruby
+			// Set.new([1, 2]) <= Set.new([1, 2, 3]) # => true
+			// Set.new([1, 4]) <= Set.new([1, 2, 3]) # => false
+			// ```
+			// @param other [Set]
+			// @return [Boolean]
+			Name: "<=",
+			Fn: setPredicate(func(t *Thread, s *SetObject, other *SetObject, sourceLine int) (bool, *Error) {
+				return s.isSubset(t, other, sourceLine)
+			}),
+		},
+		{
+			// Returns true if another object is a Set with the same elements as self, in any order.
+			//
+			// ```ruby
+			// Set.new([1, 2]) == Set.new([2, 1]) # => true
+			// Set.new([1, 2]) == [1, 2]          # => false
+			// ```
+			// @param other [Object]
+			// @return [Boolean]
+			Name: "==",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					s := receiver.(*SetObject)
+					other, ok := args[0].(*SetObject)
+					if !ok || s.size() != other.size() {
+						return FALSE
+					}
+
+					equal, err := s.isSubset(t, other, sourceLine)
+					if err != nil {
+						return err
+					}
+
+					return toBooleanObject(equal)
+				}
+			},
+		},
+		{
+			// Returns true if every element of another Set is in self.
+			//
+			// ```ruby
+			// Set.new([1, 2, 3]) >= Set.new([1, 2]) # => true
+			// ```
+			// @param other [Set]
+			// @return [Boolean]
+			Name: ">=",
+			Fn: setPredicate(func(t *Thread, s *SetObject, other *SetObject, sourceLine int) (bool, *Error) {
+				return other.isSubset(t, s, sourceLine)
+			}),
+		},
+		{
+			// Returns a new Set of the elements that are in either self or another Set or Array, but not both.
+			//
+			// ```ruby
+			// (Set.new([1, 2, 3]) ^ Set.new([3, 4])).to_a # => [1, 2, 4]
+			// ```
+			// @param other [Set/Array]
+			// @return [Set]
+			Name: "^",
+			Fn: setOperation(func(t *Thread, s *SetObject, other *SetObject, sourceLine int) Object {
+				elements := []Object{}
+
+				for _, pair := range [][2]*SetObject{{s, other}, {other, s}} {
+					for _, element := range pair[0].elements() {
+						found, err := pair[1].contains(t, element, sourceLine)
+						if err != nil {
+							return err
+						}
+
+						if !found {
+							elements = append(elements, element)
+						}
+					}
+				}
+
+				return t.initSetObject(elements, sourceLine)
+			}),
+		},
+		{
+			// Returns a new Set of the elements in self or another Set or Array.
+			//
+			// ```ruby
+			// (Set.new([1, 2]) | Set.new([2, 3])).to_a # => [1, 2, 3]
+			// (Set.new([1, 2]) | [4]).to_a             # => [1, 2, 4]
+			// ```
+			// @param other [Set/Array]
+			// @return [Set]
+			Name: "|",
+			Fn: setOperation(func(t *Thread, s *SetObject, other *SetObject, sourceLine int) Object {
+				return t.initSetObject(append(s.elements(), other.elements()...), sourceLine)
+			}),
+		},
+		{
+			// Adds an object to self and returns self. Adding an element that's already in self does nothing.
+			//
+			// ```ruby
+			// Set.new([1]).add(2).add(1).to_a # => [1, 2]
+			// ```
+			// @param element [Object]
+			// @return [Set]
+			Name: "add",
+			Fn:   setAdd,
+		},
+		{
+			// Adds an object to self and returns self, or returns nil if the object is already in self.
+			//
+			// ```ruby
+			// Set.new([1]).add?(2).to_a # => [1, 2]
+			// Set.new([1]).add?(1)      # => nil
+			// ```
+			// @param element [Object]
+			// @return [Set]
+			Name: "add?",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					added, err := receiver.(*SetObject).add(t, args[0], sourceLine)
+					if err != nil {
+						return err
+					}
+
+					if !added {
+						return NULL
+					}
+
+					return receiver
+				}
+			},
+		},
+		{
+			// Removes all elements from self and returns self.
+			//
+			// ```ruby
+			// Set.new([1, 2]).clear.size # => 0
+			// ```
+			// @return [Set]
+			Name: "clear",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					s := receiver.(*SetObject)
+					s.buckets = map[string][]Object{}
+					s.keys = nil
+
+					return s
+				}
+			},
+		},
+		{
+			// Removes an object from self and returns self. Removing an object that isn't in self does nothing.
+			//
+			// ```ruby
+			// Set.new([1, 2]).delete(1).to_a # => [2]
+			// Set.new([1, 2]).delete(3).to_a # => [1, 2]
+			// ```
+			// @param element [Object]
+			// @return [Set]
+			Name: "delete",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					if err := receiver.(*SetObject).delete(t, args[0], sourceLine); err != nil {
+						return err
+					}
+
+					return receiver
+				}
+			},
+		},
+		{
+			// Returns true if self and another Set have no elements in common.
+			//
+			// ```ruby
+			// Set.new([1, 2]).disjoint?(Set.new([3])) # => true
+			// Set.new([1, 2]).disjoint?(Set.new([2])) # => false
+			// ```
+			// @param other [Set]
+			// @return [Boolean]
+			Name: "disjoint?",
+			Fn: setPredicate(func(t *Thread, s *SetObject, other *SetObject, sourceLine int) (bool, *Error) {
+				intersect, err := s.intersects(t, other, sourceLine)
+				return !intersect, err
+			}),
+		},
+		{
+			// Yields each element of self in the order they were added, and returns self.
+			//
+			// ```ruby
+			// sum = 0
+			// Set.new([1, 2, 2]).each do |x|
+			//   sum += x
+			// end
+			// sum # => 3
+			// ```
+			// @param block [Block]
+			// @return [Set]
+			Name: "each",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					if blockFrame == nil {
+						return t.vm.InitErrorObject(errors.InternalError, sourceLine, errors.CantYieldWithoutBlockFormat)
+					}
+
+					s := receiver.(*SetObject)
+					if blockIsEmpty(blockFrame) {
+						return s
+					}
+
+					// The block may change self, so it iterates a copy of the elements
+					elements := s.elements()
+
+					// If it's an empty set, pop the block's call frame
+					if len(elements) == 0 {
+						t.callFrameStack.pop()
+					}
+
+					for _, element := range elements {
+						t.builtinMethodYield(blockFrame, element)
+					}
+
+					return s
+				}
+			},
+		},
+		{
+			// Returns true if self has no elements.
+			//
+			// ```ruby
+			// Set.new.empty?    # => true
+			// Set.new([1]).empty? # => false
+			// ```
+			// @return [Boolean]
+			Name: "empty?",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return toBooleanObject(receiver.(*SetObject).size() == 0)
+				}
+			},
+		},
+		{
+			// Returns true if an object is an element of self.
+			//
+			// ```ruby
+			// Set.new([1, [2, 3]]).include?([2, 3]) # => true
+			// Set.new([1, [2, 3]]).include?(2)      # => false
+			// ```
+			// @param element [Object]
+			// @return [Boolean]
+			Name: "include?",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					found, err := receiver.(*SetObject).contains(t, args[0], sourceLine)
+					if err != nil {
+						return err
+					}
+
+					return toBooleanObject(found)
+				}
+			},
+		},
+		{
+			// Returns true if self and another Set have at least one element in common.
+			//
+			// ```ruby
+			// Set.new([1, 2]).intersect?(Set.new([2, 3])) # => true
+			// ```
+			// @param other [Set]
+			// @return [Boolean]
+			Name: "intersect?",
+			Fn: setPredicate(func(t *Thread, s *SetObject, other *SetObject, sourceLine int) (bool, *Error) {
+				return s.intersects(t, other, sourceLine)
+			}),
+		},
+		{
+			// Returns the number of elements in self.
+			//
+			// ```ruby
+			// Set.new([1, 1, 2]).length # => 2
+			// ```
+			// @return [Integer]
+			Name: "length",
+			Fn:   setSize,
+		},
+		{
+			// Returns the number of elements in self.
+			//
+			// ```ruby
+			// Set.new([1, 1, 2]).size # => 2
+			// ```
+			// @return [Integer]
+			Name: "size",
+			Fn:   setSize,
+		},
+		{
+			// Returns true if every element of self is in another Set.
+			//
+			// ```ruby
+			// Set.new([1, 2]).subset?(Set.new([1, 2, 3])) # => true
+			// Set.new.subset?(Set.new([1]))               # => true
+			// ```
+			// @param other [Set]
+			// @return [Boolean]
+			Name: "subset?",
+			Fn: setPredicate(func(t *Thread, s *SetObject, other *SetObject, sourceLine int) (bool, *Error) {
+				return s.isSubset(t, other, sourceLine)
+			}),
+		},
+		{
+			// Returns true if every element of another Set is in self.
+			//
+			// ```ruby
+			// Set.new([1, 2, 3]).superset?(Set.new([3])) # => true
+			// ```
+			// @param other [Set]
+			// @return [Boolean]
+			Name: "superset?",
+			Fn: setPredicate(func(t *Thread, s *SetObject, other *SetObject, sourceLine int) (bool, *Error) {
+				return other.isSubset(t, s, sourceLine)
+			}),
+		},
+		{
+			// Returns the elements of self in an Array, in the order they were added.
+			//
+			// ```ruby
+			// Set.new([3, 1, 3, 2]).to_a # => [3, 1, 2]
+			// ```
+			// @return [Array]
+			Name: "to_a",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return t.vm.InitArrayObject(receiver.(*SetObject).elements())
+				}
+			},
+		},
+		{
+			// Returns the elements of self as a JSON array.
+			//
+			// ```ruby
+			// Set.new([1, "a"]).to_json # => "[1,\"a\"]"
+			// ```
+			// @return [String]
+			Name: "to_json",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return t.vm.InitStringObject(receiver.(*SetObject).toJSON(t))
+				}
+			},
+		},
+		{
+			// Returns self formatted like "#<Set: {1, 2, 3}>".
+			//
+			// ```ruby
+			// Set.new([1, "a"]).to_s # => "#<Set: {1, \"a\"}>"
+			// ```
+			// @return [String]
+			Name: "to_s",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return t.vm.InitStringObject(receiver.(*SetObject).toString())
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+// initSetObject returns a Set of the elements, or an Error if a user-defined `hash` or `eql?` method fails.
+func (t *Thread) initSetObject(elements []Object, sourceLine int) Object {
+	s := &SetObject{
+		baseObj: &baseObj{class: t.vm.topLevelClass(classes.SetClass)},
+		buckets: map[string][]Object{},
+	}
+
+	for _, element := range elements {
+		if _, err := s.add(t, element, sourceLine); err != nil {
+			return err
+		}
+	}
+
+	return s
+}
+
+func (vm *VM) initSetClass() *RClass {
+	sc := vm.initializeClass(classes.SetClass)
+	sc.setBuiltinMethods(builtinSetInstanceMethods(), false)
+	sc.setBuiltinMethods(builtinSetClassMethods(), true)
+	vm.libFiles = append(vm.libFiles, "set.gb")
+	return sc
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// Value returns the elements of the set
+func (s *SetObject) Value() interface{} {
+	return s.elements()
+}
+
+// toString returns the object's elements formatted like "#<Set: {1, 2, 3}>"
+func (s *SetObject) toString() string {
+	var out bytes.Buffer
+	elements := []string{}
+
+	for _, element := range s.elements() {
+		if str, ok := element.(*StringObject); ok {
+			elements = append(elements, strconv.Quote(str.value))
+		} else {
+			elements = append(elements, element.toString())
+		}
+	}
+
+	out.WriteString("#<Set: {")
+	out.WriteString(strings.Join(elements, ", "))
+	out.WriteString("}>")
+
+	return out.String()
+}
+
+// toJSON returns the object's elements as a JSON array
+func (s *SetObject) toJSON(t *Thread) string {
+	var out bytes.Buffer
+	elements := []string{}
+
+	for _, element := range s.elements() {
+		elements = append(elements, element.toJSON(t))
+	}
+
+	out.WriteString("[")
+	out.WriteString(strings.Join(elements, ","))
+	out.WriteString("]")
+
+	return out.String()
+}
+
+// elements returns the elements of the set in the order they were added.
+func (s *SetObject) elements() []Object {
+	elements := []Object{}
+
+	for _, key := range s.keys {
+		elements = append(elements, s.buckets[key]...)
+	}
+
+	return elements
+}
+
+// size returns the number of elements in the set.
+func (s *SetObject) size() int {
+	size := 0
+
+	for _, bucket := range s.buckets {
+		size += len(bucket)
+	}
+
+	return size
+}
+
+// find returns the key of the object and the index of the element that's the same as it in the key's bucket,
+// or -1 if there's no such element.
+func (s *SetObject) find(t *Thread, obj Object, sourceLine int) (string, int, *Error) {
+	key, userDefined, err := t.setKey(obj, sourceLine)
+	if err != nil {
+		return "", -1, err
+	}
+
+	bucket := s.buckets[key]
+
+	// Keys that don't come from user-defined `hash` methods are only equal for the same elements
+	if !userDefined {
+		if len(bucket) == 0 {
+			return key, -1, nil
+		}
+
+		return key, 0, nil
+	}
+
+	for i, element := range bucket {
+		equal, err := t.eqlElements(obj, element, sourceLine)
+		if err != nil {
+			return "", -1, err
+		}
+
+		if equal {
+			return key, i, nil
+		}
+	}
+
+	return key, -1, nil
+}
+
+// contains returns true if the object is an element of the set.
+func (s *SetObject) contains(t *Thread, obj Object, sourceLine int) (bool, *Error) {
+	_, index, err := s.find(t, obj, sourceLine)
+	return index >= 0, err
+}
+
+// add adds the object to the set, and returns false if it's already an element.
+func (s *SetObject) add(t *Thread, obj Object, sourceLine int) (bool, *Error) {
+	key, index, err := s.find(t, obj, sourceLine)
+	if err != nil || index >= 0 {
+		return false, err
+	}
+
+	if _, ok := s.buckets[key]; !ok {
+		s.keys = append(s.keys, key)
+	}
+
+	s.buckets[key] = append(s.buckets[key], obj)
+
+	return true, nil
+}
+
+// delete removes the object from the set if it's an element.
+func (s *SetObject) delete(t *Thread, obj Object, sourceLine int) *Error {
+	key, index, err := s.find(t, obj, sourceLine)
+	if err != nil || index < 0 {
+		return err
+	}
+
+	bucket := s.buckets[key]
+	bucket = append(bucket[:index:index], bucket[index+1:]...)
+
+	if len(bucket) > 0 {
+		s.buckets[key] = bucket
+		return nil
+	}
+
+	delete(s.buckets, key)
+
+	for i, k := range s.keys {
+		if k == key {
+			s.keys = append(s.keys[:i:i], s.keys[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+// filter returns a new Set of the elements of the set that the function returns true for.
+func (s *SetObject) filter(t *Thread, sourceLine int, keep func(element Object) (bool, *Error)) Object {
+	elements := []Object{}
+
+	for _, element := range s.elements() {
+		ok, err := keep(element)
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			elements = append(elements, element)
+		}
+	}
+
+	return t.initSetObject(elements, sourceLine)
+}
+
+// isSubset returns true if every element of the set is an element of the other set.
+func (s *SetObject) isSubset(t *Thread, other *SetObject, sourceLine int) (bool, *Error) {
+	if s.size() > other.size() {
+		return false, nil
+	}
+
+	for _, element := range s.elements() {
+		found, err := other.contains(t, element, sourceLine)
+		if err != nil || !found {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// intersects returns true if the set and the other set have an element in common.
+func (s *SetObject) intersects(t *Thread, other *SetObject, sourceLine int) (bool, *Error) {
+	for _, element := range s.elements() {
+		found, err := other.contains(t, element, sourceLine)
+		if err != nil || found {
+			return found, err
+		}
+	}
+
+	return false, nil
+}
+
+// Other helper functions -----------------------------------------------
+
+// setKey returns the key of an object in a Set, which is the same for builtin values that are equal.
+// Objects with a user-defined `hash` method get a key from its result, and it returns true for them,
+// or for Arrays, Hashes and Sets that contain them, which means elements with the key need to be
+// compared with eqlElements. Other objects get a key of their own.
+func (t *Thread) setKey(obj Object, sourceLine int) (string, bool, *Error) {
+	if _, ok := obj.findMethod("hash").(*MethodObject); !ok {
+		return t.valueKey(obj, sourceLine)
+	}
+
+	result := t.callMethod(obj, "hash", sourceLine)
+	if err, ok := result.(*Error); ok {
+		return "", false, err
+	}
+
+	hash, ok := result.(*IntegerObject)
+	if !ok {
+		return "", false, t.vm.InitErrorObject(errors.TypeError, sourceLine, "Expect hash to return Integer. got: %s", result.Class().Name)
+	}
+
+	return "hash:" + hash.toString(), true, nil
+}
+
+// valueKey returns the key of an object in a Set from its value, without calling a user-defined `hash` method
+// of the object itself.
+func (t *Thread) valueKey(obj Object, sourceLine int) (string, bool, *Error) {
+	switch obj := obj.(type) {
+	case *IntegerObject:
+		return "Integer:" + obj.toString(), false, nil
+	case *FloatObject:
+		value := obj.value
+		// 0.0 and -0.0 are equal
+		if value == 0 {
+			value = 0
+		}
+
+		return "Float:" + strconv.FormatUint(math.Float64bits(value), 16), false, nil
+	case *StringObject:
+		return "String:" + strconv.Quote(obj.value), false, nil
+	case *DecimalObject:
+		// Decimals are equal to the Integers of their values
+		if obj.value.IsInt() {
+			return "Integer:" + obj.value.Num().String(), false, nil
+		}
+
+		return "Decimal:" + obj.toString(), false, nil
+	case *BooleanObject, *NullObject, *RangeObject, *DateObject:
+		return obj.Class().Name + ":" + obj.toString(), false, nil
+	case *TimeObject:
+		return "Time:" + obj.value.UTC().Format(time.RFC3339Nano), false, nil
+	case *DurationObject:
+		return "Duration:" + strconv.FormatInt(int64(obj.value), 10), false, nil
+	case *ArrayObject:
+		return t.setKeyOf("Array", obj.Elements, false, sourceLine)
+	case *HashObject:
+		elements := []Object{}
+
+		for _, key := range obj.sortedKeys() {
+			elements = append(elements, t.vm.InitStringObject(key), obj.Pairs[key])
+		}
+
+		return t.setKeyOf("Hash", elements, false, sourceLine)
+	case *SetObject:
+		return t.setKeyOf("Set", obj.elements(), true, sourceLine)
+	}
+
+	return fmt.Sprintf("Object:%p", obj), false, nil
+}
+
+// hashValue returns the Integer that the builtin `hash` methods return for an object, which is computed from
+// its key in a Set, so objects that are the same element of a Set have the same hash.
+func (t *Thread) hashValue(obj Object, sourceLine int) Object {
+	key, _, err := t.valueKey(obj, sourceLine)
+	if err != nil {
+		return err
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(key))
+
+	return t.vm.InitIntegerObject(int(h.Sum64()))
+}
+
+// setKeyOf returns the key of a collection of the elements. The order of the elements is ignored if unordered is true.
+func (t *Thread) setKeyOf(className string, elements []Object, unordered bool, sourceLine int) (string, bool, *Error) {
+	keys := []string{}
+	userDefined := false
+
+	for _, element := range elements {
+		key, u, err := t.setKey(element, sourceLine)
+		if err != nil {
+			return "", false, err
+		}
+
+		keys = append(keys, key)
+		userDefined = userDefined || u
+	}
+
+	if unordered {
+		sort.Strings(keys)
+	}
+
+	return className + ":[" + strings.Join(keys, ", ") + "]", userDefined, nil
+}
+
+// eqlElements returns true if two objects with the same key are the same element of a Set. Objects with
+// user-defined `hash` methods are compared with their `eql?` methods, or `==` if they don't have one.
+func (t *Thread) eqlElements(a, b Object, sourceLine int) (bool, *Error) {
+	switch a := a.(type) {
+	case *ArrayObject:
+		// The same keys mean the same number of elements
+		for i, element := range a.Elements {
+			equal, err := t.eqlElements(element, b.(*ArrayObject).Elements[i], sourceLine)
+			if err != nil || !equal {
+				return false, err
+			}
+		}
+
+		return true, nil
+	case *HashObject:
+		for key, value := range a.Pairs {
+			equal, err := t.eqlElements(value, b.(*HashObject).Pairs[key], sourceLine)
+			if err != nil || !equal {
+				return false, err
+			}
+		}
+
+		return true, nil
+	case *SetObject:
+		return a.isSubset(t, b.(*SetObject), sourceLine)
+	case *RObject:
+		method := "=="
+		if a.findMethod("eql?") != nil {
+			method = "eql?"
+		}
+
+		result := t.callMethod(a, method, sourceLine, b)
+		if err, ok := result.(*Error); ok {
+			return false, err
+		}
+
+		return result.isTruthy(), nil
+	default:
+		// Other objects have the same key only if they're equal
+		return true, nil
+	}
+}
+
+// setArg returns the Set of the elements of an argument that's a Set or an Array.
+func (t *Thread) setArg(arg Object, sourceLine int) Object {
+	switch arg := arg.(type) {
+	case *SetObject:
+		return arg
+	case *ArrayObject:
+		return t.initSetObject(arg.Elements, sourceLine)
+	default:
+		return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Set or Array", arg.Class().Name)
+	}
+}
+
+// setOperation returns the body of a Set method that returns a new Set from self and another Set or Array.
+func setOperation(operation func(t *Thread, s *SetObject, other *SetObject, sourceLine int) Object) func(receiver Object, sourceLine int) builtinMethodBody {
+	return func(receiver Object, sourceLine int) builtinMethodBody {
+		return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+			}
+
+			arg := t.setArg(args[0], sourceLine)
+			other, ok := arg.(*SetObject)
+			if !ok {
+				return arg
+			}
+
+			return operation(t, receiver.(*SetObject), other, sourceLine)
+		}
+	}
+}
+
+// setPredicate returns the body of a Set method that returns a Boolean about self and another Set.
+func setPredicate(predicate func(t *Thread, s *SetObject, other *SetObject, sourceLine int) (bool, *Error)) func(receiver Object, sourceLine int) builtinMethodBody {
+	return func(receiver Object, sourceLine int) builtinMethodBody {
+		return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+			}
+
+			other, ok := args[0].(*SetObject)
+			if !ok {
+				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.SetClass, args[0].Class().Name)
+			}
+
+			result, err := predicate(t, receiver.(*SetObject), other, sourceLine)
+			if err != nil {
+				return err
+			}
+
+			return toBooleanObject(result)
+		}
+	}
+}
+
+// setAdd is the body of `Set#add` and `Set#<<`.
+func setAdd(receiver Object, sourceLine int) builtinMethodBody {
+	return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+		if len(args) != 1 {
+			return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+		}
+
+		if _, err := receiver.(*SetObject).add(t, args[0], sourceLine); err != nil {
+			return err
+		}
+
+		return receiver
+	}
+}
+
+// setSize is the body of `Set#size` and `Set#length`.
+func setSize(receiver Object, sourceLine int) builtinMethodBody {
+	return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+		if len(args) != 0 {
+			return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+		}
+
+		return t.vm.InitIntegerObject(receiver.(*SetObject).size())
+	}
+}
@@ -0,0 +1,355 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestSetClassMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Set.new.size`, 0},
+		{`Set.new([]).empty?`, true},
+		{`Set.new([1, 1, 2]).to_a`, []interface{}{1, 2}},
+		{`Set.new([3, 1, 3, 2]).to_a`, []interface{}{3, 1, 2}},
+		{`Set.new(1..3).to_a`, []interface{}{1, 2, 3}},
+		{`Set.new(Set.new([1, 2])).size`, 2},
+		{`Set.new({ a: 1 }).to_a`, []interface{}{[]interface{}{"a", 1}}},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestSetClassMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Set.new(1)`, "TypeError: Expect argument to be Enumerable. got: Integer", 1},
+		{`Set.new([1], [2])`, "ArgumentError: Expect 0..1 arguments. got: 2", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestSetElementEquality(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Set.new([1, 1, "1", 1.0, 1.0]).size`, 3},
+		{`Set.new([0.0, -0.0]).size`, 1},
+		{`Set.new(["a", "a", "b"]).size`, 2},
+		{`Set.new([nil, nil, true, true, false]).size`, 3},
+		{`Set.new([[1, 2], [1, 2], [2, 1]]).size`, 2},
+		{`Set.new([[1, [2]], [1, [2]]]).size`, 1},
+		{`Set.new([["a, b"], ["a", "b"]]).size`, 2},
+		{`Set.new([{ a: 1 }, { a: 1 }, { a: 2 }]).size`, 2},
+		{`Set.new([Set.new([1, 2]), Set.new([2, 1])]).size`, 1},
+		{`Set.new([1..2, 1..2]).size`, 1},
+		{`Set.new(["1.5".to_d, "1.5".to_d]).size`, 1},
+		{`Set.new([1, 1.to_d]).size`, 1},
+		{`Set.new([1, "1.5".to_d]).size`, 2},
+		{`Set.new([Duration.seconds(60), Duration.minutes(1)]).size`, 1},
+		{`Set.new([Time.at(0, "UTC"), Time.at(0, "+09:00")]).size`, 1},
+		{`Set.new([Date.new(2024), Date.new(2024, 1, 1)]).size`, 1},
+		{`Set.new([2 ** 100, 2 ** 100, 2 ** 101]).size`, 2},
+		{`Set.new([1, (2 ** 64) / (2 ** 64)]).size`, 1},
+		{`Set.new([[1, 2]]).include?([1, 2])`, true},
+		{`
+		class Foo; end
+
+		foo = Foo.new
+		Set.new([foo, foo, Foo.new]).size
+		`, 2},
+		{`
+		class Point
+		  attr_reader :x, :y
+
+		  def initialize(x, y)
+		    @x = x
+		    @y = y
+		  end
+
+		  def hash
+		    @x * 31 + @y
+		  end
+
+		  def eql?(other)
+		    @x == other.x && @y == other.y
+		  end
+		end
+
+		# Point.new(1, 2) and Point.new(0, 33) have the same hash, but aren't eql?
+		s = Set.new([Point.new(1, 2), Point.new(1, 2), Point.new(0, 33)])
+		[s.size, s.include?(Point.new(0, 33)), s.include?(Point.new(33, 0))]
+		`, []interface{}{2, true, false}},
+		{`
+		class Point
+		  attr_reader :x, :y
+
+		  def initialize(x, y)
+		    @x = x
+		    @y = y
+		  end
+
+		  def hash
+		    0
+		  end
+
+		  def eql?(other)
+		    @x == other.x && @y == other.y
+		  end
+		end
+
+		s = Set.new([[Point.new(1, 2)], [Point.new(1, 2)], [Point.new(2, 1)]])
+		s.delete([Point.new(1, 2)])
+		s.size
+		`, 1},
+		{`
+		class Name
+		  attr_reader :value
+
+		  def initialize(value)
+		    @value = value
+		  end
+
+		  def hash
+		    @value.size
+		  end
+		end
+
+		# Without eql?, objects with the same hash are compared with ==
+		Set.new([Name.new("a"), Name.new("a"), Name.new("b")]).size
+		`, 2},
+		{`
+		class Key
+		  attr_reader :value
+
+		  def initialize(value)
+		    @value = value
+		  end
+
+		  def hash
+		    @value.hash
+		  end
+
+		  def eql?(other)
+		    @value == other.value
+		  end
+		end
+
+		s = Set.new([Key.new(1), Key.new(1), Key.new("1"), Key.new([1, "a"]), Key.new([1, "a"])])
+		[s.size, s.include?(Key.new("1")), s.include?(Key.new(2))]
+		`, []interface{}{3, true, false}},
+		{`
+		class Point
+		  def initialize(x, y)
+		    @x = x
+		    @y = y
+		  end
+
+		  def hash
+		    [@x, @y].hash
+		  end
+		end
+
+		Set.new([Point.new(1, 2), Point.new(1, 2), Point.new(2, 1)]).size
+		`, 2},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestSetElementEqualityFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`
+		class Foo
+		  def hash
+		    "foo"
+		  end
+		end
+
+		Set.new([Foo.new])
+		`, "TypeError: Expect hash to return Integer. got: String", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestSetInstanceMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Set.new([1]).add(2).add(1).to_a`, []interface{}{1, 2}},
+		{`
+		s = Set.new
+		s << 1 << 1 << 2
+		s.to_a
+		`, []interface{}{1, 2}},
+		{`Set.new([1]).add?(2).to_a`, []interface{}{1, 2}},
+		{`Set.new([1]).add?(1)`, nil},
+		{`Set.new([1, 2, 3]).delete(2).to_a`, []interface{}{1, 3}},
+		{`Set.new([1, 2]).delete(3).to_a`, []interface{}{1, 2}},
+		{`
+		s = Set.new([1, 2, 3])
+		s.delete(1)
+		s.add(1)
+		s.to_a
+		`, []interface{}{2, 3, 1}},
+		{`Set.new([1, 2]).clear.empty?`, true},
+		{`Set.new([1, [2, 3]]).include?([2, 3])`, true},
+		{`Set.new([1, [2, 3]]).include?(2)`, false},
+		{`Set.new([1, 1, 2]).size`, 2},
+		{`Set.new([1, 1, 2]).length`, 2},
+		{`Set.new([1, "a", nil]).to_s`, `#<Set: {1, "a", nil}>`},
+		{`Set.new([1, "a", [2]]).to_json`, `[1,"a",[2]]`},
+		{`{ ids: Set.new([1, 1]) }.to_json`, `{"ids":[1]}`},
+		{`
+		sum = 0
+		r = Set.new([1, 2, 2]).each do |x|
+		  sum += x
+		end
+		[sum, r.size]
+		`, []interface{}{3, 2}},
+		{`
+		Set.new.each do |x|
+		  x
+		end.size
+		`, 0},
+		{`
+		s = Set.new([1, 2])
+		s.each do |x|
+		  s.add(x + 10)
+		end
+		s.size
+		`, 4},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestSetInstanceMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Set.new.add`, "ArgumentError: Expect 1 arguments. got: 0", 1},
+		{`Set.new.size(1)`, "ArgumentError: Expect 0 arguments. got: 1", 1},
+		{`Set.new.each`, "InternalError: Can't yield without a block", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`(Set.new([1, 2]) | Set.new([2, 3])).to_a`, []interface{}{1, 2, 3}},
+		{`(Set.new([1, 2]) | [4, 4]).to_a`, []interface{}{1, 2, 4}},
+		{`(Set.new([1, 2, 3]) & Set.new([2, 3, 4])).to_a`, []interface{}{2, 3}},
+		{`(Set.new([1, 2, 3]) & [3, 1]).to_a`, []interface{}{1, 3}},
+		{`(Set.new([1, 2, 3]) - Set.new([2])).to_a`, []interface{}{1, 3}},
+		{`(Set.new([[1], [2]]) - [[1]]).to_a`, []interface{}{[]interface{}{2}}},
+		{`(Set.new([1, 2, 3]) ^ Set.new([3, 4])).to_a`, []interface{}{1, 2, 4}},
+		{`
+		a = Set.new([1, 2])
+		a | Set.new([3])
+		a.size
+		`, 2},
+		{`Set.new([1, 2]).subset?(Set.new([1, 2, 3]))`, true},
+		{`Set.new([1, 4]).subset?(Set.new([1, 2, 3]))`, false},
+		{`Set.new.subset?(Set.new)`, true},
+		{`Set.new([1, 2]) <= Set.new([1, 2])`, true},
+		{`Set.new([1, 2, 3]).superset?(Set.new([3]))`, true},
+		{`Set.new([1, 2, 3]) >= Set.new([4])`, false},
+		{`Set.new([1, 2]).disjoint?(Set.new([3]))`, true},
+		{`Set.new([1, 2]).intersect?(Set.new([2, 3]))`, true},
+		{`Set.new([1, 2]) == Set.new([2, 1])`, true},
+		{`Set.new([1, 2]) == Set.new([1])`, false},
+		{`Set.new([1, 2]) == [1, 2]`, false},
+		{`Set.new([1, 2]) != Set.new([1, 3])`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestSetOperationsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Set.new([1]) | 1`, "TypeError: Expect argument to be Set or Array. got: Integer", 1},
+		{`Set.new([1]) & "1"`, "TypeError: Expect argument to be Set or Array. got: String", 1},
+		{`Set.new([1]).subset?([1])`, "TypeError: Expect argument to be Set. got: Array", 1},
+		{`Set.new([1]) <= (1..2)`, "TypeError: Expect argument to be Set. got: Range", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestSetEnumerable(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Set.new([1, 2, 2, 3]).map do |x| x * 2 end`, []interface{}{2, 4, 6}},
+		{`Set.new([3, 1, 3, 2]).sort`, []interface{}{1, 2, 3}},
+		{`Set.new([1, 2, 3]).select do |x| x.odd? end`, []interface{}{1, 3}},
+		{`Set.new([1, 2, 3]).find do |x| x > 1 end`, 2},
+		{`Set.new([1, 2, 2, 3]).reduce(0) do |sum, x| sum + x end`, 6},
+		{`Set.new([1, 2, 3]).entries`, []interface{}{1, 2, 3}},
+		{`Set.new(["a", "b"]).any? do |x| x == "b" end`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
@@ -1,20 +1,28 @@
 package vm
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"io"
 	"io/ioutil"
 	"log"
+	"math"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
+	"time"
 	"unicode"
 
 	"fmt"
-	"strconv"
 
 	"github.com/fatih/structs"
 	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
 	"github.com/gorilla/mux"
 )
 
@@ -34,12 +42,13 @@ type response struct {
 	status      int
 	body        string
 	contentType string
+	headers     http.Header
+	cookies     []*http.Cookie
+	file        string
 }
 
 // Instance methods -----------------------------------------------------
 func builtinSimpleServerInstanceMethods() []*BuiltinMethodObject {
-	router := mux.NewRouter()
-
 	return []*BuiltinMethodObject{
 		{
 			Name: "mount",
@@ -48,7 +57,7 @@ func builtinSimpleServerInstanceMethods() []*BuiltinMethodObject {
 					path := args[0].(*StringObject).value
 					method := args[1].(*StringObject).value
 
-					router.HandleFunc(path, newHandler(t, blockFrame)).Methods(method)
+					routerFor(t, receiver).HandleFunc(path, newHandler(t, blockFrame)).Methods(method)
 
 					return receiver
 				}
@@ -60,64 +69,60 @@ func builtinSimpleServerInstanceMethods() []*BuiltinMethodObject {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 					prefix := args[0].(*StringObject).value
 					fileName := args[1].(*StringObject).value
-					router.PathPrefix(prefix).Handler(http.StripPrefix(prefix, http.FileServer(http.Dir(fileName))))
+					routerFor(t, receiver).PathPrefix(prefix).Handler(http.StripPrefix(prefix, http.FileServer(http.Dir(fileName))))
 
 					return receiver
 				}
 			},
 		},
 		{
-			Name: "start",
+			// Starts the server with the given options Hash. It's called by `SimpleServer#start`,
+			// which accepts the options as keyword arguments:
+			//
+			// ```ruby
+			// server.start(tls_cert: "cert.pem", tls_key: "key.pem", client_ca: "ca.pem", read_timeout: 5)
+			// server.start(socket: "/tmp/goby.sock")
+			// ```
+			//
+			// Supported options are host, socket, tls_cert, tls_key, client_ca, read_timeout,
+			// write_timeout, idle_timeout (all in seconds) and max_header_bytes.
+			// HTTP/2 is enabled automatically when the server is started with TLS.
+			Name: "listen",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-					var port string
-					var serveStatic bool
 					server := receiver.(*RObject)
+					opts := &HashObject{Pairs: map[string]Object{}}
 
-					portVar, ok := server.InstanceVariableGet("@port")
-
-					if !ok {
-						port = "8080"
-					} else {
-						switch p := portVar.(type) {
-						case *StringObject:
-							port = p.value
-						case *IntegerObject:
-							port = strconv.Itoa(p.value)
-						default:
-							fmt.Printf("Unexpected type %s for port setting\n", portVar.Class().Name)
+					if len(args) > 0 {
+						h, ok := args[0].(*HashObject)
+
+						if !ok {
+							return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.HashClass, args[0].Class().Name)
 						}
+
+						opts = h
 					}
 
-					log.Println("SimpleServer start listening on port: " + port)
+					config, err := newServerConfig(server, opts)
+
+					if err != nil {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, err.Error())
+					}
 
 					c := make(chan os.Signal, 1)
 					signal.Notify(c, os.Interrupt)
 
 					go func() {
 						for range c {
+							config.close()
 							log.Println("SimpleServer gracefully stopped")
 							os.Exit(0)
 						}
 					}()
 
-					fileRoot, serveStatic := server.InstanceVariables.get("@file_root")
+					config.server.Handler = newServerHandler(t, server, routerFor(t, server))
 
-					router.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-						log.Printf("%s %s %s %d\n", r.Method, r.URL.Path, r.Proto, 404)
-					})
-
-					if serveStatic && fileRoot.Class() != t.vm.objectClass.getClassConstant(classes.NullClass) {
-						fr := fileRoot.(*StringObject).value
-						currentDir, _ := os.Getwd()
-						fp := filepath.Join(currentDir, fr)
-						fs := http.FileServer(http.Dir(fp))
-						http.Handle("/", fs)
-					} else {
-						http.Handle("/", router)
-					}
-
-					err := http.ListenAndServe(":"+port, nil)
+					err = config.serve()
 
 					if err != http.ErrServerClosed { // HL
 						log.Fatalf("listen: %s\n", err)
@@ -127,6 +132,60 @@ func builtinSimpleServerInstanceMethods() []*BuiltinMethodObject {
 				}
 			},
 		},
+		{
+			// Dispatches a request to the server's routes without listening on a port, and returns a
+			// `Net::HTTP::Response` object. It's used by the `net/http/test` library:
+			//
+			// ```ruby
+			// require "net/http/test"
+			//
+			// app = Net::HTTP::Test.new(server)
+			// app.get("/users/1", headers: { Accept: "application/json" }).status_code # => 200
+			// ```
+			//
+			// @param method [String], path [String], headers [Hash], body [String]
+			// @return [Response]
+			Name: "handle",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 4 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 4, len(args))
+					}
+
+					for _, arg := range []Object{args[0], args[1], args[3]} {
+						if _, ok := arg.(*StringObject); !ok {
+							return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, arg.Class().Name)
+						}
+					}
+
+					method, path, body := args[0].(*StringObject).value, args[1].(*StringObject).value, args[3].(*StringObject).value
+
+					req, err := http.NewRequest(strings.ToUpper(method), path, strings.NewReader(body))
+					if err != nil {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, err.Error())
+					}
+
+					req.RequestURI = path
+					req.Host = "example.com"
+					req.RemoteAddr = "192.0.2.1:1234"
+
+					err = eachHashValue(args[2], func(key, value string) { req.Header.Add(key, value) })
+					if err != nil {
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, "Expect headers to be %s", err.Error())
+					}
+
+					recorder := httptest.NewRecorder()
+					newServerHandler(t, receiver.(*RObject), routerFor(t, receiver)).ServeHTTP(recorder, req)
+
+					res, err := responseGoToGoby(t, recorder.Result())
+					if err != nil {
+						return t.vm.InitErrorObject(errors.InternalError, sourceLine, err.Error())
+					}
+
+					return res
+				}
+			},
+		},
 	}
 }
 
@@ -146,6 +205,236 @@ func initSimpleServerClass(vm *VM) {
 
 // Other helper functions -----------------------------------------------
 
+// routerFor returns the server's own router, creating it the first time a route is mounted.
+func routerFor(t *Thread, server Object) *mux.Router {
+	if obj, ok := server.InstanceVariableGet("@go_router"); ok {
+		if router, ok := obj.Value().(*mux.Router); ok {
+			return router
+		}
+	}
+
+	router := mux.NewRouter()
+	router.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+		log.Printf("%s %s %s %d\n", r.Method, r.URL.Path, r.Proto, 404)
+	})
+	server.InstanceVariableSet("@go_router", t.vm.initGoObject(router))
+
+	return router
+}
+
+// newServerHandler returns the handler serving the server's routes, or its file_root when it's set.
+func newServerHandler(t *Thread, server *RObject, router *mux.Router) http.Handler {
+	serveMux := http.NewServeMux()
+	fileRoot, serveStatic := server.InstanceVariables.get("@file_root")
+
+	if serveStatic && fileRoot.Class() != t.vm.objectClass.getClassConstant(classes.NullClass) {
+		fr := fileRoot.(*StringObject).value
+		currentDir, _ := os.Getwd()
+		fp := filepath.Join(currentDir, fr)
+		fs := http.FileServer(http.Dir(fp))
+		serveMux.Handle("/", fs)
+	} else {
+		serveMux.Handle("/", router)
+	}
+
+	return serveMux
+}
+
+// serverConfig holds the http.Server and the listener settings built from SimpleServer's options.
+type serverConfig struct {
+	server  *http.Server
+	socket  string
+	tlsCert string
+	tlsKey  string
+}
+
+func newServerConfig(server *RObject, opts *HashObject) (*serverConfig, error) {
+	var host, port string
+	config := &serverConfig{server: &http.Server{}}
+
+	portVar, ok := server.InstanceVariableGet("@port")
+
+	if !ok {
+		port = "8080"
+	} else {
+		switch p := portVar.(type) {
+		case *StringObject:
+			port = p.value
+		case *IntegerObject:
+			port = p.toString()
+		default:
+			return nil, fmt.Errorf("Unexpected type %s for port setting", portVar.Class().Name)
+		}
+	}
+
+	for k, v := range opts.Pairs {
+		if v == NULL {
+			continue
+		}
+
+		switch k {
+		case "host", "socket", "tls_cert", "tls_key", "client_ca":
+			s, ok := v.(*StringObject)
+
+			if !ok {
+				return nil, fmt.Errorf("Expect %s to be a String. got: %s", k, v.Class().Name)
+			}
+
+			switch k {
+			case "host":
+				host = s.value
+			case "socket":
+				config.socket = s.value
+			case "tls_cert":
+				config.tlsCert = s.value
+			case "tls_key":
+				config.tlsKey = s.value
+			case "client_ca":
+				pool, err := loadCertPool(s.value)
+
+				if err != nil {
+					return nil, err
+				}
+
+				config.server.TLSConfig = &tls.Config{ClientCAs: pool, ClientAuth: tls.RequireAndVerifyClientCert}
+			}
+		case "read_timeout", "write_timeout", "idle_timeout":
+			d, err := toDuration(v)
+
+			if err != nil {
+				return nil, fmt.Errorf("Expect %s to be %s", k, err.Error())
+			}
+
+			switch k {
+			case "read_timeout":
+				config.server.ReadTimeout = d
+			case "write_timeout":
+				config.server.WriteTimeout = d
+			case "idle_timeout":
+				config.server.IdleTimeout = d
+			}
+		case "max_header_bytes":
+			i, ok := v.(*IntegerObject)
+
+			if !ok {
+				return nil, fmt.Errorf("Expect max_header_bytes to be an Integer. got: %s", v.Class().Name)
+			}
+
+			if i.bigValue != nil {
+				return nil, fmt.Errorf("Expect max_header_bytes to be at most %d. got: %s", math.MaxInt, i.toString())
+			}
+
+			config.server.MaxHeaderBytes = i.value
+		default:
+			return nil, fmt.Errorf("Unknown server option: %s", k)
+		}
+	}
+
+	if (config.tlsCert == "") != (config.tlsKey == "") {
+		return nil, fmt.Errorf("Both tls_cert and tls_key are required to serve TLS")
+	}
+
+	if config.server.TLSConfig != nil && config.tlsCert == "" {
+		return nil, fmt.Errorf("client_ca requires tls_cert and tls_key")
+	}
+
+	config.server.Addr = host + ":" + port
+
+	return config, nil
+}
+
+func (c *serverConfig) serve() error {
+	var l net.Listener
+	var err error
+
+	if len(c.socket) > 0 {
+		removeStaleSocket(c.socket)
+		l, err = net.Listen("unix", c.socket)
+		log.Println("SimpleServer start listening on socket: " + c.socket)
+	} else {
+		l, err = net.Listen("tcp", c.server.Addr)
+		log.Println("SimpleServer start listening on " + c.server.Addr)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if len(c.tlsCert) > 0 {
+		return c.server.ServeTLS(l, c.tlsCert, c.tlsKey)
+	}
+
+	return c.server.Serve(l)
+}
+
+// close removes the server's socket file, since exiting the process skips the listener's own cleanup.
+func (c *serverConfig) close() {
+	if len(c.socket) > 0 {
+		os.Remove(c.socket)
+	}
+}
+
+// removeStaleSocket removes a socket file left behind by a server that didn't shut down cleanly.
+// Sockets that still accept connections are kept, so listening on them fails as usual.
+func removeStaleSocket(path string) {
+	info, err := os.Stat(path)
+
+	if err != nil || info.Mode()&os.ModeSocket == 0 {
+		return
+	}
+
+	conn, err := net.Dial("unix", path)
+
+	if err == nil {
+		conn.Close()
+		return
+	}
+
+	os.Remove(path)
+}
+
+func loadCertPool(path string) (*x509.CertPool, error) {
+	pem, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't read client_ca %s: %s", path, err.Error())
+	}
+
+	pool := x509.NewCertPool()
+
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("Can't find any certificate in client_ca %s", path)
+	}
+
+	return pool, nil
+}
+
+// toDuration converts an Integer or a Float representing seconds into time.Duration.
+// Negative values are rejected, since a negative timeout would disable it.
+func toDuration(obj Object) (time.Duration, error) {
+	var seconds float64
+
+	switch v := obj.(type) {
+	case *IntegerObject:
+		seconds = v.floatValue()
+	case *FloatObject:
+		seconds = v.value
+	default:
+		return 0, fmt.Errorf("an Integer or a Float. got: %s", obj.Class().Name)
+	}
+
+	if seconds < 0 {
+		return 0, fmt.Errorf("a non-negative number of seconds. got: %s", obj.toString())
+	}
+
+	if math.IsNaN(seconds) || seconds > math.MaxInt64/float64(time.Second) {
+		return 0, fmt.Errorf("at most %d seconds. got: %s", math.MaxInt64/int64(time.Second), obj.toString())
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
 func newHandler(t *Thread, blockFrame *normalCallFrame) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Go creates one goroutine per request, so we also need to create a new Goby thread for every request.
@@ -199,38 +488,62 @@ func initRequest(t *Thread, w http.ResponseWriter, req *http.Request) *RObject {
 	}
 
 	reqObj.InstanceVariableSet("@params", t.vm.InitHashObject(vars))
+	reqObj.InstanceVariableSet("@headers", headersGoToGoby(t, req.Header))
+	reqObj.InstanceVariableSet("@query", queryGoToGoby(t, req.URL.Query()))
 
 	return reqObj
 }
 
-func setupResponse(w http.ResponseWriter, req *http.Request, res *RObject) {
-	r := &response{}
+// queryGoToGoby converts query parameters into a Hash. Parameters given more than once become Arrays.
+func queryGoToGoby(t *Thread, values url.Values) *HashObject {
+	query := map[string]Object{}
 
-	resStatus, ok := res.InstanceVariableGet("@status")
+	for k, vs := range values {
+		if len(vs) == 1 {
+			query[k] = t.vm.InitStringObject(vs[0])
+			continue
+		}
 
-	if ok {
-		r.status = resStatus.(*IntegerObject).value
-	} else {
-		r.status = http.StatusOK
-	}
+		elems := []Object{}
 
-	resBody, ok := res.InstanceVariableGet("@body")
+		for _, v := range vs {
+			elems = append(elems, t.vm.InitStringObject(v))
+		}
 
-	if !ok {
-		r.body = ""
-	} else {
-		r.body = resBody.(*StringObject).value
+		query[k] = t.vm.InitArrayObject(elems)
 	}
 
-	h, ok := res.InstanceVariableGet("@headers")
+	return t.vm.InitHashObject(query)
+}
 
-	if headers, isHashObject := h.(*HashObject); ok && isHashObject {
-		for k, v := range headers.Pairs {
-			w.Header().Set(k, v.(*StringObject).value)
+func setupResponse(w http.ResponseWriter, req *http.Request, res *RObject) {
+	r, err := buildResponse(res)
+
+	if err != nil {
+		log.Printf("Error: %s", err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		log.Printf("%s %s %s %d\n", req.Method, req.URL.Path, req.Proto, http.StatusInternalServerError)
+		return
+	}
+
+	for k, vs := range r.headers {
+		for _, v := range vs {
+			w.Header().Add(k, v)
 		}
-	} else {
-		r.contentType = "text/plain; charset=utf-8"
-		w.Header().Set("Content-Type", r.contentType) // normal header
+	}
+
+	for _, c := range r.cookies {
+		http.SetCookie(w, c)
+	}
+
+	if len(r.file) > 0 {
+		http.ServeFile(w, req, r.file)
+		log.Printf("%s %s %s file: %s\n", req.Method, req.URL.Path, req.Proto, r.file)
+		return
+	}
+
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", r.contentType)
 	}
 
 	w.WriteHeader(r.status)
@@ -239,6 +552,207 @@ func setupResponse(w http.ResponseWriter, req *http.Request, res *RObject) {
 	log.Printf("%s %s %s %d\n", req.Method, req.URL.Path, req.Proto, r.status)
 }
 
+// buildResponse collects the response's attributes set by the Goby handler and validates their types.
+func buildResponse(res *RObject) (*response, error) {
+	r := &response{status: http.StatusOK, contentType: "text/plain; charset=utf-8", headers: http.Header{}}
+
+	if resStatus, ok := res.InstanceVariableGet("@status"); ok && resStatus != NULL {
+		status, err := statusCode(resStatus)
+
+		if err != nil {
+			return nil, err
+		}
+
+		r.status = status
+	}
+
+	if resBody, ok := res.InstanceVariableGet("@body"); ok && resBody != NULL {
+		body, isString := resBody.(*StringObject)
+
+		if !isString {
+			return nil, fmt.Errorf("Expect response body to be a String. got: %s", resBody.Class().Name)
+		}
+
+		r.body = body.value
+	}
+
+	if h, ok := res.InstanceVariableGet("@headers"); ok && h != NULL {
+		headers, isHash := h.(*HashObject)
+
+		if !isHash {
+			return nil, fmt.Errorf("Expect response headers to be a Hash. got: %s", h.Class().Name)
+		}
+
+		for k, v := range headers.Pairs {
+			switch v := v.(type) {
+			case *StringObject:
+				r.headers.Add(k, v.value)
+			case *ArrayObject:
+				for _, elem := range v.Elements {
+					s, isString := elem.(*StringObject)
+
+					if !isString {
+						return nil, fmt.Errorf("Expect values of header \"%s\" to be String. got: %s", k, elem.Class().Name)
+					}
+
+					r.headers.Add(k, s.value)
+				}
+			default:
+				return nil, fmt.Errorf("Expect header \"%s\" to be a String or an Array. got: %s", k, v.Class().Name)
+			}
+		}
+	}
+
+	if c, ok := res.InstanceVariableGet("@cookies"); ok && c != NULL {
+		cookies, isArray := c.(*ArrayObject)
+
+		if !isArray {
+			return nil, fmt.Errorf("Expect response cookies to be an Array. got: %s", c.Class().Name)
+		}
+
+		for _, elem := range cookies.Elements {
+			cookie, err := buildCookie(elem)
+
+			if err != nil {
+				return nil, err
+			}
+
+			r.cookies = append(r.cookies, cookie)
+		}
+	}
+
+	if f, ok := res.InstanceVariableGet("@file"); ok && f != NULL {
+		file, isString := f.(*StringObject)
+
+		if !isString {
+			return nil, fmt.Errorf("Expect file path to be a String. got: %s", f.Class().Name)
+		}
+
+		r.file = file.value
+	}
+
+	return r, nil
+}
+
+// statusCode converts an Integer status or a status symbol like `:not_found` into an HTTP status code.
+func statusCode(obj Object) (int, error) {
+	switch s := obj.(type) {
+	case *IntegerObject:
+		// net/http panics on codes outside of this range
+		if s.bigValue != nil || s.value < 100 || s.value > 999 {
+			return 0, fmt.Errorf("Invalid response status: %s", s.toString())
+		}
+
+		return s.value, nil
+	case *StringObject:
+		code, ok := httpStatusCodes[s.value]
+
+		if !ok {
+			return 0, fmt.Errorf("Unknown response status: %s", s.value)
+		}
+
+		return code, nil
+	default:
+		return 0, fmt.Errorf("Expect response status to be an Integer or a String. got: %s", obj.Class().Name)
+	}
+}
+
+func buildCookie(obj Object) (*http.Cookie, error) {
+	h, ok := obj.(*HashObject)
+
+	if !ok {
+		return nil, fmt.Errorf("Expect cookie to be a Hash. got: %s", obj.Class().Name)
+	}
+
+	cookie := &http.Cookie{}
+
+	for k, v := range h.Pairs {
+		switch k {
+		case "name", "value", "path", "domain", "same_site":
+			s, ok := v.(*StringObject)
+
+			if !ok {
+				return nil, fmt.Errorf("Expect cookie's %s to be a String. got: %s", k, v.Class().Name)
+			}
+
+			switch k {
+			case "name":
+				cookie.Name = s.value
+			case "value":
+				cookie.Value = s.value
+			case "path":
+				cookie.Path = s.value
+			case "domain":
+				cookie.Domain = s.value
+			case "same_site":
+				switch strings.ToLower(s.value) {
+				case "strict":
+					cookie.SameSite = http.SameSiteStrictMode
+				case "lax":
+					cookie.SameSite = http.SameSiteLaxMode
+				case "none":
+					cookie.SameSite = http.SameSiteNoneMode
+				default:
+					return nil, fmt.Errorf("Unknown cookie same_site option: %s", s.value)
+				}
+			}
+		case "max_age":
+			i, ok := v.(*IntegerObject)
+
+			if !ok {
+				return nil, fmt.Errorf("Expect cookie's max_age to be an Integer. got: %s", v.Class().Name)
+			}
+
+			if i.bigValue != nil {
+				return nil, fmt.Errorf("Expect cookie's max_age to be at most %d. got: %s", math.MaxInt, i.toString())
+			}
+
+			cookie.MaxAge = i.value
+		case "secure":
+			cookie.Secure = v.isTruthy()
+		case "http_only":
+			cookie.HttpOnly = v.isTruthy()
+		default:
+			return nil, fmt.Errorf("Unknown cookie option: %s", k)
+		}
+	}
+
+	return cookie, nil
+}
+
+// httpStatusCodes maps status symbols like `not_found` or `created` to their status codes.
+var httpStatusCodes = func() map[string]int {
+	codes := map[string]int{}
+
+	for code := 100; code < 600; code++ {
+		text := http.StatusText(code)
+
+		if text == "" {
+			continue
+		}
+
+		codes[statusSymbol(text)] = code
+	}
+
+	return codes
+}()
+
+// statusSymbol turns a status text like "Non-Authoritative Information" into "non_authoritative_information".
+func statusSymbol(text string) string {
+	var out []rune
+	text = strings.Replace(text, "'", "", -1)
+
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			out = append(out, r)
+		} else if len(out) > 0 && out[len(out)-1] != '_' {
+			out = append(out, '_')
+		}
+	}
+
+	return strings.TrimRight(string(out), "_")
+}
+
 func toSnakeCase(in string) string {
 	runes := []rune(in)
 	length := len(runes)
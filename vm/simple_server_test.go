@@ -1,9 +1,22 @@
 package vm
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
 	"io/ioutil"
+	"math/big"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -171,3 +184,423 @@ func TestServerRequestInitialization(t *testing.T) {
 	}
 
 }
+
+func TestSetupResponseHelpers(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedStatus  int
+		expectedBody    string
+		expectedHeaders map[string][]string
+	}{
+		{`
+		res.json({ id: 1 })
+		`, 200, `{"id":1}`, map[string][]string{"Content-Type": {"application/json"}}},
+		{`
+		res.redirect("/login")
+		`, 302, "", map[string][]string{"Location": {"/login"}}},
+		{`
+		res.redirect("/new", :moved_permanently)
+		`, 301, "", map[string][]string{"Location": {"/new"}}},
+		{`
+		res.status = :not_found
+		res.body = "oops"
+		`, 404, "oops", map[string][]string{"Content-Type": {"text/plain; charset=utf-8"}}},
+		{`
+		res.add_header("Vary", "Accept")
+		res.add_header("Vary", "Origin")
+		res.set_header("Content-Type", "text/csv")
+		`, 200, "", map[string][]string{"Vary": {"Accept", "Origin"}, "Content-Type": {"text/csv"}}},
+		{`
+		res.set_cookie("session", "abc", { path: "/", max_age: 60, http_only: true })
+		`, 200, "", map[string][]string{"Set-Cookie": {"session=abc; Path=/; Max-Age=60; HttpOnly"}}},
+		{`
+		res.body = 10
+		`, 500, "Internal Server Error\n", map[string][]string{}},
+		{`
+		res.status = :no_such_status
+		`, 500, "Internal Server Error\n", map[string][]string{}},
+		{`
+		res.instance_variable_set("@status", 1000)
+		`, 500, "Internal Server Error\n", map[string][]string{}},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		input := `
+		require "net/simple_server"
+
+		res = Net::HTTP::Response.new
+		` + tt.input + `
+		res
+		`
+		res := v.testEval(t, input, getFilename()).(*RObject)
+		recorder := httptest.NewRecorder()
+		setupResponse(recorder, httptest.NewRequest("GET", "/", nil), res)
+
+		if recorder.Code != tt.expectedStatus {
+			t.Fatalf("At test case %d: Expect response code to be %d. got=%d", i, tt.expectedStatus, recorder.Code)
+		}
+
+		if recorder.Body.String() != tt.expectedBody {
+			t.Fatalf("At test case %d: Expect response body to be %q. got=%q", i, tt.expectedBody, recorder.Body.String())
+		}
+
+		for k, expected := range tt.expectedHeaders {
+			values := recorder.Result().Header[k]
+
+			if strings.Join(values, ",") != strings.Join(expected, ",") {
+				t.Fatalf("At test case %d: Expect header %s to be %v. got=%v", i, k, expected, values)
+			}
+		}
+	}
+}
+
+func TestResponseStatusFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`res.status = 99`, "ArgumentError: 'Invalid response status: 99'", 2},
+		{`res.status = 1000`, "ArgumentError: 'Invalid response status: 1000'", 2},
+		{`res.redirect("/", 7)`, "ArgumentError: 'Invalid response status: 7'", 3},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		input := `
+		require "net/simple_server"
+
+		res = Net::HTTP::Response.new
+		` + tt.input
+		evaluated := v.testEval(t, input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+	}
+}
+
+func TestStatusSymbol(t *testing.T) {
+	tests := map[string]string{
+		"Not Found":                     "not_found",
+		"Non-Authoritative Information": "non_authoritative_information",
+		"I'm a teapot":                  "im_a_teapot",
+		"HTTP Version Not Supported":    "http_version_not_supported",
+	}
+
+	for text, expected := range tests {
+		if s := statusSymbol(text); s != expected {
+			t.Fatalf("Expect status symbol of %q to be %s. got=%s", text, expected, s)
+		}
+	}
+}
+
+func TestServerStartWithTLS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goby-tls")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	ca, caKey := generateTestCert(t, dir, "ca", nil, nil)
+	generateTestCert(t, dir, "server", ca, caKey)
+	clientCert, _ := generateTestCert(t, dir, "client", ca, caKey)
+
+	serverScript := fmt.Sprintf(`
+	require "net/simple_server"
+
+	server = Net::SimpleServer.new(4001)
+	server.get "/" do |req, res|
+	  res.body = req.protocol
+	end
+
+	server.start(host: "127.0.0.1", tls_cert: "%[1]s/server.pem", tls_key: "%[1]s/server-key.pem", client_ca: "%[1]s/ca.pem", read_timeout: 5, idle_timeout: 1.5)
+	`, dir)
+
+	go func() {
+		v := initTestVM()
+		v.testEval(t, serverScript, getFilename())
+	}()
+
+	time.Sleep(1 * time.Second)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	clientPair, err := tls.LoadX509KeyPair(filepath.Join(dir, "client.pem"), filepath.Join(dir, "client-key.pem"))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{RootCAs: pool, Certificates: []tls.Certificate{clientPair}},
+		ForceAttemptHTTP2: true,
+	}}
+
+	resp, err := client.Get("https://127.0.0.1:4001/")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+
+	if resp.ProtoMajor != 2 || string(body) != "HTTP/2.0" {
+		t.Fatalf("Expect the request to be served with HTTP/2. got=%s (%s)", resp.Proto, body)
+	}
+
+	if clientCert.Subject.CommonName != "client" {
+		t.Fatalf("Unexpected client certificate %s", clientCert.Subject.CommonName)
+	}
+
+	// Requests without a client certificate should be rejected
+	client = &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+
+	if _, err := client.Get("https://127.0.0.1:4001/"); err == nil {
+		t.Fatal("Expect request without client certificate to fail")
+	}
+}
+
+func TestServerStartWithUnixSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goby-socket")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	socket := filepath.Join(dir, "goby.sock")
+
+	// Leave a stale socket behind, like a server that was killed would
+	stale, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	serverScript := fmt.Sprintf(`
+	require "net/simple_server"
+
+	server = Net::SimpleServer.new(4002)
+	server.get "/" do |req, res|
+	  res.body = "Hello from socket"
+	end
+
+	server.start(socket: "%s", max_header_bytes: 4096)
+	`, socket)
+
+	go func() {
+		v := initTestVM()
+		v.testEval(t, serverScript, getFilename())
+	}()
+
+	time.Sleep(1 * time.Second)
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return net.Dial("unix", socket)
+		},
+	}}
+
+	resp, err := client.Get("http://goby/")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+
+	if string(body) != "Hello from socket" {
+		t.Fatalf("Expect response body to be \"Hello from socket\". got=%s", body)
+	}
+}
+
+func TestServerStartFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`
+		require "net/simple_server"
+
+		Net::SimpleServer.new(4003).start(tls_cert: "cert.pem")
+		`, "ArgumentError: Both tls_cert and tls_key are required to serve TLS", 1},
+		{`
+		require "net/simple_server"
+
+		Net::SimpleServer.new(4003).start(read_timeout: "5")
+		`, "ArgumentError: Expect read_timeout to be an Integer or a Float. got: String", 1},
+		{`
+		require "net/simple_server"
+
+		Net::SimpleServer.new(4003).start(read_timeout: 2 ** 64)
+		`, "ArgumentError: Expect read_timeout to be at most 9223372036 seconds. got: 18446744073709551616", 1},
+		{`
+		require "net/simple_server"
+
+		Net::SimpleServer.new(4003).start(idle_timeout: 9223372037)
+		`, "ArgumentError: Expect idle_timeout to be at most 9223372036 seconds. got: 9223372037", 1},
+		{`
+		require "net/simple_server"
+
+		Net::SimpleServer.new(4003).start(write_timeout: -1)
+		`, "ArgumentError: Expect write_timeout to be a non-negative number of seconds. got: -1", 1},
+		{`
+		require "net/simple_server"
+
+		Net::SimpleServer.new(4003).start(read_timeout: -0.5)
+		`, "ArgumentError: Expect read_timeout to be a non-negative number of seconds. got: -0.5", 1},
+		{`
+		require "net/simple_server"
+
+		Net::SimpleServer.new(4003).start(max_header_bytes: 2 ** 64)
+		`, "ArgumentError: Expect max_header_bytes to be at most 9223372036854775807. got: 18446744073709551616", 1},
+		{`
+		require "net/simple_server"
+
+		Net::SimpleServer.new(4003).listen({ foo: 1 })
+		`, "ArgumentError: Unknown server option: foo", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+	}
+}
+
+// generateTestCert writes a "<name>.pem" and "<name>-key.pem" pair into dir. The certificate is self-signed when parent is nil.
+func generateTestCert(t *testing.T, dir, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	certPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPem := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+
+	if err := ioutil.WriteFile(filepath.Join(dir, name+".pem"), certPem, 0600); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, name+"-key.pem"), keyPem, 0600); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	return cert, key
+}
+
+func TestServerTestHarness(t *testing.T) {
+	server := `
+	require "net/http/test"
+
+	server = Net::SimpleServer.new(3000)
+	server.get("/users/{id}") do |req, res|
+	  res.json({ id: req.params["id"], accept: req.headers["Accept"], page: req.query["page"] })
+	end
+	server.post("/users") do |req, res|
+	  res.status = :created
+	  res.body = req.headers["Content-Type"].to_s + " " + req.body
+	end
+	server.delete("/users/{id}") do |req, res|
+	  res.status = 204
+	end
+	server.get("/slow") do |req, res|
+	  sleep(0.2)
+	  res.body = "slow"
+	end
+	app = Net::HTTP::Test.new(server)
+	`
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`app.get("/users/1").status_code`, 200},
+		{`app.get("/users/1").json["id"]`, "1"},
+		{`app.get("/users/1?page=2").json["page"]`, "2"},
+		{`app.get("/users/1", headers: { Accept: "text/html" }).json["accept"]`, "text/html"},
+		{`app.get("/users/1").headers["Content-Type"]`, "application/json"},
+		{`app.post("/users", body: "Stan").body`, " Stan"},
+		{`app.post_json("/users", { name: "Stan" }).body`, `application/json {"name":"Stan"}`},
+		{`app.post_json("/users", { name: "Stan" }).status_code`, 201},
+		{`app.delete("/users/1").status_code`, 204},
+		{`app.get("/missing").status_code`, 404},
+		{`
+		c = Channel.new
+		3.times do
+		  thread do
+		    c.deliver(app.get("/slow").body)
+		  end
+		end
+		c.receive + c.receive + c.receive
+		`, "slowslowslow"},
+		{`
+		describe = Spec.describe "GET /users/{id}" do
+		  it "returns the user" do
+		    expect(app.get("/users/1")).to have_status(200)
+		  end
+		  it "responds with json" do
+		    expect(app.get("/users/1")).to have_header("Content-Type", "application/json")
+		  end
+		  it "doesn't find unknown routes" do
+		    expect(app.get("/missing")).to have_status(200)
+		  end
+		end
+		Spec.describes.last.examples.map do |example|
+		  example.test_result
+		end
+		`, []interface{}{true, true, false}},
+		{`
+		admin = Net::SimpleServer.new(3001)
+		admin.get("/users/{id}") do |req, res|
+		  res.body = "admin"
+		end
+		admin.get("/admin") do |req, res|
+		  res.body = "admin only"
+		end
+		admin_app = Net::HTTP::Test.new(admin)
+		[admin_app.get("/users/1").body, app.get("/users/1").json["id"], app.get("/admin").status_code]
+		`, []interface{}{"admin", "1", 404}},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, server+tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+	}
+
+	testsFail := []errorTestCase{
+		{`server.handle("GET", "/users/1", 1, "")`, "TypeError: Expect headers to be a Hash. got: Integer", 1},
+		{`server.handle("GET", "/users/1", {})`, "ArgumentError: Expect 4 arguments. got: 3", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, server+tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+	}
+}
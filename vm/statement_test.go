@@ -51,6 +51,25 @@ func TestBreakStatement(t *testing.T) {
 		
 		y
 		`, 12},
+		// The block is yielded through a method defined in Goby
+		{`
+		def each_of(array)
+		  array.each do |i|
+		    yield(i)
+		  end
+		end
+
+		y = 0
+
+		each_of([1, 2, 3]) do |i|
+		  y += i
+		  if i == 2
+		    break
+		  end
+		end
+
+		y
+		`, 3},
 		{`
 		x = 0
 		y = 0
@@ -222,6 +241,81 @@ func TestDefStatementWithSplatArgument(t *testing.T) {
 
 		foo(10, 20, 30)
 		`, 60},
+		{`
+		def foo(*args)
+		  args
+		end
+
+		foo(1, 2)
+		`, []interface{}{1, 2}},
+		{`
+		def foo(*args)
+		  args
+		end
+
+		foo
+		`, []interface{}{}},
+		{`
+		def foo(a, b = 2, *args)
+		  [a, b, args]
+		end
+
+		foo(1)
+		`, []interface{}{1, 2, []interface{}{}}},
+		{`
+		def foo(a, b = 2, *args)
+		  [a, b, args]
+		end
+
+		foo(1, 3, 4, 5)
+		`, []interface{}{1, 3, []interface{}{4, 5}}},
+		{`
+		def foo(a, *args)
+		  [a, args]
+		end
+
+		arr = [2, 3]
+		foo(1, *arr)
+		`, []interface{}{1, []interface{}{2, 3}}},
+		// Keyword arguments go to keyword parameters, and positional ones after the normal parameters to the splat
+		{`
+		def foo(bar, a: 1, *args)
+		  bar + a + args.length * 100
+		end
+
+		foo(10, 20, 30, a: 5)
+		`, 215},
+		{`
+		def foo(bar, a: 1, *args)
+		  bar + a + args[0]
+		end
+
+		foo(10, 20)
+		`, 31},
+		// Without keyword parameters, keyword arguments are passed to the splat parameter as a Hash
+		{`
+		def foo(bar, *args)
+		  bar + args[0] + args[1][:a] * args[1][:b]
+		end
+
+		foo(10, 20, a: 3, b: 4)
+		`, 42},
+		{`
+		def foo(*args)
+		  args.length
+		end
+
+		foo(1, 2)
+		`, 2},
+		{`
+		class Foo
+		  def method_missing(name, *args)
+		    name + " " + args[1][:limit].to_s
+		  end
+		end
+
+		Foo.new.bar(1, limit: 10)
+		`, "bar 10"},
 	}
 
 	for i, tt := range tests {
@@ -346,6 +440,15 @@ func TestDefStatementWithKeywordArgument(t *testing.T) {
 
 		foo(b: 20, a: 10, 40, 100, "foo", 50)
 		`, 100},
+		// Keyword arguments on the right-hand side of an assignment
+		{`
+		def foo(a:, b: 10)
+		  a - b
+		end
+
+		result = foo(a: 20, b: false.to_s.length)
+		result
+		`, 15},
 	}
 
 	for i, tt := range tests {
@@ -490,6 +593,30 @@ func TestModuleStatement(t *testing.T) {
 
 		Bar.ten
 		`, 10},
+		// A module included by classes with different superclasses
+		{`
+		module Foo
+		  def ten
+		    10
+		  end
+		end
+
+		class Base
+		  def five
+		    5
+		  end
+		end
+
+		class Bar < Base
+		  include Foo
+		end
+
+		class Baz
+		  include Foo
+		end
+
+		[Bar.new.ten * Bar.new.five, Baz.new.ten, Baz.new.respond_to?(:five), Bar.ancestors.include?(Foo)]
+		`, []interface{}{50, 10, false, true}},
 	}
 
 	for i, tt := range tests {
@@ -1,7 +1,7 @@
 package vm
 
 import (
-	"fmt"
+	"math/big"
 	"strconv"
 	"strings"
 	"unicode"
@@ -9,6 +9,7 @@ import (
 
 	"github.com/goby-lang/goby/vm/classes"
 	"github.com/goby-lang/goby/vm/errors"
+	"golang.org/x/text/unicode/norm"
 )
 
 // StringObject represents string instances
@@ -26,33 +27,36 @@ import (
 //
 // **Note:**
 //
-// - Currently, manipulations are based upon Golang's Unicode manipulations.
-// - Currently, UTF-8 encoding is assumed based upon Golang's string manipulation, but the encoding is not actually specified(TBD).
+// - Strings are sequences of UTF-8 characters (code points): `length`, `[]` and other index-based methods count characters, `bytesize` counts bytes.
+// - A String can be converted to "ISO-8859-1" or "US-ASCII" with `encode`, or relabeled with `force_encoding`.
+// - Methods returning Strings keep self's encoding. Concatenating Strings of different encodings raises an `Encoding::CompatibilityError`, unless one of them is ASCII-only.
 // - `String.new` is not supported.
 type StringObject struct {
 	*baseObj
-	value string
+	value    string
+	encoding string
 }
 
 // Class methods --------------------------------------------------------
 func builtinStringClassMethods() []*BuiltinMethodObject {
 	return []*BuiltinMethodObject{
 		{
-			// The String.fmt implements formatted I/O with functions analogous to C's printf and scanf
-			// Currently only support plain "%s" formatting
-			// TODO: Support other kind of formatting such as %f, %v ... etc
+			// Returns the arguments formatted with the format string, like C's sprintf.
+			// See `Kernel#format` for the directives.
 			//
 			// ```ruby
 			// String.fmt("Hello! %s Lang!", "Goby")                    # => "Hello! Goby Lang!"
 			// String.fmt("I love to eat %s and %s!", "Sushi", "Ramen") # => "I love to eat Sushi and Ramen"
+			// String.fmt("%05.1f%%", 12.345)                           # => "012.3%"
 			// ```
 			//
+			// @param format [String], *args [Object]
 			// @return [String]
 			Name: "fmt",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 					if len(args) < 1 {
-						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect at least 1 argument. got=%v", strconv.Itoa(len(args)))
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentMoreFormat, 1, len(args))
 					}
 
 					formatObj, ok := args[0].(*StringObject)
@@ -61,20 +65,13 @@ func builtinStringClassMethods() []*BuiltinMethodObject {
 						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
 					}
 
-					format := formatObj.value
-					arguments := []interface{}{}
+					s, err := t.format(formatObj.value, args[1:], sourceLine)
 
-					for _, arg := range args[1:] {
-						arguments = append(arguments, arg.toString())
-					}
-
-					count := strings.Count(format, "%s")
-
-					if len(args[1:]) != count {
-						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect %d string arguments. got=%d", count, len(args[1:]))
+					if err != nil {
+						return err
 					}
 
-					return t.vm.InitStringObject(fmt.Sprintf(format, arguments...))
+					return t.vm.InitStringObject(s)
 				}
 			},
 		},
@@ -105,7 +102,7 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 
-					leftValue := receiver.(*StringObject).value
+					left := receiver.(*StringObject)
 					r := args[0]
 					right, ok := r.(*StringObject)
 
@@ -113,8 +110,7 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, r.Class().Name)
 					}
 
-					rightValue := right.value
-					return t.vm.InitStringObject(leftValue + rightValue)
+					return left.concat(t, right, sourceLine)
 				}
 			},
 		},
@@ -130,7 +126,7 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 
-					leftValue := receiver.(*StringObject).value
+					left := receiver.(*StringObject)
 					r := args[0]
 					right, ok := r.(*IntegerObject)
 
@@ -138,17 +134,51 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, r.Class().Name)
 					}
 
-					if right.value < 0 {
-						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Second argument must be greater than or equal to 0. got=%v", right.value)
+					times, err := right.intValue(t, sourceLine)
+
+					if err != nil {
+						return err
 					}
 
-					var result string
+					if times < 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Second argument must be greater than or equal to 0. got=%v", times)
+					}
 
-					for i := 0; i < right.value; i++ {
-						result += leftValue
+					return t.vm.initStringObjectWithEncoding(strings.Repeat(left.value, times), left.encoding)
+				}
+			},
+		},
+		{
+			// Returns the argument formatted with self as the format string. Pass an array to format more than one
+			// argument. See `Kernel#format` for the directives.
+			//
+			// ```ruby
+			// "%05d" % 42                    # => "00042"
+			// "%s is %.1f" % ["pi", 3.14159] # => "pi is 3.1"
+			// ```
+			//
+			// @param argument [Object]
+			// @return [String]
+			Name: "%",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
 					}
 
-					return t.vm.InitStringObject(result)
+					arguments := []Object{args[0]}
+
+					if arr, ok := args[0].(*ArrayObject); ok {
+						arguments = arr.Elements
+					}
+
+					s, err := t.format(receiver.(*StringObject).value, arguments, sourceLine)
+
+					if err != nil {
+						return err
+					}
+
+					return t.vm.InitStringObject(s)
 				}
 			},
 		},
@@ -369,28 +399,33 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect 1 argument. got=%d", len(args))
 					}
 
-					str := receiver.(*StringObject).value
+					str := receiver.(*StringObject)
+					chars := str.chars()
 					i := args[0]
 
 					switch index := i.(type) {
 					case *IntegerObject:
-						indexValue := index.value
+						indexValue, err := index.intValue(t, sourceLine)
+
+						if err != nil {
+							return err
+						}
 
 						if indexValue < 0 {
-							strLength := utf8.RuneCountInString(str)
-							if -indexValue > strLength {
+							strLength := len(chars)
+							if indexValue < -strLength {
 								return NULL
 							}
-							return t.vm.InitStringObject(string([]rune(str)[strLength+indexValue]))
+							return t.vm.initStringObjectWithEncoding(chars[strLength+indexValue], str.encoding)
 						}
 
-						if len(str) > indexValue {
-							return t.vm.InitStringObject(string([]rune(str)[indexValue]))
+						if len(chars) > indexValue {
+							return t.vm.initStringObjectWithEncoding(chars[indexValue], str.encoding)
 						}
 
 						return NULL
 					case *RangeObject:
-						strLength := utf8.RuneCountInString(str)
+						strLength := len(chars)
 						start := index.Start
 						end := index.End
 
@@ -414,7 +449,11 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 							end = strLength - 1
 						}
 
-						return t.vm.InitStringObject(string([]rune(str)[start : end+1]))
+						if end < start {
+							return t.vm.initStringObjectWithEncoding("", str.encoding)
+						}
+
+						return t.vm.initStringObjectWithEncoding(strings.Join(chars[start:end+1], ""), str.encoding)
 					default:
 						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, i.Class().Name)
 					}
@@ -445,7 +484,8 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect 2 arguments. got=%v", strconv.Itoa(len(args)))
 					}
 
-					str := receiver.(*StringObject).value
+					str := receiver.(*StringObject)
+					chars := str.chars()
 					i := args[0]
 					index, ok := i.(*IntegerObject)
 
@@ -453,8 +493,13 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, i.Class().Name)
 					}
 
-					indexValue := index.value
-					strLength := utf8.RuneCountInString(str)
+					indexValue, err := index.intValue(t, sourceLine)
+
+					if err != nil {
+						return err
+					}
+
+					strLength := len(chars)
 
 					if strLength < indexValue {
 						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Index value out of range. got=%v", strconv.Itoa(indexValue))
@@ -466,11 +511,16 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 					if !ok {
 						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, r.Class().Name)
 					}
-					replaceStrValue := replaceStr.value
+
+					encoding, ok := compatibleEncoding(str, replaceStr)
+
+					if !ok {
+						return t.vm.initIncompatibleEncodingError(sourceLine, str, replaceStr)
+					}
 
 					// Negative Index Case
 					if indexValue < 0 {
-						if -indexValue > strLength {
+						if indexValue < -strLength {
 							return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Index value out of range. got=%v", strconv.Itoa(indexValue))
 						}
 						// Change to positive index to replace the string
@@ -478,11 +528,32 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 					}
 
 					if strLength == indexValue {
-						return t.vm.InitStringObject(str + replaceStrValue)
+						return t.vm.initStringObjectWithEncoding(str.value+replaceStr.value, encoding)
 					}
-					// Using rune type to support UTF-8 encoding to replace character
-					result := string([]rune(str)[:indexValue]) + replaceStrValue + string([]rune(str)[indexValue+1:])
-					return t.vm.InitStringObject(result)
+					// Replaces the bytes of the character, so the other characters are kept as they are
+					result := strings.Join(chars[:indexValue], "") + replaceStr.value + strings.Join(chars[indexValue+1:], "")
+					return t.vm.initStringObjectWithEncoding(result, encoding)
+				}
+			},
+		},
+		{
+			// Returns the length of self in bytes.
+			//
+			// ```ruby
+			// "Goby".bytesize # => 4
+			// "café".bytesize # => 5
+			// "😊".bytesize   # => 4
+			// ```
+			//
+			// @return [Integer]
+			Name: "bytesize",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return t.vm.InitIntegerObject(len(receiver.(*StringObject).value))
 				}
 			},
 		},
@@ -501,12 +572,44 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 
-					str := receiver.(*StringObject).value
-					start := string([]rune(str)[0])
-					rest := string([]rune(str)[1:])
-					result := strings.ToUpper(start) + strings.ToLower(rest)
+					str := receiver.(*StringObject)
+					if str.value == "" {
+						return t.vm.initStringObjectWithEncoding(str.value, str.encoding)
+					}
 
-					return t.vm.InitStringObject(result)
+					_, width := decodeRune(str.value, str.encoding)
+					start := mapChars(str.value[:width], str.encoding, unicode.ToUpper)
+					rest := mapChars(str.value[width:], str.encoding, unicode.ToLower)
+
+					return t.vm.initStringObjectWithEncoding(start+rest, str.encoding)
+				}
+			},
+		},
+		{
+			// Returns an array of the characters of self.
+			//
+			// ```ruby
+			// "Goby".chars    # => ["G", "o", "b", "y"]
+			// "日本語".chars  # => ["日", "本", "語"]
+			// "😊🐟".chars    # => ["😊", "🐟"]
+			// ```
+			//
+			// @return [Array]
+			Name: "chars",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					str := receiver.(*StringObject)
+					elems := []Object{}
+
+					for _, char := range str.chars() {
+						elems = append(elems, t.vm.initStringObjectWithEncoding(char, str.encoding))
+					}
+
+					return t.vm.InitArrayObject(elems)
 				}
 			},
 		},
@@ -524,11 +627,45 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 
-					str := receiver.(*StringObject).value
-					strLength := utf8.RuneCountInString(str)
+					str := receiver.(*StringObject)
+					chars := str.chars()
+					if len(chars) == 0 {
+						return t.vm.initStringObjectWithEncoding(str.value, str.encoding)
+					}
 
-					// Support UTF-8 Encoding
-					return t.vm.InitStringObject(string([]rune(str)[:strLength-1]))
+					return t.vm.initStringObjectWithEncoding(str.value[:len(str.value)-len(chars[len(chars)-1])], str.encoding)
+				}
+			},
+		},
+		{
+			// Returns an array of the Unicode code points of self's characters.
+			// It raises an ArgumentError if self has an invalid byte sequence.
+			//
+			// ```ruby
+			// "Goby".codepoints # => [71, 111, 98, 121]
+			// "é😊".codepoints  # => [233, 128522]
+			// ```
+			//
+			// @return [Array]
+			Name: "codepoints",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					str := receiver.(*StringObject)
+					runes, ok := str.codepoints()
+					if !ok {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Invalid byte sequence in %s", str.encoding)
+					}
+
+					elems := []Object{}
+					for _, r := range runes {
+						elems = append(elems, t.vm.InitIntegerObject(int(r)))
+					}
+
+					return t.vm.InitArrayObject(elems)
 				}
 			},
 		},
@@ -548,7 +685,7 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect 1 argument. got=%v", strconv.Itoa(len(args)))
 					}
 
-					str := receiver.(*StringObject).value
+					str := receiver.(*StringObject)
 					c := args[0]
 					concatStr, ok := c.(*StringObject)
 
@@ -556,7 +693,7 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, c.Class().Name)
 					}
 
-					return t.vm.InitStringObject(str + concatStr.value)
+					return str.concat(t, concatStr, sourceLine)
 				}
 			},
 		},
@@ -575,10 +712,7 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 
-					str := receiver.(*StringObject).value
-
-					// Support UTF-8 Encoding
-					return t.vm.InitIntegerObject(utf8.RuneCountInString(str))
+					return t.vm.InitIntegerObject(receiver.(*StringObject).charCount())
 				}
 			},
 		},
@@ -600,7 +734,7 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect 1 argument. got=%v", strconv.Itoa(len(args)))
 					}
 
-					str := receiver.(*StringObject).value
+					str := receiver.(*StringObject)
 					d := args[0]
 					deleteStr, ok := d.(*StringObject)
 
@@ -608,7 +742,7 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, d.Class().Name)
 					}
 
-					return t.vm.InitStringObject(strings.Replace(str, deleteStr.value, "", -1))
+					return t.vm.initStringObjectWithEncoding(strings.Replace(str.value, deleteStr.value, "", -1), str.encoding)
 				}
 			},
 		},
@@ -625,9 +759,9 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 
-					str := receiver.(*StringObject).value
+					str := receiver.(*StringObject)
 
-					return t.vm.InitStringObject(strings.ToLower(str))
+					return t.vm.initStringObjectWithEncoding(mapChars(str.value, str.encoding, unicode.ToLower), str.encoding)
 				}
 			},
 		},
@@ -661,16 +795,16 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 					if blockFrame == nil {
 						return t.vm.InitErrorObject(errors.InternalError, sourceLine, errors.CantYieldWithoutBlockFormat)
 					}
-					str := receiver.(*StringObject).value
+					str := receiver.(*StringObject)
 					if blockIsEmpty(blockFrame) {
-						return t.vm.InitStringObject(str)
+						return t.vm.initStringObjectWithEncoding(str.value, str.encoding)
 					}
 
-					for _, byte := range []byte(str) {
+					for _, byte := range []byte(str.value) {
 						t.builtinMethodYield(blockFrame, t.vm.InitIntegerObject(int(byte)))
 					}
 
-					return t.vm.InitStringObject(str)
+					return t.vm.initStringObjectWithEncoding(str.value, str.encoding)
 				}
 			},
 		},
@@ -702,16 +836,16 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 						return t.vm.InitErrorObject(errors.InternalError, sourceLine, errors.CantYieldWithoutBlockFormat)
 					}
 
-					str := receiver.(*StringObject).value
+					str := receiver.(*StringObject)
 					if blockIsEmpty(blockFrame) {
-						return t.vm.InitStringObject(str)
+						return t.vm.initStringObjectWithEncoding(str.value, str.encoding)
 					}
 
-					for _, char := range []rune(str) {
-						t.builtinMethodYield(blockFrame, t.vm.InitStringObject(string(char)))
+					for _, char := range str.chars() {
+						t.builtinMethodYield(blockFrame, t.vm.initStringObjectWithEncoding(char, str.encoding))
 					}
 
-					return t.vm.InitStringObject(str)
+					return t.vm.initStringObjectWithEncoding(str.value, str.encoding)
 				}
 			},
 		},
@@ -739,17 +873,17 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 						return t.vm.InitErrorObject(errors.InternalError, sourceLine, errors.CantYieldWithoutBlockFormat)
 					}
 
-					str := receiver.(*StringObject).value
+					str := receiver.(*StringObject)
 					if blockIsEmpty(blockFrame) {
-						return t.vm.InitStringObject(str)
+						return t.vm.initStringObjectWithEncoding(str.value, str.encoding)
 					}
-					lineArray := strings.Split(str, "\n")
+					lineArray := strings.Split(str.value, "\n")
 
 					for _, line := range lineArray {
-						t.builtinMethodYield(blockFrame, t.vm.InitStringObject(line))
+						t.builtinMethodYield(blockFrame, t.vm.initStringObjectWithEncoding(line, str.encoding))
 					}
 
-					return t.vm.InitStringObject(str)
+					return t.vm.initStringObjectWithEncoding(str.value, str.encoding)
 				}
 			},
 		},
@@ -775,6 +909,72 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 				}
 			},
 		},
+		{
+			// Returns a copy of self converted to the given encoding, which can be "UTF-8", "ISO-8859-1" or "US-ASCII".
+			// It raises an EncodingError if self has an invalid byte sequence or a character the encoding can't represent.
+			//
+			// ```ruby
+			// s = "café".encode("ISO-8859-1")
+			// s.encoding                 # => "ISO-8859-1"
+			// s.bytesize                 # => 4
+			// s.encode("UTF-8") == "café" # => true
+			// "日本".encode("ISO-8859-1") # => EncodingError
+			// ```
+			//
+			// @param encoding [String]
+			// @return [String]
+			Name: "encode",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					name, ok := args[0].(*StringObject)
+					if !ok {
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+					}
+
+					encoding, ok := lookupEncoding(name.value)
+					if !ok {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Unknown encoding name: %s", name.value)
+					}
+
+					str := receiver.(*StringObject)
+					runes, ok := str.codepoints()
+					if !ok {
+						return t.vm.InitErrorObject(errors.EncodingError, sourceLine, "Invalid byte sequence in %s", str.encoding)
+					}
+
+					value, r, ok := encodeRunes(runes, encoding)
+					if !ok {
+						return t.vm.InitErrorObject(errors.EncodingError, sourceLine, "U+%04X from %s can't be converted to %s", r, str.encoding, encoding)
+					}
+
+					return t.vm.initStringObjectWithEncoding(value, encoding)
+				}
+			},
+		},
+		{
+			// Returns the name of self's encoding.
+			//
+			// ```ruby
+			// "Goby".encoding                        # => "UTF-8"
+			// "Goby".encode("US-ASCII").encoding     # => "US-ASCII"
+			// ```
+			//
+			// @return [String]
+			Name: "encoding",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return t.vm.InitStringObject(receiver.(*StringObject).encoding)
+				}
+			},
+		},
 		{
 			// Returns true if receiver string end with the argument string
 			//
@@ -801,18 +1001,7 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, c.Class().Name)
 					}
 
-					compareStrValue := compareStr.value
-					compareStrLength := utf8.RuneCountInString(compareStrValue)
-					strLength := utf8.RuneCountInString(str)
-
-					if compareStrLength > strLength {
-						return FALSE
-					}
-
-					if compareStrValue == string([]rune(str)[strLength-compareStrLength:]) {
-						return TRUE
-					}
-					return FALSE
+					return toBooleanObject(strings.HasSuffix(str, compareStr.value))
 				}
 			},
 		},
@@ -845,6 +1034,124 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 				}
 			},
 		},
+		{
+			// Returns a copy of self with the same bytes, labeled with the given encoding.
+			// Unlike `encode`, the bytes are not converted, so the result may not be a valid string in its encoding.
+			//
+			// ```ruby
+			// s = "café".encode("ISO-8859-1").force_encoding("UTF-8")
+			// s.valid_encoding? # => false
+			// s.bytesize        # => 4
+			// ```
+			//
+			// @param encoding [String]
+			// @return [String]
+			Name: "force_encoding",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					name, ok := args[0].(*StringObject)
+					if !ok {
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+					}
+
+					encoding, ok := lookupEncoding(name.value)
+					if !ok {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Unknown encoding name: %s", name.value)
+					}
+
+					return t.vm.initStringObjectWithEncoding(receiver.(*StringObject).value, encoding)
+				}
+			},
+		},
+		{
+			// Returns an array of the extended grapheme clusters of self, the user-perceived characters defined by
+			// Unicode Standard Annex #29. So a character followed by combining marks, an emoji with modifiers or
+			// joined emoji, a flag, or a Hangul syllable made of jamo stays in one element.
+			// The Unicode 14.0 character properties are used.
+			//
+			// ```ruby
+			// nfd = "é".unicode_normalize(:nfd)
+			// nfd.chars.length          # => 2
+			// nfd.grapheme_clusters     # => ["é"]
+			// "👍🏽🇯🇵".grapheme_clusters # => ["👍🏽", "🇯🇵"]
+			// ```
+			//
+			// @return [Array]
+			Name: "grapheme_clusters",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					str := receiver.(*StringObject)
+					if str.encoding != utf8Encoding {
+						elems := []Object{}
+						for _, char := range str.chars() {
+							elems = append(elems, t.vm.initStringObjectWithEncoding(char, str.encoding))
+						}
+						return t.vm.InitArrayObject(elems)
+					}
+
+					elems := []Object{}
+					for _, cluster := range graphemeClusters(str.value) {
+						elems = append(elems, t.vm.InitStringObject(cluster))
+					}
+
+					return t.vm.InitArrayObject(elems)
+				}
+			},
+		},
+		{
+			// Returns a copy of self with all the matches of the pattern replaced. The pattern is a Regexp,
+			// or a String that matches literally.
+			// The replacement can refer to the match with `\0`, the groups with `\1` to `\9` or `\k<name>`,
+			// and the text before and after the match with `` \` `` and `\'`.
+			// With a block instead of the replacement, the block's result replaces each match. The block
+			// takes the matched text and the MatchData.
+			//
+			// ```ruby
+			// "Hello Goby".gsub(/o/, "0")                      # => "Hell0 G0by"
+			// "John Smith".gsub(/(\w+) (\w+)/, '\2, \1')       # => "Smith, John"
+			// "2018-05".gsub(/(?<y>\d+)-(?<m>\d+)/, '\k<m>/\k<y>') # => "05/2018"
+			// "a-b-c".gsub("-") do |s| "+" end                 # => "a+b+c"
+			// "goby lang".gsub(/\w+/) do |word|
+			//   word.capitalize
+			// end                                              # => "Goby Lang"
+			// ```
+			//
+			// @param pattern [String/Regexp], replacement [String]
+			// @return [String]
+			Name: "gsub",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					return receiver.(*StringObject).substitute(t, args, blockFrame, -1, sourceLine)
+				}
+			},
+		},
+		{
+			// Returns a hash of self, which is the same for Strings with the same content.
+			//
+			// ```ruby
+			// "foo".hash == ("f" + "oo").hash # => true
+			// "foo".hash == "bar".hash        # => false
+			// ```
+			// @return [Integer]
+			Name: "hash",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return t.hashValue(receiver, sourceLine)
+				}
+			},
+		},
 		{
 			// Checks if the specified string is included in the receiver
 			//
@@ -901,7 +1208,7 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect 2 arguments. got=%d", len(args))
 					}
 
-					str := receiver.(*StringObject).value
+					str := receiver.(*StringObject)
 					i := args[0]
 					index, ok := i.(*IntegerObject)
 
@@ -909,20 +1216,33 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, i.Class().Name)
 					}
 
-					indexValue := index.value
+					indexValue, err := index.intValue(t, sourceLine)
+
+					if err != nil {
+						return err
+					}
+
 					ins := args[1]
 					insertStr, ok := ins.(*StringObject)
 
 					if !ok {
 						return t.vm.InitErrorObject(errors.TypeError, sourceLine, "Expect insert string to be String. got: %s", ins.Class().Name)
 					}
-					strLength := utf8.RuneCountInString(str)
+
+					encoding, ok := compatibleEncoding(str, insertStr)
+
+					if !ok {
+						return t.vm.initIncompatibleEncodingError(sourceLine, str, insertStr)
+					}
+
+					chars := str.chars()
+					strLength := len(chars)
 
 					if indexValue < 0 {
-						if -indexValue > strLength+1 {
+						if indexValue < -strLength-1 {
 							return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Index value out of range. got=%v", indexValue)
-						} else if -indexValue == strLength+1 {
-							return t.vm.InitStringObject(insertStr.value + str)
+						} else if indexValue == -strLength-1 {
+							return t.vm.initStringObjectWithEncoding(insertStr.value+str.value, encoding)
 						}
 						// Change it to positive index value to replace the string via index
 						indexValue += strLength
@@ -932,14 +1252,12 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Index value out of range. got=%v", indexValue)
 					}
 
-					// Support UTF-8 Encoding
-					return t.vm.InitStringObject(string([]rune(str)[:indexValue]) + insertStr.value + string([]rune(str)[indexValue:]))
+					return t.vm.initStringObjectWithEncoding(strings.Join(chars[:indexValue], "")+insertStr.value+strings.Join(chars[indexValue:], ""), encoding)
 				}
 			},
 		},
 		{
-			// Returns the character length of self
-			// **Note:** the length is currently byte-based, instead of charcode-based.
+			// Returns the character length of self. Use `bytesize` for the length in bytes.
 			//
 			// ```ruby
 			// "zero".length # => 4
@@ -952,10 +1270,7 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 
-					str := receiver.(*StringObject).value
-
-					// Support UTF-8 Encoding
-					return t.vm.InitIntegerObject(utf8.RuneCountInString(str))
+					return t.vm.InitIntegerObject(receiver.(*StringObject).charCount())
 				}
 			},
 		},
@@ -981,7 +1296,7 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect 1..2 arguments. got=%v", strconv.Itoa(len(args)))
 					}
 
-					str := receiver.(*StringObject).value
+					str := receiver.(*StringObject)
 
 					l := args[0]
 					strLength, ok := l.(*IntegerObject)
@@ -990,12 +1305,14 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 						return t.vm.InitErrorObject(errors.TypeError, sourceLine, "Expect justify width to be Integer. got: %s", l.Class().Name)
 					}
 
-					strLengthValue := strLength.value
+					strLengthValue, err := strLength.intValue(t, sourceLine)
 
-					var padStrValue string
-					if len(args) == 1 {
-						padStrValue = " "
-					} else {
+					if err != nil {
+						return err
+					}
+
+					pad := t.vm.InitStringObject(" ")
+					if len(args) == 2 {
 						p := args[1]
 						padStr, ok := p.(*StringObject)
 
@@ -1003,21 +1320,16 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 							return t.vm.InitErrorObject(errors.TypeError, sourceLine, "Expect padding string to be String. got: %s", p.Class().Name)
 						}
 
-						padStrValue = padStr.value
+						pad = padStr
 					}
 
-					currentStrLength := utf8.RuneCountInString(str)
-					padStrLength := utf8.RuneCountInString(padStrValue)
+					encoding, ok := compatibleEncoding(str, pad)
 
-					if strLengthValue > currentStrLength {
-						for i := currentStrLength; i < strLengthValue; i += padStrLength {
-							str += padStrValue
-						}
-						str = string([]rune(str)[:strLengthValue])
+					if !ok {
+						return t.vm.initIncompatibleEncodingError(sourceLine, str, pad)
 					}
 
-					// Support UTF-8 Encoding
-					return t.vm.InitStringObject(str)
+					return t.vm.initStringObjectWithEncoding(str.value+str.padding(pad, strLengthValue), encoding)
 				}
 			},
 		},
@@ -1158,7 +1470,6 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 		},
 		{
 			// Returns a new String with reverse order of self
-			// **Note:** the length is currently byte-based, instead of charcode-based.
 			//
 			// ```ruby
 			// "reverse".reverse           # => "esrever"
@@ -1171,15 +1482,15 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 
-					str := receiver.(*StringObject).value
+					str := receiver.(*StringObject)
+					chars := str.chars()
 
-					var revert string
-					for i := utf8.RuneCountInString(str) - 1; i >= 0; i-- {
-						revert += string([]rune(str)[i])
+					var revert strings.Builder
+					for i := len(chars) - 1; i >= 0; i-- {
+						revert.WriteString(chars[i])
 					}
 
-					// Support UTF-8 Encoding
-					return t.vm.InitStringObject(revert)
+					return t.vm.initStringObjectWithEncoding(revert.String(), str.encoding)
 				}
 			},
 		},
@@ -1205,7 +1516,7 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect 1..2 arguments. got=%v", strconv.Itoa(len(args)))
 					}
 
-					str := receiver.(*StringObject).value
+					str := receiver.(*StringObject)
 					l := args[0]
 					strLength, ok := l.(*IntegerObject)
 
@@ -1213,12 +1524,14 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 						return t.vm.InitErrorObject(errors.TypeError, sourceLine, "Expect justify width to be Integer. got: %s", l.Class().Name)
 					}
 
-					strLengthValue := strLength.value
+					strLengthValue, err := strLength.intValue(t, sourceLine)
 
-					var padStrValue string
-					if len(args) == 1 {
-						padStrValue = " "
-					} else {
+					if err != nil {
+						return err
+					}
+
+					pad := t.vm.InitStringObject(" ")
+					if len(args) == 2 {
 						p := args[1]
 						padStr, ok := p.(*StringObject)
 
@@ -1226,32 +1539,124 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 							return t.vm.InitErrorObject(errors.TypeError, sourceLine, "Expect padding string to be String. got: %s", p.Class().Name)
 						}
 
-						padStrValue = padStr.value
+						pad = padStr
 					}
 
-					padStrLength := utf8.RuneCountInString(padStrValue)
+					encoding, ok := compatibleEncoding(str, pad)
 
-					if strLengthValue > len(str) {
-						origin := str
-						originStrLength := utf8.RuneCountInString(origin)
-						for i := originStrLength; i < strLengthValue; i += padStrLength {
-							str = padStrValue + str
+					if !ok {
+						return t.vm.initIncompatibleEncodingError(sourceLine, str, pad)
+					}
+
+					return t.vm.initStringObjectWithEncoding(str.padding(pad, strLengthValue)+str.value, encoding)
+				}
+			},
+		},
+		{
+			// Returns an array of all the matches of the pattern, which is a Regexp, or a String that matches literally.
+			// If the pattern has groups, each match is an array of the groups' captures.
+			// With a block, yields each match and returns self.
+			//
+			// ```ruby
+			// "a1 b22 c333".scan(/\d+/)         # => ["1", "22", "333"]
+			// "a1 b22".scan(/(\w)(\d+)/)        # => [["a", "1"], ["b", "22"]]
+			// "a1 b22".scan(/(\w)(\d+)/) do |letter, digits|
+			//   puts(letter + ":" + digits)
+			// end
+			// # => a:1
+			// # => b:22
+			// ```
+			//
+			// @param pattern [String/Regexp]
+			// @return [Array]
+			Name: "scan",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					re, ok := toRegexp(args[0])
+
+					if !ok {
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "String or Regexp", args[0].Class().Name)
+					}
+
+					groupNumbers := re.GetGroupNumbers()[1:]
+					results := []Object{}
+
+					for _, m := range findMatches(re, receiver.(*StringObject).value, -1) {
+						if len(groupNumbers) == 0 {
+							results = append(results, t.vm.InitStringObject(m.String()))
+							continue
 						}
-						currentStrLength := utf8.RuneCountInString(str)
-						if currentStrLength > strLengthValue {
-							chopLength := currentStrLength - strLengthValue
-							str = string([]rune(str)[:currentStrLength-originStrLength-chopLength]) + origin
+
+						captures := []Object{}
+
+						for _, n := range groupNumbers {
+							captures = append(captures, t.vm.initCaptureObject(m.GroupByNumber(n)))
 						}
+
+						results = append(results, t.vm.InitArrayObject(captures))
 					}
 
-					// Support UTF-8 Encoding
-					return t.vm.InitStringObject(str)
+					if blockFrame == nil {
+						return t.vm.InitArrayObject(results)
+					}
+
+					if blockIsEmpty(blockFrame) {
+						return receiver
+					}
+
+					// If there's no match, pop the block's call frame
+					if len(results) == 0 {
+						t.callFrameStack.pop()
+					}
+
+					for _, result := range results {
+						t.builtinMethodYield(blockFrame, result)
+					}
+
+					return receiver
+				}
+			},
+		},
+		{
+			// Returns a copy of self with each invalid byte replaced with the given replacement,
+			// which defaults to "�" for UTF-8 strings and "?" for other encodings.
+			//
+			// ```ruby
+			// s = "café".encode("ISO-8859-1").force_encoding("UTF-8")
+			// s.scrub      # => "caf�"
+			// s.scrub("?") # => "caf?"
+			// "Goby".scrub # => "Goby"
+			// ```
+			//
+			// @param replacement [String]
+			// @return [String]
+			Name: "scrub",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) > 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRangeFormat, 0, 1, len(args))
+					}
+
+					str := receiver.(*StringObject)
+					replacement := defaultReplacement(str.encoding)
+					if len(args) == 1 {
+						r, ok := args[0].(*StringObject)
+						if !ok {
+							return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+						}
+						replacement = r.value
+					}
+
+					return t.vm.initStringObjectWithEncoding(str.scrub(replacement), str.encoding)
 				}
 			},
 		},
 		{
-			// Returns the character length of self
-			// **Note:** the length is currently byte-based, instead of charcode-based.
+			// Returns the character length of self. Use `bytesize` for the length in bytes.
 			//
 			// ```ruby
 			// "zero".size  # => 4
@@ -1264,10 +1669,7 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 
-					str := receiver.(*StringObject).value
-
-					// Support UTF-8 Encoding
-					return t.vm.InitIntegerObject(utf8.RuneCountInString(str))
+					return t.vm.InitIntegerObject(receiver.(*StringObject).charCount())
 				}
 			},
 		},
@@ -1316,10 +1718,10 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect 1 argument. got=%v", strconv.Itoa(len(args)))
 					}
 
-					str := receiver.(*StringObject).value
-					strLength := utf8.RuneCountInString(str)
+					str := receiver.(*StringObject)
+					chars := str.chars()
+					strLength := len(chars)
 
-					// All Case Support UTF-8 Encoding
 					switch args[0].(type) {
 					case *RangeObject:
 						ran := args[0].(*RangeObject)
@@ -1328,48 +1730,53 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 							if ran.Start > strLength {
 								return NULL
 							} else if ran.Start > ran.End {
-								return t.vm.InitStringObject("")
+								return t.vm.initStringObjectWithEncoding("", str.encoding)
 							}
-							return t.vm.InitStringObject(string([]rune(str)[ran.Start : ran.End+1]))
+							return t.vm.initStringObjectWithEncoding(strings.Join(chars[ran.Start:ran.End+1], ""), str.encoding)
 						case ran.Start < 0 && ran.End >= 0:
 							positiveStart := strLength + ran.Start
 							if -ran.Start > strLength {
 								return NULL
 							} else if positiveStart > ran.End {
-								return t.vm.InitStringObject("")
+								return t.vm.initStringObjectWithEncoding("", str.encoding)
 							}
-							return t.vm.InitStringObject(string([]rune(str)[positiveStart : ran.End+1]))
+							return t.vm.initStringObjectWithEncoding(strings.Join(chars[positiveStart:ran.End+1], ""), str.encoding)
 						case ran.Start >= 0 && ran.End < 0:
 							positiveEnd := strLength + ran.End
 							if ran.Start > strLength {
 								return NULL
 							} else if positiveEnd < 0 || ran.Start > positiveEnd {
-								return t.vm.InitStringObject("")
+								return t.vm.initStringObjectWithEncoding("", str.encoding)
 							}
-							return t.vm.InitStringObject(string([]rune(str)[ran.Start : positiveEnd+1]))
+							return t.vm.initStringObjectWithEncoding(strings.Join(chars[ran.Start:positiveEnd+1], ""), str.encoding)
 						default:
 							positiveStart := strLength + ran.Start
 							positiveEnd := strLength + ran.End
 							if positiveStart < 0 {
 								return NULL
 							} else if positiveStart > positiveEnd {
-								return t.vm.InitStringObject("")
+								return t.vm.initStringObjectWithEncoding("", str.encoding)
 							}
-							return t.vm.InitStringObject(string([]rune(str)[positiveStart : positiveEnd+1]))
+							return t.vm.initStringObjectWithEncoding(strings.Join(chars[positiveStart:positiveEnd+1], ""), str.encoding)
 						}
 
 					case *IntegerObject:
-						intValue := args[0].(*IntegerObject).value
+						intValue, err := args[0].(*IntegerObject).intValue(t, sourceLine)
+
+						if err != nil {
+							return err
+						}
+
 						if intValue < 0 {
-							if -intValue > strLength {
+							if intValue < -strLength {
 								return NULL
 							}
-							return t.vm.InitStringObject(string([]rune(str)[strLength+intValue]))
+							return t.vm.initStringObjectWithEncoding(chars[strLength+intValue], str.encoding)
 						}
 						if intValue > strLength-1 {
 							return NULL
 						}
-						return t.vm.InitStringObject(string([]rune(str)[intValue]))
+						return t.vm.initStringObjectWithEncoding(chars[intValue], str.encoding)
 
 					default:
 						return t.vm.InitErrorObject(errors.TypeError, sourceLine, "Expect slice range to be Range or Integer. got: %s", args[0].Class().Name)
@@ -1378,34 +1785,70 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 			},
 		},
 		{
-			// Returns an array of strings separated by the given separator
+			// Returns an array of strings separated by the given separator, which is a String or a Regexp.
+			// The groups of a Regexp separator are included in the result.
+			// With a positive limit, returns at most limit strings, where the last one has the rest of self.
+			// With a limit of 0, the trailing empty strings are removed.
 			//
 			// ```ruby
 			// "Hello World".split("o") # => ["Hell", " W", "rld"]
 			// "Goby".split("")         # => ["G", "o", "b", "y"]
-			// "Hello\nWorld\nGoby".split("o") # => ["Hello", "World", "Goby"]
+			// "Hello\nWorld\nGoby".split("\n") # => ["Hello", "World", "Goby"]
 			// "Hello🐟World🐟Goby".split("🐟") # => ["Hello", "World", "Goby"]
+			// "a1b22c".split(/\d+/)    # => ["a", "b", "c"]
+			// "a-b_c".split(/([-_])/)  # => ["a", "-", "b", "_", "c"]
+			// "a,b,c".split(",", 2)    # => ["a", "b,c"]
+			// "a,b,,".split(",", 0)    # => ["a", "b"]
 			// ```
 			//
+			// @param separator [String/Regexp], limit [Integer]
 			// @return [Array]
 			Name: "split",
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-					if len(args) != 1 {
-						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect 1 argument. got=%v", strconv.Itoa(len(args)))
+					if len(args) < 1 || len(args) > 2 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRangeFormat, 1, 2, len(args))
 					}
 
-					s := args[0]
-					seperator, ok := s.(*StringObject)
+					// A negative limit means no limit, like when there isn't one
+					limit := -1
 
-					if !ok {
-						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, s.Class().Name)
+					if len(args) == 2 {
+						l, ok := args[1].(*IntegerObject)
+
+						if !ok {
+							return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[1].Class().Name)
+						}
+
+						var err *Error
+						if limit, err = l.intValue(t, sourceLine); err != nil {
+							return err
+						}
 					}
 
 					str := receiver.(*StringObject).value
-					arr := strings.Split(str, seperator.value)
+					var arr []string
+
+					switch separator := args[0].(type) {
+					case *StringObject:
+						if limit > 0 {
+							arr = strings.SplitN(str, separator.value, limit)
+						} else {
+							arr = strings.Split(str, separator.value)
+						}
+					case *RegexpObject:
+						arr = splitByRegexp(separator.regexp, str, limit)
+					default:
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "String or Regexp", args[0].Class().Name)
+					}
+
+					if limit == 0 {
+						for len(arr) > 0 && arr[len(arr)-1] == "" {
+							arr = arr[:len(arr)-1]
+						}
+					}
 
-					var elements []Object
+					elements := []Object{}
 					for i := 0; i < len(arr); i++ {
 						elements = append(elements, t.vm.InitStringObject(arr[i]))
 					}
@@ -1440,18 +1883,7 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, c.Class().Name)
 					}
 
-					compareStrValue := compareStr.value
-					compareStrLength := utf8.RuneCountInString(compareStrValue)
-					strLength := utf8.RuneCountInString(str)
-
-					if compareStrLength > strLength {
-						return FALSE
-					}
-
-					if compareStrValue == string([]rune(str)[:compareStrLength]) {
-						return TRUE
-					}
-					return FALSE
+					return toBooleanObject(strings.HasPrefix(str, compareStr.value))
 				}
 			},
 		},
@@ -1470,22 +1902,31 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 
-					str := receiver.(*StringObject).value
-
-					for {
-						str = strings.Trim(str, " ")
+					str := receiver.(*StringObject)
 
-						if strings.HasPrefix(str, "\n") || strings.HasPrefix(str, "\t") || strings.HasPrefix(str, "\r") || strings.HasPrefix(str, "\v") {
-							str = string([]rune(str)[1:])
-							continue
-						}
-						if strings.HasSuffix(str, "\n") || strings.HasSuffix(str, "\t") || strings.HasSuffix(str, "\r") || strings.HasSuffix(str, "\v") {
-							str = string([]rune(str)[:utf8.RuneCountInString(str)-2])
-							continue
-						}
-						break
-					}
-					return t.vm.InitStringObject(str)
+					// The whitespace characters are ASCII, so trimming their bytes works in every encoding
+					return t.vm.initStringObjectWithEncoding(strings.Trim(str.value, "\x00\t\n\v\f\r "), str.encoding)
+				}
+			},
+		},
+		{
+			// Returns a copy of self with the first match of the pattern replaced. See `#gsub` for the pattern,
+			// the replacement and the block.
+			//
+			// ```ruby
+			// "Hello Goby".sub(/o/, "0")                # => "Hell0 Goby"
+			// "John Smith".sub(/(\w+)/, '<\1>')         # => "<John> Smith"
+			// "goby lang".sub(/\w+/) do |word|
+			//   word.upcase
+			// end                                       # => "GOBY lang"
+			// ```
+			//
+			// @param pattern [String/Regexp], replacement [String]
+			// @return [String]
+			Name: "sub",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					return receiver.(*StringObject).substitute(t, args, blockFrame, 1, sourceLine)
 				}
 			},
 		},
@@ -1503,11 +1944,10 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 
 					str := receiver.(*StringObject)
-					strLength := utf8.RuneCountInString(str.value)
 					elems := []Object{}
 
-					for i := 0; i < strLength; i++ {
-						elems = append(elems, t.vm.InitStringObject(string([]rune(str.value)[i])))
+					for _, char := range str.chars() {
+						elems = append(elems, t.vm.initStringObjectWithEncoding(char, str.encoding))
 					}
 
 					return t.vm.InitArrayObject(elems)
@@ -1595,10 +2035,9 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 
 					str := receiver.(*StringObject).value
-					parsedStr, err := strconv.ParseInt(str, 10, 0)
 
-					if err == nil {
-						return t.vm.InitIntegerObject(int(parsedStr))
+					if n, ok := new(big.Int).SetString(str, 10); ok {
+						return t.vm.initBigIntegerObject(n)
 					}
 
 					var digits string
@@ -1613,8 +2052,8 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 					}
 
 					if len(digits) > 0 {
-						parsedStr, _ = strconv.ParseInt(digits, 10, 0)
-						return t.vm.InitIntegerObject(int(parsedStr))
+						n, _ := new(big.Int).SetString(digits, 10)
+						return t.vm.initBigIntegerObject(n)
 					}
 
 					return t.vm.InitIntegerObject(0)
@@ -1633,9 +2072,58 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 
-					str := receiver.(*StringObject).value
+					str := receiver.(*StringObject)
+
+					return t.vm.initStringObjectWithEncoding(str.value, str.encoding)
+				}
+			},
+		},
+		{
+			// Returns a copy of self in the given Unicode normalization form, which is one of
+			// "nfc" (the default), "nfd", "nfkc" and "nfkd".
+			//
+			// ```ruby
+			// nfd = "é".unicode_normalize(:nfd)
+			// nfd.length                   # => 2
+			// nfd.unicode_normalize.length # => 1
+			// "ｶ".unicode_normalize(:nfkc) # => "カ"
+			// ```
+			//
+			// @param form [String]
+			// @return [String]
+			Name: "unicode_normalize",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) > 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRangeFormat, 0, 1, len(args))
+					}
+
+					form := norm.NFC
+					if len(args) == 1 {
+						f, ok := args[0].(*StringObject)
+						if !ok {
+							return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+						}
 
-					return t.vm.InitStringObject(str)
+						switch f.value {
+						case "nfc":
+						case "nfd":
+							form = norm.NFD
+						case "nfkc":
+							form = norm.NFKC
+						case "nfkd":
+							form = norm.NFKD
+						default:
+							return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Invalid normalization form: %s", f.value)
+						}
+					}
+
+					str := receiver.(*StringObject)
+					if str.encoding != utf8Encoding {
+						return t.vm.InitErrorObject(errors.EncodingError, sourceLine, "Unicode normalization is not applicable to %s", str.encoding)
+					}
+
+					return t.vm.InitStringObject(form.String(str.value))
 				}
 			},
 		},
@@ -1651,9 +2139,32 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
 				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 
-					str := receiver.(*StringObject).value
+					str := receiver.(*StringObject)
 
-					return t.vm.InitStringObject(strings.ToUpper(str))
+					return t.vm.initStringObjectWithEncoding(mapChars(str.value, str.encoding, unicode.ToUpper), str.encoding)
+				}
+			},
+		},
+		{
+			// Returns true if self is a valid byte sequence in its encoding.
+			//
+			// ```ruby
+			// latin1 = "café".encode("ISO-8859-1")
+			// "café".valid_encoding?                       # => true
+			// latin1.valid_encoding?                       # => true
+			// latin1.force_encoding("UTF-8").valid_encoding? # => false
+			// ```
+			//
+			// @return [Boolean]
+			Name: "valid_encoding?",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					str := receiver.(*StringObject)
+					return toBooleanObject(validEncoding(str.value, str.encoding))
 				}
 			},
 		},
@@ -1674,12 +2185,21 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 // Functions for initialization -----------------------------------------
 
 func (vm *VM) InitStringObject(value string) *StringObject {
+	return vm.initStringObjectWithEncoding(value, utf8Encoding)
+}
+
+func (vm *VM) initStringObjectWithEncoding(value string, encoding string) *StringObject {
 	return &StringObject{
-		baseObj: &baseObj{class: vm.topLevelClass(classes.StringClass)},
-		value:   value,
+		baseObj:  &baseObj{class: vm.topLevelClass(classes.StringClass)},
+		value:    value,
+		encoding: encoding,
 	}
 }
 
+func (vm *VM) initIncompatibleEncodingError(sourceLine int, a, b *StringObject) *Error {
+	return vm.InitErrorObject(errors.CompatibilityError, sourceLine, "Incompatible character encodings: %s and %s", a.encoding, b.encoding)
+}
+
 func (vm *VM) initStringClass() *RClass {
 	sc := vm.initializeClass(classes.StringClass)
 	sc.setBuiltinMethods(builtinStringInstanceMethods(), false)
@@ -1687,6 +2207,183 @@ func (vm *VM) initStringClass() *RClass {
 	return sc
 }
 
+// Other helper functions -----------------------------------------------
+
+// substitute returns a copy of the string with at most limit matches of the pattern replaced, or all of
+// them if limit is negative. The matches are replaced with the replacement in args, or the block's results.
+func (s *StringObject) substitute(t *Thread, args []Object, blockFrame *normalCallFrame, limit int, sourceLine int) Object {
+	if len(args) < 1 || len(args) > 2 {
+		return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRangeFormat, 1, 2, len(args))
+	}
+
+	if len(args) == 1 && blockFrame == nil {
+		return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect a replacement or a block")
+	}
+
+	re, ok := toRegexp(args[0])
+
+	if !ok {
+		return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "String or Regexp", args[0].Class().Name)
+	}
+
+	var replacement *StringObject
+
+	if len(args) == 2 {
+		replacement, ok = args[1].(*StringObject)
+
+		if !ok {
+			return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, args[1].Class().Name)
+		}
+	}
+
+	matches := findMatches(re, s.value, limit)
+
+	// The block is only yielded when it's used to replace the matches
+	useBlock := replacement == nil && !blockIsEmpty(blockFrame)
+
+	if blockFrame != nil && !blockIsEmpty(blockFrame) && (!useBlock || len(matches) == 0) {
+		t.callFrameStack.pop()
+	}
+
+	text := []rune(s.value)
+	var out strings.Builder
+	start := 0
+
+	for _, m := range matches {
+		out.WriteString(string(text[start:m.Index]))
+
+		switch {
+		case replacement != nil:
+			out.WriteString(expandReplacement(replacement.value, m, text))
+		case useBlock:
+			result := t.builtinMethodYield(blockFrame, t.vm.InitStringObject(m.String()), t.vm.initMatchDataObject(m, re.String(), s.value)).Target
+
+			if result != NULL {
+				out.WriteString(result.toString())
+			}
+		}
+
+		start = m.Index + m.Length
+	}
+
+	out.WriteString(string(text[start:]))
+
+	return t.vm.InitStringObject(out.String())
+}
+
+// splitByRegexp splits the text by the regexp's matches, and includes the captures of the regexp's groups.
+// With a positive limit, it splits the text into at most limit parts.
+func splitByRegexp(re *Regexp, text string, limit int) []string {
+	runes := []rune(text)
+	groupNumbers := re.GetGroupNumbers()[1:]
+	result := []string{}
+	start := 0
+	parts := 1
+
+	for _, m := range findMatches(re, text, -1) {
+		if limit > 0 && parts >= limit {
+			break
+		}
+
+		// An empty match doesn't split the text at the start of a part or at the end of the text
+		if m.Length == 0 && (m.Index == start || m.Index == len(runes)) {
+			continue
+		}
+
+		result = append(result, string(runes[start:m.Index]))
+
+		for _, n := range groupNumbers {
+			if g := m.GroupByNumber(n); g != nil && len(g.Captures) > 0 {
+				result = append(result, g.String())
+			}
+		}
+
+		start = m.Index + m.Length
+		parts++
+	}
+
+	return append(result, string(runes[start:]))
+}
+
+// concat returns the concatenation of s and other. It raises an Encoding::CompatibilityError if their
+// encodings differ and neither of them is ASCII-only.
+func (s *StringObject) concat(t *Thread, other *StringObject, sourceLine int) Object {
+	encoding, ok := compatibleEncoding(s, other)
+
+	if !ok {
+		return t.vm.initIncompatibleEncodingError(sourceLine, s, other)
+	}
+
+	return t.vm.initStringObjectWithEncoding(s.value+other.value, encoding)
+}
+
+// padding returns the characters of pad repeated to justify s to width characters for ljust and rjust.
+func (s *StringObject) padding(pad *StringObject, width int) string {
+	padChars := pad.chars()
+
+	if len(padChars) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	for i := 0; i < width-s.charCount(); i++ {
+		b.WriteString(padChars[i%len(padChars)])
+	}
+
+	return b.String()
+}
+
+// charCount returns the number of characters in the string. Each invalid byte counts as one character.
+func (s *StringObject) charCount() int {
+	if s.encoding == utf8Encoding {
+		return utf8.RuneCountInString(s.value)
+	}
+	return len(s.value)
+}
+
+// chars splits the string into its characters, keeping each invalid byte as a character of its own.
+func (s *StringObject) chars() []string {
+	chars := []string{}
+	for i := 0; i < len(s.value); {
+		_, width := decodeRune(s.value[i:], s.encoding)
+		chars = append(chars, s.value[i:i+width])
+		i += width
+	}
+
+	return chars
+}
+
+// codepoints decodes the string in its encoding. It returns false if the string has an invalid byte sequence.
+func (s *StringObject) codepoints() ([]rune, bool) {
+	runes := []rune{}
+	for i := 0; i < len(s.value); {
+		r, width := decodeRune(s.value[i:], s.encoding)
+		if r == utf8.RuneError && width == 1 {
+			return nil, false
+		}
+		runes = append(runes, r)
+		i += width
+	}
+
+	return runes, true
+}
+
+// scrub returns a copy of the string with each invalid byte replaced with the replacement.
+func (s *StringObject) scrub(replacement string) string {
+	var b strings.Builder
+	for i := 0; i < len(s.value); {
+		r, width := decodeRune(s.value[i:], s.encoding)
+		if r == utf8.RuneError && width == 1 {
+			b.WriteString(replacement)
+		} else {
+			b.WriteString(s.value[i : i+width])
+		}
+		i += width
+	}
+
+	return b.String()
+}
+
 // Polymorphic helper functions -----------------------------------------
 
 // Value returns the object
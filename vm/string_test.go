@@ -265,12 +265,16 @@ func TestStringOperation(t *testing.T) {
 		{`"Hello"[-6]`, nil},
 		{`"Hello🍣"[5]`, "🍣"},
 		{`"Hello🍣"[-1]`, "🍣"},
+		{`"🍣🍺"[1]`, "🍺"},
+		{`"🍣🍺"[2]`, nil},
 		{`"Hello"[1..3]`, "ell"},
 		{`"Hello"[1..5]`, "ello"},
 		{`"Hello"[-3..-1]`, "llo"},
 		{`"Hello"[-6..-1]`, nil},
 		{`"Hello🍣"[-3..-3]`, "l"},
 		{`"Hello🍣"[1..-1]`, "ello🍣"},
+		{`"Hello"[3..1]`, ""},
+		{`"Hello"[1..-7]`, ""},
 		{`"Hello\nWorld"[5]`, "\n"},
 		{`"\"Maxwell\""[0]`, "\""},
 		{`"\"Maxwell\""[-1]`, "\""},
@@ -306,6 +310,9 @@ func TestStringOperationFail(t *testing.T) {
 		{`"Taipei" + 101`, "TypeError: Expect argument to be String. got: Integer", 1},
 		{`"Taipei" * "101"`, "TypeError: Expect argument to be Integer. got: String", 1},
 		{`"Taipei" * (-101)`, "ArgumentError: Second argument must be greater than or equal to 0. got=-101", 1},
+		{`"Taipei" * (2 ** 64)`, "RangeError: 18446744073709551616 is out of range", 1},
+		{`"Taipei"[2 ** 64]`, "RangeError: 18446744073709551616 is out of range", 1},
+		{`"Taipei"[2 ** 64] = "a"`, "RangeError: 18446744073709551616 is out of range", 1},
 		{`"Taipei"[1] = 1`, "TypeError: Expect argument to be String. got: Integer", 1},
 		{`"Taipei"[1] = true`, "TypeError: Expect argument to be String. got: Boolean", 1},
 		{`"Taipei"[]`, "ArgumentError: Expect 1 argument. got=0", 1},
@@ -336,6 +343,7 @@ func TestStringCapitalizeMethod(t *testing.T) {
 		{`"all lower".capitalize`, "All lower"},
 		{`"heLlo\nWoRLd".capitalize`, "Hello\nworld"},
 		{`"🍣HeLlO🍺".capitalize`, "🍣hello🍺"},
+		{`"".capitalize`, ""},
 	}
 
 	for i, tt := range tests {
@@ -355,6 +363,7 @@ func TestStringChopMethod(t *testing.T) {
 		{`"Hello".chop`, "Hell"},
 		{`"Hello\n".chop`, "Hello"},
 		{`"Hello🍣".chop`, "Hello"},
+		{`"".chop`, ""},
 	}
 
 	for i, tt := range tests {
@@ -759,6 +768,27 @@ func TestStringEqualMethodFail(t *testing.T) {
 	}
 }
 
+func TestStringHashMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"foo".hash.is_a?(Integer)`, true},
+		{`"foo".hash == ("f" + "oo").hash`, true},
+		{`"foo".hash == "bar".hash`, false},
+		{`"".hash == "".hash`, true},
+		{`"1".hash == 1.hash`, false},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestStringIncludeMethod(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -1042,6 +1072,7 @@ func TestStringRightJustifyMethod(t *testing.T) {
 		{`"Hello".rjust(7)`, "  Hello"},
 		{`"Hello".rjust(10, "xo")`, "xoxoxHello"},
 		{`"Hello".rjust(10, "🍣🍺")`, "🍣🍺🍣🍺🍣Hello"},
+		{`"🍣🍺".rjust(3, "x")`, "x🍣🍺"},
 	}
 
 	for i, tt := range tests {
@@ -1236,12 +1267,186 @@ func TestStringSplitMethod(t *testing.T) {
 	}
 }
 
+func TestStringSplitMethodWithRegexpAndLimit(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"a1b22c".split(/\d+/)`, []interface{}{"a", "b", "c"}},
+		{`"a-b_c".split(/([-_])/)`, []interface{}{"a", "-", "b", "_", "c"}},
+		{`"abc".split(//)`, []interface{}{"a", "b", "c"}},
+		{`"axb".split(/x*/)`, []interface{}{"a", "b"}},
+		{`"a/b".split(/\//)`, []interface{}{"a", "b"}},
+		{`"日本語".split(/本/)`, []interface{}{"日", "語"}},
+		{`"a1b2c3".split(/\d/, 2)`, []interface{}{"a", "b2c3"}},
+		{`"a,b,c".split(",", 2)`, []interface{}{"a", "b,c"}},
+		{`"a,b,,".split(",")`, []interface{}{"a", "b", "", ""}},
+		{`"a,b,,".split(",", -1)`, []interface{}{"a", "b", "", ""}},
+		{`"a,b,,".split(",", 0)`, []interface{}{"a", "b"}},
+		{`"a1b2".split(/\d/, 0)`, []interface{}{"a", "b"}},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringScanMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"a1 b22 c333".scan(/\d+/)`, []interface{}{"1", "22", "333"}},
+		{`"a1 b22".scan(/(\w)(\d+)/)`, []interface{}{[]interface{}{"a", "1"}, []interface{}{"b", "22"}}},
+		{`"a1 b".scan(/(\w)(\d)?/)`, []interface{}{[]interface{}{"a", "1"}, []interface{}{"b", nil}}},
+		{`"1.5 + 1.5".scan("1.5")`, []interface{}{"1.5", "1.5"}},
+		{`"abc".scan(/x/)`, []interface{}{}},
+		{`
+		result = []
+		s = "a1 b22".scan(/(\w)(\d+)/) do |letter, digits|
+		  result.push(letter + digits.length.to_s)
+		end
+		result.push(s)
+		`, []interface{}{"a1", "b2", "a1 b22"}},
+		{`
+		"abc".scan(/x/) do |m|
+		  m
+		end
+		`, "abc"},
+		{`
+		"abc".scan(/b/) do |m|
+		end
+		`, "abc"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringScanMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`"abc".scan`, "ArgumentError: Expect 1 arguments. got: 0", 1},
+		{`"abc".scan(1)`, "TypeError: Expect argument to be String or Regexp. got: Integer", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringGsubAndSubMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"Hello Goby".gsub(/o/, "0")`, "Hell0 G0by"},
+		{`"Hello Goby".sub(/o/, "0")`, "Hell0 Goby"},
+		{`"a.b.c".gsub(".", "!")`, "a!b!c"},
+		{`"a.b.c".sub(".", "!")`, "a!b.c"},
+		{`"abc".gsub(/x/, "y")`, "abc"},
+		{`"abc".gsub(//, "-")`, "-a-b-c-"},
+		{`"日本語".gsub(/本/, "-")`, "日-語"},
+		// References to the match
+		{`"John Smith".gsub(/(\w+) (\w+)/, '\2, \1')`, "Smith, John"},
+		{`"2018-05".sub(/(?<y>\d+)-(?<m>\d+)/, '\k<m>/\k<y>')`, "05/2018"},
+		{`"x-y".sub(/-/, '[\0|\&|\` + "`" + `|\\]')`, "x[-|-|x|\\]y"},
+		{`"x-y".sub(/-/, "\\'")`, "xyy"},
+		{`"ab".sub(/(a)/, '\2\9')`, "b"},
+		// Blocks
+		{`
+		"a-b-c".gsub("-") do |s|
+		  "+"
+		end
+		`, "a+b+c"},
+		{`
+		"goby lang".gsub(/\w+/) do |word|
+		  word.capitalize
+		end
+		`, "Goby Lang"},
+		{`
+		"goby lang".sub(/\w+/) do |word|
+		  word.upcase
+		end
+		`, "GOBY lang"},
+		{`
+		"goby lang".gsub(/(?<first>\w)\w*/) do |word, m|
+		  m["first"]
+		end
+		`, "g l"},
+		{`
+		"a1b2".gsub(/\d/) do |d|
+		  d.to_i * 2
+		end
+		`, "a2b4"},
+		{`
+		"a1b2".gsub(/\d/) do |d|
+		  nil
+		end
+		`, "ab"},
+		{`
+		"a1b2".gsub(/\d/) do |d|
+		end
+		`, "ab"},
+		{`
+		"abc".gsub(/x/) do |d|
+		  "y"
+		end
+		`, "abc"},
+		// The replacement wins over the block
+		{`
+		"abc".gsub(/b/, "-") do |d|
+		  "y"
+		end
+		`, "a-c"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringGsubAndSubMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`"abc".gsub`, "ArgumentError: Expect 1..2 arguments. got: 0", 1},
+		{`"abc".sub(/a/)`, "ArgumentError: Expect a replacement or a block", 1},
+		{`"abc".gsub(1, "a")`, "TypeError: Expect argument to be String or Regexp. got: Integer", 1},
+		{`"abc".sub(/a/, 1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestStringSplitMethodFail(t *testing.T) {
 	testsFail := []errorTestCase{
-		{`"Hello World".split`, "ArgumentError: Expect 1 argument. got=0", 1},
-		{`"Hello World".split(true)`, "TypeError: Expect argument to be String. got: Boolean", 1},
-		{`"Hello World".split(123)`, "TypeError: Expect argument to be String. got: Integer", 1},
-		{`"Hello World".split(1..2)`, "TypeError: Expect argument to be String. got: Range", 1},
+		{`"Hello World".split`, "ArgumentError: Expect 1..2 arguments. got: 0", 1},
+		{`"Hello World".split(true)`, "TypeError: Expect argument to be String or Regexp. got: Boolean", 1},
+		{`"Hello World".split(123)`, "TypeError: Expect argument to be String or Regexp. got: Integer", 1},
+		{`"Hello World".split(1..2)`, "TypeError: Expect argument to be String or Regexp. got: Range", 1},
+		{`"Hello World".split("o", "1")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`"Hello World".split("o", 1, 2)`, "ArgumentError: Expect 1..2 arguments. got: 3", 1},
 	}
 
 	for i, tt := range testsFail {
@@ -1335,6 +1540,273 @@ func TestStringUpcaseMethod(t *testing.T) {
 
 // Other test
 
+func TestStringBytesizeMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"Goby".bytesize`, 4},
+		{`"".bytesize`, 0},
+		{`"café".bytesize`, 5},
+		{`"日本語".bytesize`, 9},
+		{`"🍣".bytesize`, 4},
+		{`"🍣".length`, 1},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringCharsAndCodepointsMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"Goby".chars`, []interface{}{"G", "o", "b", "y"}},
+		{`"".chars`, []interface{}{}},
+		{`"日本語".chars`, []interface{}{"日", "本", "語"}},
+		{`"🍣🍺".chars`, []interface{}{"🍣", "🍺"}},
+		{`"Goby".codepoints`, []interface{}{71, 111, 98, 121}},
+		{`"é🍣".codepoints`, []interface{}{233, 127843}},
+		{`"café".encode("ISO-8859-1").codepoints`, []interface{}{99, 97, 102, 233}},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringCharsAndCodepointsMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`"Goby".chars(1)`, "ArgumentError: Expect 0 arguments. got: 1", 1},
+		{`"café".encode("ISO-8859-1").force_encoding("UTF-8").codepoints`, "ArgumentError: Invalid byte sequence in UTF-8", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringEncodingMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"Goby".encoding`, "UTF-8"},
+		{`"café".encode("ISO-8859-1").encoding`, "ISO-8859-1"},
+		{`"café".encode("latin1").encoding`, "ISO-8859-1"},
+		{`"café".encode("ISO-8859-1").bytesize`, 4},
+		{`"café".encode("ISO-8859-1").length`, 4},
+		{`"café".encode("ISO-8859-1").encode("UTF-8") == "café"`, true},
+		{`"Goby".encode("US-ASCII").encoding`, "US-ASCII"},
+		{`"café".force_encoding("ISO-8859-1").length`, 5},
+		{`"café".force_encoding("ISO-8859-1").force_encoding("UTF-8") == "café"`, true},
+		{`"café".valid_encoding?`, true},
+		{`"café".encode("ISO-8859-1").valid_encoding?`, true},
+		{`"café".encode("ISO-8859-1").force_encoding("UTF-8").valid_encoding?`, false},
+		{`"café".force_encoding("US-ASCII").valid_encoding?`, false},
+		{`
+		s = "café".encode("ISO-8859-1").force_encoding("UTF-8")
+		s.length
+		`, 4},
+		{`
+		s = "café".encode("ISO-8859-1").force_encoding("UTF-8")
+		s.scrub
+		`, "caf\uFFFD"},
+		{`
+		s = "café".encode("ISO-8859-1").force_encoding("UTF-8")
+		s.scrub("?")
+		`, "caf?"},
+		{`"café".force_encoding("US-ASCII").scrub`, "caf??"},
+		{`"café".scrub`, "café"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringEncodingMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`"Goby".encode`, "ArgumentError: Expect 1 arguments. got: 0", 1},
+		{`"Goby".encode(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`"Goby".encode("EBCDIC")`, "ArgumentError: Unknown encoding name: EBCDIC", 1},
+		{`"日本".encode("ISO-8859-1")`, "EncodingError: U+65E5 from UTF-8 can't be converted to ISO-8859-1", 1},
+		{`"café".encode("US-ASCII")`, "EncodingError: U+00E9 from UTF-8 can't be converted to US-ASCII", 1},
+		{`"café".force_encoding("US-ASCII").encode("UTF-8")`, "EncodingError: Invalid byte sequence in US-ASCII", 1},
+		{`"Goby".force_encoding("EBCDIC")`, "ArgumentError: Unknown encoding name: EBCDIC", 1},
+		{`"Goby".encoding(1)`, "ArgumentError: Expect 0 arguments. got: 1", 1},
+		{`"Goby".valid_encoding?(1)`, "ArgumentError: Expect 0 arguments. got: 1", 1},
+		{`"Goby".scrub("?", "!")`, "ArgumentError: Expect 0..1 arguments. got: 2", 1},
+		{`"Goby".scrub(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringMethodsKeepEncoding(t *testing.T) {
+	latin1 := `s = "héllo".encode("ISO-8859-1")
+	`
+	invalid := `s = "café".encode("ISO-8859-1").force_encoding("UTF-8")
+	`
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{latin1 + `s.reverse.encode("UTF-8")`, "olléh"},
+		{latin1 + `s.upcase.encode("UTF-8")`, "HÉLLO"},
+		{latin1 + `s.upcase.downcase.encode("UTF-8")`, "héllo"},
+		{latin1 + `s.upcase.capitalize.encode("UTF-8")`, "Héllo"},
+		{latin1 + `s[1].encode("UTF-8")`, "é"},
+		{latin1 + `s[-4..-3].encode("UTF-8")`, "él"},
+		{latin1 + `s.slice(1..2).encode("UTF-8")`, "él"},
+		{latin1 + `s.slice(1).encoding`, "ISO-8859-1"},
+		{latin1 + `s.chop.encode("UTF-8")`, "héll"},
+		{latin1 + `s.insert(1, "-").encode("UTF-8")`, "h-éllo"},
+		{latin1 + `s.ljust(7, "!").encode("UTF-8")`, "héllo!!"},
+		{latin1 + `s.rjust(7).encode("UTF-8")`, "  héllo"},
+		{latin1 + `(" " + s + " ").strip.encode("UTF-8")`, "héllo"},
+		{latin1 + `s.delete("l").encode("UTF-8")`, "héo"},
+		{latin1 + `(s * 2).encode("UTF-8")`, "héllohéllo"},
+		{`("héllo".encode("ISO-8859-1")[0] = "j").encode("UTF-8")`, "jéllo"},
+		{latin1 + `(s + "!").encoding`, "ISO-8859-1"},
+		{latin1 + `("!" + s).encoding`, "ISO-8859-1"},
+		{latin1 + `s.concat("!").encode("UTF-8")`, "héllo!"},
+		{latin1 + `(s + "é".encode("ISO-8859-1")).encode("UTF-8")`, "hélloé"},
+		{latin1 + `s.start_with("hé".encode("ISO-8859-1"))`, true},
+		{latin1 + `s.end_with?("llo")`, true},
+		{invalid + `s.reverse.valid_encoding?`, false},
+		{invalid + `s.reverse.reverse == s`, true},
+		{invalid + `s.upcase.bytesize`, 4},
+		{invalid + `s.upcase.scrub("?")`, "CAF?"},
+		{invalid + `s[3].bytesize`, 1},
+		{invalid + `s.chop == "caf"`, true},
+		{`"Goby\n".strip`, "Goby"},
+		{`"\t Goby Lang\r\n".strip`, "Goby Lang"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringMethodsKeepEncodingFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`"héllo".encode("ISO-8859-1") + "é"`, "Encoding::CompatibilityError: Incompatible character encodings: ISO-8859-1 and UTF-8", 1},
+		{`"é" + "héllo".encode("ISO-8859-1")`, "Encoding::CompatibilityError: Incompatible character encodings: UTF-8 and ISO-8859-1", 1},
+		{`"héllo".encode("ISO-8859-1").concat("é")`, "Encoding::CompatibilityError: Incompatible character encodings: ISO-8859-1 and UTF-8", 1},
+		{`"héllo".encode("ISO-8859-1").insert(0, "é")`, "Encoding::CompatibilityError: Incompatible character encodings: ISO-8859-1 and UTF-8", 1},
+		{`"héllo".encode("ISO-8859-1").ljust(7, "é")`, "Encoding::CompatibilityError: Incompatible character encodings: ISO-8859-1 and UTF-8", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringGraphemeClustersMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"Goby".grapheme_clusters`, []interface{}{"G", "o", "b", "y"}},
+		{`"é".unicode_normalize(:nfd).grapheme_clusters`, []interface{}{"e\u0301"}},
+		{`"👍🏽".grapheme_clusters`, []interface{}{"👍🏽"}},
+		{`"🇯🇵🇺🇸".grapheme_clusters`, []interface{}{"🇯🇵", "🇺🇸"}},
+		{`"👨‍👩‍👧!".grapheme_clusters`, []interface{}{"👨‍👩‍👧", "!"}},
+		{`"한국".unicode_normalize(:nfd).grapheme_clusters.length`, 2},
+		{`"a\r\nb".grapheme_clusters.length`, 3},
+		{`"🇯🇵🇺".grapheme_clusters`, []interface{}{"🇯🇵", "🇺"}},
+		{`"👨🏽‍🦰".grapheme_clusters`, []interface{}{"👨🏽‍🦰"}},
+		// A zero width joiner only joins emoji when it follows one
+		{"\"a\u200d😀\".grapheme_clusters", []interface{}{"a\u200d", "😀"}},
+		// Spacing marks and prepended concatenation marks stay with their base character
+		{`"नमस्ते".grapheme_clusters`, []interface{}{"न", "म", "स्", "ते"}},
+		{"\"\u06001\".grapheme_clusters", []interface{}{"\u06001"}},
+		{"\"\u1100\u1161\u11a8\".grapheme_clusters.length", 1},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringUnicodeNormalizeMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"é".unicode_normalize(:nfd)`, "e\u0301"},
+		{`"é".unicode_normalize(:nfd).length`, 2},
+		{`"é".unicode_normalize(:nfd).unicode_normalize`, "é"},
+		{`"é".unicode_normalize(:nfd).unicode_normalize(:nfc).length`, 1},
+		{`"ｶ".unicode_normalize(:nfkc)`, "カ"},
+		{`"ﬁ".unicode_normalize(:nfkd)`, "fi"},
+		{`"ﬁ".unicode_normalize(:nfc)`, "ﬁ"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringUnicodeNormalizeMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`"Goby".unicode_normalize(:nfx)`, "ArgumentError: Invalid normalization form: nfx", 1},
+		{`"Goby".unicode_normalize(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`"Goby".unicode_normalize(:nfc, :nfd)`, "ArgumentError: Expect 0..1 arguments. got: 2", 1},
+		{`"café".encode("ISO-8859-1").unicode_normalize`, "EncodingError: Unicode normalization is not applicable to ISO-8859-1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestStringMethodChaining(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -1360,6 +1832,8 @@ func TestFormattedString(t *testing.T) {
 		{`String.fmt("This is %s", "goby")`, "This is goby"},
 		{`String.fmt("This is %slang", "goby")`, "This is gobylang"},
 		{`String.fmt("This is %s %s", "goby", "ruby")`, "This is goby ruby"},
+		{`String.fmt("%d%%", 50)`, "50%"},
+		{`String.fmt("no directives")`, "no directives"},
 	}
 
 	for i, tt := range tests {
@@ -3,6 +3,7 @@ package vm
 import (
 	"fmt"
 	"io/ioutil"
+	"math/big"
 	"os"
 	"path/filepath"
 	"strings"
@@ -32,6 +33,26 @@ func (t *Thread) VM() *VM {
 	return t.vm
 }
 
+// BlockGiven returns true if a block is passed to the native method that's being executed.
+func (t *Thread) BlockGiven() bool {
+	cf, ok := t.callFrameStack.top().(*goMethodCallFrame)
+
+	return ok && cf.blockFrame != nil
+}
+
+// Yield calls the block passed to the native method that's being executed with the given arguments,
+// and returns the block's result. Native methods should check BlockGiven before calling it.
+func (t *Thread) Yield(args ...Object) Object {
+	cf := t.callFrameStack.top().(*goMethodCallFrame)
+
+	return t.builtinMethodYield(cf.blockFrame, args...).Target
+}
+
+// LoadFile executes the Goby file at the given path, like require_relative does.
+func (t *Thread) LoadFile(path string) error {
+	return t.execFile(path)
+}
+
 func (t *Thread) isMainThread() bool {
 	return t.id == mainThreadID
 }
@@ -251,8 +272,8 @@ func (t *Thread) builtinMethodYield(blockFrame *normalCallFrame, args ...Object)
 	c.sourceLine = blockFrame.SourceLine()
 	c.isBlock = true
 
-	for i := 0; i < len(args); i++ {
-		c.insertLCL(i, 0, args[i])
+	for i, arg := range blockFrame.instructionSet.destructureBlockArgs(args) {
+		c.insertLCL(i, 0, arg)
 	}
 
 	t.callFrameStack.push(c)
@@ -347,6 +368,22 @@ func (t *Thread) sendMethod(methodName string, argCount int, blockFrame *normalC
 	}
 }
 
+// callMethod calls the receiver's method with the arguments from Go code, like native methods do, and
+// returns the result.
+func (t *Thread) callMethod(receiver Object, methodName string, sourceLine int, args ...Object) Object {
+	t.Stack.Push(&Pointer{Target: receiver})
+	// sendMethod expects the method name to be on the stack, like the `send` method has it
+	t.Stack.Push(&Pointer{Target: NULL})
+
+	for _, arg := range args {
+		t.Stack.Push(&Pointer{Target: arg})
+	}
+
+	t.sendMethod(methodName, len(args), nil, sourceLine)
+
+	return t.Stack.Pop().Target
+}
+
 func (t *Thread) evalBuiltinMethod(receiver Object, method *BuiltinMethodObject, receiverPtr, argCount int, argSet *bytecode.ArgSet, blockFrame *normalCallFrame, sourceLine int, fileName string) {
 	cf := newGoMethodCallFrame(method.Fn(receiver, sourceLine), method.Name, fileName, sourceLine)
 	cf.sourceLine = sourceLine
@@ -419,8 +456,7 @@ func (t *Thread) evalMethodObject(call *callObject, sourceLine int) {
 			case bytecode.NormalArg, bytecode.OptionedArg:
 				call.assignNormalAndOptionedArguments(paramIndex, stack)
 			case bytecode.SplatArg:
-				call.argIndex = paramIndex
-				call.assignSplatArgument(stack, t.vm.InitArrayObject([]Object{}))
+				call.assignSplatArgument(stack, t.vm.InitArrayObject([]Object{}), t.vm.InitHashObject(map[string]Object{}))
 			}
 		}
 	} else {
@@ -468,3 +504,118 @@ func blockIsEmpty(blockFrame *normalCallFrame) bool {
 	}
 	return false
 }
+
+// compareObjects compares Integers, Floats and Strings by their values. It returns false if the
+// objects can't be compared.
+func compareObjects(a, b Object) (int, bool) {
+	switch a := a.(type) {
+	case *IntegerObject:
+		switch b := b.(type) {
+		case *IntegerObject:
+			return compareIntegers(a, b), true
+		case *FloatObject:
+			result, ok := compareFloatToInteger(b.value, a)
+			return -result, ok
+		}
+	case *FloatObject:
+		switch b := b.(type) {
+		case *IntegerObject:
+			return compareFloatToInteger(a.value, b)
+		case *FloatObject:
+			return a.compare(b)
+		}
+	case *StringObject:
+		if b, ok := b.(*StringObject); ok {
+			return strings.Compare(a.value, b.value), true
+		}
+	}
+
+	return 0, false
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compare compares two objects with compareObjects, or with their `<=>` method if it can't compare them.
+// It returns an error if `<=>` isn't defined or doesn't return an Integer.
+func (t *Thread) compare(a, b Object, sourceLine int) (int, *Error) {
+	if result, ok := compareObjects(a, b); ok {
+		return result, nil
+	}
+
+	if result, ok := t.spaceship(a, b, sourceLine); ok {
+		return result, nil
+	}
+
+	return 0, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.CantCompareFormat, a.Class().Name, b.Class().Name)
+}
+
+// spaceship calls a's `<=>` method with b. It returns false if the method isn't defined or doesn't return
+// an Integer, which is how `<=>` tells the objects can't be compared.
+func (t *Thread) spaceship(a, b Object, sourceLine int) (int, bool) {
+	switch a.(type) {
+	// compareObjects already compared them with every object their `<=>` accepts
+	case *IntegerObject, *FloatObject, *StringObject:
+		return 0, false
+	}
+
+	if a.findMethod("<=>") == nil {
+		return 0, false
+	}
+
+	result, ok := t.callMethod(a, "<=>", sourceLine, b).(*IntegerObject)
+
+	if !ok {
+		return 0, false
+	}
+
+	return result.comparisonValue(), true
+}
+
+// compareElements compares two elements with the block if it's given, which returns an Integer like
+// `<=>` does, or with compare otherwise.
+func (t *Thread) compareElements(blockFrame *normalCallFrame, a, b Object, sourceLine int) (int, *Error) {
+	if blockFrame == nil {
+		return t.compare(a, b, sourceLine)
+	}
+
+	returned := t.builtinMethodYield(blockFrame, a, b).Target
+	result, ok := returned.(*IntegerObject)
+
+	if !ok {
+		return 0, t.vm.InitErrorObject(errors.TypeError, sourceLine, "Expect the block to return Integer. got: %s", returned.Class().Name)
+	}
+
+	return result.comparisonValue(), nil
+}
+
+// add returns the sum of two objects. Integers and Floats are added directly, other objects with
+// their `+` method.
+func (t *Thread) add(a, b Object, sourceLine int) Object {
+	switch a := a.(type) {
+	case *IntegerObject:
+		switch b := b.(type) {
+		case *IntegerObject:
+			return t.vm.integerOperation(a, b, addInts, (*big.Int).Add)
+		case *FloatObject:
+			return t.vm.initFloatObject(a.floatValue() + b.value)
+		}
+	case *FloatObject:
+		switch b := b.(type) {
+		case *IntegerObject:
+			return t.vm.initFloatObject(a.value + b.floatValue())
+		case *FloatObject:
+			return t.vm.initFloatObject(a.value + b.value)
+		}
+	}
+
+	return t.callMethod(a, "+", sourceLine, b)
+}
@@ -0,0 +1,1094 @@
+package vm
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// TimeObject represents an instant in time with nanosecond precision in a time zone, backed by Go's `time.Time`.
+// Time zones can be given as "UTC", as an offset like "+09:00" or "-0500", or as an IANA name like "Asia/Tokyo".
+// Times without a zone are in the local time zone.
+//
+// Subtracting two Times returns a Duration, and adding a Duration or a number of seconds to a Time returns a new Time.
+// Times include Comparable, and are serialized to JSON in the RFC 3339 format.
+//
+// ```ruby
+// t = Time.new(2024, 1, 2, 3, 4, 5, "UTC")
+// t.to_s                        # => "2024-01-02 03:04:05 UTC"
+// t.iso8601                     # => "2024-01-02T03:04:05Z"
+// t.strftime("%b %-d, %Y")      # => "Jan 2, 2024"
+// (t + 60).min                  # => 5
+// t.localtime("+09:00").hour    # => 12
+// (Time.now - t).class          # => Duration
+// Time.measure do
+//
+//	heavy_work
+//
+// end                           # => 1.234s
+// ```
+type TimeObject struct {
+	*baseObj
+	value time.Time
+}
+
+// Class methods --------------------------------------------------------
+func builtinTimeClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns the Time that's the given number of seconds after the Unix epoch, in the given time zone
+			// or the local one.
+			//
+			// ```ruby
+			// Time.at(0, "UTC").to_s             # => "1970-01-01 00:00:00 UTC"
+			// Time.at(1.5, "UTC").nsec           # => 500000000
+			// Time.at(1704164645).to_i           # => 1704164645
+			// ```
+			// @param seconds [Numeric], zone [String]
+			// @return [Time]
+			Name: "at",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) < 1 || len(args) > 2 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRangeFormat, 1, 2, len(args))
+					}
+
+					var tm time.Time
+
+					switch seconds := args[0].(type) {
+					case *IntegerObject:
+						if seconds.bigValue != nil {
+							return t.vm.InitErrorObject(errors.RangeError, sourceLine, "%s is out of range for Time", seconds.toString())
+						}
+
+						tm = time.Unix(int64(seconds.value), 0)
+					case *FloatObject:
+						whole, fraction := math.Modf(seconds.value)
+						if math.IsNaN(seconds.value) || math.Abs(whole) >= math.MaxInt64 {
+							return t.vm.InitErrorObject(errors.RangeError, sourceLine, "%s is out of range for Time", seconds.toString())
+						}
+
+						tm = time.Unix(int64(whole), int64(math.Round(fraction*1e9)))
+					default:
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Numeric", args[0].Class().Name)
+					}
+
+					loc, err := t.locationArg(args, 1, sourceLine)
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initTimeObject(tm.In(loc))
+				}
+			},
+		},
+		{
+			// Yields the block and returns how long it took as a Duration. It uses a monotonic clock,
+			// so changes of the system clock don't affect it.
+			//
+			// ```ruby
+			// d = Time.measure do
+			//   sleep(1)
+			// end
+			// d.to_i # => 1
+			// ```
+			// @param block [Block]
+			// @return [Duration]
+			Name: "measure",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					if blockFrame == nil {
+						return t.vm.InitErrorObject(errors.InternalError, sourceLine, errors.CantYieldWithoutBlockFormat)
+					}
+
+					start := t.vm.clock()
+					t.builtinMethodYield(blockFrame)
+
+					return t.vm.initDurationObject(t.vm.clock().Sub(start))
+				}
+			},
+		},
+		{
+			// Returns the Time of the given date and time of day in the given time zone, or the local one.
+			// The seconds can be a Float. Without arguments, it returns the current time like `Time.now`.
+			//
+			// ```ruby
+			// Time.new(2024, 1, 2).to_s                     # => "2024-01-02 00:00:00 +0900"
+			// Time.new(2024, 1, 2, 3, 4, 5.5, "UTC").to_s   # => "2024-01-02 03:04:05 UTC"
+			// Time.new(2024, 13, 1)                         # => ArgumentError
+			// ```
+			// @param year [Integer], month [Integer], day [Integer], hour [Integer], min [Integer], sec [Numeric], zone [String]
+			// @return [Time]
+			Name: "new",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) == 0 {
+						return t.vm.initTimeObject(t.vm.clock())
+					}
+
+					if len(args) > 7 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRangeFormat, 0, 7, len(args))
+					}
+
+					// year, month, day, hour, min, sec
+					fields := []int{0, 1, 1, 0, 0, 0}
+					limits := [][2]int{{math.MinInt32, math.MaxInt32}, {1, 12}, {1, 31}, {0, 24}, {0, 59}, {0, 60}}
+					nsec := 0
+
+					for i := 0; i < len(args) && i < len(fields); i++ {
+						switch arg := args[i].(type) {
+						case *IntegerObject:
+							fields[i] = arg.value
+							if arg.bigValue != nil {
+								fields[i] = limits[i][1] + 1
+							}
+						case *FloatObject:
+							if i != len(fields)-1 {
+								return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, arg.Class().Name)
+							}
+
+							whole, fraction := math.Modf(arg.value)
+							fields[i] = int(whole)
+							nsec = int(math.Round(fraction * 1e9))
+						default:
+							return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, arg.Class().Name)
+						}
+
+						if fields[i] < limits[i][0] || fields[i] > limits[i][1] {
+							return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Argument out of range: %s", args[i].toString())
+						}
+					}
+
+					loc, err := t.locationArg(args, 6, sourceLine)
+					if err != nil {
+						return err
+					}
+
+					tm := time.Date(fields[0], time.Month(fields[1]), fields[2], fields[3], fields[4], fields[5], nsec, loc)
+
+					return t.vm.initTimeObject(tm)
+				}
+			},
+		},
+		{
+			// Returns the current time in the given time zone, or the local one.
+			//
+			// ```ruby
+			// Time.now            # => 2024-01-02 12:04:05 +0900
+			// Time.now("UTC")     # => 2024-01-02 03:04:05 UTC
+			// ```
+			// @param zone [String]
+			// @return [Time]
+			Name: "now",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) > 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRangeFormat, 0, 1, len(args))
+					}
+
+					loc, err := t.locationArg(args, 0, sourceLine)
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initTimeObject(t.vm.clock().In(loc))
+				}
+			},
+		},
+		{
+			// Parses a String into a Time. Without a format, it accepts the RFC 3339 format, the format of `Time#to_s`,
+			// "2006-01-02 15:04:05", "2006-01-02" and the RFC 1123 format. Otherwise the format uses the directives of
+			// `strftime`, like "%d/%m/%Y %H:%M". Times without a zone are in the local time zone.
+			//
+			// ```ruby
+			// Time.parse("2024-01-02T03:04:05Z").to_i               # => 1704164645
+			// Time.parse("2024-01-02 03:04:05 +0900").hour          # => 3
+			// Time.parse("02/01/2024 03:04", "%d/%m/%Y %H:%M").month # => 1
+			// Time.parse("yesterday")                               # => ArgumentError
+			// ```
+			// @param time [String], format [String]
+			// @return [Time]
+			Name: "parse",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					tm, err := t.parseTimeArgs(args, timeLayouts, sourceLine)
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initTimeObject(tm)
+				}
+			},
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinTimeInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns the Time that's a Duration or a number of seconds after self.
+			//
+			// ```ruby
+			// t = Time.new(2024, 1, 2, 3, 4, 5, "UTC")
+			// (t + 60).to_s                    # => "2024-01-02 03:05:05 UTC"
+			// (t + Duration.hours(1)).to_s     # => "2024-01-02 04:04:05 UTC"
+			// ```
+			// @param duration [Duration/Numeric]
+			// @return [Time]
+			Name: "+",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					d, err := t.durationArg(args[0], sourceLine)
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initTimeObject(receiver.(*TimeObject).value.Add(d))
+				}
+			},
+		},
+		{
+			// Returns the Duration between self and another Time, or the Time that's a Duration or a number
+			// of seconds before self. Raises a RangeError if the Times are more than about 292 years apart.
+			//
+			// ```ruby
+			// t = Time.new(2024, 1, 2, 3, 4, 5, "UTC")
+			// (t - Time.new(2024, 1, 2, 0, 0, 0, "UTC")).to_s # => "3h4m5s"
+			// (t - 5).to_s                                    # => "2024-01-02 03:04:00 UTC"
+			// ```
+			// @param other [Time/Duration/Numeric]
+			// @return [Duration/Time]
+			Name: "-",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					tm := receiver.(*TimeObject).value
+
+					if other, ok := args[0].(*TimeObject); ok {
+						d := tm.Sub(other.value)
+
+						// Sub saturates instead of overflowing, so adding the result back gives another Time
+						if !other.value.Add(d).Equal(tm) {
+							return t.vm.InitErrorObject(errors.RangeError, sourceLine, "Duration is out of range")
+						}
+
+						return t.vm.initDurationObject(d)
+					}
+
+					d, err := t.durationArg(args[0], sourceLine)
+					if err != nil {
+						return err
+					}
+
+					if d == math.MinInt64 {
+						return t.vm.InitErrorObject(errors.RangeError, sourceLine, "Duration is out of range")
+					}
+
+					return t.vm.initTimeObject(tm.Add(-d))
+				}
+			},
+		},
+		{
+			// Returns 1 if self is later than another Time, -1 if it's earlier, 0 if they're the same instant,
+			// even in different time zones, and nil for anything that isn't a Time. Times include Comparable,
+			// so `<`, `==`, `between?` and the like are based on this method.
+			//
+			// ```ruby
+			// Time.at(0, "UTC") <=> Time.at(1, "UTC")          # => -1
+			// Time.at(0, "UTC") == Time.at(0, "+09:00")        # => true
+			// ```
+			// @param other [Time]
+			// @return [Integer]
+			Name: "<=>",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					other, ok := args[0].(*TimeObject)
+					if !ok {
+						return NULL
+					}
+
+					return t.vm.InitIntegerObject(compareTimes(receiver.(*TimeObject).value, other.value))
+				}
+			},
+		},
+		{
+			// Returns the day of the month of self, from 1 to 31.
+			//
+			// ```ruby
+			// Time.new(2024, 1, 2).day # => 2
+			// ```
+			// @return [Integer]
+			Name: "day",
+			Fn:   timeField(func(tm time.Time) int { return tm.Day() }),
+		},
+		{
+			// Returns the hour of the day of self, from 0 to 23.
+			//
+			// ```ruby
+			// Time.new(2024, 1, 2, 3, 4, 5).hour # => 3
+			// ```
+			// @return [Integer]
+			Name: "hour",
+			Fn:   timeField(func(tm time.Time) int { return tm.Hour() }),
+		},
+		{
+			// Returns self in the ISO 8601 format, with the given number of fractional second digits, which defaults to 0.
+			//
+			// ```ruby
+			// t = Time.at(1704164645.5, "UTC")
+			// t.iso8601                         # => "2024-01-02T03:04:05Z"
+			// t.iso8601(3)                      # => "2024-01-02T03:04:05.500Z"
+			// t.localtime("+09:00").iso8601     # => "2024-01-02T12:04:05+09:00"
+			// ```
+			// @param digits [Integer]
+			// @return [String]
+			Name: "iso8601",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) > 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRangeFormat, 0, 1, len(args))
+					}
+
+					digits := 0
+					if len(args) == 1 {
+						d, ok := args[0].(*IntegerObject)
+						if !ok {
+							return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+						}
+
+						if d.bigValue != nil || d.value < 0 || d.value > 9 {
+							return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect the number of digits to be between 0 and 9. got: %s", d.toString())
+						}
+						digits = d.value
+					}
+
+					layout := "2006-01-02T15:04:05Z07:00"
+					if digits > 0 {
+						layout = "2006-01-02T15:04:05." + "000000000"[:digits] + "Z07:00"
+					}
+
+					return t.vm.InitStringObject(receiver.(*TimeObject).value.Format(layout))
+				}
+			},
+		},
+		{
+			// Returns the same instant as self in the given time zone, or the local one.
+			//
+			// ```ruby
+			// t = Time.new(2024, 1, 2, 3, 4, 5, "UTC")
+			// t.localtime("+09:00").to_s         # => "2024-01-02 12:04:05 +0900"
+			// t.localtime("America/New_York").hour # => 22
+			// ```
+			// @param zone [String]
+			// @return [Time]
+			Name: "localtime",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) > 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRangeFormat, 0, 1, len(args))
+					}
+
+					loc, err := t.locationArg(args, 0, sourceLine)
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initTimeObject(receiver.(*TimeObject).value.In(loc))
+				}
+			},
+		},
+		{
+			// Returns the minute of the hour of self, from 0 to 59.
+			//
+			// ```ruby
+			// Time.new(2024, 1, 2, 3, 4, 5).min # => 4
+			// ```
+			// @return [Integer]
+			Name: "min",
+			Fn:   timeField(func(tm time.Time) int { return tm.Minute() }),
+		},
+		{
+			// Returns the month of the year of self, from 1 to 12.
+			//
+			// ```ruby
+			// Time.new(2024, 1, 2).month # => 1
+			// ```
+			// @return [Integer]
+			Name: "month",
+			Fn:   timeField(func(tm time.Time) int { return int(tm.Month()) }),
+		},
+		{
+			// Returns the nanoseconds of the second of self.
+			//
+			// ```ruby
+			// Time.at(1.25).nsec # => 250000000
+			// ```
+			// @return [Integer]
+			Name: "nsec",
+			Fn:   timeField(func(tm time.Time) int { return tm.Nanosecond() }),
+		},
+		{
+			// Returns the second of the minute of self, from 0 to 59.
+			//
+			// ```ruby
+			// Time.new(2024, 1, 2, 3, 4, 5).sec # => 5
+			// ```
+			// @return [Integer]
+			Name: "sec",
+			Fn:   timeField(func(tm time.Time) int { return tm.Second() }),
+		},
+		{
+			// Formats self with the given format, which can contain these directives:
+			//
+			// - `%Y`, `%C`, `%y`: the year, its century and its last two digits
+			// - `%m`, `%B`, `%b`: the month number, the month name and its abbreviation
+			// - `%d`, `%e`, `%j`: the day of the month, padded with zeros or blanks, and the day of the year
+			// - `%H`, `%k`, `%I`, `%l`, `%P`, `%p`: the hour, padded with zeros or blanks, on a 12-hour clock and "am"/"AM"
+			// - `%M`, `%S`, `%L`, `%N`: the minute, the second, its milliseconds and its nanoseconds
+			// - `%A`, `%a`, `%u`, `%w`: the weekday name, its abbreviation, and its number from Monday (1) or Sunday (0)
+			// - `%z`, `%:z`, `%Z`: the zone offset like "+0900" or "+09:00", and the zone name
+			// - `%s`: the number of seconds since the Unix epoch
+			// - `%F`, `%T`, `%D`, `%R`, `%r`, `%c`: "%Y-%m-%d", "%H:%M:%S", "%m/%d/%y", "%H:%M", "%I:%M:%S %p", "%a %b %e %H:%M:%S %Y"
+			// - `%%`, `%n`, `%t`: a percent sign, a newline and a tab
+			//
+			// A `-` after the `%` removes the padding of a number, like `%-d`. Unknown directives are kept as they are.
+			//
+			// ```ruby
+			// t = Time.new(2024, 1, 2, 15, 4, 5, "UTC")
+			// t.strftime("%Y-%m-%d %H:%M:%S %z") # => "2024-01-02 15:04:05 +0000"
+			// t.strftime("%b %-d, %Y %-I%P")     # => "Jan 2, 2024 3pm"
+			// ```
+			// @param format [String]
+			// @return [String]
+			Name: "strftime",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 1 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 1, len(args))
+					}
+
+					format, ok := args[0].(*StringObject)
+					if !ok {
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+					}
+
+					return t.vm.InitStringObject(strftime(receiver.(*TimeObject).value, format.value))
+				}
+			},
+		},
+		{
+			// Returns the date of self in its time zone.
+			//
+			// ```ruby
+			// Time.new(2024, 1, 2, 23, 0, 0, "UTC").to_date.to_s                     # => "2024-01-02"
+			// Time.new(2024, 1, 2, 23, 0, 0, "UTC").localtime("+09:00").to_date.to_s # => "2024-01-03"
+			// ```
+			// @return [Date]
+			Name: "to_date",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					tm := receiver.(*TimeObject).value
+
+					return t.vm.initDateObject(tm.Year(), tm.Month(), tm.Day())
+				}
+			},
+		},
+		{
+			// Returns the number of seconds since the Unix epoch as a Float.
+			//
+			// ```ruby
+			// Time.at(1.5).to_f # => 1.5
+			// ```
+			// @return [Float]
+			Name: "to_f",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					tm := receiver.(*TimeObject).value
+
+					return t.vm.initFloatObject(float64(tm.Unix()) + float64(tm.Nanosecond())/1e9)
+				}
+			},
+		},
+		{
+			// Returns the number of whole seconds since the Unix epoch.
+			//
+			// ```ruby
+			// Time.parse("2024-01-02T03:04:05Z").to_i # => 1704164645
+			// ```
+			// @return [Integer]
+			Name: "to_i",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return t.vm.InitIntegerObject(int(receiver.(*TimeObject).value.Unix()))
+				}
+			},
+		},
+		{
+			// Returns self as a JSON string in the RFC 3339 format, with nanoseconds if it has any.
+			//
+			// ```ruby
+			// Time.at(0, "UTC").to_json   # => "\"1970-01-01T00:00:00Z\""
+			// Time.at(1.5, "UTC").to_json # => "\"1970-01-01T00:00:01.5Z\""
+			// ```
+			// @return [String]
+			Name: "to_json",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return t.vm.InitStringObject(receiver.(*TimeObject).toJSON(t))
+				}
+			},
+		},
+		{
+			// Returns self formatted like "2024-01-02 03:04:05 +0900", or "2024-01-02 03:04:05 UTC" in UTC.
+			//
+			// ```ruby
+			// Time.at(0, "UTC").to_s    # => "1970-01-01 00:00:00 UTC"
+			// Time.at(0, "-05:00").to_s # => "1969-12-31 19:00:00 -0500"
+			// ```
+			// @return [String]
+			Name: "to_s",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return t.vm.InitStringObject(receiver.(*TimeObject).toString())
+				}
+			},
+		},
+		{
+			// Returns the same instant as self in UTC.
+			//
+			// ```ruby
+			// Time.new(2024, 1, 2, 12, 0, 0, "+09:00").utc.to_s # => "2024-01-02 03:00:00 UTC"
+			// ```
+			// @return [Time]
+			Name: "utc",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return t.vm.initTimeObject(receiver.(*TimeObject).value.UTC())
+				}
+			},
+		},
+		{
+			// Returns true if self is in UTC.
+			//
+			// ```ruby
+			// Time.now("UTC").utc?    # => true
+			// Time.now("+09:00").utc? # => false
+			// ```
+			// @return [Boolean]
+			Name: "utc?",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					return toBooleanObject(receiver.(*TimeObject).value.Location() == time.UTC)
+				}
+			},
+		},
+		{
+			// Returns the offset of the time zone of self from UTC in seconds.
+			//
+			// ```ruby
+			// Time.now("+09:00").utc_offset # => 32400
+			// ```
+			// @return [Integer]
+			Name: "utc_offset",
+			Fn: timeField(func(tm time.Time) int {
+				_, offset := tm.Zone()
+				return offset
+			}),
+		},
+		{
+			// Returns the day of the week of self, from 0 (Sunday) to 6 (Saturday).
+			//
+			// ```ruby
+			// Time.new(2024, 1, 2).wday # => 2
+			// ```
+			// @return [Integer]
+			Name: "wday",
+			Fn:   timeField(func(tm time.Time) int { return int(tm.Weekday()) }),
+		},
+		{
+			// Returns the day of the year of self, from 1 to 366.
+			//
+			// ```ruby
+			// Time.new(2024, 2, 1).yday # => 32
+			// ```
+			// @return [Integer]
+			Name: "yday",
+			Fn:   timeField(func(tm time.Time) int { return tm.YearDay() }),
+		},
+		{
+			// Returns the year of self.
+			//
+			// ```ruby
+			// Time.new(2024, 1, 2).year # => 2024
+			// ```
+			// @return [Integer]
+			Name: "year",
+			Fn:   timeField(func(tm time.Time) int { return tm.Year() }),
+		},
+		{
+			// Returns the name of the time zone of self, like "UTC", "JST" or "+09:00".
+			//
+			// ```ruby
+			// Time.now("UTC").zone        # => "UTC"
+			// Time.now("Asia/Tokyo").zone # => "JST"
+			// ```
+			// @return [String]
+			Name: "zone",
+			Fn: func(receiver Object, sourceLine int) builtinMethodBody {
+				return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+					if len(args) != 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+					}
+
+					name, _ := receiver.(*TimeObject).value.Zone()
+
+					return t.vm.InitStringObject(name)
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initTimeObject(value time.Time) *TimeObject {
+	return &TimeObject{
+		baseObj: &baseObj{class: vm.topLevelClass(classes.TimeClass)},
+		value:   value,
+	}
+}
+
+func (vm *VM) initTimeClass() *RClass {
+	tc := vm.initializeClass(classes.TimeClass)
+	tc.setBuiltinMethods(builtinTimeInstanceMethods(), false)
+	tc.setBuiltinMethods(builtinTimeClassMethods(), true)
+	return tc
+}
+
+// initTimeComparisons includes Comparable in Time, Date and Duration, which needs the module to be initialized.
+func (vm *VM) initTimeComparisons() {
+	comparable := vm.topLevelClass(classes.ComparableModule)
+
+	for _, name := range []string{classes.TimeClass, classes.DateClass, classes.DurationClass} {
+		vm.topLevelClass(name).includeModule(comparable)
+	}
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// Value returns the object
+func (t *TimeObject) Value() interface{} {
+	return t.value
+}
+
+// toString returns the object's value formatted like "2024-01-02 03:04:05 +0900"
+func (t *TimeObject) toString() string {
+	if t.value.Location() == time.UTC {
+		return t.value.Format("2006-01-02 15:04:05 UTC")
+	}
+
+	return t.value.Format("2006-01-02 15:04:05 -0700")
+}
+
+// toJSON returns the object's value in the RFC 3339 format
+func (t *TimeObject) toJSON(thread *Thread) string {
+	return strconv.Quote(t.value.Format(time.RFC3339Nano))
+}
+
+// Other helper functions -----------------------------------------------
+
+// timeLayouts are the layouts `Time.parse` tries when it isn't given a format.
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 Z07:00",
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// parseTimeArgs parses the String in args with the format in args if there is one, or with the first of the
+// layouts that matches it. Times without a zone are in the local time zone.
+func (t *Thread) parseTimeArgs(args []Object, layouts []string, sourceLine int) (time.Time, *Error) {
+	if len(args) < 1 || len(args) > 2 {
+		return time.Time{}, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRangeFormat, 1, 2, len(args))
+	}
+
+	for _, arg := range args {
+		if _, ok := arg.(*StringObject); !ok {
+			return time.Time{}, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, arg.Class().Name)
+		}
+	}
+
+	s := args[0].(*StringObject).value
+
+	if len(args) == 2 {
+		format := args[1].(*StringObject).value
+
+		layout, ok := strptimeLayout(format)
+		if !ok {
+			return time.Time{}, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Unsupported format: %q", format)
+		}
+
+		layouts = []string{layout}
+	}
+
+	for _, layout := range layouts {
+		if tm, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return tm, nil
+		}
+	}
+
+	if len(args) == 2 {
+		return time.Time{}, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Can't parse %q with format %q", s, args[1].(*StringObject).value)
+	}
+
+	return time.Time{}, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Can't parse %q", s)
+}
+
+// locationArg returns the time zone named by args[index], or the local time zone if there's no such argument.
+func (t *Thread) locationArg(args []Object, index int, sourceLine int) (*time.Location, *Error) {
+	if len(args) <= index {
+		return time.Local, nil
+	}
+
+	name, ok := args[index].(*StringObject)
+	if !ok {
+		return nil, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, args[index].Class().Name)
+	}
+
+	loc, ok := loadLocation(name.value)
+	if !ok {
+		return nil, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Invalid time zone: %q", name.value)
+	}
+
+	return loc, nil
+}
+
+// loadLocation returns the time zone with the given name, which can be "UTC", an offset like "+09:00" or
+// "-0500", or an IANA name like "Asia/Tokyo".
+func loadLocation(name string) (*time.Location, bool) {
+	switch name {
+	case "UTC", "Z":
+		return time.UTC, true
+	case "", "Local":
+		return nil, false
+	}
+
+	if name[0] == '+' || name[0] == '-' {
+		digits := name[1:]
+		if len(digits) == 5 && digits[2] == ':' {
+			digits = digits[:2] + digits[3:]
+		}
+
+		if len(digits) != 4 {
+			return nil, false
+		}
+
+		hours, err1 := strconv.Atoi(digits[:2])
+		minutes, err2 := strconv.Atoi(digits[2:])
+		if err1 != nil || err2 != nil || hours < 0 || hours > 23 || minutes < 0 || minutes > 59 {
+			return nil, false
+		}
+
+		offset := hours*3600 + minutes*60
+		if name[0] == '-' {
+			offset = -offset
+		}
+
+		return time.FixedZone(fmt.Sprintf("%c%02d:%02d", name[0], hours, minutes), offset), true
+	}
+
+	loc, err := time.LoadLocation(name)
+
+	return loc, err == nil
+}
+
+// durationArg converts a Duration or a number of seconds to a time.Duration.
+func (t *Thread) durationArg(arg Object, sourceLine int) (time.Duration, *Error) {
+	if d, ok := arg.(*DurationObject); ok {
+		return d.value, nil
+	}
+
+	switch d := t.vm.numericToDuration(arg, time.Second, sourceLine).(type) {
+	case *DurationObject:
+		return d.value, nil
+	case *Error:
+		if _, ok := arg.(Numeric); !ok {
+			return 0, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Duration or Numeric", arg.Class().Name)
+		}
+
+		return 0, d
+	}
+
+	return 0, nil
+}
+
+// timeField returns the body of a Time method that returns an Integer field of the Time.
+func timeField(field func(tm time.Time) int) func(receiver Object, sourceLine int) builtinMethodBody {
+	return func(receiver Object, sourceLine int) builtinMethodBody {
+		return func(t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentFormat, 0, len(args))
+			}
+
+			return t.vm.InitIntegerObject(field(receiver.(*TimeObject).value))
+		}
+	}
+}
+
+// compareTimes returns -1, 0 or 1 if a is earlier than, the same instant as or later than b.
+func compareTimes(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// strftime formats tm with the directives of Ruby's `Time#strftime`.
+func strftime(tm time.Time, format string) string {
+	var out bytes.Buffer
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i == len(format)-1 {
+			out.WriteByte(format[i])
+			continue
+		}
+
+		start := i
+		i++
+
+		pad := true
+		if format[i] == '-' && i+1 < len(format) {
+			pad = false
+			i++
+		}
+
+		colon := false
+		if format[i] == ':' && i+1 < len(format) && format[i+1] == 'z' {
+			colon = true
+			i++
+		}
+
+		if s, ok := strftimeDirective(tm, format[i], pad, colon); ok {
+			out.WriteString(s)
+		} else {
+			out.WriteString(format[start : i+1])
+		}
+	}
+
+	return out.String()
+}
+
+// strftimeDirective returns tm formatted with a single directive, or false if it's unknown.
+func strftimeDirective(tm time.Time, directive byte, pad bool, colon bool) (string, bool) {
+	number := func(n int, width int, padding byte) string {
+		s := strconv.Itoa(n)
+		if !pad || n < 0 || len(s) >= width {
+			return s
+		}
+
+		return strings.Repeat(string(padding), width-len(s)) + s
+	}
+
+	hour12 := tm.Hour() % 12
+	if hour12 == 0 {
+		hour12 = 12
+	}
+
+	switch directive {
+	case 'Y':
+		return number(tm.Year(), 4, '0'), true
+	case 'C':
+		return number(tm.Year()/100, 2, '0'), true
+	case 'y':
+		return number(tm.Year()%100, 2, '0'), true
+	case 'm':
+		return number(int(tm.Month()), 2, '0'), true
+	case 'B':
+		return tm.Month().String(), true
+	case 'b', 'h':
+		return tm.Month().String()[:3], true
+	case 'd':
+		return number(tm.Day(), 2, '0'), true
+	case 'e':
+		return number(tm.Day(), 2, ' '), true
+	case 'j':
+		return number(tm.YearDay(), 3, '0'), true
+	case 'H':
+		return number(tm.Hour(), 2, '0'), true
+	case 'k':
+		return number(tm.Hour(), 2, ' '), true
+	case 'I':
+		return number(hour12, 2, '0'), true
+	case 'l':
+		return number(hour12, 2, ' '), true
+	case 'P':
+		return tm.Format("pm"), true
+	case 'p':
+		return tm.Format("PM"), true
+	case 'M':
+		return number(tm.Minute(), 2, '0'), true
+	case 'S':
+		return number(tm.Second(), 2, '0'), true
+	case 'L':
+		return fmt.Sprintf("%03d", tm.Nanosecond()/int(time.Millisecond)), true
+	case 'N':
+		return fmt.Sprintf("%09d", tm.Nanosecond()), true
+	case 'A':
+		return tm.Weekday().String(), true
+	case 'a':
+		return tm.Weekday().String()[:3], true
+	case 'u':
+		return strconv.Itoa((int(tm.Weekday())+6)%7 + 1), true
+	case 'w':
+		return strconv.Itoa(int(tm.Weekday())), true
+	case 'z':
+		if colon {
+			return tm.Format("-07:00"), true
+		}
+		return tm.Format("-0700"), true
+	case 'Z':
+		name, _ := tm.Zone()
+		return name, true
+	case 's':
+		return strconv.FormatInt(tm.Unix(), 10), true
+	case 'F':
+		return strftime(tm, "%Y-%m-%d"), true
+	case 'T', 'X':
+		return strftime(tm, "%H:%M:%S"), true
+	case 'D', 'x':
+		return strftime(tm, "%m/%d/%y"), true
+	case 'R':
+		return strftime(tm, "%H:%M"), true
+	case 'r':
+		return strftime(tm, "%I:%M:%S %p"), true
+	case 'c':
+		return strftime(tm, "%a %b %e %H:%M:%S %Y"), true
+	case '%':
+		return "%", true
+	case 'n':
+		return "\n", true
+	case 't':
+		return "\t", true
+	default:
+		return "", false
+	}
+}
+
+// strptimeLayouts are the Go layouts of the `strftime` directives `Time.parse` and `Date.parse` accept.
+// Numbers other than years can have one or two digits.
+var strptimeLayouts = map[byte]string{
+	'Y': "2006",
+	'y': "06",
+	'm': "1",
+	'B': "January",
+	'b': "Jan",
+	'h': "Jan",
+	'd': "2",
+	'e': "_2",
+	'j': "002",
+	'H': "15",
+	'I': "3",
+	'p': "PM",
+	'M': "4",
+	'S': "5",
+	'L': "000",
+	'N': "000000000",
+	'A': "Monday",
+	'a': "Mon",
+	'z': "Z0700",
+	'Z': "MST",
+	'F': "2006-01-02",
+	'T': "15:04:05",
+	'%': "%",
+}
+
+// strptimeLayout converts a format with `strftime` directives to a Go layout. It returns false if the format
+// has a directive that can't be parsed, or digits, which Go would take for a part of the layout.
+func strptimeLayout(format string) (string, bool) {
+	var layout bytes.Buffer
+
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+
+		switch {
+		case c == '%' && i+2 < len(format) && format[i+1] == ':' && format[i+2] == 'z':
+			layout.WriteString("Z07:00")
+			i += 2
+		case c == '%' && i+1 < len(format):
+			l, ok := strptimeLayouts[format[i+1]]
+			if !ok {
+				return "", false
+			}
+
+			layout.WriteString(l)
+			i++
+		case c >= '0' && c <= '9':
+			return "", false
+		default:
+			layout.WriteByte(c)
+		}
+	}
+
+	return layout.String(), true
+}
@@ -0,0 +1,265 @@
+package vm
+
+import (
+	"testing"
+	"time"
+)
+
+// fixedTime is the time the clock of the VMs in these tests returns, 2024-01-02 03:04:05.5 UTC
+var fixedTime = time.Date(2024, 1, 2, 3, 4, 5, 500000000, time.UTC)
+
+func initFixedClockTestVM() *VM {
+	v := initTestVM()
+	v.clock = func() time.Time { return fixedTime }
+	return v
+}
+
+func TestTimeClassMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Time.now.to_f`, 1704164645.5},
+		{`Time.now("UTC").to_s`, "2024-01-02 03:04:05 UTC"},
+		{`Time.now("+09:00").to_s`, "2024-01-02 12:04:05 +0900"},
+		{`Time.now("-0530").to_s`, "2024-01-01 21:34:05 -0530"},
+		{`Time.now("Asia/Tokyo").zone`, "JST"},
+		{`Time.new.to_i`, 1704164645},
+		{`Time.at(0, "UTC").to_s`, "1970-01-01 00:00:00 UTC"},
+		{`Time.at(1.25, "UTC").nsec`, 250000000},
+		{`Time.at(-1, "UTC").to_s`, "1969-12-31 23:59:59 UTC"},
+		{`Time.at(1704164645).to_i`, 1704164645},
+		{`Time.new(2024, 1, 2, 3, 4, 5, "UTC").to_s`, "2024-01-02 03:04:05 UTC"},
+		{`Time.new(2024, 1, 2, 3, 4, 5.5, "UTC").nsec`, 500000000},
+		{`Time.new(2024, 2, 3, 0, 0, 0, "UTC").to_i`, 1706918400},
+		{`Time.new(2024, 1, 2, 12, 0, 0, "+09:00").utc.to_s`, "2024-01-02 03:00:00 UTC"},
+		{`Time.new(2024).month`, 1},
+		{`Time.parse("2024-01-02T03:04:05Z").to_i`, 1704164645},
+		{`Time.parse("2024-01-02T03:04:05.5+09:00").utc.to_s`, "2024-01-01 18:04:05 UTC"},
+		{`Time.parse("2024-01-02 03:04:05 +0900").hour`, 3},
+		{`Time.parse("2024-01-02 03:04:05 +0900").utc_offset`, 32400},
+		{`Time.parse("2024-01-02 03:04:05 UTC").utc?`, true},
+		{`Time.parse("Tue, 02 Jan 2024 03:04:05 +0000").to_i`, 1704164645},
+		{`Time.parse("2024-01-02").day`, 2},
+		{`Time.parse("02/01/2024 03:04", "%d/%m/%Y %H:%M").month`, 1},
+		{`Time.parse("Jan 2, 2024 3:04 PM +0900", "%b %e, %Y %I:%M %p %z").utc.to_s`, "2024-01-02 06:04:00 UTC"},
+		{`Time.parse("2024-01-02 03:04:05 +09:00", "%F %T %:z").utc.hour`, 18},
+	}
+
+	for i, tt := range tests {
+		v := initFixedClockTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestTimeClassMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Time.now("Mars/Olympus")`, "ArgumentError: Invalid time zone: \"Mars/Olympus\"", 1},
+		{`Time.now("+25:00")`, "ArgumentError: Invalid time zone: \"+25:00\"", 1},
+		{`Time.now(9)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`Time.at("0")`, "TypeError: Expect argument to be Numeric. got: String", 1},
+		{`Time.at()`, "ArgumentError: Expect 1..2 arguments. got: 0", 1},
+		{`Time.new(2024, 13, 1)`, "ArgumentError: Argument out of range: 13", 1},
+		{`Time.new(2024, 1, 32)`, "ArgumentError: Argument out of range: 32", 1},
+		{`Time.new(2024, 1, 1, 0, 60)`, "ArgumentError: Argument out of range: 60", 1},
+		{`Time.new(2024, 1.5)`, "TypeError: Expect argument to be Integer. got: Float", 1},
+		{`Time.parse("yesterday")`, "ArgumentError: Can't parse \"yesterday\"", 1},
+		{`Time.parse("2024-01-02", "%d/%m/%Y")`, "ArgumentError: Can't parse \"2024-01-02\" with format \"%d/%m/%Y\"", 1},
+		{`Time.parse("2024", "%Q")`, "ArgumentError: Unsupported format: \"%Q\"", 1},
+		{`Time.parse(2024)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`Time.measure`, "InternalError: Can't yield without a block", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initFixedClockTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestTimeMeasure(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		Time.measure do
+		  Time.now
+		end.to_s
+		`, "3s"},
+		{`
+		count = 0
+		Time.measure do
+		  count += 1
+		end
+		count
+		`, 1},
+		{`
+		Time.measure do
+		end.to_f
+		`, 1.5},
+	}
+
+	for i, tt := range tests {
+		// The clock advances by 1.5 seconds every time it's read
+		now := fixedTime
+		v := initTestVM()
+		v.clock = func() time.Time {
+			now = now.Add(1500 * time.Millisecond)
+			return now
+		}
+
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestTimeInstanceMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Time.now("UTC").year`, 2024},
+		{`Time.now("UTC").month`, 1},
+		{`Time.now("UTC").day`, 2},
+		{`Time.now("UTC").hour`, 3},
+		{`Time.now("UTC").min`, 4},
+		{`Time.now("UTC").sec`, 5},
+		{`Time.now("UTC").nsec`, 500000000},
+		{`Time.now("UTC").wday`, 2},
+		{`Time.new(2024, 12, 31, 0, 0, 0, "UTC").yday`, 366},
+		{`Time.now("UTC").zone`, "UTC"},
+		{`Time.now("+09:00").zone`, "+09:00"},
+		{`Time.now("+09:00").utc_offset`, 32400},
+		{`Time.now("+09:00").utc?`, false},
+		{`Time.now("+09:00").utc.utc?`, true},
+		{`Time.now("UTC").localtime("+09:00").hour`, 12},
+		{`Time.now("UTC").localtime("America/New_York").to_s`, "2024-01-01 22:04:05 -0500"},
+		{`Time.now("UTC").to_i`, 1704164645},
+		{`Time.now("-05:00").to_i`, 1704164645},
+		{`Time.now("UTC").to_f`, 1704164645.5},
+		{`Time.now("UTC").iso8601`, "2024-01-02T03:04:05Z"},
+		{`Time.now("UTC").iso8601(3)`, "2024-01-02T03:04:05.500Z"},
+		{`Time.now("+09:00").iso8601`, "2024-01-02T12:04:05+09:00"},
+		{`Time.now("UTC").to_json`, `"2024-01-02T03:04:05.5Z"`},
+		{`{ time: Time.at(0, "+09:00") }.to_json`, `{"time":"1970-01-01T09:00:00+09:00"}`},
+		{`Time.new(2024, 1, 2, 23, 0, 0, "UTC").to_date.to_s`, "2024-01-02"},
+		{`Time.new(2024, 1, 2, 23, 0, 0, "UTC").localtime("+09:00").to_date.to_s`, "2024-01-03"},
+		{`Time.parse(Time.now("+09:00").to_s).to_i`, 1704164645},
+	}
+
+	for i, tt := range tests {
+		v := initFixedClockTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestTimeInstanceMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Time.now.iso8601(10)`, "ArgumentError: Expect the number of digits to be between 0 and 9. got: 10", 1},
+		{`Time.now.iso8601("3")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`Time.now.localtime("Nowhere")`, "ArgumentError: Invalid time zone: \"Nowhere\"", 1},
+		{`Time.now.strftime(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`Time.now.year(1)`, "ArgumentError: Expect 0 arguments. got: 1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initFixedClockTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestTimeStrftime(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Time.now("UTC").strftime("%Y-%m-%d %H:%M:%S %z")`, "2024-01-02 03:04:05 +0000"},
+		{`Time.now("+09:00").strftime("%:z %Z")`, "+09:00 +09:00"},
+		{`Time.now("UTC").strftime("%C %y %j %s")`, "20 24 002 1704164645"},
+		{`Time.now("UTC").strftime("%B %b %h %A %a %u %w")`, "January Jan Jan Tuesday Tue 2 2"},
+		{`Time.now("UTC").strftime("%e|%k|%l|%I %p %P")`, " 2| 3| 3|03 AM am"},
+		{`Time.now("UTC").strftime("%-d/%-m %-H:%M")`, "2/1 3:04"},
+		{`Time.now("UTC").strftime("%L %N")`, "500 500000000"},
+		{`Time.now("UTC").strftime("%F %T")`, "2024-01-02 03:04:05"},
+		{`Time.now("UTC").strftime("%D %R %r")`, "01/02/24 03:04 03:04:05 AM"},
+		{`Time.now("UTC").strftime("%c")`, "Tue Jan  2 03:04:05 2024"},
+		{`Time.now("UTC").strftime("100%% %Q %")`, "100% %Q %"},
+		{`Time.new(2024, 1, 2, 15, 0, 0, "UTC").strftime("%b %-d, %Y %-I%P")`, "Jan 2, 2024 3pm"},
+		{`Time.new(2024, 1, 2, 0, 0, 0, "UTC").strftime("%I %l")`, "12 12"},
+	}
+
+	for i, tt := range tests {
+		v := initFixedClockTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestTimeArithmeticAndComparison(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`(Time.now("UTC") + 60).to_s`, "2024-01-02 03:05:05 UTC"},
+		{`(Time.now("UTC") + 0.5).to_f`, 1704164646.0},
+		{`(Time.now("UTC") + Duration.hours(1)).hour`, 4},
+		{`(Duration.hours(1) + Time.now("UTC")).hour`, 4},
+		{`(Time.now("UTC") - 5).sec`, 0},
+		{`(Time.now("UTC") - Time.new(2024, 1, 2, 0, 0, 0, "UTC")).to_s`, "3h4m5.5s"},
+		{`(Time.now("UTC") - Time.now("UTC")).class.name`, "Duration"},
+		{`(Time.now("UTC") - Time.now("UTC")).zero?`, true},
+		{`Time.now("UTC") <=> Time.now("UTC") + 1`, -1},
+		{`Time.now("UTC") + 1 <=> Time.now("UTC")`, 1},
+		{`Time.now("UTC") <=> Time.now("+09:00")`, 0},
+		{`Time.now <=> 1`, nil},
+		{`Time.now("UTC") == Time.now("+09:00")`, true},
+		{`Time.now < Time.now + 1`, true},
+		{`Time.now >= Time.now + 1`, false},
+		{`Time.now.between?(Time.now - 1, Time.now + 1)`, true},
+		{`Time.now == 1`, false},
+	}
+
+	for i, tt := range tests {
+		v := initFixedClockTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestTimeArithmeticFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Time.now + "1"`, "TypeError: Expect argument to be Duration or Numeric. got: String", 1},
+		{`Time.now - "1"`, "TypeError: Expect argument to be Duration or Numeric. got: String", 1},
+		{`Time.now + Time.now`, "TypeError: Expect argument to be Duration or Numeric. got: Time", 1},
+		{`Time.now + 10000000000000`, "RangeError: Duration is out of range", 1},
+		{`Time.new(2400, 1, 1) - Time.new(2000, 1, 1)`, "RangeError: Duration is out of range", 1},
+		{`Time.new(1600, 1, 1) - Time.new(2000, 1, 1)`, "RangeError: Duration is out of range", 1},
+		{`Time.now < 1`, "ArgumentError: Can't compare Time with Integer", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initFixedClockTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/goby-lang/goby/compiler"
 	"github.com/goby-lang/goby/compiler/bytecode"
@@ -84,11 +85,14 @@ type VM struct {
 	libFiles []string
 
 	threadCount int64
+
+	// clock returns the current time, which tests can replace with a fixed one
+	clock func() time.Time
 }
 
 // New initializes a vm to initialize state and returns it.
 func New(fileDir string, args []string) (vm *VM, e error) {
-	vm = &VM{args: args}
+	vm = &VM{args: args, clock: time.Now}
 	vm.mainThread.vm = vm
 	vm.threadCount++
 
@@ -249,6 +253,12 @@ func (vm *VM) initConstants() {
 		vm.initMatchDataClass(),
 		vm.initGoMapClass(),
 		vm.initDecimalClass(),
+		vm.initTimeClass(),
+		vm.initDateClass(),
+		vm.initDurationClass(),
+		vm.initSetClass(),
+		vm.initComparableModule(),
+		vm.initMathModule(),
 	}
 
 	// Init error classes
@@ -258,6 +268,11 @@ func (vm *VM) initConstants() {
 		vm.objectClass.setClassConstant(c)
 	}
 
+	vm.initRegexpOptions()
+	vm.initFloatConstants()
+	vm.initMathConstants()
+	vm.initTimeComparisons()
+
 	// Init ARGV
 	args := []Object{}
 
@@ -296,6 +311,17 @@ func (vm *VM) currentFilePath() string {
 	return frame.FileName()
 }
 
+// Constant returns the top-level constant with the given name, or nil if it isn't defined.
+func (vm *VM) Constant(name string) Object {
+	ptr, ok := vm.objectClass.constants[name]
+
+	if !ok {
+		return nil
+	}
+
+	return ptr.Target
+}
+
 // loadConstant makes sure we don't create a class twice.
 func (vm *VM) loadConstant(name string, isModule bool) *RClass {
 	var c *RClass